@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"strconv"
+	"task-api/models"
+	"task-api/services"
+	"text/tabwriter"
+	"time"
+)
+
+// newMigrateCmd erstellt "server migrate": wendet ausstehende Migrationen an oder zeigt mit --status den
+// Stand der Datenbank.
+func newMigrateCmd(getenv func(string) string) *cobra.Command {
+	var baseline int
+	var status bool
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply pending database migrations",
+		Long: "Apply all pending migrations in order and record them in schema_migrations.\n\n" +
+			"Databases created before schema_migrations existed must be adopted once with\n" +
+			"--baseline <last applied version>; those versions are then recorded without running them.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withRepositories(getenv, func(cfg *config, repos *repositories) error {
+				maintenance, err := repos.maintenanceService(cfg)
+				if err != nil {
+					return err
+				}
+				out := cmd.OutOrStdout()
+				if status {
+					s, err := maintenance.SchemaStatus()
+					if err != nil {
+						return err
+					}
+					printSchemaStatus(out, s)
+					return nil
+				}
+				applied, err := maintenance.Migrate(baseline)
+				for _, m := range applied {
+					fmt.Fprintf(out, "applied %s\n", m.Name)
+				}
+				if err != nil {
+					return err
+				}
+				if baseline > 0 {
+					fmt.Fprintf(out, "baseline set to version %d\n", baseline)
+				}
+				if len(applied) == 0 {
+					fmt.Fprintln(out, "schema is up to date")
+				}
+				return nil
+			})
+		},
+	}
+	cmd.Flags().IntVar(&baseline, "baseline", 0, "mark versions 1..N as applied (only for untracked databases)")
+	cmd.Flags().BoolVar(&status, "status", false, "show the schema version and pending migrations instead of applying them")
+	return cmd
+}
+
+// printSchemaStatus gibt den Stand der Datenbank für "migrate --status" aus.
+func printSchemaStatus(w io.Writer, s *services.SchemaStatus) {
+	if !s.Tracked {
+		fmt.Fprintln(w, "schema_migrations: missing (untracked database)")
+	}
+	fmt.Fprintf(w, "current version: %d\nlatest version:  %d\n", s.Current, s.Latest)
+	for _, m := range s.Pending {
+		fmt.Fprintf(w, "pending: %s\n", m.Name)
+	}
+	for _, v := range s.Unknown {
+		fmt.Fprintf(w, "unknown: version %d is applied but not part of this binary\n", v)
+	}
+}
+
+// newPurgeCmd erstellt "server purge": löscht über die API gelöschte Tasks endgültig und mit --events
+// zusätzlich alte, zugestellte Ereignisse aus der Outbox.
+func newPurgeCmd(getenv func(string) string) *cobra.Command {
+	var olderThan time.Duration
+	var dryRun, events bool
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Permanently delete tasks that were deleted longer ago than a given age",
+		Long: "DELETE /tasks/:id only marks tasks as deleted. purge removes tasks deleted more than --older-than\n" +
+			"ago for good, together with their tags, assignees, comments, attachments and attachment files.\n\n" +
+			"With --events it also deletes task events from the outbox that are older than --older-than, have\n" +
+			"been dispatched and have no pending webhook deliveries, together with their delivery log.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withRepositories(getenv, func(cfg *config, repos *repositories) error {
+				maintenance, err := repos.maintenanceService(cfg)
+				if err != nil {
+					return err
+				}
+				verb := "purged"
+				if dryRun {
+					verb = "would be purged"
+				}
+				n, err := maintenance.PurgeTasks(olderThan, dryRun)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%d deleted tasks %s\n", n, verb)
+				if !events {
+					return nil
+				}
+				n, err = maintenance.PurgeEvents(olderThan, dryRun)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%d events %s\n", n, verb)
+				return nil
+			})
+		},
+	}
+	cmd.Flags().DurationVar(&olderThan, "older-than", 30*24*time.Hour, "minimum time since deletion (and age of the events with --events)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only count what would be deleted")
+	cmd.Flags().BoolVar(&events, "events", false, "also delete dispatched task events and their delivery log")
+	return cmd
+}
+
+// newKeysCmd erstellt "server keys" mit den Unterbefehlen list, create und rotate.
+func newKeysCmd(getenv func(string) string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage users and their API keys",
+	}
+
+	var tenant string
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the users of a tenant (API keys are stored hashed and cannot be shown)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withRepositories(getenv, func(cfg *config, repos *repositories) error {
+				return listUsers(cmd.OutOrStdout(), &services.UserService{Repo: repos.Users}, tenant)
+			})
+		},
+	}
+	list.Flags().StringVar(&tenant, "tenant", services.DefaultTenant, "tenant of the users")
+
+	var req models.CreateUserRequest
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user and print its API key",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withRepositories(getenv, func(cfg *config, repos *repositories) error {
+				user, err := (&services.UserService{Repo: repos.Users}).CreateUser(req, adminActor(req.Tenant))
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "created user %d (%s, tenant %s)\napi key: %s\n", user.ID, user.Email, user.Tenant, user.APIKey)
+				return nil
+			})
+		},
+	}
+	create.Flags().StringVar(&req.Name, "name", "", "name of the user (required)")
+	create.Flags().StringVar(&req.Email, "email", "", "email of the user (required)")
+	create.Flags().StringVar(&req.Tenant, "tenant", "", "tenant of the user (default \"default\")")
+	_ = create.MarkFlagRequired("name")
+	_ = create.MarkFlagRequired("email")
+
+	rotate := &cobra.Command{
+		Use:   "rotate <user-id>",
+		Short: "Replace the API key of a user; the old key stops working immediately",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid user id %q", args[0])
+			}
+			return withRepositories(getenv, func(cfg *config, repos *repositories) error {
+				key, err := (&services.UserService{Repo: repos.Users}).RotateAPIKey(id)
+				if err != nil {
+					return fmt.Errorf("user %d: %w", id, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "api key: %s\n", key)
+				return nil
+			})
+		},
+	}
+
+	cmd.AddCommand(list, create, rotate)
+	return cmd
+}
+
+// adminActor ist der Akteur der Admin-Befehle im Mandanten tenant (leer = Default-Mandant). Nur darüber
+// lassen sich ohne API-Key Benutzer in anderen Mandanten als "default" anlegen.
+func adminActor(tenant string) *models.User {
+	if tenant == "" {
+		tenant = services.DefaultTenant
+	}
+	return &models.User{Tenant: tenant}
+}
+
+// listUsers gibt die Benutzer eines Mandanten als Tabelle aus.
+func listUsers(w io.Writer, users services.UserServiceInterface, tenant string) error {
+	list, err := users.GetAllUsers(tenant)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tEMAIL\tTENANT\tCREATED")
+	for _, u := range list {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", u.ID, u.Name, u.Email, u.Tenant, u.CreatedAt.Format(time.DateOnly))
+	}
+	return tw.Flush()
+}
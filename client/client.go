@@ -0,0 +1,187 @@
+// Package client ist ein typisierter Go-Client für die REST-API (Version v2).
+// Er entpackt die Antworten ({"data", "meta"}), übersetzt Fehlerantworten in *APIError und wiederholt
+// idempotente Requests (GET, PUT, DELETE) bei Netzwerkfehlern, 429 und 5xx mit exponentiellem Backoff.
+//
+//	c := client.New("http://localhost:8080", os.Getenv("TASK_API_KEY"))
+//	task, err := c.GetTask(ctx, 42)
+//	if errors.Is(err, client.ErrNotFound) {
+//		...
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiVersion ist die Version der REST-API, die der Client verwendet.
+const apiVersion = "v2"
+
+// Standardwerte von New.
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryWait    = 200 * time.Millisecond
+	DefaultMaxRetryWait = 5 * time.Second
+)
+
+// Client ruft die REST-API auf. Die Felder können nach New angepasst werden, aber nicht mehr,
+// sobald der Client von mehreren Goroutinen verwendet wird.
+type Client struct {
+	BaseURL    string       // z.B. "http://localhost:8080", ohne Versionspräfix
+	APIKey     string       // Wird als "Authorization: Bearer <key>" gesendet; leer = ohne Anmeldung
+	HTTPClient *http.Client // Default: http.DefaultClient
+
+	MaxRetries   int           // Wiederholungen idempotenter Requests; 0 = keine
+	RetryWait    time.Duration // Wartezeit vor der ersten Wiederholung, verdoppelt sich danach
+	MaxRetryWait time.Duration // Obergrenze der Wartezeit, auch für Retry-After
+}
+
+// New erzeugt einen Client für die API unter baseURL mit den Standardwerten für Wiederholungen.
+func New(baseURL, apiKey string) *Client {
+	return &Client{
+		BaseURL:      strings.TrimRight(baseURL, "/"),
+		APIKey:       apiKey,
+		HTTPClient:   http.DefaultClient,
+		MaxRetries:   DefaultMaxRetries,
+		RetryWait:    DefaultRetryWait,
+		MaxRetryWait: DefaultMaxRetryWait,
+	}
+}
+
+// idempotent gibt zurück, ob ein Request mit dieser Methode gefahrlos wiederholt werden kann.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryable gibt zurück, ob eine Antwort mit diesem Status auf ein vorübergehendes Problem hinweist.
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || (status >= http.StatusInternalServerError && status != http.StatusNotImplemented)
+}
+
+// do sendet einen Request an /v2<path> und dekodiert "data" (und bei Listen "meta") der Antwort in
+// data bzw. meta, sofern sie nicht nil sind. body wird als JSON gesendet, wenn er nicht nil ist.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, data, meta any) error {
+	u := strings.TrimRight(c.BaseURL, "/") + "/" + apiVersion + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		status, header, respBody, err := c.send(ctx, method, u, payload)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt < c.MaxRetries && idempotent(method) && (err != nil || retryable(status)) {
+			if err := c.wait(ctx, attempt, header); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if status >= http.StatusBadRequest {
+			return newAPIError(status, respBody)
+		}
+		if status == http.StatusNoContent || (data == nil && meta == nil) {
+			return nil
+		}
+		var envelope struct {
+			Data json.RawMessage `json:"data"`
+			Meta json.RawMessage `json:"meta"`
+		}
+		if err := json.Unmarshal(respBody, &envelope); err != nil {
+			return fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+		}
+		if data != nil {
+			if err := json.Unmarshal(envelope.Data, data); err != nil {
+				return fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+			}
+		}
+		if meta != nil && len(envelope.Meta) > 0 {
+			if err := json.Unmarshal(envelope.Meta, meta); err != nil {
+				return fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+			}
+		}
+		return nil
+	}
+}
+
+// send führt einen einzelnen Versuch aus und liest die Antwort vollständig.
+func (c *Client) send(ctx context.Context, method, u string, payload []byte) (int, http.Header, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return resp.StatusCode, resp.Header, data, nil
+}
+
+// wait wartet vor der Wiederholung attempt+1: Retry-After der Antwort, sonst RetryWait * 2^attempt mit
+// zufälliger Streuung (50–100 %), jeweils höchstens MaxRetryWait. Bricht ab, wenn ctx endet.
+func (c *Client) wait(ctx context.Context, attempt int, header http.Header) error {
+	d := c.RetryWait << attempt
+	if d <= 0 || (c.MaxRetryWait > 0 && d > c.MaxRetryWait) {
+		d = c.MaxRetryWait
+	}
+	d = d/2 + rand.N(d/2+1)
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds >= 0 {
+		d = time.Duration(seconds) * time.Second
+		if c.MaxRetryWait > 0 {
+			d = min(d, c.MaxRetryWait)
+		}
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
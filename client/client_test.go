@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+	"time"
+)
+
+// newTestApp baut die API wie der Server mit handlers.NewApp auf (alle Routen, Authentifizierung und
+// Prüfung gegen die OpenAPI-Spezifikation), jedoch mit Mock-Services. Der API-Key "key-1" gehört Anna (ID 1).
+func newTestApp(tasks *services.MockTaskService) http.Handler {
+	users := &services.MockUserService{
+		Users:   []*models.User{{ID: 1, Name: "Anna", Email: "anna@example.com", Tenant: "default"}},
+		APIKeys: map[string]int{"key-1": 1},
+	}
+	app := handlers.NewApp(handlers.APIHandlers{
+		Tasks:   &handlers.TaskHandler{Service: tasks},
+		Users:   &handlers.UserHandler{Service: users},
+		OpenAPI: &handlers.OpenAPIHandler{},
+	}, handlers.AppOptions{Users: users})
+	return adaptor.FiberApp(app)
+}
+
+// newTestClient startet handler auf einem Test-Server und gibt einen Client ohne Wartezeit zwischen
+// Wiederholungen zurück.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c := New(server.URL+"/", "key-1")
+	c.RetryWait = time.Millisecond
+	return c
+}
+
+// flaky beantwortet die ersten failures Requests mit status und leitet danach an next weiter.
+func flaky(next http.Handler, failures int32, status int, calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Test_Client_TaskLifecycle prüft Anlegen, Laden, Ändern und Löschen gegen die echten Handler.
+func Test_Client_TaskLifecycle(t *testing.T) {
+	due := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	tasks := &services.MockTaskService{Tasks: []*models.Task{
+		{ID: 1, Title: "Release vorbereiten", Description: "Changelog", Status: "todo", Priority: "high", DueAt: &due},
+	}}
+	c := newTestClient(t, newTestApp(tasks))
+	ctx := context.Background()
+
+	created, err := c.CreateTask(ctx, models.CreateTaskRequest{Title: "Deploy", Priority: "low"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Deploy", created.Title)
+	assert.Equal(t, "low", created.Priority)
+
+	task, err := c.GetTask(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Release vorbereiten", task.Title)
+	assert.True(t, task.DueAt.Equal(due))
+
+	task.Title = "Release 1.0 vorbereiten"
+	task.Status = "in progress"
+	updated, err := c.UpdateTask(ctx, task)
+	assert.NoError(t, err)
+	assert.Equal(t, "Release 1.0 vorbereiten", updated.Title)
+	assert.Equal(t, "in progress", tasks.Tasks[0].Status)
+
+	done := "done"
+	patched, err := c.PatchTask(ctx, 1, TaskPatch{Status: &done})
+	assert.NoError(t, err)
+	assert.Equal(t, "done", patched.Status)
+	assert.Equal(t, "Release 1.0 vorbereiten", tasks.Tasks[0].Title)
+	assert.Equal(t, "Changelog", tasks.Tasks[0].Description)
+
+	assert.NoError(t, c.DeleteTask(ctx, 1))
+	_, err = c.GetTask(ctx, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// Test_Client_TypedErrors prüft, dass Fehlerantworten als *APIError mit Code, Meldung und Feldern ankommen.
+func Test_Client_TypedErrors(t *testing.T) {
+	c := newTestClient(t, newTestApp(&services.MockTaskService{}))
+	ctx := context.Background()
+
+	_, err := c.CreateTask(ctx, models.CreateTaskRequest{Title: "Deploy", Priority: "urgent"})
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "validation_error", apiErr.Code)
+	assert.Equal(t, []FieldError{{In: "body", Pointer: "/priority", Message: apiErr.Fields[0].Message}}, apiErr.Fields)
+
+	_, err = c.GetTask(ctx, 7)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrValidation)
+	assert.EqualError(t, err, "404 not_found: Task with ID 7 not found")
+
+	c.APIKey = "unknown"
+	_, err = c.ListTasks(ctx, ListOptions{Assignee: "me"})
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+// Test_Client_TaskPages prüft, dass der Iterator alle Seiten mit Filter und Seitengröße liest.
+func Test_Client_TaskPages(t *testing.T) {
+	tasks := &services.MockTaskService{}
+	for id := 1; id <= 5; id++ {
+		tasks.Tasks = append(tasks.Tasks, &models.Task{ID: id, Title: "Task", Tags: []string{"backend"}, Assignees: []int{1}})
+	}
+	tasks.Tasks = append(tasks.Tasks, &models.Task{ID: 6, Title: "Fremd", Tags: []string{"frontend"}})
+	c := newTestClient(t, newTestApp(tasks))
+	ctx := context.Background()
+
+	var ids [][]int
+	for page, err := range c.TaskPages(ctx, ListOptions{TagsAny: []string{"backend"}, Assignee: "me", Limit: 2}) {
+		assert.NoError(t, err)
+		assert.Equal(t, 5, page.Total)
+		var pageIDs []int
+		for _, task := range page.Tasks {
+			pageIDs = append(pageIDs, task.ID)
+		}
+		ids = append(ids, pageIDs)
+	}
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, ids)
+
+	// Abbruch nach der ersten Seite lädt keine weiteren Seiten
+	pages := 0
+	for range c.TaskPages(ctx, ListOptions{Limit: 1}) {
+		pages++
+		break
+	}
+	assert.Equal(t, 1, pages)
+
+	page, err := c.ListTasks(ctx, ListOptions{Fields: []string{"title"}, Offset: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, page.Total)
+	assert.Len(t, page.Tasks, 2)
+	assert.Equal(t, "Fremd", page.Tasks[1].Title)
+	assert.Empty(t, page.Tasks[1].Tags)
+	assert.False(t, page.HasMore())
+
+	for _, err := range c.TaskPages(ctx, ListOptions{Limit: 500}) {
+		assert.ErrorIs(t, err, ErrValidation)
+	}
+}
+
+// Test_Client_Retries prüft, dass idempotente Requests bei 503 wiederholt werden, POST aber nicht.
+func Test_Client_Retries(t *testing.T) {
+	tasks := &services.MockTaskService{Tasks: []*models.Task{{ID: 1, Title: "Release", Status: "todo"}}}
+	var calls atomic.Int32
+	c := newTestClient(t, flaky(newTestApp(tasks), 2, http.StatusServiceUnavailable, &calls))
+	ctx := context.Background()
+
+	task, err := c.GetTask(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Release", task.Title)
+	assert.Equal(t, int32(3), calls.Load())
+
+	calls.Store(0)
+	_, err = c.CreateTask(ctx, models.CreateTaskRequest{Title: "Deploy"})
+	assert.ErrorIs(t, err, ErrServer)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Nach MaxRetries Wiederholungen kommt der letzte Fehler zurück
+	calls.Store(0)
+	c = newTestClient(t, flaky(newTestApp(tasks), 100, http.StatusServiceUnavailable, &calls))
+	c.MaxRetries = 1
+	title := "Release 2"
+	_, err = c.PatchTask(ctx, 1, TaskPatch{Title: &title})
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, "Service Unavailable", apiErr.Message)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, "Release", tasks.Tasks[0].Title)
+}
+
+// Test_Client_ContextCancel prüft, dass ein abgebrochener Kontext das Warten auf die Wiederholung beendet.
+func Test_Client_ContextCancel(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, flaky(http.NotFoundHandler(), 100, http.StatusTooManyRequests, &calls))
+	c.RetryWait = time.Hour
+	c.MaxRetryWait = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.GetTask(ctx, 1)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(1), calls.Load())
+}
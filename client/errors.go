@@ -0,0 +1,77 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Fehlerklassen für errors.Is. Ein *APIError entspricht der Klasse seines HTTP-Status.
+var (
+	ErrValidation   = errors.New("validation error") // 400
+	ErrUnauthorized = errors.New("unauthorized")     // 401
+	ErrForbidden    = errors.New("forbidden")        // 403
+	ErrNotFound     = errors.New("not found")        // 404
+	ErrConflict     = errors.New("conflict")         // 409, z.B. unzulässiger Statuswechsel
+	ErrRateLimited  = errors.New("rate limited")     // 429
+	ErrServer       = errors.New("server error")     // 5xx
+)
+
+// FieldError ist ein einzelner Verstoß in einem Validierungsfehler.
+type FieldError struct {
+	In      string `json:"in"`      // "body", "query" oder "path"
+	Pointer string `json:"pointer"` // JSON Pointer bzw. Name des Parameters, z.B. "/title"
+	Message string `json:"message"`
+}
+
+// APIError ist eine Fehlerantwort der API ({"error": {"code", "message", "fields"}}).
+type APIError struct {
+	StatusCode int          `json:"-"`
+	Code       string       `json:"code"` // z.B. "not_found", "validation_error", "conflict"
+	Message    string       `json:"message"`
+	Fields     []FieldError `json:"fields"`      // Nur bei Validierungsfehlern
+	NextStates []string     `json:"next_states"` // Nur bei unzulässigem Statuswechsel: erlaubte Folgestatus
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+	for _, f := range e.Fields {
+		msg += fmt.Sprintf("; %s: %s", f.Pointer, f.Message)
+	}
+	return msg
+}
+
+// Is ordnet den Fehler anhand des HTTP-Status einer der Fehlerklassen (ErrNotFound, ...) zu.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// newAPIError liest eine Fehlerantwort. Antworten ohne das Fehlerformat von v2 (z.B. von einem Proxy)
+// werden mit dem Statustext als Meldung übernommen.
+func newAPIError(status int, body []byte) *APIError {
+	var envelope struct {
+		Error *APIError `json:"error"`
+	}
+	if json.Unmarshal(body, &envelope) != nil || envelope.Error == nil {
+		envelope.Error = &APIError{Message: http.StatusText(status)}
+	}
+	envelope.Error.StatusCode = status
+	return envelope.Error
+}
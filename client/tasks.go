@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"task-api/models"
+	"time"
+)
+
+// DefaultPageSize ist die Seitengröße von TaskPages ohne ListOptions.Limit (Maximum der API).
+const DefaultPageSize = 100
+
+// ListOptions sind Filter und Ausschnitt für ListTasks und TaskPages (Query-Parameter von GET /tasks).
+// Nicht gesetzte Felder schränken das Ergebnis nicht ein.
+type ListOptions struct {
+	Overdue   *bool
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	TagsAny   []string
+	TagsAll   []string
+	TagsNone  []string
+	Assignee  string   // Benutzer-ID oder "me"
+	Watcher   string   // Benutzer-ID oder "me"
+	Fields    []string // Nur diese Felder laden (siehe models.TaskFields); andere bleiben leer
+
+	Limit  int // Höchstens so viele Tasks (1 bis 100); 0 = alle
+	Offset int // So viele Tasks am Anfang überspringen
+}
+
+// query baut die Query-Parameter von GET /tasks.
+func (o ListOptions) query() url.Values {
+	q := url.Values{}
+	if o.Overdue != nil {
+		q.Set("overdue", strconv.FormatBool(*o.Overdue))
+	}
+	for name, t := range map[string]*time.Time{"due_before": o.DueBefore, "due_after": o.DueAfter} {
+		if t != nil {
+			q.Set(name, t.Format(time.RFC3339))
+		}
+	}
+	for name, list := range map[string][]string{"tags_any": o.TagsAny, "tags_all": o.TagsAll, "tags_none": o.TagsNone, "fields": o.Fields} {
+		if len(list) > 0 {
+			q.Set(name, strings.Join(list, ","))
+		}
+	}
+	for name, v := range map[string]string{"assignee": o.Assignee, "watcher": o.Watcher} {
+		if v != "" {
+			q.Set(name, v)
+		}
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	return q
+}
+
+// TaskPage ist eine Seite aus ListTasks.
+type TaskPage struct {
+	Tasks  []*models.Task
+	Total  int // Anzahl aller passenden Tasks
+	Offset int // Position des ersten Tasks der Seite
+}
+
+// HasMore gibt zurück, ob nach dieser Seite weitere Tasks folgen.
+func (p *TaskPage) HasMore() bool {
+	return len(p.Tasks) > 0 && p.Offset+len(p.Tasks) < p.Total
+}
+
+// TaskPatch enthält die Felder, die PatchTask ändert. Nur gesetzte Felder werden gesendet.
+type TaskPatch struct {
+	Title          *string    `json:"title,omitempty"`
+	Description    *string    `json:"description,omitempty"`
+	Status         *string    `json:"status,omitempty"`
+	Priority       *string    `json:"priority,omitempty"`
+	ParentID       *int       `json:"parent_id,omitempty"` // 0 entfernt die Zuordnung
+	StartAt        *time.Time `json:"start_at,omitempty"`
+	DueAt          *time.Time `json:"due_at,omitempty"`
+	RecurrenceRule *string    `json:"recurrence_rule,omitempty"` // "" entfernt die Wiederholung
+	Scope          string     `json:"scope,omitempty"`           // Bei Serien: "this" (Default) oder "future"
+
+	OverrideBlockers bool `json:"override_blockers,omitempty"`
+	Reopen           bool `json:"reopen,omitempty"`
+}
+
+// CreateTask legt einen Task an (POST /tasks). Wird nicht wiederholt, da ein erneuter Versuch einen
+// zweiten Task anlegen könnte.
+func (c *Client) CreateTask(ctx context.Context, req models.CreateTaskRequest) (*models.Task, error) {
+	var task models.Task
+	if err := c.do(ctx, http.MethodPost, "/tasks", nil, req, &task, nil); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetTask lädt einen Task (GET /tasks/:id).
+func (c *Client) GetTask(ctx context.Context, id int) (*models.Task, error) {
+	var task models.Task
+	if err := c.do(ctx, http.MethodGet, "/tasks/"+strconv.Itoa(id), nil, nil, &task, nil); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ListTasks lädt eine Seite von Tasks (GET /tasks). Ohne opts.Limit enthält die Seite alle passenden Tasks.
+func (c *Client) ListTasks(ctx context.Context, opts ListOptions) (*TaskPage, error) {
+	page := &TaskPage{Offset: opts.Offset}
+	var meta struct {
+		Total int `json:"total"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/tasks", opts.query(), nil, &page.Tasks, &meta); err != nil {
+		return nil, err
+	}
+	page.Total = meta.Total
+	return page, nil
+}
+
+// TaskPages liefert die Seiten von ListTasks ab opts.Offset mit opts.Limit Tasks (Default DefaultPageSize),
+// bis alle passenden Tasks gelesen sind. Nach einem Fehler endet die Iteration.
+//
+//	for page, err := range c.TaskPages(ctx, client.ListOptions{TagsAny: []string{"backend"}}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) TaskPages(ctx context.Context, opts ListOptions) iter.Seq2[*TaskPage, error] {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultPageSize
+	}
+	return func(yield func(*TaskPage, error) bool) {
+		for {
+			page, err := c.ListTasks(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(page, nil) || !page.HasMore() {
+				return
+			}
+			opts.Offset += len(page.Tasks)
+		}
+	}
+}
+
+// UpdateTask speichert Titel, Beschreibung, Status, Priorität, Parent, Termine und Wiederholung von task
+// (PUT /tasks/:id), z.B. nach Änderungen an einem Task aus GetTask. Leere Texte und Termine lässt die API
+// unverändert; ohne Parent bzw. Wiederholung werden diese entfernt. task muss daher alle Felder enthalten
+// (nicht aus ListTasks mit Fields); einzelne Felder ändert PatchTask.
+func (c *Client) UpdateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+	parentID := 0
+	if task.ParentID != nil {
+		parentID = *task.ParentID
+	}
+	req := models.CreateTaskRequest{
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         task.Status,
+		Priority:       task.Priority,
+		ParentID:       &parentID,
+		StartAt:        task.StartAt,
+		DueAt:          task.DueAt,
+		RecurrenceRule: &task.RecurrenceRule,
+	}
+	return c.putTask(ctx, task.ID, req)
+}
+
+// PatchTask ändert nur die gesetzten Felder von patch. Die API hat keine eigene PATCH-Route; PUT /tasks/:id
+// lässt fehlende Felder unverändert, daher wird patch per PUT gesendet.
+func (c *Client) PatchTask(ctx context.Context, id int, patch TaskPatch) (*models.Task, error) {
+	return c.putTask(ctx, id, patch)
+}
+
+// putTask sendet body an PUT /tasks/:id.
+func (c *Client) putTask(ctx context.Context, id int, body any) (*models.Task, error) {
+	var task models.Task
+	if err := c.do(ctx, http.MethodPut, "/tasks/"+strconv.Itoa(id), nil, body, &task, nil); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteTask löscht einen Task (DELETE /tasks/:id). Geht die Antwort verloren, kann die Wiederholung
+// mit ErrNotFound enden, obwohl der Task gelöscht wurde.
+func (c *Client) DeleteTask(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, "/tasks/"+strconv.Itoa(id), nil, nil, nil, nil)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiVersion ist die Version der REST-API, die taskctl verwendet.
+const apiVersion = "v2"
+
+// apiClient ruft die REST-API auf und entpackt die Antworten von v2 ({"data", "meta"}).
+type apiClient struct {
+	server string
+	apiKey string
+	http   *http.Client
+}
+
+// apiError ist eine Fehlerantwort der API ({"error": {"code", "message", "fields"}}).
+type apiError struct {
+	Status  int
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Fields  []struct {
+		Pointer string `json:"pointer"`
+		Message string `json:"message"`
+	} `json:"fields"`
+}
+
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Message)
+	for _, f := range e.Fields {
+		msg += fmt.Sprintf("\n  %s: %s", f.Pointer, f.Message)
+	}
+	return msg
+}
+
+func newAPIClient(server, apiKey string) *apiClient {
+	return &apiClient{
+		server: strings.TrimRight(server, "/"),
+		apiKey: apiKey,
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sendet einen Request an /v2<path> und gibt den Inhalt von "data" zurück (nil bei 204).
+// body wird als JSON gesendet, wenn er nicht nil ist.
+func (c *apiClient) do(method, path string, query url.Values, body any) (json.RawMessage, error) {
+	u := c.server + "/" + apiVersion + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var envelope struct {
+			Error *apiError `json:"error"`
+		}
+		if json.Unmarshal(data, &envelope) != nil || envelope.Error == nil {
+			return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+		}
+		envelope.Error.Status = resp.StatusCode
+		return nil, envelope.Error
+	}
+	if resp.StatusCode == http.StatusNoContent || len(data) == 0 {
+		return nil, nil
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("%s %s: invalid response: %w", method, path, err)
+	}
+	return envelope.Data, nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// Standardwerte, wenn weder Flag, Umgebungsvariable noch Profil einen Wert liefern.
+const (
+	defaultProfile = "default"
+	defaultServer  = "http://localhost:8080"
+)
+
+// profile enthält Server und Zugangsdaten für eine Instanz der API.
+type profile struct {
+	Server string `yaml:"server"`
+	APIKey string `yaml:"api_key,omitempty"`
+}
+
+// config ist der Inhalt der Konfigurationsdatei.
+type config struct {
+	Current  string              `yaml:"current,omitempty"` // Profil ohne --profile
+	Profiles map[string]*profile `yaml:"profiles"`
+}
+
+// configPath bestimmt den Pfad der Konfigurationsdatei: --config, TASKCTL_CONFIG oder
+// <Benutzer-Konfigurationsverzeichnis>/taskctl/config.yaml.
+func (o *globalOptions) configPath() (string, error) {
+	if o.configFile != "" {
+		return o.configFile, nil
+	}
+	if p := os.Getenv("TASKCTL_CONFIG"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "taskctl", "config.yaml"), nil
+}
+
+// loadConfig liest die Konfigurationsdatei. Fehlt die Datei, ist die Konfiguration leer.
+func (o *globalOptions) loadConfig() (*config, string, error) {
+	path, err := o.configPath()
+	if err != nil {
+		return nil, "", err
+	}
+	cfg := &config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		data, err = nil, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*profile{}
+	}
+	return cfg, path, nil
+}
+
+// saveConfig schreibt die Konfiguration. Die Datei ist nur für den Benutzer lesbar, da sie API-Keys enthält.
+func saveConfig(path string, cfg *config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// profileName bestimmt das aktive Profil: --profile, TASKCTL_PROFILE, current aus der Datei oder "default".
+func (o *globalOptions) profileName(cfg *config) string {
+	for _, name := range []string{o.profile, os.Getenv("TASKCTL_PROFILE"), cfg.Current} {
+		if name != "" {
+			return name
+		}
+	}
+	return defaultProfile
+}
+
+// newClient erzeugt einen Client für das aktive Profil. Flags haben Vorrang vor Umgebungsvariablen,
+// diese vor dem Profil. Ein ausdrücklich gewähltes, aber nicht vorhandenes Profil ist ein Fehler.
+func (o *globalOptions) newClient() (*apiClient, error) {
+	cfg, _, err := o.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	name := o.profileName(cfg)
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		if name != defaultProfile {
+			return nil, fmt.Errorf("profile %q not found; create it with: taskctl config set %s --server <url>", name, name)
+		}
+		p = &profile{}
+	}
+
+	server := firstNonEmpty(o.server, os.Getenv("TASKCTL_SERVER"), p.Server, defaultServer)
+	apiKey := firstNonEmpty(o.apiKey, os.Getenv("TASKCTL_API_KEY"), p.APIKey)
+	return newAPIClient(server, apiKey), nil
+}
+
+// firstNonEmpty gibt den ersten nicht leeren Wert zurück.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// completeProfiles vervollständigt Profilnamen aus der Konfigurationsdatei.
+func (o *globalOptions) completeProfiles(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	cfg, _, err := o.loadConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return sortedProfiles(cfg), cobra.ShellCompDirectiveNoFileComp
+}
+
+// sortedProfiles gibt die Namen aller Profile alphabetisch zurück.
+func sortedProfiles(cfg *config) []string {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// newConfigCmd baut "taskctl config" mit den Unterbefehlen set, use, list und delete auf.
+func newConfigCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage profiles with server URL and API key",
+	}
+
+	var use bool
+	set := &cobra.Command{
+		Use:   "set <profile> [--server <url>] [--api-key <key>]",
+		Short: "Create or change a profile (uses the global --server and --api-key flags)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := opts.loadConfig()
+			if err != nil {
+				return err
+			}
+			p, ok := cfg.Profiles[args[0]]
+			if !ok {
+				p = &profile{Server: defaultServer}
+				cfg.Profiles[args[0]] = p
+			}
+			if cmd.Flags().Changed("server") {
+				p.Server = strings.TrimRight(opts.server, "/")
+			}
+			if cmd.Flags().Changed("api-key") {
+				p.APIKey = opts.apiKey
+			}
+			if use || cfg.Current == "" {
+				cfg.Current = args[0]
+			}
+			return saveConfig(path, cfg)
+		},
+	}
+	set.Flags().BoolVar(&use, "use", false, "make this the current profile")
+
+	useCmd := &cobra.Command{
+		Use:               "use <profile>",
+		Short:             "Set the profile used without --profile",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := opts.loadConfig()
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %q not found", args[0])
+			}
+			cfg.Current = args[0]
+			return saveConfig(path, cfg)
+		},
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List profiles (API keys are masked)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, _, err := opts.loadConfig()
+			if err != nil {
+				return err
+			}
+			current := opts.profileName(cfg)
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "CURRENT\tNAME\tSERVER\tAPI KEY")
+			for _, name := range sortedProfiles(cfg) {
+				marker := ""
+				if name == current {
+					marker = "*"
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", marker, name, cfg.Profiles[name].Server, maskKey(cfg.Profiles[name].APIKey))
+			}
+			return tw.Flush()
+		},
+	}
+
+	del := &cobra.Command{
+		Use:               "delete <profile>",
+		Short:             "Delete a profile",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeProfiles,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := opts.loadConfig()
+			if err != nil {
+				return err
+			}
+			if _, ok := cfg.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %q not found", args[0])
+			}
+			delete(cfg.Profiles, args[0])
+			if cfg.Current == args[0] {
+				cfg.Current = ""
+			}
+			return saveConfig(path, cfg)
+		},
+	}
+
+	cmd.AddCommand(set, useCmd, list, del)
+	return cmd
+}
+
+// maskKey zeigt nur die letzten vier Zeichen eines API-Keys.
+func maskKey(key string) string {
+	if key == "" {
+		return "-"
+	}
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
@@ -0,0 +1,75 @@
+// Command taskctl ist ein Kommandozeilen-Client für die Task-API.
+//
+// taskctl spricht die REST-API in Version v2 an und gibt Tasks als Tabelle, JSON oder YAML aus.
+// Server-URL und API-Key kommen aus Profilen in der Konfigurationsdatei (siehe "taskctl config"),
+// aus den Umgebungsvariablen TASKCTL_PROFILE, TASKCTL_SERVER und TASKCTL_API_KEY oder aus Flags.
+//
+// Beispiele:
+//
+//	taskctl config set default --server http://localhost:8080 --api-key <key>
+//	taskctl list --assignee me --overdue -o table
+//	taskctl create --title "Release vorbereiten" --priority high --due 2026-05-01
+//	taskctl update 7 --status done
+//	taskctl completion bash > /etc/bash_completion.d/taskctl
+package main
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// globalOptions sind die Flags, die für alle Unterbefehle gelten.
+type globalOptions struct {
+	configFile string // Pfad der Konfigurationsdatei (leer = Standardpfad)
+	profile    string // Name des Profils
+	server     string // Überschreibt die Server-URL des Profils
+	apiKey     string // Überschreibt den API-Key des Profils
+	output     string // Ausgabeformat: table, json oder yaml
+}
+
+// newRootCmd baut den Befehlsbaum von taskctl auf. Die Shell-Vervollständigung ("taskctl completion
+// bash|zsh|fish|powershell") stellt cobra bereit.
+func newRootCmd() *cobra.Command {
+	opts := &globalOptions{}
+	root := &cobra.Command{
+		Use:           "taskctl",
+		Short:         "Command-line client for the task API",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.configFile, "config", "", "config file (default $XDG_CONFIG_HOME/taskctl/config.yaml, env TASKCTL_CONFIG)")
+	flags.StringVarP(&opts.profile, "profile", "p", "", "profile to use (env TASKCTL_PROFILE)")
+	flags.StringVar(&opts.server, "server", "", "server URL, overrides the profile (env TASKCTL_SERVER)")
+	flags.StringVar(&opts.apiKey, "api-key", "", "API key, overrides the profile (env TASKCTL_API_KEY)")
+	flags.StringVarP(&opts.output, "output", "o", formatTable, "output format: table, json or yaml")
+	_ = root.RegisterFlagCompletionFunc("output", fixedCompletion(outputFormats...))
+	_ = root.RegisterFlagCompletionFunc("profile", opts.completeProfiles)
+
+	root.AddCommand(
+		newListCmd(opts),
+		newSearchCmd(opts),
+		newGetCmd(opts),
+		newCreateCmd(opts),
+		newUpdateCmd(opts),
+		newDeleteCmd(opts),
+		newConfigCmd(opts),
+	)
+	return root
+}
+
+// fixedCompletion liefert eine Vervollständigung mit festen Werten.
+func fixedCompletion(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"task-api/models"
+	"text/tabwriter"
+	"time"
+)
+
+// Ausgabeformate (--output).
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// outputFormats sind alle Werte von --output.
+var outputFormats = []string{formatTable, formatJSON, formatYAML}
+
+// tableTimeFormat ist das Format von Zeitpunkten in der Tabelle (lokale Zeitzone).
+const tableTimeFormat = "2006-01-02 15:04"
+
+// checkOutput prüft den Wert von --output.
+func checkOutput(format string) error {
+	if !slices.Contains(outputFormats, format) {
+		return fmt.Errorf("output must be one of: %s", strings.Join(outputFormats, ", "))
+	}
+	return nil
+}
+
+// printTasks gibt Tasks im gewählten Format aus. JSON und YAML geben die Antwort der API unverändert
+// wieder (inkl. fields= und include=), die Tabelle zeigt die wichtigsten Felder aus models.Task.
+func printTasks(w io.Writer, format string, raw json.RawMessage, tasks []*models.Task) error {
+	switch format {
+	case formatJSON:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, raw, "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		_, err := buf.WriteTo(w)
+		return err
+	case formatYAML:
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tSTATUS\tPRIORITY\tDUE\tTAGS\tASSIGNEES")
+	for _, t := range tasks {
+		due := "-"
+		if t.DueAt != nil {
+			due = t.DueAt.In(time.Local).Format(tableTimeFormat)
+			if t.IsOverdue {
+				due += " (overdue)"
+			}
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.Title, t.Status, t.Priority, due,
+			strings.Join(t.Tags, ","), joinIDs(t.Assignees))
+	}
+	return tw.Flush()
+}
+
+// joinIDs gibt IDs kommagetrennt zurück.
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ",")
+}
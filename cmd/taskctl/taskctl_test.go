@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+	"time"
+)
+
+// setupServer startet die Task-Routen von v2 mit Mock-Services auf einem Test-Server.
+// Der Benutzer mit dem API-Key "key-1" ist Anna (ID 1).
+func setupServer(t *testing.T) (*httptest.Server, *services.MockTaskService) {
+	due := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	tasks := &services.MockTaskService{Tasks: []*models.Task{
+		{ID: 1, Title: "Release vorbereiten", Status: "todo", Priority: "high", DueAt: &due, Tags: []string{"backend"}, Assignees: []int{1}},
+		{ID: 2, Title: "Doku", Description: "README für den Release", Status: "done", Priority: "low", Tags: []string{}},
+	}}
+	users := &services.MockUserService{
+		Users:   []*models.User{{ID: 1, Name: "Anna", Email: "anna@example.com", Tenant: "default"}},
+		APIKeys: map[string]int{"key-1": 1},
+	}
+
+	app := fiber.New()
+	app.Use(handlers.NegotiateVersion(handlers.VersionOptions{}))
+	app.Use(handlers.Authenticate(users))
+	h := &handlers.TaskHandler{Service: tasks}
+	v2 := app.Group("/"+handlers.APIVersion2, handlers.EnvelopeResponses())
+	v2.Get("/tasks", h.ListTasks)
+	v2.Post("/tasks", h.CreateTask)
+	v2.Get("/tasks/:id", h.GetTaskByID)
+	v2.Put("/tasks/:id", h.UpdateTask)
+	v2.Delete("/tasks/:id", h.DeleteTask)
+	v2.Get("/workflow", h.GetWorkflow)
+
+	server := httptest.NewServer(adaptor.FiberApp(app))
+	t.Cleanup(server.Close)
+	return server, tasks
+}
+
+// run führt taskctl mit einer Konfigurationsdatei im Testverzeichnis aus und gibt die Ausgabe zurück.
+func run(t *testing.T, configPath string, args ...string) (string, error) {
+	t.Setenv("TASKCTL_PROFILE", "")
+	t.Setenv("TASKCTL_SERVER", "")
+	t.Setenv("TASKCTL_API_KEY", "")
+	cmd := newRootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(append([]string{"--config", configPath}, args...))
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+// Test_Config_Profiles prüft Anlegen, Wechseln und Auflisten von Profilen sowie die Rechte der Datei.
+func Test_Config_Profiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "taskctl", "config.yaml")
+
+	_, err := run(t, path, "config", "set", "local", "--server", "http://localhost:8080/", "--api-key", "secret-1234")
+	assert.NoError(t, err)
+	_, err = run(t, path, "config", "set", "prod", "--server", "https://tasks.example.com")
+	assert.NoError(t, err)
+
+	out, err := run(t, path, "config", "list")
+	assert.NoError(t, err)
+	assert.Regexp(t, `\*\s+local\s+http://localhost:8080\s+\*\*\*\*1234`, out)
+	assert.NotContains(t, out, "secret")
+
+	_, err = run(t, path, "config", "use", "prod")
+	assert.NoError(t, err)
+	out, _ = run(t, path, "config", "list")
+	assert.Regexp(t, `\*\s+prod`, out)
+
+	_, err = run(t, path, "config", "use", "missing")
+	assert.EqualError(t, err, `profile "missing" not found`)
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+// Test_List_FiltersAndFormats prüft, dass Filter-Flags als Query-Parameter ankommen und die Ausgabe
+// als Tabelle, JSON und YAML.
+func Test_List_FiltersAndFormats(t *testing.T) {
+	server, _ := setupServer(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	_, err := run(t, path, "config", "set", "test", "--server", server.URL, "--api-key", "key-1")
+	assert.NoError(t, err)
+
+	out, err := run(t, path, "list")
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, 3)
+	assert.Regexp(t, `^ID\s+TITLE\s+STATUS`, lines[0])
+	assert.Regexp(t, `^1\s+Release vorbereiten\s+todo\s+high`, lines[1])
+
+	out, err = run(t, path, "list", "--assignee", "me", "--tags-any", "backend", "-o", "json")
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"title": "Release vorbereiten"`)
+	assert.NotContains(t, out, "Doku")
+
+	out, err = run(t, path, "list", "--fields", "id,title", "-o", "yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "- id: 2\n  title: Doku\n")
+	assert.NotContains(t, out, "status")
+
+	_, err = run(t, path, "list", "--due-before", "gestern")
+	assert.ErrorContains(t, err, "400 validation_error: due_before")
+
+	_, err = run(t, path, "list", "-o", "xml")
+	assert.EqualError(t, err, "output must be one of: table, json, yaml")
+}
+
+// Test_Search_MatchesTitleDescriptionAndTags prüft die Suche im Client.
+func Test_Search_MatchesTitleDescriptionAndTags(t *testing.T) {
+	server, _ := setupServer(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	out, err := run(t, path, "--server", server.URL, "search", "release", "-o", "json")
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"id": 1`)
+	assert.Contains(t, out, `"id": 2`)
+
+	out, err = run(t, path, "--server", server.URL, "search", "BACKEND")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "Release vorbereiten")
+	assert.NotContains(t, out, "Doku")
+
+	out, err = run(t, path, "--server", server.URL, "search", "nichts", "-o", "json")
+	assert.NoError(t, err)
+	assert.Equal(t, "[]\n", out)
+}
+
+// Test_CreateUpdateDelete prüft den Lebenszyklus eines Tasks über die CLI: update sendet nur die
+// angegebenen Felder, Fehler der API werden mit Status und Code ausgegeben.
+func Test_CreateUpdateDelete(t *testing.T) {
+	server, tasks := setupServer(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	out, err := run(t, path, "--server", server.URL, "create", "--title", "Deploy", "--priority", "high", "--due", "2026-06-01T10:00:00Z", "-o", "json")
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"title": "Deploy"`)
+	assert.Contains(t, out, `"due_at": "2026-06-01T10:00:00Z"`)
+
+	_, err = run(t, path, "--server", server.URL, "create")
+	assert.ErrorContains(t, err, `required flag(s) "title" not set`)
+
+	_, err = run(t, path, "--server", server.URL, "update", "1", "--status", "in progress")
+	assert.NoError(t, err)
+	assert.Equal(t, "in progress", tasks.Tasks[0].Status)
+	assert.Equal(t, "Release vorbereiten", tasks.Tasks[0].Title)
+	assert.Equal(t, "high", tasks.Tasks[0].Priority)
+
+	_, err = run(t, path, "--server", server.URL, "update", "1", "-o", "json")
+	assert.EqualError(t, err, "nothing to update; set at least one field, e.g. --status done")
+
+	_, err = run(t, path, "--server", server.URL, "update", "1", "--priority", "urgent")
+	assert.ErrorContains(t, err, "400 validation_error")
+
+	out, err = run(t, path, "--server", server.URL, "delete", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, "task 2 deleted\n", out)
+
+	_, err = run(t, path, "--server", server.URL, "get", "2")
+	assert.ErrorContains(t, err, "404 not_found")
+}
+
+// Test_Completion_TaskIDsAndStatuses prüft die dynamische Vervollständigung von IDs und Status.
+func Test_Completion_TaskIDsAndStatuses(t *testing.T) {
+	server, _ := setupServer(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	out, err := run(t, path, "--server", server.URL, "__complete", "get", "")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "1\tRelease vorbereiten\n2\tDoku\n")
+
+	out, err = run(t, path, "--server", server.URL, "__complete", "update", "1", "--status", "")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "todo\nin progress\ndone\n")
+
+	out, err = run(t, path, "completion", "bash")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "__start_taskctl")
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"task-api/models"
+	"time"
+)
+
+// taskFilterFlags bilden die Query-Parameter von GET /tasks auf Flags ab (Flag -> Parameter).
+// overdue ist als Bool-Flag gesondert definiert.
+var taskFilterFlags = []struct{ flag, param, usage string }{
+	{"due-before", "due_before", "only tasks due before this time (RFC 3339 or YYYY-MM-DD)"},
+	{"due-after", "due_after", "only tasks due after this time (RFC 3339 or YYYY-MM-DD)"},
+	{"tz", "tz", "time zone for dates without time, e.g. Europe/Berlin"},
+	{"tags-any", "tags_any", "comma-separated: at least one of these tags"},
+	{"tags-all", "tags_all", "comma-separated: all of these tags"},
+	{"tags-none", "tags_none", "comma-separated: none of these tags"},
+	{"assignee", "assignee", "user ID or \"me\""},
+	{"watcher", "watcher", "user ID or \"me\""},
+	{"fields", "fields", "comma-separated: only these fields (" + strings.Join(models.TaskFields, ", ") + ")"},
+	{"include", "include", "comma-separated: embedded relations (" + strings.Join(models.TaskIncludes, ", ") + ")"},
+}
+
+// taskFilter hält die Werte der Filter-Flags eines Befehls.
+type taskFilter struct {
+	values  map[string]*string
+	overdue bool
+}
+
+// addTaskFilterFlags registriert die Filter-Flags von GET /tasks an cmd; skip nennt Flags, die der
+// Befehl nicht anbietet.
+func addTaskFilterFlags(cmd *cobra.Command, skip ...string) *taskFilter {
+	f := &taskFilter{values: map[string]*string{}}
+	for _, def := range taskFilterFlags {
+		if slices.Contains(skip, def.flag) {
+			continue
+		}
+		f.values[def.param] = cmd.Flags().String(def.flag, "", def.usage)
+	}
+	cmd.Flags().BoolVar(&f.overdue, "overdue", false, "only overdue tasks (--overdue=false: only tasks that are not overdue)")
+	return f
+}
+
+// query gibt die gesetzten Filter als Query-Parameter zurück.
+func (f *taskFilter) query(cmd *cobra.Command) url.Values {
+	q := url.Values{}
+	for param, v := range f.values {
+		if *v != "" {
+			q.Set(param, *v)
+		}
+	}
+	if cmd.Flags().Changed("overdue") {
+		q.Set("overdue", strconv.FormatBool(f.overdue))
+	}
+	return q
+}
+
+// listTasks ruft GET /tasks auf und gibt die Antwort roh und als Tasks zurück.
+func listTasks(client *apiClient, query url.Values) (json.RawMessage, []*models.Task, error) {
+	raw, err := client.do(http.MethodGet, "/tasks", query, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tasks []*models.Task
+	if err := json.Unmarshal(raw, &tasks); err != nil {
+		return nil, nil, err
+	}
+	return raw, tasks, nil
+}
+
+// newListCmd baut "taskctl list" auf.
+func newListCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List tasks (filters as in GET /tasks)",
+		Args:  cobra.NoArgs,
+	}
+	filter := addTaskFilterFlags(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := opts.clientFor(cmd)
+		if err != nil {
+			return err
+		}
+		raw, tasks, err := listTasks(client, filter.query(cmd))
+		if err != nil {
+			return err
+		}
+		return printTasks(cmd.OutOrStdout(), opts.output, raw, tasks)
+	}
+	return cmd
+}
+
+// newSearchCmd baut "taskctl search" auf. Die API hat keine Volltextsuche; gesucht wird daher im Client
+// in Titel, Beschreibung und Tags der gefilterten Tasks (ohne Beachtung der Groß-/Kleinschreibung).
+func newSearchCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <text>",
+		Short: "Search tasks by title, description and tags (filters as in GET /tasks)",
+		Args:  cobra.ExactArgs(1),
+	}
+	filter := addTaskFilterFlags(cmd, "fields")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		client, err := opts.clientFor(cmd)
+		if err != nil {
+			return err
+		}
+		raw, tasks, err := listTasks(client, filter.query(cmd))
+		if err != nil {
+			return err
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return err
+		}
+
+		text := strings.ToLower(args[0])
+		var matched []*models.Task
+		var matchedRaw []json.RawMessage
+		for i, t := range tasks {
+			if taskMatches(t, text) {
+				matched = append(matched, t)
+				matchedRaw = append(matchedRaw, items[i])
+			}
+		}
+		if matchedRaw == nil {
+			matchedRaw = []json.RawMessage{}
+		}
+		out, err := json.Marshal(matchedRaw)
+		if err != nil {
+			return err
+		}
+		return printTasks(cmd.OutOrStdout(), opts.output, out, matched)
+	}
+	return cmd
+}
+
+// taskMatches prüft, ob Titel, Beschreibung oder ein Tag den (kleingeschriebenen) Text enthalten.
+func taskMatches(t *models.Task, text string) bool {
+	if strings.Contains(strings.ToLower(t.Title), text) || strings.Contains(strings.ToLower(t.Description), text) {
+		return true
+	}
+	for _, tag := range t.Tags {
+		if strings.Contains(strings.ToLower(tag), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// newGetCmd baut "taskctl get" auf.
+func newGetCmd(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:               "get <id>",
+		Short:             "Show a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseTaskID(args[0])
+			if err != nil {
+				return err
+			}
+			return opts.sendTask(cmd, http.MethodGet, fmt.Sprintf("/tasks/%d", id), nil)
+		},
+	}
+}
+
+// taskFields hält die Werte der Flags, die Felder eines Tasks setzen (create und update).
+type taskFields struct {
+	title, description, status, priority string
+	parent                               int
+	start, due, recurrence               string
+}
+
+// addTaskFieldFlags registriert die Flags für die Felder von CreateTaskRequest an cmd.
+func addTaskFieldFlags(cmd *cobra.Command, opts *globalOptions) *taskFields {
+	f := &taskFields{}
+	cmd.Flags().StringVar(&f.title, "title", "", "title (max 200 characters)")
+	cmd.Flags().StringVar(&f.description, "description", "", "description (max 1000 characters)")
+	cmd.Flags().StringVar(&f.status, "status", "", "status of the workflow, e.g. todo, \"in progress\", done")
+	cmd.Flags().StringVar(&f.priority, "priority", "", "low, medium or high")
+	cmd.Flags().IntVar(&f.parent, "parent", 0, "ID of the parent task")
+	cmd.Flags().StringVar(&f.start, "start", "", "planned start (RFC 3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&f.due, "due", "", "due date (RFC 3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&f.recurrence, "recurrence", "", "recurrence rule (RFC 5545), e.g. FREQ=WEEKLY;BYDAY=MO")
+	_ = cmd.RegisterFlagCompletionFunc("priority", fixedCompletion("low", "medium", "high"))
+	_ = cmd.RegisterFlagCompletionFunc("status", opts.completeStatuses)
+	return f
+}
+
+// request baut den Request-Body aus den gesetzten Flags; nicht gesetzte Flags bleiben leer bzw. nil
+// und ändern beim Update nichts.
+func (f *taskFields) request(cmd *cobra.Command) (models.CreateTaskRequest, error) {
+	req := models.CreateTaskRequest{Title: f.title, Description: f.description, Status: f.status, Priority: f.priority}
+	changed := cmd.Flags().Changed
+	if changed("parent") {
+		req.ParentID = &f.parent
+	}
+	var err error
+	if changed("start") {
+		if req.StartAt, err = parseTimeFlag(f.start); err != nil {
+			return req, fmt.Errorf("--start %w", err)
+		}
+	}
+	if changed("due") {
+		if req.DueAt, err = parseTimeFlag(f.due); err != nil {
+			return req, fmt.Errorf("--due %w", err)
+		}
+	}
+	if changed("recurrence") {
+		req.RecurrenceRule = &f.recurrence
+	}
+	return req, nil
+}
+
+// parseTimeFlag parst einen Zeitpunkt als RFC 3339 oder als Datum (YYYY-MM-DD, Tagesbeginn in lokaler Zeit).
+func parseTimeFlag(v string) (*time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, v, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("must be RFC 3339 (2026-05-01T09:00:00+02:00) or a date (2026-05-01)")
+	}
+	return &t, nil
+}
+
+// newCreateCmd baut "taskctl create" auf.
+func newCreateCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create --title <title>",
+		Short: "Create a task",
+		Args:  cobra.NoArgs,
+	}
+	fields := addTaskFieldFlags(cmd, opts)
+	_ = cmd.MarkFlagRequired("title")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		req, err := fields.request(cmd)
+		if err != nil {
+			return err
+		}
+		return opts.sendTask(cmd, http.MethodPost, "/tasks", req)
+	}
+	return cmd
+}
+
+// newUpdateCmd baut "taskctl update" auf. Nur die angegebenen Felder werden geändert.
+func newUpdateCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "update <id>",
+		Short:             "Change fields of a task (only the given flags are changed)",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs,
+	}
+	fields := addTaskFieldFlags(cmd, opts)
+	var scope string
+	var reopen, overrideBlockers bool
+	cmd.Flags().StringVar(&scope, "scope", "", "for recurring tasks: this (default) or future")
+	cmd.Flags().BoolVar(&reopen, "reopen", false, "confirm transitions that reopen a task, e.g. done -> todo")
+	cmd.Flags().BoolVar(&overrideBlockers, "override-blockers", false, "change the status despite unfinished dependencies")
+	_ = cmd.RegisterFlagCompletionFunc("scope", fixedCompletion("this", "future"))
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		id, err := parseTaskID(args[0])
+		if err != nil {
+			return err
+		}
+		changed := false
+		cmd.LocalFlags().VisitAll(func(f *pflag.Flag) { changed = changed || f.Changed })
+		if !changed {
+			return fmt.Errorf("nothing to update; set at least one field, e.g. --status done")
+		}
+		req, err := fields.request(cmd)
+		if err != nil {
+			return err
+		}
+		req.Scope, req.Reopen, req.OverrideBlockers = scope, reopen, overrideBlockers
+		return opts.sendTask(cmd, http.MethodPut, fmt.Sprintf("/tasks/%d", id), req)
+	}
+	return cmd
+}
+
+// newDeleteCmd baut "taskctl delete" auf.
+func newDeleteCmd(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:               "delete <id>...",
+		Short:             "Delete tasks",
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids := make([]int, len(args))
+			for i, arg := range args {
+				id, err := parseTaskID(arg)
+				if err != nil {
+					return err
+				}
+				ids[i] = id
+			}
+			client, err := opts.clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			for _, id := range ids {
+				if _, err := client.do(http.MethodDelete, fmt.Sprintf("/tasks/%d", id), nil, nil); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "task %d deleted\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+// parseTaskID parst die ID eines Tasks aus einem Argument.
+func parseTaskID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid task ID %q", arg)
+	}
+	return id, nil
+}
+
+// clientFor prüft --output und erzeugt den Client für das aktive Profil.
+func (o *globalOptions) clientFor(cmd *cobra.Command) (*apiClient, error) {
+	if err := checkOutput(o.output); err != nil {
+		return nil, err
+	}
+	return o.newClient()
+}
+
+// sendTask sendet einen Request, dessen Antwort ein Task ist, und gibt den Task aus.
+func (o *globalOptions) sendTask(cmd *cobra.Command, method, path string, body any) error {
+	client, err := o.clientFor(cmd)
+	if err != nil {
+		return err
+	}
+	raw, err := client.do(method, path, nil, body)
+	if err != nil {
+		return err
+	}
+	task := &models.Task{}
+	if err := json.Unmarshal(raw, task); err != nil {
+		return err
+	}
+	return printTasks(cmd.OutOrStdout(), o.output, raw, []*models.Task{task})
+}
+
+// completeTaskIDs vervollständigt Task-IDs mit dem Titel als Beschreibung.
+func (o *globalOptions) completeTaskIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	client, err := o.newClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	_, tasks, err := listTasks(client, url.Values{"fields": {"id,title"}})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var ids []string
+	for _, t := range tasks {
+		if id := strconv.Itoa(t.ID); strings.HasPrefix(id, toComplete) {
+			ids = append(ids, id+"\t"+t.Title)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeStatuses vervollständigt die Status aus dem Workflow des Servers.
+func (o *globalOptions) completeStatuses(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	client, err := o.newClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	raw, err := client.do(http.MethodGet, "/workflow", nil, nil)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var workflow models.Workflow
+	if err := json.Unmarshal(raw, &workflow); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return workflow.StatusNames(), cobra.ShellCompDirectiveNoFileComp
+}
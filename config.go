@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"task-api/handlers"
+	"task-api/services"
+	"task-api/storage"
+	"time"
+)
+
+// config enthält alle Einstellungen des Servers aus den Umgebungsvariablen. Sie wird von allen
+// Befehlen (serve, migrate, seed, purge, keys, doctor) über loadConfig gelesen.
+type config struct {
+	Postgres postgresConfig
+	GRPCPort string // GRPC_PORT (Default: 9090)
+
+	ParentDeleteMode string // PARENT_DELETE_MODE: reject (Default), orphan oder cascade
+
+	AttachmentMaxSize      int64    // ATTACHMENT_MAX_SIZE in Bytes
+	AttachmentAllowedTypes []string // ATTACHMENT_ALLOWED_TYPES, kommagetrennt; leer = alle
+	AttachmentStorage      string   // ATTACHMENT_STORAGE: local (Default) oder s3
+	AttachmentDir          string   // ATTACHMENT_DIR für local (Default: ./data/attachments)
+	S3                     storage.S3Store
+
+	ImportMaxSyncRows int // IMPORT_MAX_SYNC_ROWS
+
+	ReminderLeadTime       time.Duration // REMINDER_LEAD_TIME (Default: 1h)
+	ReminderPollInterval   time.Duration // REMINDER_POLL_INTERVAL (Default: 1m)
+	RecurrenceLeadTime     time.Duration // RECURRENCE_LEAD_TIME (Default: 24h)
+	RecurrencePollInterval time.Duration // RECURRENCE_POLL_INTERVAL (Default: 1m)
+
+	WebhookPollInterval time.Duration // WEBHOOK_POLL_INTERVAL (Default: 5s)
+	WebhookMaxAttempts  int           // WEBHOOK_MAX_ATTEMPTS (Default: 8)
+	WebhookBaseBackoff  time.Duration // WEBHOOK_BASE_BACKOFF (Default: 30s)
+	WebhookMaxBackoff   time.Duration // WEBHOOK_MAX_BACKOFF (Default: 6h)
+
+	EventLogSize int // EVENT_LOG_SIZE
+
+	GraphQLMaxDepth      int // GRAPHQL_MAX_DEPTH
+	GraphQLMaxComplexity int // GRAPHQL_MAX_COMPLEXITY
+
+	APIv1Deprecation time.Time // API_V1_DEPRECATION
+	APIv1Sunset      time.Time // API_V1_SUNSET
+
+	// Warnings sind ungültige Werte, für die der Default verwendet wird, und fehlende, aber
+	// empfohlene Angaben. Errors sind Einstellungen, mit denen der Server nicht starten kann.
+	Warnings []string
+	Errors   []string
+}
+
+// postgresConfig sind die Verbindungsdaten der Datenbank (POSTGRES_*).
+type postgresConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DB       string
+}
+
+// ConnString erstellt den Connection-String für lib/pq.
+func (p postgresConfig) ConnString() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		p.Host, p.Port, p.User, p.Password, p.DB)
+}
+
+// loadConfig liest die Konfiguration mit getenv (os.Getenv, in Tests eine Map) und prüft sie.
+func loadConfig(getenv func(string) string) *config {
+	env := &envReader{getenv: getenv}
+	cfg := &config{
+		Postgres: postgresConfig{
+			Host:     getenv("POSTGRES_HOST"),
+			Port:     getenv("POSTGRES_PORT"),
+			User:     getenv("POSTGRES_USER"),
+			Password: getenv("POSTGRES_PASSWORD"),
+			DB:       getenv("POSTGRES_DB"),
+		},
+		GRPCPort:         env.string("GRPC_PORT", "9090"),
+		ParentDeleteMode: getenv("PARENT_DELETE_MODE"),
+
+		AttachmentMaxSize:      int64(env.int("ATTACHMENT_MAX_SIZE", services.DefaultMaxAttachmentSize)),
+		AttachmentAllowedTypes: env.list("ATTACHMENT_ALLOWED_TYPES"),
+		AttachmentStorage:      env.string("ATTACHMENT_STORAGE", "local"),
+		AttachmentDir:          env.string("ATTACHMENT_DIR", "./data/attachments"),
+		S3: storage.S3Store{
+			Endpoint:        getenv("S3_ENDPOINT"),
+			Region:          getenv("S3_REGION"),
+			Bucket:          getenv("S3_BUCKET"),
+			AccessKeyID:     getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: getenv("S3_SECRET_ACCESS_KEY"),
+		},
+
+		ImportMaxSyncRows: env.int("IMPORT_MAX_SYNC_ROWS", services.DefaultMaxSyncImportRows),
+
+		ReminderLeadTime:       env.duration("REMINDER_LEAD_TIME", time.Hour),
+		ReminderPollInterval:   env.duration("REMINDER_POLL_INTERVAL", time.Minute),
+		RecurrenceLeadTime:     env.duration("RECURRENCE_LEAD_TIME", 24*time.Hour),
+		RecurrencePollInterval: env.duration("RECURRENCE_POLL_INTERVAL", time.Minute),
+
+		WebhookPollInterval: env.duration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+		WebhookMaxAttempts:  env.int("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookBaseBackoff:  env.duration("WEBHOOK_BASE_BACKOFF", 30*time.Second),
+		WebhookMaxBackoff:   env.duration("WEBHOOK_MAX_BACKOFF", 6*time.Hour),
+
+		EventLogSize: env.int("EVENT_LOG_SIZE", services.DefaultEventLogSize),
+
+		GraphQLMaxDepth:      env.int("GRAPHQL_MAX_DEPTH", handlers.DefaultGraphQLMaxDepth),
+		GraphQLMaxComplexity: env.int("GRAPHQL_MAX_COMPLEXITY", handlers.DefaultGraphQLMaxComplexity),
+
+		APIv1Deprecation: env.date("API_V1_DEPRECATION", time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)),
+		APIv1Sunset:      env.date("API_V1_SUNSET", time.Date(2027, 4, 18, 0, 0, 0, 0, time.UTC)),
+	}
+	cfg.Warnings = env.warnings
+	cfg.validate()
+	return cfg
+}
+
+// validate ergänzt Warnings und Errors um Prüfungen, die mehrere Werte betreffen.
+func (cfg *config) validate() {
+	for name, v := range map[string]string{"POSTGRES_HOST": cfg.Postgres.Host, "POSTGRES_USER": cfg.Postgres.User, "POSTGRES_DB": cfg.Postgres.DB} {
+		if v == "" {
+			cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("%s is not set, the lib/pq default is used", name))
+		}
+	}
+	if mode := cfg.ParentDeleteMode; mode != "" && !slices.Contains([]string{services.ParentDeleteReject, services.ParentDeleteOrphan, services.ParentDeleteCascade}, mode) {
+		cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("invalid PARENT_DELETE_MODE %q, parents with subtasks cannot be deleted", mode))
+	}
+	if cfg.WebhookBaseBackoff > cfg.WebhookMaxBackoff {
+		cfg.Warnings = append(cfg.Warnings, "WEBHOOK_BASE_BACKOFF is greater than WEBHOOK_MAX_BACKOFF")
+	}
+	if !cfg.APIv1Sunset.After(cfg.APIv1Deprecation) {
+		cfg.Warnings = append(cfg.Warnings, "API_V1_SUNSET should be after API_V1_DEPRECATION")
+	}
+
+	switch cfg.AttachmentStorage {
+	case "local":
+	case "s3":
+		for name, v := range map[string]string{"S3_ENDPOINT": cfg.S3.Endpoint, "S3_BUCKET": cfg.S3.Bucket, "S3_ACCESS_KEY_ID": cfg.S3.AccessKeyID, "S3_SECRET_ACCESS_KEY": cfg.S3.SecretAccessKey} {
+			if v == "" {
+				cfg.Errors = append(cfg.Errors, fmt.Sprintf("%s is required for ATTACHMENT_STORAGE=s3", name))
+			}
+		}
+	default:
+		cfg.Errors = append(cfg.Errors, fmt.Sprintf("unknown ATTACHMENT_STORAGE %q (expected local or s3)", cfg.AttachmentStorage))
+	}
+	slices.Sort(cfg.Warnings)
+	slices.Sort(cfg.Errors)
+}
+
+// blobStore erstellt den Blob-Storage für Anhänge anhand von ATTACHMENT_STORAGE:
+// "local" speichert unter ATTACHMENT_DIR, "s3" in einem S3-kompatiblen Bucket.
+func (cfg *config) blobStore() storage.BlobStore {
+	if cfg.AttachmentStorage == "s3" {
+		s3 := cfg.S3
+		return &s3
+	}
+	return &storage.LocalStore{Dir: cfg.AttachmentDir}
+}
+
+// envReader liest typisierte Werte aus Umgebungsvariablen. Ungültige Werte werden als Warnung
+// gesammelt und durch den Default ersetzt.
+type envReader struct {
+	getenv   func(string) string
+	warnings []string
+}
+
+// string liest die Umgebungsvariable name; ist sie nicht gesetzt, wird def zurückgegeben.
+func (e *envReader) string(name, def string) string {
+	if v := e.getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// duration liest eine Dauer (z.B. "30m", "2h") aus der Umgebungsvariable name.
+// Ist die Variable nicht gesetzt oder ungültig, wird def zurückgegeben.
+func (e *envReader) duration(name string, def time.Duration) time.Duration {
+	v := e.getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		e.warnings = append(e.warnings, fmt.Sprintf("invalid duration %q for %s, using %s", v, name, def))
+		return def
+	}
+	return d
+}
+
+// int liest eine Ganzzahl aus der Umgebungsvariable name.
+// Ist die Variable nicht gesetzt oder ungültig, wird def zurückgegeben.
+func (e *envReader) int(name string, def int) int {
+	v := e.getenv(name)
+	if v == "" {
+		return def
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		e.warnings = append(e.warnings, fmt.Sprintf("invalid integer %q for %s, using %d", v, name, def))
+		return def
+	}
+	return i
+}
+
+// date liest einen Zeitpunkt (RFC 3339 oder Datum "2006-01-02" in UTC) aus der Umgebungsvariable name.
+// Ist die Variable nicht gesetzt oder ungültig, wird def zurückgegeben.
+func (e *envReader) date(name string, def time.Time) time.Time {
+	v := e.getenv(name)
+	if v == "" {
+		return def
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+	e.warnings = append(e.warnings, fmt.Sprintf("invalid date %q for %s, using %s", v, name, def.Format(time.RFC3339)))
+	return def
+}
+
+// list liest eine kommagetrennte Liste aus der Umgebungsvariable name.
+// Gibt nil zurück, wenn die Variable nicht gesetzt ist.
+func (e *envReader) list(name string) []string {
+	var list []string
+	for _, item := range strings.Split(e.getenv(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"task-api/storage"
+	"testing"
+	"time"
+)
+
+// mapEnv gibt eine getenv-Funktion für die Werte in env zurück.
+func mapEnv(env map[string]string) func(string) string {
+	return func(name string) string { return env[name] }
+}
+
+// Test_LoadConfig_Defaults prüft die Defaults und die Warnungen für fehlende Postgres-Angaben.
+func Test_LoadConfig_Defaults(t *testing.T) {
+	cfg := loadConfig(mapEnv(nil))
+
+	assert.Equal(t, "9090", cfg.GRPCPort)
+	assert.Equal(t, "local", cfg.AttachmentStorage)
+	assert.Equal(t, 5*time.Second, cfg.WebhookPollInterval)
+	assert.Equal(t, 8, cfg.WebhookMaxAttempts)
+	assert.Equal(t, &storage.LocalStore{Dir: "./data/attachments"}, cfg.blobStore())
+	assert.Empty(t, cfg.Errors)
+	assert.Equal(t, []string{
+		"POSTGRES_DB is not set, the lib/pq default is used",
+		"POSTGRES_HOST is not set, the lib/pq default is used",
+		"POSTGRES_USER is not set, the lib/pq default is used",
+	}, cfg.Warnings)
+}
+
+// Test_LoadConfig_Invalid prüft, dass ungültige Werte als Warnung gemeldet und durch Defaults ersetzt werden
+// und dass eine unvollständige S3-Konfiguration ein Fehler ist.
+func Test_LoadConfig_Invalid(t *testing.T) {
+	cfg := loadConfig(mapEnv(map[string]string{
+		"POSTGRES_HOST":         "db",
+		"POSTGRES_USER":         "postgres",
+		"POSTGRES_DB":           "tasks",
+		"WEBHOOK_POLL_INTERVAL": "soon",
+		"WEBHOOK_MAX_ATTEMPTS":  "many",
+		"WEBHOOK_BASE_BACKOFF":  "10h",
+		"PARENT_DELETE_MODE":    "purge",
+		"API_V1_SUNSET":         "2026-01-01",
+		"ATTACHMENT_STORAGE":    "s3",
+		"S3_ENDPOINT":           "https://s3.example.com",
+		"S3_BUCKET":             "attachments",
+	}))
+
+	assert.Equal(t, 5*time.Second, cfg.WebhookPollInterval)
+	assert.Equal(t, 8, cfg.WebhookMaxAttempts)
+	assert.Equal(t, "host=db port= user=postgres password= dbname=tasks sslmode=disable", cfg.Postgres.ConnString())
+	assert.Equal(t, []string{
+		"API_V1_SUNSET should be after API_V1_DEPRECATION",
+		"WEBHOOK_BASE_BACKOFF is greater than WEBHOOK_MAX_BACKOFF",
+		"invalid PARENT_DELETE_MODE \"purge\", parents with subtasks cannot be deleted",
+		"invalid duration \"soon\" for WEBHOOK_POLL_INTERVAL, using 5s",
+		"invalid integer \"many\" for WEBHOOK_MAX_ATTEMPTS, using 8",
+	}, cfg.Warnings)
+	assert.Equal(t, []string{
+		"S3_ACCESS_KEY_ID is required for ATTACHMENT_STORAGE=s3",
+		"S3_SECRET_ACCESS_KEY is required for ATTACHMENT_STORAGE=s3",
+	}, cfg.Errors)
+
+	_, err := requireConfig(mapEnv(map[string]string{"ATTACHMENT_STORAGE": "ftp"}))
+	assert.EqualError(t, err, "invalid configuration: unknown ATTACHMENT_STORAGE \"ftp\" (expected local or s3) (run \"server doctor\" for details)")
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"task-api/migrations"
+	"task-api/models"
+	"task-api/repository"
+	"task-api/services"
+	"time"
+)
+
+// dbConnectTimeout begrenzt den Verbindungstest beim Start eines Befehls.
+const dbConnectTimeout = 10 * time.Second
+
+// openDB öffnet die Datenbankverbindung und prüft, ob sie möglich ist.
+func openDB(cfg *config) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.Postgres.ConnString())
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dbConnectTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// repositories enthält die Postgres-Repositories, die sich alle Befehle teilen.
+type repositories struct {
+	Tasks       *repository.PostgresTaskRepository
+	Users       *repository.PostgresUserRepository
+	Comments    *repository.PostgresCommentRepository
+	Attachments *repository.PostgresAttachmentRepository
+	Tags        *repository.PostgresTagRepository
+	Webhooks    *repository.PostgresWebhookRepository
+	Events      *repository.PostgresEventRepository
+	Workflow    *repository.PostgresWorkflowRepository
+	Schema      *repository.PostgresSchemaRepository
+	ImportJobs  *repository.PostgresImportJobRepository
+}
+
+// newRepositories erstellt alle Repositories für die Verbindung db.
+func newRepositories(db *sql.DB, cfg *config) *repositories {
+	return &repositories{
+		Tasks:       &repository.PostgresTaskRepository{DB: db},
+		Users:       &repository.PostgresUserRepository{DB: db},
+		Comments:    &repository.PostgresCommentRepository{DB: db},
+		Attachments: &repository.PostgresAttachmentRepository{DB: db},
+		Tags:        &repository.PostgresTagRepository{DB: db},
+		Webhooks:    &repository.PostgresWebhookRepository{DB: db},
+		Events:      &repository.PostgresEventRepository{DB: db, ConnStr: cfg.Postgres.ConnString()},
+		Workflow:    &repository.PostgresWorkflowRepository{DB: db},
+		Schema:      &repository.PostgresSchemaRepository{DB: db},
+		ImportJobs:  &repository.PostgresImportJobRepository{DB: db},
+	}
+}
+
+// taskService erstellt den TaskService mit dem Workflow aus der Datenbank (bzw. dem Standard-Workflow,
+// wenn keine Status hinterlegt sind).
+func (r *repositories) taskService(cfg *config) (*services.TaskService, error) {
+	workflow, err := r.Workflow.GetWorkflow()
+	if err != nil {
+		return nil, err
+	}
+	if len(workflow.Statuses) == 0 {
+		log.Println("no workflow statuses found in database, using default workflow")
+		workflow = models.DefaultWorkflow()
+	}
+	return &services.TaskService{
+		Repo:             r.Tasks,
+		ParentDeleteMode: cfg.ParentDeleteMode,
+		Workflow:         workflow,
+		Users:            r.Users,
+	}, nil
+}
+
+// maintenanceService erstellt den MaintenanceService mit den eingebetteten Migrationen und der
+// Anhang-Ablage aus cfg.
+func (r *repositories) maintenanceService(cfg *config) (*services.MaintenanceService, error) {
+	list, err := migrations.All()
+	if err != nil {
+		return nil, err
+	}
+	return &services.MaintenanceService{
+		Schema:     r.Schema,
+		Tasks:      r.Tasks,
+		Webhooks:   r.Webhooks,
+		Store:      cfg.blobStore(),
+		Migrations: list,
+	}, nil
+}
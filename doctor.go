@@ -0,0 +1,166 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"task-api/migrations"
+	"task-api/repository"
+	"task-api/services"
+)
+
+// Ergebnis einer Prüfung von "server doctor".
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// checkResult ist eine Zeile des Doctor-Berichts.
+type checkResult struct {
+	Level   string // checkOK, checkWarn oder checkFail
+	Check   string // z.B. "config", "database", "schema"
+	Message string
+}
+
+// doctor prüft Konfiguration, Speicher für Anhänge, Datenbankverbindung, Schema-Version und Rechte.
+// Schema ist nil, wenn keine Verbindung zur Datenbank möglich war (DBErr).
+type doctor struct {
+	Config     *config
+	Schema     repository.SchemaRepositoryInterface
+	DBErr      error
+	Migrations []migrations.Migration
+}
+
+// run führt alle Prüfungen aus. Ohne Datenbankverbindung entfallen Schema und Rechte.
+func (d *doctor) run() []checkResult {
+	var results []checkResult
+	add := func(level, check, format string, args ...any) {
+		results = append(results, checkResult{Level: level, Check: check, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, e := range d.Config.Errors {
+		add(checkFail, "config", "%s", e)
+	}
+	for _, w := range d.Config.Warnings {
+		add(checkWarn, "config", "%s", w)
+	}
+	if len(d.Config.Errors)+len(d.Config.Warnings) == 0 {
+		add(checkOK, "config", "all settings valid")
+	}
+
+	switch d.Config.AttachmentStorage {
+	case "local":
+		if err := checkWritable(d.Config.AttachmentDir); errors.Is(err, fs.ErrNotExist) {
+			add(checkWarn, "attachments", "%s does not exist yet, it is created on the first upload", d.Config.AttachmentDir)
+		} else if err != nil {
+			add(checkFail, "attachments", "%s is not writable: %v", d.Config.AttachmentDir, err)
+		} else {
+			add(checkOK, "attachments", "%s is writable", d.Config.AttachmentDir)
+		}
+	case "s3":
+		add(checkOK, "attachments", "s3 bucket %s at %s (not checked)", d.Config.S3.Bucket, d.Config.S3.Endpoint)
+	}
+
+	if d.DBErr != nil {
+		add(checkFail, "database", "cannot connect to %s:%s/%s: %v", d.Config.Postgres.Host, d.Config.Postgres.Port, d.Config.Postgres.DB, d.DBErr)
+		return results
+	}
+	version, err := d.Schema.ServerVersion()
+	if err != nil {
+		add(checkFail, "database", "%v", err)
+		return results
+	}
+	add(checkOK, "database", "PostgreSQL %s", version)
+
+	status, err := (&services.MaintenanceService{Schema: d.Schema, Migrations: d.Migrations}).SchemaStatus()
+	switch {
+	case err != nil:
+		add(checkFail, "schema", "%v", err)
+	case !status.Tracked || status.Current == 0:
+		if exists, _ := d.Schema.HasTable("tasks"); exists {
+			add(checkFail, "schema", "schema_migrations is missing or empty; run \"server migrate --baseline <last applied version>\"")
+		} else {
+			add(checkFail, "schema", "database is empty; run \"server migrate\"")
+		}
+	case len(status.Pending) > 0:
+		names := make([]string, 0, len(status.Pending))
+		for _, m := range status.Pending {
+			names = append(names, m.Name)
+		}
+		add(checkFail, "schema", "version %d, %d pending migrations (%s); run \"server migrate\"", status.Current, len(names), strings.Join(names, ", "))
+	case len(status.Unknown) > 0:
+		add(checkWarn, "schema", "version %d is newer than this binary (latest %d)", status.Current, status.Latest)
+	default:
+		add(checkOK, "schema", "version %d (latest)", status.Current)
+	}
+
+	missing, err := d.Schema.MissingPrivileges()
+	switch {
+	case err != nil:
+		add(checkFail, "privileges", "%v", err)
+	case len(missing) > 0:
+		add(checkFail, "privileges", "missing %s", strings.Join(missing, ", "))
+	default:
+		add(checkOK, "privileges", "all tables and sequences accessible")
+	}
+	return results
+}
+
+// checkWritable prüft, ob im Verzeichnis dir Dateien angelegt werden können.
+func checkWritable(dir string) error {
+	if _, err := os.Stat(dir); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// printReport gibt die Ergebnisse aus und gibt die Anzahl der fehlgeschlagenen Prüfungen zurück.
+func printReport(w io.Writer, results []checkResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.Level == checkFail {
+			failed++
+		}
+		fmt.Fprintf(w, "%-4s  %-11s  %s\n", strings.ToUpper(r.Level), r.Check, r.Message)
+	}
+	return failed
+}
+
+// newDoctorCmd erstellt "server doctor". Anders als die übrigen Befehle bricht doctor bei ungültiger
+// Konfiguration oder fehlender Datenbank nicht ab, sondern meldet alle Probleme und endet mit Exit-Code 1.
+func newDoctorCmd(getenv func(string) string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check configuration, database connectivity, schema version and privileges",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := loadConfig(getenv)
+			list, err := migrations.All()
+			if err != nil {
+				return err
+			}
+			d := &doctor{Config: cfg, Migrations: list}
+			db, err := openDB(cfg)
+			if err != nil {
+				d.DBErr = err
+			} else {
+				defer db.Close()
+				d.Schema = newRepositories(db, cfg).Schema
+			}
+			if failed := printReport(cmd.OutOrStdout(), d.run()); failed > 0 {
+				return fmt.Errorf("%d checks failed", failed)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"task-api/migrations"
+	"task-api/repository"
+	"testing"
+)
+
+// doctorMigrations sind zwei Migrationen für die Doctor-Tests.
+var doctorMigrations = []migrations.Migration{
+	{Version: 1, Name: "001_tasks.sql"},
+	{Version: 2, Name: "002_tags.sql"},
+}
+
+// doctorConfig gibt eine gültige Konfiguration mit dem Anhang-Verzeichnis dir zurück.
+func doctorConfig(dir string) *config {
+	return loadConfig(mapEnv(map[string]string{
+		"POSTGRES_HOST": "db", "POSTGRES_PORT": "5432", "POSTGRES_USER": "postgres", "POSTGRES_DB": "tasks",
+		"ATTACHMENT_DIR": dir,
+	}))
+}
+
+// Test_Doctor_Healthy prüft den Bericht einer aktuellen Datenbank mit allen Rechten.
+func Test_Doctor_Healthy(t *testing.T) {
+	d := &doctor{
+		Config:     doctorConfig(t.TempDir()),
+		Migrations: doctorMigrations,
+		Schema: &repository.MockSchemaRepository{
+			ServerVersionFunc:     func() (string, error) { return "16.2", nil },
+			AppliedVersionsFunc:   func() ([]int, error) { return []int{1, 2}, nil },
+			MissingPrivilegesFunc: func() ([]string, error) { return nil, nil },
+		},
+	}
+
+	var out bytes.Buffer
+	assert.Equal(t, 0, printReport(&out, d.run()))
+	assert.Contains(t, out.String(), "OK    config       all settings valid\n")
+	assert.Contains(t, out.String(), "OK    database     PostgreSQL 16.2\n")
+	assert.Contains(t, out.String(), "OK    schema       version 2 (latest)\n")
+	assert.Contains(t, out.String(), "OK    privileges   all tables and sequences accessible\n")
+}
+
+// Test_Doctor_Problems prüft, dass fehlende Migrationen, Rechte und ein nicht beschreibbares
+// Anhang-Verzeichnis als Fehler gemeldet werden.
+func Test_Doctor_Problems(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(file, nil, 0o644))
+	d := &doctor{
+		Config:     doctorConfig(file),
+		Migrations: doctorMigrations,
+		Schema: &repository.MockSchemaRepository{
+			ServerVersionFunc:     func() (string, error) { return "16.2", nil },
+			AppliedVersionsFunc:   func() ([]int, error) { return []int{1}, nil },
+			MissingPrivilegesFunc: func() ([]string, error) { return []string{"tasks: DELETE", "tags_id_seq: USAGE"}, nil },
+		},
+	}
+
+	results := d.run()
+	assert.Equal(t, 3, printReport(&bytes.Buffer{}, results))
+	assert.Equal(t, checkResult{checkFail, "schema", "version 1, 1 pending migrations (002_tags.sql); run \"server migrate\""}, results[3])
+	assert.Equal(t, checkResult{checkFail, "privileges", "missing tasks: DELETE, tags_id_seq: USAGE"}, results[4])
+	assert.Equal(t, checkFail, results[1].Level)
+
+	// Bestehende Datenbank ohne schema_migrations
+	d.Schema = &repository.MockSchemaRepository{
+		ServerVersionFunc:     func() (string, error) { return "16.2", nil },
+		AppliedVersionsFunc:   func() ([]int, error) { return nil, nil },
+		HasTableFunc:          func(name string) (bool, error) { return true, nil },
+		MissingPrivilegesFunc: func() ([]string, error) { return nil, nil },
+	}
+	assert.Contains(t, d.run()[3].Message, "server migrate --baseline")
+}
+
+// Test_Doctor_NoDatabase prüft, dass ohne Verbindung nur Konfiguration und Verbindung gemeldet werden.
+func Test_Doctor_NoDatabase(t *testing.T) {
+	d := &doctor{
+		Config: loadConfig(mapEnv(map[string]string{"ATTACHMENT_STORAGE": "s3"})),
+		DBErr:  errors.New("connection refused"),
+	}
+
+	var out bytes.Buffer
+	failed := printReport(&out, d.run())
+	assert.Equal(t, 5, failed) // 4 fehlende S3-Angaben und die Datenbank
+	assert.Contains(t, out.String(), "WARN  config       POSTGRES_HOST is not set, the lib/pq default is used\n")
+	assert.Contains(t, out.String(), "FAIL  database     cannot connect to :/: connection refused\n")
+	assert.NotContains(t, out.String(), "schema")
+}
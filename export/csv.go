@@ -0,0 +1,40 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// csvWriter schreibt RFC-4180-CSV mit Kopfzeile.
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), record: make([]string, len(columns))}
+	if err := cw.w.Write(columns); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+// WriteRow schreibt eine Zeile. Texte, die eine Tabellenkalkulation als Formel ausführen würde
+// (Beginn mit =, +, -, @, Tab oder Wagenrücklauf), werden mit einem Apostroph entschärft.
+func (cw *csvWriter) WriteRow(values []any) error {
+	for i, v := range values {
+		text := formatText(v)
+		if _, ok := v.(string); ok && text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+			text = "'" + text
+		}
+		cw.record[i] = text
+	}
+	return cw.w.Write(cw.record)
+}
+
+// Close schreibt gepufferte Zeilen.
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
@@ -0,0 +1,102 @@
+// Package export schreibt Tabellen zeilenweise als CSV, NDJSON oder XLSX.
+// Alle Writer geben Zeilen sofort an den darunterliegenden io.Writer weiter, sodass auch sehr große
+// Exporte nicht im Speicher gehalten werden.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format ist ein Exportformat.
+type Format string
+
+// Unterstützte Exportformate.
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+	XLSX   Format = "xlsx"
+)
+
+// Formats sind alle unterstützten Formate in der Reihenfolge, in der sie bei der Aushandlung bevorzugt werden.
+var Formats = []Format{CSV, NDJSON, XLSX}
+
+// ContentType gibt den MIME-Typ des Formats zurück.
+func (f Format) ContentType() string {
+	switch f {
+	case NDJSON:
+		return "application/x-ndjson"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// MediaType gibt den MIME-Typ ohne Parameter zurück (für Accept und die OpenAPI-Spezifikation).
+func (f Format) MediaType() string {
+	mediaType, _, _ := strings.Cut(f.ContentType(), ";")
+	return mediaType
+}
+
+// Writer schreibt die Zeilen einer Tabelle. Die Spalten werden beim Erstellen festgelegt;
+// jede Zeile enthält einen Wert pro Spalte.
+type Writer interface {
+	// WriteRow schreibt eine Zeile.
+	WriteRow(values []any) error
+
+	// Close schreibt ausstehende Daten (bei XLSX das Ende der Datei). Der io.Writer wird nicht geschlossen.
+	Close() error
+}
+
+// NewWriter erstellt einen Writer für das Format f, der nach w schreibt.
+// CSV und XLSX beginnen mit einer Kopfzeile aus columns; NDJSON verwendet columns als Schlüssel.
+func NewWriter(f Format, w io.Writer, columns []string) (Writer, error) {
+	switch f {
+	case CSV:
+		return newCSVWriter(w, columns)
+	case NDJSON:
+		return newNDJSONWriter(w, columns), nil
+	case XLSX:
+		return newXLSXWriter(w, columns)
+	}
+	return nil, fmt.Errorf("unsupported format %q", f)
+}
+
+// formatText gibt einen Wert für CSV und Textzellen aus: nil als leere Zelle, Zeitpunkte als RFC 3339 und
+// Listen kommagetrennt.
+func formatText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ", ")
+	case []int:
+		parts := make([]string, len(v))
+		for i, id := range v {
+			parts[i] = strconv.Itoa(id)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,86 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTable schreibt die Zeilen im Format f und gibt die Ausgabe zurück.
+func writeTable(t *testing.T, f Format, columns []string, rows ...[]any) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(f, &buf, columns)
+	assert.NoError(t, err)
+	for _, row := range rows {
+		assert.NoError(t, w.WriteRow(row))
+	}
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// Test_CSV_EscapesValues prüft Kopfzeile, Quoting, Listen, Zeitpunkte und leere Werte.
+func Test_CSV_EscapesValues(t *testing.T) {
+	due := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	out := writeTable(t, CSV, []string{"id", "title", "tags", "due_at", "parent_id"},
+		[]any{1, `Say "hi", then go`, []string{"a", "b"}, &due, (*int)(nil)})
+
+	assert.Equal(t, "id,title,tags,due_at,parent_id\n"+
+		`1,"Say ""hi"", then go","a, b",2026-05-01T12:00:00Z,`+"\n", string(out))
+}
+
+// Test_CSV_GuardsFormulas prüft, dass Texte, die eine Tabellenkalkulation als Formel ausführen würde,
+// mit einem Apostroph beginnen, Zahlen aber unverändert bleiben.
+func Test_CSV_GuardsFormulas(t *testing.T) {
+	out := writeTable(t, CSV, []string{"title", "priority"},
+		[]any{"=HYPERLINK(\"http://x\")", -1},
+		[]any{"@SUM(A1)", 2})
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	assert.Equal(t, `"'=HYPERLINK(""http://x"")",-1`, lines[1])
+	assert.Equal(t, "'@SUM(A1),2", lines[2])
+}
+
+// Test_NDJSON_KeepsColumnOrder prüft, dass jede Zeile ein JSON-Objekt mit den Spalten in fester Reihenfolge ist.
+func Test_NDJSON_KeepsColumnOrder(t *testing.T) {
+	out := writeTable(t, NDJSON, []string{"title", "id", "tags"},
+		[]any{"Release", 1, []string{"backend"}},
+		[]any{"Docs", 2, []string{}})
+
+	assert.Equal(t, `{"title":"Release","id":1,"tags":["backend"]}`+"\n"+
+		`{"title":"Docs","id":2,"tags":[]}`+"\n", string(out))
+}
+
+// Test_XLSX_IsReadableWorkbook prüft, dass die Datei ein gültiges ZIP-Archiv mit Tabellenblatt ist und
+// Texte escaped sowie Zahlen als Zahlen gespeichert werden.
+func Test_XLSX_IsReadableWorkbook(t *testing.T) {
+	out := writeTable(t, XLSX, []string{"id", "title"}, []any{7, "A & <B>"})
+
+	r, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	assert.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files, "xl/workbook.xml")
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c s="1" t="inlineStr"><is><t xml:space="preserve">title</t></is></c>`)
+	assert.Contains(t, sheet, `<c><v>7</v></c>`)
+	assert.Contains(t, sheet, `A &amp; &lt;B&gt;`)
+	assert.True(t, strings.HasSuffix(sheet, "</sheetData></worksheet>"))
+}
+
+// Test_NewWriter_UnknownFormat prüft den Fehler bei einem unbekannten Format.
+func Test_NewWriter_UnknownFormat(t *testing.T) {
+	_, err := NewWriter("pdf", io.Discard, []string{"id"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// ndjsonWriter schreibt ein JSON-Objekt pro Zeile; die Schlüssel stehen in der Reihenfolge der Spalten.
+type ndjsonWriter struct {
+	w    io.Writer
+	keys [][]byte
+	buf  bytes.Buffer
+}
+
+func newNDJSONWriter(w io.Writer, columns []string) *ndjsonWriter {
+	keys := make([][]byte, len(columns))
+	for i, c := range columns {
+		keys[i], _ = json.Marshal(c)
+	}
+	return &ndjsonWriter{w: w, keys: keys}
+}
+
+// WriteRow schreibt eine Zeile als JSON-Objekt mit abschließendem Zeilenumbruch.
+func (nw *ndjsonWriter) WriteRow(values []any) error {
+	nw.buf.Reset()
+	nw.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			nw.buf.WriteByte(',')
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		nw.buf.Write(nw.keys[i])
+		nw.buf.WriteByte(':')
+		nw.buf.Write(value)
+	}
+	nw.buf.WriteString("}\n")
+	_, err := nw.w.Write(nw.buf.Bytes())
+	return err
+}
+
+// Close hat bei NDJSON nichts mehr zu schreiben.
+func (nw *ndjsonWriter) Close() error {
+	return nil
+}
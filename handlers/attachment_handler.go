@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"mime"
+	"strconv"
+	"strings"
+	"task-api/models"
+	"task-api/services"
+)
+
+// AttachmentHandler stellt die HTTP-Endpoints für Dateianhänge bereit.
+type AttachmentHandler struct {
+	Service services.AttachmentServiceInterface
+}
+
+// attachmentErrorResponse übersetzt Fehler aus dem AttachmentService in die passende HTTP-Antwort.
+func attachmentErrorResponse(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "not found",
+			"message": err.Error(),
+		})
+	case "authentication required":
+		return unauthorizedResponse(c)
+	case "forbidden":
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   "forbidden",
+			"message": "only the uploader can delete an attachment",
+		})
+	case "file too large":
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	case "unsupported file type":
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	case "invalid filename", "empty file", "incomplete upload":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "internal error",
+		"message": err.Error(),
+	})
+}
+
+// UploadAttachment verarbeitet POST /tasks/:id/attachments (multipart/form-data, Feld "file").
+//
+// Antwort:
+//
+//	201 - Anhang gespeichert + Metadaten (JSON)
+//	400 - Kein Feld "file" / leere Datei / ungültiger Dateiname
+//	401 - Kein API-Key angegeben
+//	404 - Task existiert nicht
+//	413 - Datei überschreitet die maximale Größe
+//	415 - Dateityp nicht erlaubt
+func (h *AttachmentHandler) UploadAttachment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "multipart field \"file\" is required",
+		})
+	}
+	f, err := file.Open()
+	if err != nil {
+		return attachmentErrorResponse(c, err)
+	}
+	defer f.Close()
+
+	attachment, err := h.Service.Upload(id, file.Filename, f, file.Size, currentUser(c))
+	if err != nil {
+		return attachmentErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(attachment)
+}
+
+// GetAttachments verarbeitet GET /tasks/:id/attachments.
+//
+// Antwort:
+//
+//	200 - OK + Array von Anhängen (Metadaten)
+//	404 - Task existiert nicht
+func (h *AttachmentHandler) GetAttachments(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	attachments, err := h.Service.GetAttachments(id)
+	if err != nil {
+		return attachmentErrorResponse(c, err)
+	}
+	if attachments == nil {
+		attachments = []*models.Attachment{}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"attachments": attachments,
+		"total":       len(attachments),
+	})
+}
+
+// GetAttachment verarbeitet GET /attachments/:id und liefert die Metadaten eines Anhangs.
+//
+// Antwort:
+//
+//	200 - Metadaten (JSON)
+//	404 - Anhang existiert nicht
+func (h *AttachmentHandler) GetAttachment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	attachment, err := h.Service.GetAttachment(id)
+	if err != nil {
+		return attachmentErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(attachment)
+}
+
+// DownloadAttachment verarbeitet GET /attachments/:id/content.
+// Der Inhalt wird gestreamt; ein einzelner Byte-Bereich kann per Range-Header angefordert werden.
+//
+// Antwort:
+//
+//	200 - Vollständiger Inhalt
+//	206 - Angeforderter Bereich (Content-Range)
+//	304 - Inhalt unverändert (If-None-Match)
+//	404 - Anhang existiert nicht
+//	416 - Bereich liegt außerhalb der Datei
+func (h *AttachmentHandler) DownloadAttachment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	attachment, err := h.Service.GetAttachment(id)
+	if err != nil {
+		return attachmentErrorResponse(c, err)
+	}
+
+	etag := `"` + attachment.Checksum + `"`
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+	if c.Get(fiber.HeaderIfNoneMatch) == etag {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	offset, length, partial, err := parseRange(c.Get(fiber.HeaderRange), attachment.Size)
+	if err != nil {
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", attachment.Size))
+		return c.Status(fiber.StatusRequestedRangeNotSatisfiable).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	}
+	// If-Range: Bereich nur liefern, wenn sich der Inhalt nicht geändert hat
+	if ifRange := c.Get(fiber.HeaderIfRange); partial && ifRange != "" && ifRange != etag {
+		offset, length, partial = 0, attachment.Size, false
+	}
+
+	r, err := h.Service.OpenAttachment(attachment, offset, length)
+	if err != nil {
+		return attachmentErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	if partial {
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, attachment.Size))
+		c.Status(fiber.StatusPartialContent)
+	}
+	return c.SendStream(r, int(length))
+}
+
+// parseRange wertet einen Range-Header ("bytes=0-99", "bytes=100-", "bytes=-100") für eine Datei
+// der Größe size aus. Ohne Header oder bei mehreren Bereichen wird die ganze Datei geliefert.
+// Gibt einen Fehler zurück, wenn der Bereich nicht erfüllbar ist.
+func parseRange(header string, size int64) (offset, length int64, partial bool, err error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if header == "" || !ok || strings.Contains(spec, ",") {
+		return 0, size, false, nil
+	}
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, size, false, nil
+	}
+
+	invalid := fmt.Errorf("range not satisfiable")
+	if startStr == "" {
+		// Suffix-Bereich: die letzten n Bytes
+		n, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, false, invalid
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, true, nil
+	}
+
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false, invalid
+	}
+	end := size - 1
+	if endStr != "" {
+		end, err = strconv.ParseInt(endStr, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false, invalid
+		}
+		if end > size-1 {
+			end = size - 1
+		}
+	}
+	return start, end - start + 1, true, nil
+}
+
+// DeleteAttachment verarbeitet DELETE /attachments/:id.
+//
+// Antwort:
+//
+//	204 - Erfolgreich gelöscht (Kein Body)
+//	401 - Kein API-Key angegeben
+//	403 - Anhang wurde von einem anderen Benutzer hochgeladen
+//	404 - Anhang existiert nicht
+func (h *AttachmentHandler) DeleteAttachment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	if err := h.Service.DeleteAttachment(id, currentUser(c)); err != nil {
+		return attachmentErrorResponse(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
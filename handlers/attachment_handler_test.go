@@ -0,0 +1,130 @@
+package handlers_test
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"task-api/handlers"
+	"task-api/services"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setupAttachmentHandler initialisiert einen Fiber-App-Server mit Authentifizierung und allen
+// Anhang-Routen. Benutzer 1 meldet sich mit "anna-key" an.
+func setupAttachmentHandler(mockService *services.MockAttachmentService) *fiber.App {
+	app := fiber.New()
+	app.Use(handlers.Authenticate(&services.MockUserService{
+		Users:   testUsers(),
+		APIKeys: map[string]int{"anna-key": 1, "ben-key": 2},
+	}))
+
+	handler := handlers.AttachmentHandler{Service: mockService}
+	app.Get("/tasks/:id/attachments", handler.GetAttachments)
+	app.Post("/tasks/:id/attachments", handler.UploadAttachment)
+	app.Get("/attachments/:id", handler.GetAttachment)
+	app.Get("/attachments/:id/content", handler.DownloadAttachment)
+	app.Delete("/attachments/:id", handler.DeleteAttachment)
+	return app
+}
+
+// uploadRequest erstellt einen multipart-Upload mit einer Datei im Feld "file".
+func uploadRequest(url, filename string, content []byte, key string) *http.Request {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, _ := w.CreateFormFile("file", filename)
+	part.Write(content)
+	w.Close()
+
+	req := httptest.NewRequest("POST", url, &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	return req
+}
+
+// Test_UploadAttachment_Handler prüft Upload, Anmeldung, Größen- und Typ-Limits.
+func Test_UploadAttachment_Handler(t *testing.T) {
+	app := setupAttachmentHandler(&services.MockAttachmentService{TaskIDs: []int{1}, MaxSize: 64})
+
+	resp, _ := app.Test(uploadRequest("/tasks/1/attachments", "a.txt", []byte("hello"), ""))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = app.Test(uploadRequest("/tasks/1/attachments", "a.txt", bytes.Repeat([]byte("x"), 65), "anna-key"))
+	assert.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	resp, _ = app.Test(uploadRequest("/tasks/1/attachments", "a.bin", []byte("MZ\x90\x00\x00\x00\x00\x00"), "anna-key"))
+	assert.Equal(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, _ = app.Test(uploadRequest("/tasks/9/attachments", "a.txt", []byte("hello"), "anna-key"))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	resp, err := app.Test(uploadRequest("/tasks/1/attachments", "a.txt", []byte("hello"), "anna-key"))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	data, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(data), `"content_type":"text/plain"`)
+	assert.Contains(t, string(data), `"checksum":"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"`)
+	assert.NotContains(t, string(data), "storage_key")
+}
+
+// Test_DownloadAttachment_Handler_Range prüft vollständige und teilweise Downloads sowie ungültige Bereiche.
+func Test_DownloadAttachment_Handler_Range(t *testing.T) {
+	app := setupAttachmentHandler(&services.MockAttachmentService{TaskIDs: []int{1}})
+	resp, _ := app.Test(uploadRequest("/tasks/1/attachments", "zahlen.txt", []byte("0123456789"), "anna-key"))
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	cases := []struct {
+		rangeHeader  string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{"", fiber.StatusOK, "0123456789", ""},
+		{"bytes=2-5", fiber.StatusPartialContent, "2345", "bytes 2-5/10"},
+		{"bytes=7-", fiber.StatusPartialContent, "789", "bytes 7-9/10"},
+		{"bytes=-3", fiber.StatusPartialContent, "789", "bytes 7-9/10"},
+		{"bytes=8-100", fiber.StatusPartialContent, "89", "bytes 8-9/10"},
+		{"bytes=10-", fiber.StatusRequestedRangeNotSatisfiable, "", "bytes */10"},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/attachments/1/content", nil)
+		if tc.rangeHeader != "" {
+			req.Header.Set("Range", tc.rangeHeader)
+		}
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.status, resp.StatusCode, tc.rangeHeader)
+		assert.Equal(t, tc.contentRange, resp.Header.Get("Content-Range"), tc.rangeHeader)
+		if tc.body != "" {
+			data, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, tc.body, string(data), tc.rangeHeader)
+			assert.Equal(t, "text/plain", resp.Header.Get("Content-Type"))
+			assert.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+		}
+	}
+}
+
+// Test_DeleteAttachment_Handler prüft, dass nur der hochladende Benutzer löschen darf.
+func Test_DeleteAttachment_Handler(t *testing.T) {
+	app := setupAttachmentHandler(&services.MockAttachmentService{TaskIDs: []int{1}})
+	app.Test(uploadRequest("/tasks/1/attachments", "a.txt", []byte("hello"), "anna-key"))
+
+	req := httptest.NewRequest("DELETE", "/attachments/1", nil)
+	req.Header.Set("X-API-Key", "ben-key")
+	resp, _ := app.Test(req)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	req = httptest.NewRequest("DELETE", "/attachments/1", nil)
+	req.Header.Set("X-API-Key", "anna-key")
+	resp, _ = app.Test(req)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/attachments/1", nil))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
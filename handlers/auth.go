@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"strings"
+	"task-api/models"
+	"task-api/services"
+)
+
+// userLocalsKey ist der Schlüssel, unter dem der angemeldete Benutzer in c.Locals abgelegt wird.
+const userLocalsKey = "user"
+
+// userContextKey ist der Schlüssel, unter dem der angemeldete Benutzer außerhalb von Fiber-Handlern
+// (gRPC, GraphQL-Resolver) im context.Context abgelegt wird.
+type userContextKey struct{}
+
+// Authenticate liefert eine Middleware, die den API-Key aus "Authorization: Bearer <key>"
+// oder "X-API-Key" liest und den zugehörigen Benutzer in c.Locals ablegt.
+// Requests ohne Key bleiben anonym; ein unbekannter Key wird mit 401 abgelehnt.
+func Authenticate(users services.UserServiceInterface) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := apiKeyFromRequest(c)
+		if key == "" {
+			return c.Next()
+		}
+
+		user, err := users.Authenticate(key)
+		if err != nil {
+			return errorResponse(c, fiber.StatusUnauthorized, fiber.Map{
+				"error":   "unauthorized",
+				"message": "invalid api key",
+			})
+		}
+		c.Locals(userLocalsKey, user)
+		return c.Next()
+	}
+}
+
+// apiKeyFromRequest liest den API-Key aus dem Authorization- oder X-API-Key-Header.
+func apiKeyFromRequest(c *fiber.Ctx) string {
+	if auth := c.Get(fiber.HeaderAuthorization); auth != "" {
+		if key, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return strings.TrimSpace(key)
+		}
+	}
+	return strings.TrimSpace(c.Get("X-API-Key"))
+}
+
+// currentUser gibt den angemeldeten Benutzer zurück oder nil bei anonymen Requests.
+func currentUser(c *fiber.Ctx) *models.User {
+	user, _ := c.Locals(userLocalsKey).(*models.User)
+	return user
+}
+
+// userFromContext gibt den im Context abgelegten Benutzer zurück oder nil bei anonymen Aufrufen.
+func userFromContext(ctx context.Context) *models.User {
+	user, _ := ctx.Value(userContextKey{}).(*models.User)
+	return user
+}
+
+// unauthorizedResponse beantwortet Requests, die einen angemeldeten Benutzer erfordern.
+func unauthorizedResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"error":   "unauthorized",
+		"message": "authentication required",
+	})
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"net/url"
+	"strings"
+	"task-api/ical"
+	"task-api/models"
+	"task-api/services"
+)
+
+// calendarRefresh ist das Intervall, in dem Kalender-Apps den Feed neu laden sollen (RFC 7986 und Outlook).
+const calendarRefresh = "PT15M"
+
+// calendarUIDDomain ist der Domain-Teil der UIDs im Feed. Die UID hängt nur von der Task-ID ab,
+// damit Kalender-Apps einen Task nach Änderungen wiedererkennen.
+const calendarUIDDomain = "task-api"
+
+// Darstellungen eines Tasks im Feed (Query-Parameter type).
+const (
+	calendarTodo  = "todo"
+	calendarEvent = "event"
+)
+
+// taskPriorities ordnet den Prioritäten eines Tasks die PRIORITY von iCalendar zu (1 = höchste, 9 = niedrigste).
+var taskPriorities = map[string]int{"high": 1, "medium": 5, "low": 9}
+
+// CalendarHandler stellt die Tasks eines Benutzers als iCalendar-Feed für Kalender-Apps bereit.
+type CalendarHandler struct {
+	Tasks services.TaskServiceInterface
+	Users services.UserServiceInterface
+}
+
+// Feed verarbeitet GET /calendar.ics.
+// Liefert alle Tasks mit Fälligkeit als RFC-5545-Kalender, mit type=todo (Default) als VTODO, mit
+// type=event als Termin (VEVENT). Es gelten dieselben Filter wie bei GET /tasks; "me" ist der Benutzer
+// des Tokens. Kalender-Apps können keine Header setzen, daher wird das Kalender-Token des Benutzers als
+// Query-Parameter token übergeben (alternativ ein API-Key im Header).
+// Der Feed trägt ein ETag über seinen Inhalt; bei passendem If-None-Match antwortet der Endpoint mit 304.
+//
+// Antwort:
+//
+//	200 - Kalender (text/calendar)
+//	304 - Kalender unverändert (If-None-Match)
+//	400 - Ungültige Filter oder ungültiges type=
+//	401 - Kein oder unbekanntes Kalender-Token
+func (h *CalendarHandler) Feed(c *fiber.Ctx) error {
+	if token := c.Query("token"); token != "" {
+		user, err := h.Users.AuthenticateCalendar(token)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "unauthorized",
+				"message": "invalid calendar token",
+			})
+		}
+		c.Locals(userLocalsKey, user)
+	}
+	user := currentUser(c)
+	if user == nil {
+		return unauthorizedResponse(c)
+	}
+
+	kind := c.Query("type", calendarTodo)
+	if kind != calendarTodo && kind != calendarEvent {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "type must be todo or event",
+		})
+	}
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	}
+	filter.HasDueDate = true
+
+	tasks, err := h.Tasks.GetAllTasks(filter)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := writeCalendar(&buf, user, tasks, h.Tasks.GetWorkflow(), kind); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, ical.ContentType)
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="tasks.ics"`)
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+// etagMatches prüft, ob ein If-None-Match-Header das ETag enthält. Der Header darf eine Liste von ETags
+// oder "*" enthalten; verglichen wird schwach, d.h. ohne das Präfix W/ (RFC 9110, Abschnitt 13.1.2).
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCalendar schreibt die Tasks als VCALENDAR. Alle Werte stammen aus den Tasks (DTSTAMP ist der
+// Zeitpunkt der letzten Änderung), damit derselbe Inhalt immer dasselbe ETag ergibt.
+func writeCalendar(w io.Writer, user *models.User, tasks []*models.Task, workflow *models.Workflow, kind string) error {
+	e := ical.NewEncoder(w)
+	e.Begin("VCALENDAR")
+	e.Value("VERSION", "2.0")
+	e.Value("PRODID", "-//task-api//Tasks//DE")
+	e.Value("CALSCALE", "GREGORIAN")
+	e.Value("METHOD", "PUBLISH")
+	e.Text("X-WR-CALNAME", fmt.Sprintf("Tasks (%s)", user.Name))
+	e.Value("REFRESH-INTERVAL;VALUE=DURATION", calendarRefresh)
+	e.Value("X-PUBLISHED-TTL", calendarRefresh)
+	for _, t := range tasks {
+		if kind == calendarEvent {
+			writeTaskEvent(e, t)
+		} else {
+			writeTaskTodo(e, t, workflow)
+		}
+	}
+	e.End("VCALENDAR")
+	return e.Close()
+}
+
+// writeTaskProperties schreibt die Eigenschaften, die VTODO und VEVENT gemeinsam haben.
+func writeTaskProperties(e *ical.Encoder, t *models.Task) {
+	e.Value("UID", fmt.Sprintf("task-%d@%s", t.ID, calendarUIDDomain))
+	e.Time("DTSTAMP", t.UpdatedAt)
+	e.Time("CREATED", t.CreatedAt)
+	e.Time("LAST-MODIFIED", t.UpdatedAt)
+	e.Text("SUMMARY", t.Title)
+	e.Text("DESCRIPTION", t.Description)
+	e.Texts("CATEGORIES", t.Tags)
+	if p, ok := taskPriorities[t.Priority]; ok {
+		e.Int("PRIORITY", p)
+	}
+}
+
+// writeTaskTodo schreibt einen Task als VTODO. Der Status wird über den Workflow abgebildet: Startstatus ->
+// NEEDS-ACTION, erledigte Status -> COMPLETED (mit Zeitpunkt der letzten Änderung), alle anderen -> IN-PROCESS.
+func writeTaskTodo(e *ical.Encoder, t *models.Task, workflow *models.Workflow) {
+	e.Begin("VTODO")
+	writeTaskProperties(e, t)
+	if t.StartAt != nil {
+		e.Time("DTSTART", *t.StartAt)
+	}
+	e.Time("DUE", *t.DueAt)
+
+	switch s := workflow.Status(t.Status); {
+	case s != nil && s.Final:
+		e.Value("STATUS", "COMPLETED")
+		e.Time("COMPLETED", t.UpdatedAt)
+		e.Int("PERCENT-COMPLETE", 100)
+	case s != nil && s.Initial:
+		e.Value("STATUS", "NEEDS-ACTION")
+	default:
+		e.Value("STATUS", "IN-PROCESS")
+	}
+	if t.Progress != nil && !workflow.IsFinal(t.Status) {
+		e.Int("PERCENT-COMPLETE", *t.Progress)
+	}
+	e.End("VTODO")
+}
+
+// writeTaskEvent schreibt einen Task als Termin (VEVENT) vom Beginn bis zur Fälligkeit. Ohne Beginn ist der
+// Termin ein Zeitpunkt zur Fälligkeit.
+func writeTaskEvent(e *ical.Encoder, t *models.Task) {
+	e.Begin("VEVENT")
+	writeTaskProperties(e, t)
+	if t.StartAt != nil && t.StartAt.Before(*t.DueAt) {
+		e.Time("DTSTART", *t.StartAt)
+		e.Time("DTEND", *t.DueAt)
+	} else {
+		e.Time("DTSTART", *t.DueAt)
+	}
+	e.Value("STATUS", "CONFIRMED")
+	e.Value("TRANSP", "TRANSPARENT")
+	e.End("VEVENT")
+}
+
+// calendarURL gibt den Pfad des Feeds mit dem Kalender-Token zurück.
+func calendarURL(token string) string {
+	return "/calendar.ics?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,166 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setupCalendarHandler initialisiert einen Fiber-App-Server mit Authentifizierung, den Routen für
+// Kalender-Tokens und dem Feed unter Verwendung von Mock-Services.
+func setupCalendarHandler(users *services.MockUserService, tasks *services.MockTaskService) *fiber.App {
+	app := fiber.New()
+	app.Use(handlers.Authenticate(users))
+
+	userHandler := handlers.UserHandler{Service: users}
+	app.Post("/users/me/calendar-token", userHandler.CreateCalendarToken)
+	app.Delete("/users/me/calendar-token", userHandler.RevokeCalendarToken)
+
+	calendarHandler := handlers.CalendarHandler{Tasks: tasks, Users: users}
+	app.Get("/calendar.ics", calendarHandler.Feed)
+	return app
+}
+
+// calendarTasks liefert Tasks in allen Status des Standard-Workflows und einen Task ohne Fälligkeit.
+func calendarTasks() []*models.Task {
+	at := func(day int) *time.Time {
+		t := time.Date(2026, 5, day, 9, 0, 0, 0, time.UTC)
+		return &t
+	}
+	updated := time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC)
+	return []*models.Task{
+		{ID: 1, Title: "Release; v2", Status: "todo", Priority: "high", DueAt: at(10), Tags: []string{"backend"},
+			Assignees: []int{1}, CreatedAt: updated, UpdatedAt: updated},
+		{ID: 2, Title: "Review", Status: "in progress", Priority: "medium", StartAt: at(2), DueAt: at(4),
+			CreatedAt: updated, UpdatedAt: updated},
+		{ID: 3, Title: "Docs", Status: "done", Priority: "low", DueAt: at(1), CreatedAt: updated, UpdatedAt: updated},
+		{ID: 4, Title: "Ohne Termin", Status: "todo", Priority: "low", Assignees: []int{1}, CreatedAt: updated, UpdatedAt: updated},
+	}
+}
+
+// getCalendar ruft path mit optionalem If-None-Match ab und gibt Antwort und Body zurück.
+func getCalendar(t *testing.T, app *fiber.App, path, ifNoneMatch string) (*http.Response, string) {
+	req := httptest.NewRequest("GET", path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	return resp, string(data)
+}
+
+// Test_CalendarToken_CreateAndRevoke prüft, dass ein erzeugtes Token den Feed freigibt, ein neues Token das
+// alte ersetzt und der Feed nach dem Entfernen mit 401 antwortet.
+func Test_CalendarToken_CreateAndRevoke(t *testing.T) {
+	users := &services.MockUserService{Users: testUsers(), APIKeys: map[string]int{"key-1": 1}}
+	app := setupCalendarHandler(users, &services.MockTaskService{Tasks: calendarTasks()})
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/users/me/calendar-token", nil))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	req := httptest.NewRequest("POST", "/users/me/calendar-token", nil)
+	req.Header.Set("X-API-Key", "key-1")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	var token models.CalendarToken
+	data, _ := io.ReadAll(resp.Body)
+	assert.NoError(t, json.Unmarshal(data, &token))
+	assert.Equal(t, "/calendar.ics?token="+token.Token, token.URL)
+
+	resp, body := getCalendar(t, app, token.URL, "")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/calendar; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.Contains(t, body, "X-WR-CALNAME:Tasks (Anna)\r\n")
+
+	req = httptest.NewRequest("DELETE", "/users/me/calendar-token", nil)
+	req.Header.Set("X-API-Key", "key-1")
+	resp, _ = app.Test(req)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	resp, _ = getCalendar(t, app, token.URL, "")
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	resp, _ = getCalendar(t, app, "/calendar.ics", "")
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+// Test_CalendarFeed_Todos prüft die Abbildung von Status, Priorität, Fälligkeit und Tags auf VTODO sowie,
+// dass Tasks ohne Fälligkeit fehlen.
+func Test_CalendarFeed_Todos(t *testing.T) {
+	users := &services.MockUserService{Users: testUsers(), CalendarTokens: map[string]int{"cal-1": 1}}
+	app := setupCalendarHandler(users, &services.MockTaskService{Tasks: calendarTasks()})
+
+	resp, body := getCalendar(t, app, "/calendar.ics?token=cal-1", "")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	todos := strings.Split(body, "BEGIN:VTODO\r\n")[1:]
+	assert.Len(t, todos, 3)
+	assert.Contains(t, todos[0], "UID:task-1@task-api\r\n")
+	assert.Contains(t, todos[0], `SUMMARY:Release\; v2`+"\r\n")
+	assert.Contains(t, todos[0], "DUE:20260510T090000Z\r\n")
+	assert.Contains(t, todos[0], "PRIORITY:1\r\n")
+	assert.Contains(t, todos[0], "STATUS:NEEDS-ACTION\r\n")
+	assert.Contains(t, todos[0], "CATEGORIES:backend\r\n")
+	assert.Contains(t, todos[1], "DTSTART:20260502T090000Z\r\n")
+	assert.Contains(t, todos[1], "STATUS:IN-PROCESS\r\n")
+	assert.Contains(t, todos[2], "STATUS:COMPLETED\r\n")
+	assert.Contains(t, todos[2], "PRIORITY:9\r\n")
+	assert.NotContains(t, body, "Ohne Termin")
+	assert.True(t, strings.HasSuffix(body, "END:VTODO\r\nEND:VCALENDAR\r\n"))
+}
+
+// Test_CalendarFeed_EventsAndFilters prüft type=event und die Filter von GET /tasks mit "me" als
+// Benutzer des Tokens.
+func Test_CalendarFeed_EventsAndFilters(t *testing.T) {
+	users := &services.MockUserService{Users: testUsers(), CalendarTokens: map[string]int{"cal-1": 1}}
+	app := setupCalendarHandler(users, &services.MockTaskService{Tasks: calendarTasks()})
+
+	_, body := getCalendar(t, app, "/calendar.ics?token=cal-1&type=event&due_before=2026-05-05", "")
+	assert.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT\r\n"))
+	assert.Contains(t, body, "DTSTART:20260502T090000Z\r\nDTEND:20260504T090000Z\r\n")
+	assert.NotContains(t, body, "VTODO")
+
+	_, body = getCalendar(t, app, "/calendar.ics?token=cal-1&assignee=me", "")
+	assert.Equal(t, 1, strings.Count(body, "BEGIN:VTODO\r\n"))
+	assert.Contains(t, body, "UID:task-1@task-api\r\n")
+
+	resp, _ := getCalendar(t, app, "/calendar.ics?token=cal-1&type=journal", "")
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test_CalendarFeed_ETag prüft, dass das ETag bei gleichem Inhalt stabil ist, If-None-Match (auch als
+// Liste oder schwaches ETag) mit 304 beantwortet wird und eine Änderung ein neues ETag ergibt.
+func Test_CalendarFeed_ETag(t *testing.T) {
+	tasks := &services.MockTaskService{Tasks: calendarTasks()}
+	users := &services.MockUserService{Users: testUsers(), CalendarTokens: map[string]int{"cal-1": 1}}
+	app := setupCalendarHandler(users, tasks)
+
+	resp, _ := getCalendar(t, app, "/calendar.ics?token=cal-1", "")
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "private, no-cache", resp.Header.Get("Cache-Control"))
+
+	for _, header := range []string{etag, `"other", ` + etag, "W/" + etag} {
+		resp, body := getCalendar(t, app, "/calendar.ics?token=cal-1", header)
+		assert.Equal(t, fiber.StatusNotModified, resp.StatusCode, header)
+		assert.Empty(t, body)
+	}
+
+	tasks.Tasks[0].Title = "Release v3"
+	tasks.Tasks[0].UpdatedAt = tasks.Tasks[0].UpdatedAt.Add(time.Hour)
+	resp, _ = getCalendar(t, app, "/calendar.ics?token=cal-1", etag)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+}
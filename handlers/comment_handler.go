@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"task-api/models"
+	"task-api/services"
+)
+
+// CommentHandler stellt die HTTP-Endpoints für Kommentare und die Audit-Historie von Tasks bereit.
+type CommentHandler struct {
+	Service services.CommentServiceInterface
+}
+
+// commentErrorResponse übersetzt Fehler aus dem CommentService in die passende HTTP-Antwort.
+func commentErrorResponse(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "not found",
+			"message": err.Error(),
+		})
+	case "authentication required":
+		return unauthorizedResponse(c)
+	case "forbidden":
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   "forbidden",
+			"message": "only the author can change a comment",
+		})
+	case "invalid comment body":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "body is required and must be at most 10000 characters",
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "internal error",
+		"message": err.Error(),
+	})
+}
+
+// GetComments verarbeitet GET /tasks/:id/comments.
+//
+// Antwort:
+//
+//	200 - OK + Array von Kommentaren (inkl. body_html)
+//	404 - Task existiert nicht
+func (h *CommentHandler) GetComments(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	comments, err := h.Service.GetComments(id)
+	if err != nil {
+		return commentErrorResponse(c, err)
+	}
+	if comments == nil {
+		comments = []*models.Comment{}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"comments": comments,
+		"total":    len(comments),
+	})
+}
+
+// AddComment verarbeitet POST /tasks/:id/comments.
+// Erwähnungen der Form "@erika@example.com" werden mit Benutzern des eigenen Mandanten verknüpft.
+//
+// Antwort:
+//
+//	201 - Kommentar erstellt (JSON)
+//	400 - Leerer oder zu langer Body
+//	401 - Kein API-Key angegeben
+//	404 - Task existiert nicht
+//
+// Beispiel Request-Body:
+//
+//	{
+//	  "body": "Sieht gut aus, @erika@example.com bitte **reviewen**."
+//	}
+func (h *CommentHandler) AddComment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	var req models.CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "invalid request body",
+		})
+	}
+
+	comment, err := h.Service.AddComment(id, req, currentUser(c))
+	if err != nil {
+		return commentErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(comment)
+}
+
+// UpdateComment verarbeitet PATCH /comments/:id.
+// Die vorherige Fassung bleibt unter GET /comments/:id/revisions abrufbar.
+//
+// Antwort:
+//
+//	200 - Kommentar bearbeitet (JSON)
+//	400 - Leerer oder zu langer Body
+//	401 - Kein API-Key angegeben
+//	403 - Kommentar gehört einem anderen Benutzer
+//	404 - Kommentar existiert nicht
+func (h *CommentHandler) UpdateComment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	var req models.CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "invalid request body",
+		})
+	}
+
+	comment, err := h.Service.UpdateComment(id, req, currentUser(c))
+	if err != nil {
+		return commentErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(comment)
+}
+
+// DeleteComment verarbeitet DELETE /comments/:id.
+//
+// Antwort:
+//
+//	204 - Erfolgreich gelöscht (Kein Body)
+//	401 - Kein API-Key angegeben
+//	403 - Kommentar gehört einem anderen Benutzer
+//	404 - Kommentar existiert nicht
+func (h *CommentHandler) DeleteComment(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	if err := h.Service.DeleteComment(id, currentUser(c)); err != nil {
+		return commentErrorResponse(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetRevisions verarbeitet GET /comments/:id/revisions.
+//
+// Antwort:
+//
+//	200 - OK + frühere Fassungen, älteste zuerst
+//	404 - Kommentar existiert nicht
+func (h *CommentHandler) GetRevisions(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	revisions, err := h.Service.GetRevisions(id)
+	if err != nil {
+		return commentErrorResponse(c, err)
+	}
+	if revisions == nil {
+		revisions = []*models.CommentRevision{}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"revisions": revisions,
+		"total":     len(revisions),
+	})
+}
+
+// GetTaskHistory verarbeitet GET /tasks/:id/history.
+//
+// Antwort:
+//
+//	200 - OK + Einträge der Audit-Historie, älteste zuerst
+//	404 - Task existiert nicht
+func (h *CommentHandler) GetTaskHistory(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	entries, err := h.Service.GetTaskHistory(id)
+	if err != nil {
+		return commentErrorResponse(c, err)
+	}
+	if entries == nil {
+		entries = []*models.HistoryEntry{}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"history": entries,
+		"total":   len(entries),
+	})
+}
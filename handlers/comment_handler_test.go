@@ -0,0 +1,101 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setupCommentHandler initialisiert einen Fiber-App-Server mit Authentifizierung und allen
+// Kommentar-Routen. Die Benutzer 1 ("anna-key") und 2 ("ben-key") sind angemeldet nutzbar.
+func setupCommentHandler(mockService *services.MockCommentService) *fiber.App {
+	app := fiber.New()
+	app.Use(handlers.Authenticate(&services.MockUserService{
+		Users:   testUsers(),
+		APIKeys: map[string]int{"anna-key": 1, "ben-key": 2},
+	}))
+
+	handler := handlers.CommentHandler{Service: mockService}
+	app.Get("/tasks/:id/comments", handler.GetComments)
+	app.Post("/tasks/:id/comments", handler.AddComment)
+	app.Get("/tasks/:id/history", handler.GetTaskHistory)
+	app.Patch("/comments/:id", handler.UpdateComment)
+	app.Delete("/comments/:id", handler.DeleteComment)
+	app.Get("/comments/:id/revisions", handler.GetRevisions)
+	return app
+}
+
+// sendComment führt einen Request mit Kommentar-Body und optionalem API-Key aus.
+func sendComment(app *fiber.App, method, url, body, key string) int {
+	data, _ := json.Marshal(models.CommentRequest{Body: body})
+	req := httptest.NewRequest(method, url, bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, _ := app.Test(req)
+	return resp.StatusCode
+}
+
+// Test_AddComment_Handler prüft das Anlegen eines Kommentars inkl. Anmeldung, Validierung und 404.
+func Test_AddComment_Handler(t *testing.T) {
+	mockService := &services.MockCommentService{TaskIDs: []int{1}}
+	app := setupCommentHandler(mockService)
+
+	assert.Equal(t, fiber.StatusUnauthorized, sendComment(app, "POST", "/tasks/1/comments", "Hallo", ""))
+	assert.Equal(t, fiber.StatusBadRequest, sendComment(app, "POST", "/tasks/1/comments", "", "anna-key"))
+	assert.Equal(t, fiber.StatusNotFound, sendComment(app, "POST", "/tasks/2/comments", "Hallo", "anna-key"))
+	assert.Equal(t, fiber.StatusCreated, sendComment(app, "POST", "/tasks/1/comments", "**Hallo** <img src=x onerror=alert(1)>", "anna-key"))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/tasks/1/comments", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var body struct {
+		Comments []models.Comment `json:"comments"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	assert.NoError(t, json.Unmarshal(data, &body))
+	assert.Len(t, body.Comments, 1)
+	assert.Contains(t, body.Comments[0].BodyHTML, "<strong>Hallo</strong>")
+	assert.NotContains(t, body.Comments[0].BodyHTML, "onerror")
+}
+
+// Test_UpdateComment_Handler_History prüft, dass nur der Autor bearbeiten darf, die alte Fassung als
+// Revision erhalten bleibt und alle Änderungen in der Historie des Tasks erscheinen.
+func Test_UpdateComment_Handler_History(t *testing.T) {
+	mockService := &services.MockCommentService{TaskIDs: []int{1}}
+	app := setupCommentHandler(mockService)
+
+	assert.Equal(t, fiber.StatusCreated, sendComment(app, "POST", "/tasks/1/comments", "erste Fassung", "anna-key"))
+	assert.Equal(t, fiber.StatusForbidden, sendComment(app, "PATCH", "/comments/1", "fremd", "ben-key"))
+	assert.Equal(t, fiber.StatusOK, sendComment(app, "PATCH", "/comments/1", "zweite Fassung", "anna-key"))
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/comments/1/revisions", nil))
+	data, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(data), "erste Fassung")
+
+	req := httptest.NewRequest("DELETE", "/comments/1", nil)
+	req.Header.Set("X-API-Key", "anna-key")
+	resp, _ = app.Test(req)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/tasks/1/history", nil))
+	var body struct {
+		History []models.HistoryEntry `json:"history"`
+	}
+	data, _ = io.ReadAll(resp.Body)
+	assert.NoError(t, json.Unmarshal(data, &body))
+	var actions []string
+	for _, e := range body.History {
+		actions = append(actions, e.Action)
+	}
+	assert.Equal(t, []string{models.HistoryCommentAdded, models.HistoryCommentEdited, models.HistoryCommentDeleted}, actions)
+}
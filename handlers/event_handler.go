@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"task-api/models"
+	"task-api/services"
+	"time"
+)
+
+// EventHandler stellt den Event-Stream der Task-Änderungen als Server-Sent Events bereit.
+type EventHandler struct {
+	Broker services.EventBrokerInterface
+
+	// Heartbeat legt fest, wie oft ein Kommentar gesendet wird, damit Proxies die Verbindung
+	// nicht schließen und getrennte Clients erkannt werden. Default: 15 Sekunden.
+	Heartbeat time.Duration
+}
+
+// parseEventFilter liest die Filter-Parameter von GET /tasks/events aus der Query.
+func parseEventFilter(c *fiber.Ctx) (models.TaskEventFilter, error) {
+	filter := models.TaskEventFilter{
+		Statuses: parseListParam(c.Query("status")),
+		Tags:     parseListParam(c.Query("tag")),
+	}
+	if v := c.Query("assignee"); v != "" {
+		id, err := parseUserParam(c, v)
+		if err != nil {
+			return filter, fmt.Errorf("assignee %s", err.Error())
+		}
+		filter.Assignee = &id
+	}
+	return filter, nil
+}
+
+// writeEvent schreibt ein Ereignis im SSE-Format und leert den Puffer.
+func writeEvent(w *bufio.Writer, event *models.EventPayload) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Event, data)
+	return w.Flush()
+}
+
+// StreamTaskEvents verarbeitet GET /tasks/events.
+// Liefert einen Server-Sent-Events-Stream mit den Ereignissen task.created, task.updated,
+// task.deleted und task.reminder. Mit dem Header Last-Event-ID (oder ?last_event_id=) werden verpasste Ereignisse
+// aus dem Ereignis-Log nachgeliefert; ist die ID nicht mehr bekannt, folgt ein "reset"-Ereignis
+// und der Client sollte GET /tasks neu laden.
+//
+// Query-Parameter (optional):
+//
+//	status   - Kommagetrennte Liste von Status
+//	tag      - Kommagetrennte Liste von Tags (mindestens einer muss gesetzt sein)
+//	assignee - Benutzer-ID oder "me"
+//
+// Antwort:
+//
+//	200 - text/event-stream
+//	400 - Ungültiger Filter
+//	401 - assignee=me ohne API-Key
+func (h *EventHandler) StreamTaskEvents(c *fiber.Ctx) error {
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		if err.Error() == "assignee authentication required" {
+			return unauthorizedResponse(c)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	}
+
+	lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	sub, backlog, resumed := h.Broker.Subscribe(filter, lastEventID)
+
+	heartbeat := h.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.Broker.Unsubscribe(sub)
+
+		fmt.Fprint(w, "retry: 3000\n\n")
+		if !resumed {
+			fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+		for _, event := range backlog {
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case event, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				if err := writeEvent(w, event); err != nil {
+					return
+				}
+			case <-ticker.C:
+				// Schreibfehler beim Heartbeat zeigen einen getrennten Client an
+				fmt.Fprint(w, ": ping\n\n")
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,106 @@
+package handlers_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setupEventHandler initialisiert einen Fiber-App-Server mit dem Event-Stream.
+// Benutzer 1 meldet sich mit "anna-key" an.
+func setupEventHandler(broker *services.EventBroker) *fiber.App {
+	app := fiber.New()
+	app.Use(handlers.Authenticate(&services.MockUserService{
+		Users:   testUsers(),
+		APIKeys: map[string]int{"anna-key": 1},
+	}))
+
+	handler := handlers.EventHandler{Broker: broker}
+	app.Get("/tasks/events", handler.StreamTaskEvents)
+	return app
+}
+
+// streamEvents öffnet den Event-Stream, führt publish aus, sobald der Client verbunden ist,
+// beendet anschließend alle Abonnements und gibt den empfangenen Stream zurück.
+func streamEvents(t *testing.T, app *fiber.App, broker *services.EventBroker, path, lastEventID string, publish func()) (int, string) {
+	r := httptest.NewRequest("GET", path, nil)
+	if lastEventID != "" {
+		r.Header.Set("Last-Event-ID", lastEventID)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		publish()
+		broker.Close()
+	}()
+	resp, err := app.Test(r, -1)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+// Test_StreamTaskEvents_Handler prüft Format, Filter und die Wiederaufnahme per Last-Event-ID.
+func Test_StreamTaskEvents_Handler(t *testing.T) {
+	broker := &services.EventBroker{}
+	app := setupEventHandler(broker)
+	event := func(id int64, name, status string, assignees ...int) *models.EventPayload {
+		return &models.EventPayload{ID: id, Event: name, Task: &models.Task{ID: int(id), Title: "Task", Status: status, Assignees: assignees}}
+	}
+	broker.Publish(event(1, models.EventTaskCreated, "todo"))
+	broker.Publish(event(2, models.EventTaskUpdated, "done"))
+
+	status, body := streamEvents(t, app, broker, "/tasks/events?status=todo", "1", func() {
+		broker.Publish(event(3, models.EventTaskUpdated, "done"))
+		broker.Publish(event(4, models.EventTaskDeleted, "todo"))
+	})
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.True(t, strings.HasPrefix(body, "retry: 3000\n\n"))
+	assert.NotContains(t, body, "id: 2\n")
+	assert.NotContains(t, body, "id: 3\n")
+	assert.Contains(t, body, "id: 4\nevent: task.deleted\ndata: {\"id\":4,\"event\":\"task.deleted\"")
+	assert.NotContains(t, body, "event: reset")
+
+	status, body = streamEvents(t, app, broker, "/tasks/events", "1", func() {})
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Contains(t, body, "id: 2\nevent: task.updated\n")
+	assert.Contains(t, body, "id: 3\n")
+
+	status, body = streamEvents(t, app, broker, "/tasks/events", "999", func() {})
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Contains(t, body, "event: reset\ndata: {}\n\n")
+	assert.NotContains(t, body, "id: ")
+}
+
+// Test_StreamTaskEvents_Handler_Assignee prüft den Filter assignee=me und ungültige Parameter.
+func Test_StreamTaskEvents_Handler_Assignee(t *testing.T) {
+	broker := &services.EventBroker{}
+	app := setupEventHandler(broker)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/tasks/events?assignee=me", nil))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/tasks/events?assignee=anna", nil))
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	r := httptest.NewRequest("GET", "/tasks/events?assignee=me", nil)
+	r.Header.Set("X-API-Key", "anna-key")
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		broker.Publish(&models.EventPayload{ID: 1, Event: models.EventTaskCreated, Task: &models.Task{ID: 1, Assignees: []int{2}}})
+		broker.Publish(&models.EventPayload{ID: 2, Event: models.EventTaskCreated, Task: &models.Task{ID: 2, Assignees: []int{1, 2}}})
+		broker.Close()
+	}()
+	resp, err := app.Test(r, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	data, _ := io.ReadAll(resp.Body)
+	assert.NotContains(t, string(data), "id: 1\n")
+	assert.Contains(t, string(data), "id: 2\n")
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"sync"
+	"task-api/services"
+)
+
+// Standardwerte für die Grenzen einer GraphQL-Abfrage.
+const (
+	DefaultGraphQLMaxDepth      = 10
+	DefaultGraphQLMaxComplexity = 1000
+)
+
+// GraphQLHandler stellt die Task-API unter /graphql bereit.
+// Abfragen und Mutationen verwenden dieselben Services, dieselbe Validierung und dieselben
+// Fehlermeldungen wie die REST-Endpoints; Beziehungen werden pro Request gebündelt geladen.
+type GraphQLHandler struct {
+	Tasks    *TaskHandler
+	Comments services.CommentServiceInterface
+	Users    services.UserServiceInterface
+
+	MaxDepth      int // Maximale Verschachtelungstiefe einer Abfrage (0 = DefaultGraphQLMaxDepth)
+	MaxComplexity int // Maximale geschätzte Komplexität einer Abfrage (0 = DefaultGraphQLMaxComplexity)
+
+	once      sync.Once
+	schema    graphql.Schema
+	schemaErr error
+}
+
+// graphQLRequest ist der Request-Body von POST /graphql bzw. die Query-Parameter von GET /graphql.
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeGraphQL verarbeitet GET und POST /graphql.
+// POST erwartet {"query", "operationName", "variables"} als JSON; GET dieselben Angaben als
+// Query-Parameter (variables als JSON-String) und erlaubt nur Abfragen, keine Mutationen.
+// Antwort:
+//
+//	200 - Ergebnis {"data", "errors"}; Fehler einzelner Felder mit "extensions.code"
+//	      (BAD_USER_INPUT, UNAUTHENTICATED, NOT_FOUND, CONFLICT, INTERNAL_SERVER_ERROR)
+//	400 - Ungültige Anfrage, Syntax- oder Schemafehler, QUERY_TOO_DEEP oder QUERY_TOO_COMPLEX
+//	405 - Mutation per GET
+func (h *GraphQLHandler) ServeGraphQL(c *fiber.Ctx) error {
+	h.once.Do(func() { h.schema, h.schemaErr = h.buildGraphQLSchema() })
+	if h.schemaErr != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": h.schemaErr.Error(),
+		})
+	}
+
+	var req graphQLRequest
+	if c.Method() == fiber.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if v := c.Query("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				return graphQLRequestError(c, fiber.StatusBadRequest, "BAD_REQUEST", "variables must be a JSON object")
+			}
+		}
+		if isMutation(req.Query, req.OperationName) {
+			return graphQLRequestError(c, fiber.StatusMethodNotAllowed, "BAD_REQUEST", "mutations must be sent with POST")
+		}
+	} else if err := c.BodyParser(&req); err != nil {
+		return graphQLRequestError(c, fiber.StatusBadRequest, "BAD_REQUEST", "Invalid request body")
+	}
+	if req.Query == "" {
+		return graphQLRequestError(c, fiber.StatusBadRequest, "BAD_REQUEST", "query is required")
+	}
+
+	maxDepth, maxComplexity := h.MaxDepth, h.MaxComplexity
+	if maxDepth == 0 {
+		maxDepth = DefaultGraphQLMaxDepth
+	}
+	if maxComplexity == 0 {
+		maxComplexity = DefaultGraphQLMaxComplexity
+	}
+	if code, message := checkQueryLimits(req.Query, req.Variables, maxDepth, maxComplexity); code != "" {
+		return graphQLRequestError(c, fiber.StatusBadRequest, code, message)
+	}
+
+	ctx := context.WithValue(c.UserContext(), graphQLLoadersKey{}, h.newGraphQLLoaders())
+	if user := currentUser(c); user != nil {
+		ctx = context.WithValue(ctx, userContextKey{}, user)
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        ctx,
+	})
+
+	// Ohne Daten ist die Anfrage selbst ungültig (Syntax-, Schema- oder Variablenfehler)
+	if result.Data == nil && result.HasErrors() {
+		for i := range result.Errors {
+			setErrorCode(&result.Errors[i], "GRAPHQL_VALIDATION_FAILED")
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(result)
+	}
+	for i := range result.Errors {
+		// Fehler ohne eigenen Code stammen z.B. aus den Loadern
+		setErrorCode(&result.Errors[i], "INTERNAL_SERVER_ERROR")
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// setErrorCode setzt "extensions.code", sofern der Fehler noch keinen Code hat.
+func setErrorCode(err *gqlerrors.FormattedError, code string) {
+	if _, ok := err.Extensions["code"]; ok {
+		return
+	}
+	if err.Extensions == nil {
+		err.Extensions = map[string]interface{}{}
+	}
+	err.Extensions["code"] = code
+}
+
+// graphQLRequestError antwortet mit einem einzelnen Fehler im GraphQL-Antwortformat.
+func graphQLRequestError(c *fiber.Ctx, status int, code, message string) error {
+	return c.Status(status).JSON(fiber.Map{
+		"errors": []fiber.Map{{"message": message, "extensions": fiber.Map{"code": code}}},
+	})
+}
+
+// isMutation meldet, ob die auszuführende Operation einer Abfrage eine Mutation ist.
+func isMutation(query, operationName string) bool {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return false
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok || (operationName != "" && (op.Name == nil || op.Name.Value != operationName)) {
+			continue
+		}
+		if op.Operation == ast.OperationTypeMutation {
+			return true
+		}
+	}
+	return false
+}
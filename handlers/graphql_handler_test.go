@@ -0,0 +1,292 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"net/url"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+)
+
+// countingTaskService zählt die Batch-Abfragen des GraphQL-Handlers.
+type countingTaskService struct {
+	*services.MockTaskService
+	byIDs, subtasks int
+}
+
+func (s *countingTaskService) GetTasksByIDs(ids []int) ([]*models.Task, error) {
+	s.byIDs++
+	return s.MockTaskService.GetTasksByIDs(ids)
+}
+
+func (s *countingTaskService) GetSubtasksOf(parentIDs []int) ([]*models.Task, error) {
+	s.subtasks++
+	return s.MockTaskService.GetSubtasksOf(parentIDs)
+}
+
+// countingCommentService zählt die Batch-Abfragen der Kommentare.
+type countingCommentService struct {
+	*services.MockCommentService
+	calls int
+}
+
+func (s *countingCommentService) GetCommentsForTasks(taskIDs []int) ([]*models.Comment, error) {
+	s.calls++
+	return s.MockCommentService.GetCommentsForTasks(taskIDs)
+}
+
+// countingUserService zählt die Batch-Abfragen der Benutzer.
+type countingUserService struct {
+	*services.MockUserService
+	calls int
+}
+
+func (s *countingUserService) GetUsersByIDs(ids []int) ([]*models.User, error) {
+	s.calls++
+	return s.MockUserService.GetUsersByIDs(ids)
+}
+
+// graphQLFixture enthält die Services eines GraphQL-Tests.
+type graphQLFixture struct {
+	tasks    *countingTaskService
+	comments *countingCommentService
+	users    *countingUserService
+	handler  *handlers.GraphQLHandler
+	app      *fiber.App
+}
+
+// setupGraphQLHandler erstellt eine App mit /graphql. Task 1 hat die Subtasks 2 und 3,
+// Task 4 ist ein weiterer Top-Level-Task; Benutzer 1 meldet sich mit "anna-key" an.
+func setupGraphQLHandler() *graphQLFixture {
+	parent := 1
+	f := &graphQLFixture{
+		tasks: &countingTaskService{MockTaskService: &services.MockTaskService{
+			Tasks: []*models.Task{
+				{ID: 1, Title: "Release", Status: "in progress", Priority: "high", Tags: []string{"release"}, Assignees: []int{1}, Watchers: []int{2}},
+				{ID: 2, Title: "Changelog", Status: "done", ParentID: &parent, Assignees: []int{2}},
+				{ID: 3, Title: "Deploy", Status: "todo", ParentID: &parent, Assignees: []int{1, 2}},
+				{ID: 4, Title: "Retro", Status: "todo", Tags: []string{"team"}},
+			},
+			Dependencies: map[int][]int{3: {2, 4}},
+		}},
+		comments: &countingCommentService{MockCommentService: &services.MockCommentService{
+			Comments: []*models.Comment{
+				{ID: 1, TaskID: 2, AuthorID: intp(1), Body: "Fertig", BodyHTML: "<p>Fertig</p>"},
+				{ID: 2, TaskID: 3, AuthorID: intp(2), Body: "Morgen", BodyHTML: "<p>Morgen</p>"},
+			},
+		}},
+		users: &countingUserService{MockUserService: &services.MockUserService{
+			Users:   testUsers(),
+			APIKeys: map[string]int{"anna-key": 1},
+		}},
+	}
+	f.handler = &handlers.GraphQLHandler{
+		Tasks:    &handlers.TaskHandler{Service: f.tasks},
+		Comments: f.comments,
+		Users:    f.users,
+	}
+	f.app = fiber.New()
+	f.app.Use(handlers.Authenticate(f.users))
+	f.app.Get("/graphql", f.handler.ServeGraphQL)
+	f.app.Post("/graphql", f.handler.ServeGraphQL)
+	return f
+}
+
+func intp(v int) *int { return &v }
+
+// graphQLResponse ist die Antwort von /graphql.
+type graphQLResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// postGraphQL sendet eine Abfrage per POST und liefert Status und Antwort.
+func postGraphQL(t *testing.T, app *fiber.App, apiKey, query string, variables map[string]any) (int, graphQLResponse) {
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	req := httptest.NewRequest("POST", "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+
+	var out graphQLResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return resp.StatusCode, out
+}
+
+// Test_GraphQL_TaskWithRelations prüft einen Task mit Parent, Subtasks, Kommentaren und Benutzern.
+func Test_GraphQL_TaskWithRelations(t *testing.T) {
+	f := setupGraphQLHandler()
+
+	status, out := postGraphQL(t, f.app, "", `{
+		task(id: 1) { id title isOverdue dueAt assignees { name } watchers { email }
+			subtasks { title parent { id } comments { bodyHtml author { name } } } }
+		missing: task(id: 99) { id }
+	}`, nil)
+	assert.Equal(t, 200, status)
+	assert.Empty(t, out.Errors)
+
+	task := out.Data["task"].(map[string]any)
+	assert.Equal(t, "Release", task["title"])
+	assert.Nil(t, task["dueAt"])
+	assert.Equal(t, []any{map[string]any{"name": "Anna"}}, task["assignees"])
+	assert.Equal(t, []any{map[string]any{"email": "ben@example.com"}}, task["watchers"])
+
+	subtasks := task["subtasks"].([]any)
+	if assert.Len(t, subtasks, 2) {
+		changelog := subtasks[0].(map[string]any)
+		assert.Equal(t, "Changelog", changelog["title"])
+		assert.Equal(t, map[string]any{"id": float64(1)}, changelog["parent"])
+		assert.Equal(t, []any{map[string]any{"bodyHtml": "<p>Fertig</p>", "author": map[string]any{"name": "Anna"}}}, changelog["comments"])
+	}
+	assert.Nil(t, out.Data["missing"])
+}
+
+// Test_GraphQL_Batching prüft, dass Beziehungen pro Ebene mit einer Abfrage geladen werden (kein N+1).
+func Test_GraphQL_Batching(t *testing.T) {
+	f := setupGraphQLHandler()
+
+	_, out := postGraphQL(t, f.app, "", `{
+		tasks(first: 10) { nodes { id parent { title } subtasks { id } comments { author { name } } assignees { name } watchers { name } } }
+	}`, nil)
+	assert.Empty(t, out.Errors)
+	assert.Len(t, out.Data["tasks"].(map[string]any)["nodes"], 4)
+
+	assert.Equal(t, 1, f.tasks.byIDs, "parents")
+	assert.Equal(t, 1, f.tasks.subtasks, "subtasks")
+	assert.Equal(t, 1, f.comments.calls, "comments")
+	// Höchstens eine Abfrage für Assignees/Watchers (Ebene 2) und eine für Autoren (Ebene 3)
+	assert.LessOrEqual(t, f.users.calls, 2, "users")
+}
+
+// Test_GraphQL_TasksFilterAndPagination prüft Filter und Seitenaufteilung der Abfrage "tasks".
+func Test_GraphQL_TasksFilterAndPagination(t *testing.T) {
+	f := setupGraphQLHandler()
+
+	_, out := postGraphQL(t, f.app, "", `query($first: Int) {
+		tasks(first: $first, offset: 1) { totalCount hasNextPage nodes { id } }
+	}`, map[string]any{"first": 2})
+	assert.Empty(t, out.Errors)
+	assert.Equal(t, map[string]any{
+		"totalCount":  float64(4),
+		"hasNextPage": true,
+		"nodes":       []any{map[string]any{"id": float64(2)}, map[string]any{"id": float64(3)}},
+	}, out.Data["tasks"])
+
+	_, out = postGraphQL(t, f.app, "anna-key", `{ tasks(filter: {assignee: "me"}) { nodes { id } } }`, nil)
+	assert.Empty(t, out.Errors)
+	assert.Len(t, out.Data["tasks"].(map[string]any)["nodes"], 2)
+
+	_, out = postGraphQL(t, f.app, "", `{ tasks(filter: {tagsAny: ["team"]}) { totalCount } }`, nil)
+	assert.Equal(t, map[string]any{"totalCount": float64(1)}, out.Data["tasks"])
+
+	_, out = postGraphQL(t, f.app, "", `{ tasks(filter: {assignee: "me"}) { totalCount } }`, nil)
+	if assert.Len(t, out.Errors, 1) {
+		assert.Equal(t, "UNAUTHENTICATED", out.Errors[0].Extensions["code"])
+	}
+
+	_, out = postGraphQL(t, f.app, "", `{ tasks(first: 500) { totalCount } }`, nil)
+	if assert.Len(t, out.Errors, 1) {
+		assert.Equal(t, "BAD_USER_INPUT", out.Errors[0].Extensions["code"])
+	}
+}
+
+// Test_GraphQL_Mutations prüft Erstellen, Ändern und Löschen inkl. der Fehlercodes.
+func Test_GraphQL_Mutations(t *testing.T) {
+	f := setupGraphQLHandler()
+
+	_, out := postGraphQL(t, f.app, "", `mutation { createTask(input: {title: "Planung", priority: "low"}) { id title priority status } }`, nil)
+	assert.Empty(t, out.Errors)
+	assert.Equal(t, "Planung", out.Data["createTask"].(map[string]any)["title"])
+
+	_, out = postGraphQL(t, f.app, "", `mutation { createTask(input: {title: "X", priority: "urgent"}) { id } }`, nil)
+	if assert.Len(t, out.Errors, 1) {
+		assert.Equal(t, "BAD_USER_INPUT", out.Errors[0].Extensions["code"])
+	}
+
+	_, out = postGraphQL(t, f.app, "", `mutation { updateTask(id: 3, input: {status: "done"}) { status } }`, nil)
+	if assert.Len(t, out.Errors, 1) {
+		assert.Equal(t, "CONFLICT", out.Errors[0].Extensions["code"])
+	}
+
+	_, out = postGraphQL(t, f.app, "", `mutation { updateTask(id: 3, input: {status: "done", overrideBlockers: true}) { status } }`, nil)
+	assert.Empty(t, out.Errors)
+	assert.Equal(t, map[string]any{"status": "done"}, out.Data["updateTask"])
+
+	_, out = postGraphQL(t, f.app, "", `mutation { updateTask(id: 99, input: {title: "X"}) { id } }`, nil)
+	if assert.Len(t, out.Errors, 1) {
+		assert.Equal(t, "NOT_FOUND", out.Errors[0].Extensions["code"])
+	}
+
+	_, out = postGraphQL(t, f.app, "", `mutation { deleteTask(id: 4) }`, nil)
+	assert.Empty(t, out.Errors)
+	assert.Equal(t, true, out.Data["deleteTask"])
+
+	_, out = postGraphQL(t, f.app, "", `mutation { deleteTask(id: 4) }`, nil)
+	if assert.Len(t, out.Errors, 1) {
+		assert.Equal(t, "NOT_FOUND", out.Errors[0].Extensions["code"])
+	}
+}
+
+// Test_GraphQL_Get prüft Abfragen per GET und die Ablehnung von Mutationen.
+func Test_GraphQL_Get(t *testing.T) {
+	f := setupGraphQLHandler()
+
+	resp, err := f.app.Test(httptest.NewRequest("GET", "/graphql?query="+url.QueryEscape(`{ task(id: 4) { title } }`), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = f.app.Test(httptest.NewRequest("GET", "/graphql?query="+url.QueryEscape(`mutation { deleteTask(id: 4) }`), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 405, resp.StatusCode)
+	assert.Len(t, f.tasks.Tasks, 4)
+}
+
+// Test_GraphQL_Limits prüft die Grenzen für Tiefe und Komplexität sowie Syntaxfehler.
+func Test_GraphQL_Limits(t *testing.T) {
+	f := setupGraphQLHandler()
+	f.handler.MaxDepth = 4
+	f.handler.MaxComplexity = 200
+
+	status, out := postGraphQL(t, f.app, "", `{ task(id: 1) { subtasks { subtasks { subtasks { id } } } } }`, nil)
+	assert.Equal(t, 400, status)
+	if assert.Len(t, out.Errors, 1) {
+		assert.Equal(t, "QUERY_TOO_DEEP", out.Errors[0].Extensions["code"])
+	}
+
+	// Fragmente zählen mit
+	status, out = postGraphQL(t, f.app, "", `{ task(id: 1) { ...deep } }
+		fragment deep on Task { subtasks { subtasks { subtasks { id } } } }`, nil)
+	assert.Equal(t, 400, status)
+	if assert.Len(t, out.Errors, 1) {
+		assert.Equal(t, "QUERY_TOO_DEEP", out.Errors[0].Extensions["code"])
+	}
+
+	// 1 + 50 * (1 + 1 + 10 * 1) = 601
+	status, out = postGraphQL(t, f.app, "", `{ tasks { nodes { id assignees { name } } } }`, nil)
+	assert.Equal(t, 400, status)
+	if assert.Len(t, out.Errors, 1) {
+		assert.Equal(t, "QUERY_TOO_COMPLEX", out.Errors[0].Extensions["code"])
+	}
+
+	// Mit kleiner Seitengröße erlaubt: 1 + 10 * 13 = 131
+	status, out = postGraphQL(t, f.app, "", `query($n: Int) { tasks(first: $n) { nodes { id assignees { name } } } }`, map[string]any{"n": 10})
+	assert.Equal(t, 200, status)
+	assert.Empty(t, out.Errors)
+
+	status, out = postGraphQL(t, f.app, "", `{ task(id: 1) { unknown } }`, nil)
+	assert.Equal(t, 400, status)
+	if assert.Len(t, out.Errors, 1) {
+		assert.Equal(t, "GRAPHQL_VALIDATION_FAILED", out.Errors[0].Extensions["code"])
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"strconv"
+	"strings"
+)
+
+// Geschätzte Anzahl Elemente der Listen-Beziehungen eines Tasks für die Komplexitätsberechnung.
+const graphQLListMultiplier = 10
+
+// graphQLListFields sind die Felder, die eine Liste liefern; ihre Unterauswahl wird mit
+// graphQLListMultiplier gewichtet. Bei "tasks" ist der Faktor das Argument "first".
+var graphQLListFields = map[string]bool{
+	"subtasks":  true,
+	"comments":  true,
+	"assignees": true,
+	"watchers":  true,
+}
+
+// queryCost beschreibt Tiefe und Komplexität einer Abfrage.
+type queryCost struct {
+	Depth      int // Größte Verschachtelungstiefe der Felder
+	Complexity int // Geschätzte Anzahl aufzulösender Felder
+}
+
+// analyzeQuery berechnet Tiefe und Komplexität aller Operationen einer Abfrage.
+// Fragmente werden aufgelöst, Introspektionsfelder ("__schema", "__typename", ...) zählen nicht.
+// Komplexität: jedes Feld kostet 1, die Kosten seiner Unterauswahl werden bei Listen mit der
+// erwarteten Anzahl Elemente multipliziert. Syntaxfehler werden nicht gemeldet, sondern der
+// Ausführung überlassen, die sie mit Position beantwortet.
+func analyzeQuery(query string, variables map[string]interface{}) (queryCost, bool) {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return queryCost{}, false
+	}
+
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			fragments[f.Name.Value] = f
+		}
+	}
+
+	a := &queryAnalyzer{fragments: fragments, variables: variables, visiting: map[string]bool{}}
+	var cost queryCost
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			depth, complexity := a.selectionSet(op.SelectionSet)
+			cost.Depth = max(cost.Depth, depth)
+			cost.Complexity = max(cost.Complexity, complexity)
+		}
+	}
+	return cost, true
+}
+
+// queryAnalyzer durchläuft die Auswahl einer Operation.
+type queryAnalyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	visiting  map[string]bool // Fragmente auf dem aktuellen Pfad, schützt vor Zyklen
+}
+
+// selectionSet liefert Tiefe und Komplexität einer Auswahl.
+func (a *queryAnalyzer) selectionSet(set *ast.SelectionSet) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, sel := range set.Selections {
+		var d, c int
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name.Value, "__") {
+				continue
+			}
+			childDepth, childComplexity := a.selectionSet(sel.SelectionSet)
+			d = childDepth + 1
+			c = 1 + a.multiplier(sel)*childComplexity
+		case *ast.InlineFragment:
+			d, c = a.selectionSet(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			fragment, ok := a.fragments[name]
+			if !ok || a.visiting[name] {
+				continue
+			}
+			a.visiting[name] = true
+			d, c = a.selectionSet(fragment.SelectionSet)
+			delete(a.visiting, name)
+		}
+		depth = max(depth, d)
+		complexity += c
+	}
+	return depth, complexity
+}
+
+// multiplier liefert die erwartete Anzahl Elemente, die ein Feld liefert.
+func (a *queryAnalyzer) multiplier(field *ast.Field) int {
+	if graphQLListFields[field.Name.Value] {
+		return graphQLListMultiplier
+	}
+	if field.Name.Value != "tasks" {
+		return 1
+	}
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil && n >= 0 {
+				return n
+			}
+		case *ast.Variable:
+			switch n := a.variables[v.Name.Value].(type) {
+			case float64:
+				return max(int(n), 0)
+			case int:
+				return max(n, 0)
+			}
+		}
+	}
+	return graphQLDefaultPageSize
+}
+
+// checkQueryLimits prüft eine Abfrage gegen die Höchstwerte für Tiefe und Komplexität
+// (0 = unbegrenzt) und liefert bei Überschreitung den Fehlercode und die Meldung.
+func checkQueryLimits(query string, variables map[string]interface{}, maxDepth, maxComplexity int) (code, message string) {
+	cost, ok := analyzeQuery(query, variables)
+	if !ok {
+		return "", ""
+	}
+	if maxDepth > 0 && cost.Depth > maxDepth {
+		return "QUERY_TOO_DEEP", fmt.Sprintf("query depth %d exceeds the maximum of %d", cost.Depth, maxDepth)
+	}
+	if maxComplexity > 0 && cost.Complexity > maxComplexity {
+		return "QUERY_TOO_COMPLEX", fmt.Sprintf("query complexity %d exceeds the maximum of %d", cost.Complexity, maxComplexity)
+	}
+	return "", ""
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"task-api/models"
+)
+
+// batchLoader bündelt das Laden von Datensätzen nach ID (Dataloader-Muster), um N+1-Abfragen zu vermeiden.
+// Load merkt sich die ID und liefert einen Thunk. Erst beim Auswerten des ersten Thunks werden alle bis
+// dahin gesammelten IDs mit einem einzigen Aufruf von fetch geladen. graphql-go wertet die Thunks einer
+// Ebene erst aus, nachdem alle Felder dieser Ebene aufgelöst wurden – pro Ebene entsteht so eine Abfrage.
+// Geladene Werte werden für die Dauer des Requests zwischengespeichert.
+type batchLoader[V any] struct {
+	fetch func(ids []int) (map[int]V, error)
+
+	mu      sync.Mutex
+	pending []int
+	queued  map[int]bool
+	cache   map[int]V
+	errs    map[int]error
+}
+
+// newBatchLoader erstellt einen batchLoader, der fehlende IDs über fetch lädt.
+// IDs ohne Eintrag im Ergebnis von fetch erhalten den Nullwert von V.
+func newBatchLoader[V any](fetch func(ids []int) (map[int]V, error)) *batchLoader[V] {
+	return &batchLoader[V]{
+		fetch:  fetch,
+		queued: map[int]bool{},
+		cache:  map[int]V{},
+		errs:   map[int]error{},
+	}
+}
+
+// Load merkt id für die nächste Abfrage vor und gibt einen Thunk zurück, der den Wert liefert.
+func (l *batchLoader[V]) Load(id int) func() (V, error) {
+	l.mu.Lock()
+	l.enqueue(id)
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.dispatch()
+		return l.cache[id], l.errs[id]
+	}
+}
+
+// LoadMany merkt alle ids für die nächste Abfrage vor und gibt einen Thunk zurück, der die Werte
+// in der Reihenfolge der ids liefert.
+func (l *batchLoader[V]) LoadMany(ids []int) func() ([]V, error) {
+	l.mu.Lock()
+	for _, id := range ids {
+		l.enqueue(id)
+	}
+	l.mu.Unlock()
+
+	return func() ([]V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.dispatch()
+
+		values := make([]V, 0, len(ids))
+		for _, id := range ids {
+			if err := l.errs[id]; err != nil {
+				return nil, err
+			}
+			values = append(values, l.cache[id])
+		}
+		return values, nil
+	}
+}
+
+// enqueue merkt id vor, sofern sie weder geladen noch bereits vorgemerkt ist. Erfordert l.mu.
+func (l *batchLoader[V]) enqueue(id int) {
+	if _, ok := l.cache[id]; ok || l.queued[id] || l.errs[id] != nil {
+		return
+	}
+	l.queued[id] = true
+	l.pending = append(l.pending, id)
+}
+
+// dispatch lädt alle vorgemerkten IDs mit einem Aufruf von fetch. Erfordert l.mu.
+func (l *batchLoader[V]) dispatch() {
+	if len(l.pending) == 0 {
+		return
+	}
+	ids := l.pending
+	l.pending = nil
+	clear(l.queued)
+
+	values, err := l.fetch(ids)
+	for _, id := range ids {
+		if err != nil {
+			l.errs[id] = err
+			continue
+		}
+		l.cache[id] = values[id]
+	}
+}
+
+// graphQLLoaders enthält die batchLoader eines GraphQL-Requests.
+type graphQLLoaders struct {
+	tasks    *batchLoader[*models.Task]      // Task nach ID (parent)
+	subtasks *batchLoader[[]*models.Task]    // Direkte Subtasks nach Parent-ID
+	comments *batchLoader[[]*models.Comment] // Kommentare nach Task-ID
+	users    *batchLoader[*models.User]      // Benutzer nach ID (assignees, watchers, author)
+}
+
+// graphQLLoadersKey ist der Schlüssel, unter dem die Loader im Context abgelegt werden.
+type graphQLLoadersKey struct{}
+
+// newGraphQLLoaders erstellt die Loader für einen Request. Jeder Loader lädt über genau eine
+// Batch-Methode der Services.
+func (h *GraphQLHandler) newGraphQLLoaders() *graphQLLoaders {
+	return &graphQLLoaders{
+		tasks: newBatchLoader(func(ids []int) (map[int]*models.Task, error) {
+			tasks, err := h.Tasks.Service.GetTasksByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := map[int]*models.Task{}
+			for _, t := range tasks {
+				byID[t.ID] = t
+			}
+			return byID, nil
+		}),
+		subtasks: newBatchLoader(func(ids []int) (map[int][]*models.Task, error) {
+			tasks, err := h.Tasks.Service.GetSubtasksOf(ids)
+			if err != nil {
+				return nil, err
+			}
+			byParent := map[int][]*models.Task{}
+			for _, t := range tasks {
+				byParent[*t.ParentID] = append(byParent[*t.ParentID], t)
+			}
+			return byParent, nil
+		}),
+		comments: newBatchLoader(func(ids []int) (map[int][]*models.Comment, error) {
+			comments, err := h.Comments.GetCommentsForTasks(ids)
+			if err != nil {
+				return nil, err
+			}
+			byTask := map[int][]*models.Comment{}
+			for _, c := range comments {
+				byTask[c.TaskID] = append(byTask[c.TaskID], c)
+			}
+			return byTask, nil
+		}),
+		users: newBatchLoader(func(ids []int) (map[int]*models.User, error) {
+			users, err := h.Users.GetUsersByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			byID := map[int]*models.User{}
+			for _, u := range users {
+				byID[u.ID] = u
+			}
+			return byID, nil
+		}),
+	}
+}
+
+// loadersFromContext gibt die Loader des aktuellen GraphQL-Requests zurück.
+func loadersFromContext(ctx context.Context) *graphQLLoaders {
+	return ctx.Value(graphQLLoadersKey{}).(*graphQLLoaders)
+}
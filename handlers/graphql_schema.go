@@ -0,0 +1,468 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+	"task-api/models"
+	"time"
+)
+
+// Standard- und Höchstwert für das Argument "first" der Abfrage "tasks".
+const (
+	graphQLDefaultPageSize = 50
+	graphQLMaxPageSize     = 100
+)
+
+// graphQLError ist ein Fehler eines Resolvers mit Fehlercode unter "extensions.code".
+type graphQLError struct {
+	message    string
+	extensions map[string]interface{}
+}
+
+// Error gibt die Fehlermeldung zurück.
+func (e *graphQLError) Error() string {
+	return e.message
+}
+
+// Extensions gibt die Zusatzangaben des Fehlers zurück (gqlerrors.ExtendedError).
+func (e *graphQLError) Extensions() map[string]interface{} {
+	return e.extensions
+}
+
+// newGraphQLError erstellt einen Fehler mit dem angegebenen Code.
+func newGraphQLError(code, message string) error {
+	return &graphQLError{message: message, extensions: map[string]interface{}{"code": code}}
+}
+
+// graphQLErrorFrom übersetzt eine Fehlerantwort der REST-API (HTTP-Status und Body) in einen GraphQL-Fehler.
+// Zusätzliche Angaben wie "next_states" werden in die Extensions übernommen.
+func graphQLErrorFrom(httpStatus int, body fiber.Map) error {
+	message, _ := body["message"].(string)
+	code := "INTERNAL_SERVER_ERROR"
+	if body["error"] != "internal error" {
+		switch httpStatus {
+		case fiber.StatusBadRequest:
+			code = "BAD_USER_INPUT"
+		case fiber.StatusUnauthorized:
+			code = "UNAUTHENTICATED"
+		case fiber.StatusNotFound:
+			code = "NOT_FOUND"
+		case fiber.StatusConflict:
+			code = "CONFLICT"
+		}
+	}
+	err := &graphQLError{message: message, extensions: map[string]interface{}{"code": code}}
+	for k, v := range body {
+		if k != "error" && k != "message" {
+			err.extensions[k] = v
+		}
+	}
+	return err
+}
+
+// buildGraphQLSchema erstellt das GraphQL-Schema der Task-API. Beziehungen (parent, subtasks, comments,
+// assignees, watchers, author) werden über die Loader des Requests gebündelt geladen.
+func (h *GraphQLHandler) buildGraphQLSchema() (graphql.Schema, error) {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "User",
+		Description: "Ein Benutzer der API.",
+		Fields: graphql.Fields{
+			"id":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"name":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"email": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	commentType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Comment",
+		Description: "Ein Kommentar zu einem Task.",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"body": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"bodyHtml": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(*models.Comment).BodyHTML, nil },
+			},
+			"author": &graphql.Field{
+				Type:        userType,
+				Description: "Autor des Kommentars, null wenn der Benutzer gelöscht wurde.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					comment := p.Source.(*models.Comment)
+					if comment.AuthorID == nil {
+						return nil, nil
+					}
+					load := loadersFromContext(p.Context).users.Load(*comment.AuthorID)
+					return func() (interface{}, error) { return load() }, nil
+				},
+			},
+			"createdAt": timeField(func(s interface{}) *time.Time { return &s.(*models.Comment).CreatedAt }, true),
+			"updatedAt": timeField(func(s interface{}) *time.Time { return &s.(*models.Comment).UpdatedAt }, true),
+			"editedAt":  timeField(func(s interface{}) *time.Time { return s.(*models.Comment).EditedAt }, false),
+		},
+	})
+
+	var taskType *graphql.Object
+	taskType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Task",
+		Description: "Eine Aufgabe inkl. ihrer Beziehungen.",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"title":       &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"description": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"status":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"priority":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+				"progress": &graphql.Field{
+					Type:        graphql.Int,
+					Description: "Anteil erledigter Subtasks in Prozent, null ohne Subtasks.",
+				},
+				"startAt": timeField(func(s interface{}) *time.Time { return s.(*models.Task).StartAt }, false),
+				"dueAt":   timeField(func(s interface{}) *time.Time { return s.(*models.Task).DueAt }, false),
+				"isOverdue": &graphql.Field{
+					Type:    graphql.NewNonNull(graphql.Boolean),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(*models.Task).IsOverdue, nil },
+				},
+				"recurrenceRule": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						if rule := p.Source.(*models.Task).RecurrenceRule; rule != "" {
+							return rule, nil
+						}
+						return nil, nil
+					},
+				},
+				"tags":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				"createdAt": timeField(func(s interface{}) *time.Time { return &s.(*models.Task).CreatedAt }, true),
+				"updatedAt": timeField(func(s interface{}) *time.Time { return &s.(*models.Task).UpdatedAt }, true),
+				"parent": &graphql.Field{
+					Type:        taskType,
+					Description: "Übergeordneter Task, null bei Top-Level-Tasks.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						task := p.Source.(*models.Task)
+						if task.ParentID == nil {
+							return nil, nil
+						}
+						load := loadersFromContext(p.Context).tasks.Load(*task.ParentID)
+						return func() (interface{}, error) { return load() }, nil
+					},
+				},
+				"subtasks": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(taskType))),
+					Description: "Direkte Subtasks.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						load := loadersFromContext(p.Context).subtasks.Load(p.Source.(*models.Task).ID)
+						return func() (interface{}, error) {
+							tasks, err := load()
+							if tasks == nil {
+								tasks = []*models.Task{}
+							}
+							return tasks, err
+						}, nil
+					},
+				},
+				"comments": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(commentType))),
+					Description: "Kommentare, älteste zuerst.",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						load := loadersFromContext(p.Context).comments.Load(p.Source.(*models.Task).ID)
+						return func() (interface{}, error) {
+							comments, err := load()
+							if comments == nil {
+								comments = []*models.Comment{}
+							}
+							return comments, err
+						}, nil
+					},
+				},
+				"assignees": userListField(func(t *models.Task) []int { return t.Assignees }, userType, "Verantwortliche Benutzer."),
+				"watchers":  userListField(func(t *models.Task) []int { return t.Watchers }, userType, "Benutzer, die den Task beobachten."),
+			}
+		}),
+	})
+
+	taskPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "TaskPage",
+		Description: "Eine Seite der Ergebnisliste von \"tasks\".",
+		Fields: graphql.Fields{
+			"nodes":       &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(taskType)))},
+			"totalCount":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Anzahl aller Treffer."},
+			"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		},
+	})
+
+	stringList := graphql.NewList(graphql.NewNonNull(graphql.String))
+	taskFilterType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "TaskFilter",
+		Description: "Filter wie bei GET /tasks; assignee und watcher akzeptieren eine Benutzer-ID oder \"me\".",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"overdue":   &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+			"dueBefore": &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+			"dueAfter":  &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+			"tagsAny":   &graphql.InputObjectFieldConfig{Type: stringList},
+			"tagsAll":   &graphql.InputObjectFieldConfig{Type: stringList},
+			"tagsNone":  &graphql.InputObjectFieldConfig{Type: stringList},
+			"assignee":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"watcher":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	taskInputFields := func(create bool) graphql.InputObjectConfigFieldMap {
+		fields := graphql.InputObjectConfigFieldMap{
+			"title":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"description":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"status":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"priority":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"parentId":       &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"startAt":        &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+			"dueAt":          &graphql.InputObjectFieldConfig{Type: graphql.DateTime},
+			"recurrenceRule": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		}
+		if create {
+			fields["title"] = &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)}
+		} else {
+			fields["scope"] = &graphql.InputObjectFieldConfig{Type: graphql.String}
+			fields["overrideBlockers"] = &graphql.InputObjectFieldConfig{Type: graphql.Boolean}
+			fields["reopen"] = &graphql.InputObjectFieldConfig{Type: graphql.Boolean}
+		}
+		return fields
+	}
+	createTaskInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "CreateTaskInput",
+		Description: "Felder wie beim Request-Body von POST /tasks.",
+		Fields:      taskInputFields(true),
+	})
+	updateTaskInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "UpdateTaskInput",
+		Description: "Felder wie beim Request-Body von PUT /tasks/:id; nicht gesetzte Felder bleiben unverändert.",
+		Fields:      taskInputFields(false),
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"task": &graphql.Field{
+				Type:        taskType,
+				Description: "Ein Task anhand seiner ID, null wenn er nicht existiert.",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					load := loadersFromContext(p.Context).tasks.Load(p.Args["id"].(int))
+					return func() (interface{}, error) { return load() }, nil
+				},
+			},
+			"tasks": &graphql.Field{
+				Type:        graphql.NewNonNull(taskPageType),
+				Description: "Alle Tasks, die dem Filter entsprechen, seitenweise.",
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: taskFilterType},
+					"first": &graphql.ArgumentConfig{
+						Type:         graphql.Int,
+						DefaultValue: graphQLDefaultPageSize,
+						Description:  fmt.Sprintf("Anzahl der Tasks pro Seite, max %d.", graphQLMaxPageSize),
+					},
+					"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: h.resolveTasks,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createTask": &graphql.Field{
+				Type: graphql.NewNonNull(taskType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createTaskInput)},
+				},
+				Resolve: h.resolveCreateTask,
+			},
+			"updateTask": &graphql.Field{
+				Type: graphql.NewNonNull(taskType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateTaskInput)},
+				},
+				Resolve: h.resolveUpdateTask,
+			},
+			"deleteTask": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: h.resolveDeleteTask,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// timeField liefert ein DateTime-Feld, dessen Wert über get aus dem Quellobjekt gelesen wird.
+func timeField(get func(source interface{}) *time.Time, nonNull bool) *graphql.Field {
+	var typ graphql.Output = graphql.DateTime
+	if nonNull {
+		typ = graphql.NewNonNull(graphql.DateTime)
+	}
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			if t := get(p.Source); t != nil {
+				return *t, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+// userListField liefert ein Feld mit den Benutzern, deren IDs ids aus dem Task liest.
+// Unbekannte IDs (z.B. gelöschte Benutzer) werden ausgelassen.
+func userListField(ids func(*models.Task) []int, userType *graphql.Object, description string) *graphql.Field {
+	return &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+		Description: description,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			load := loadersFromContext(p.Context).users.LoadMany(ids(p.Source.(*models.Task)))
+			return func() (interface{}, error) {
+				users, err := load()
+				if err != nil {
+					return nil, err
+				}
+				found := []*models.User{}
+				for _, u := range users {
+					if u != nil {
+						found = append(found, u)
+					}
+				}
+				return found, nil
+			}, nil
+		},
+	}
+}
+
+// resolveTasks löst die Abfrage "tasks" auf.
+func (h *GraphQLHandler) resolveTasks(p graphql.ResolveParams) (interface{}, error) {
+	first, _ := p.Args["first"].(int)
+	offset, _ := p.Args["offset"].(int)
+	if first < 0 || first > graphQLMaxPageSize {
+		return nil, newGraphQLError("BAD_USER_INPUT", fmt.Sprintf("first must be between 0 and %d", graphQLMaxPageSize))
+	}
+	if offset < 0 {
+		return nil, newGraphQLError("BAD_USER_INPUT", "offset must not be negative")
+	}
+
+	var filter models.TaskFilter
+	if in, ok := p.Args["filter"].(map[string]interface{}); ok {
+		if v, ok := in["overdue"].(bool); ok {
+			filter.Overdue = &v
+		}
+		filter.DueBefore = timeArg(in, "dueBefore")
+		filter.DueAfter = timeArg(in, "dueAfter")
+		filter.TagsAny = stringsArg(in, "tagsAny")
+		filter.TagsAll = stringsArg(in, "tagsAll")
+		filter.TagsNone = stringsArg(in, "tagsNone")
+		for _, f := range []struct {
+			name   string
+			target **int
+		}{{"assignee", &filter.Assignee}, {"watcher", &filter.Watcher}} {
+			v, ok := in[f.name].(string)
+			if !ok || v == "" {
+				continue
+			}
+			id, err := parseUserID(v, userFromContext(p.Context))
+			if err != nil {
+				if err.Error() == "authentication required" {
+					return nil, newGraphQLError("UNAUTHENTICATED", "authentication required")
+				}
+				return nil, newGraphQLError("BAD_USER_INPUT", fmt.Sprintf("%s %s", f.name, err.Error()))
+			}
+			*f.target = &id
+		}
+	}
+
+	// Die Datenbank liefert nur die Tasks der Seite; first=0 fragt nur totalCount ab (Limit 0 hieße "alle")
+	tasks, total, err := h.Tasks.Service.ListTasks(filter, models.TaskProjection{}, models.TaskPage{Limit: max(first, 1), Offset: offset})
+	if err != nil {
+		return nil, newGraphQLError("INTERNAL_SERVER_ERROR", err.Error())
+	}
+	tasks = tasks[:min(first, len(tasks))]
+	return map[string]interface{}{
+		"nodes":       tasks,
+		"totalCount":  total,
+		"hasNextPage": offset+len(tasks) < total,
+	}, nil
+}
+
+// resolveCreateTask löst die Mutation "createTask" auf (wie POST /tasks).
+func (h *GraphQLHandler) resolveCreateTask(p graphql.ResolveParams) (interface{}, error) {
+	req := taskRequestFromInput(p.Args["input"].(map[string]interface{}))
+	task, err := h.Tasks.Service.CreateTask(req)
+	if err != nil {
+		return nil, graphQLErrorFrom(createTaskError(err))
+	}
+	return task, nil
+}
+
+// resolveUpdateTask löst die Mutation "updateTask" auf (wie PUT /tasks/:id).
+func (h *GraphQLHandler) resolveUpdateTask(p graphql.ResolveParams) (interface{}, error) {
+	id := p.Args["id"].(int)
+	req := taskRequestFromInput(p.Args["input"].(map[string]interface{}))
+	task, err := h.Tasks.Service.UpdateTask(id, req)
+	if err != nil {
+		return nil, graphQLErrorFrom(h.Tasks.updateTaskError(id, req, err))
+	}
+	return task, nil
+}
+
+// resolveDeleteTask löst die Mutation "deleteTask" auf (wie DELETE /tasks/:id).
+func (h *GraphQLHandler) resolveDeleteTask(p graphql.ResolveParams) (interface{}, error) {
+	id := p.Args["id"].(int)
+	if err := h.Tasks.Service.DeleteTask(id); err != nil {
+		return nil, graphQLErrorFrom(deleteTaskError(id, err))
+	}
+	return true, nil
+}
+
+// taskRequestFromInput wandelt CreateTaskInput bzw. UpdateTaskInput in einen CreateTaskRequest um.
+func taskRequestFromInput(in map[string]interface{}) models.CreateTaskRequest {
+	req := models.CreateTaskRequest{
+		StartAt: timeArg(in, "startAt"),
+		DueAt:   timeArg(in, "dueAt"),
+	}
+	req.Title, _ = in["title"].(string)
+	req.Description, _ = in["description"].(string)
+	req.Status, _ = in["status"].(string)
+	req.Priority, _ = in["priority"].(string)
+	req.Scope, _ = in["scope"].(string)
+	req.OverrideBlockers, _ = in["overrideBlockers"].(bool)
+	req.Reopen, _ = in["reopen"].(bool)
+	if v, ok := in["parentId"].(int); ok {
+		req.ParentID = &v
+	}
+	if v, ok := in["recurrenceRule"].(string); ok {
+		req.RecurrenceRule = &v
+	}
+	return req
+}
+
+// timeArg liest ein DateTime-Feld aus einem Input-Objekt, nil wenn es nicht gesetzt ist.
+func timeArg(in map[string]interface{}, name string) *time.Time {
+	if t, ok := in[name].(time.Time); ok {
+		return &t
+	}
+	return nil
+}
+
+// stringsArg liest eine String-Liste aus einem Input-Objekt.
+func stringsArg(in map[string]interface{}, name string) []string {
+	values, _ := in[name].([]interface{})
+	var result []string
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"task-api/export"
+	"task-api/importer"
+	"task-api/models"
+	"task-api/services"
+	"time"
+)
+
+// ImportHandler verbindet die Import-Endpoints mit dem ImportService.
+type ImportHandler struct {
+	Service services.ImportServiceInterface
+}
+
+// importExtensions ordnet Dateiendungen das Importformat zu, wenn format= fehlt.
+var importExtensions = map[string]export.Format{
+	".csv":    export.CSV,
+	".ndjson": export.NDJSON,
+	".jsonl":  export.NDJSON,
+}
+
+// ImportTasks verarbeitet POST /tasks/import (multipart/form-data, Feld "file", optional "mapping").
+// Das Format kommt aus format=, sonst aus Dateiendung bzw. Content-Type der Datei. Mit dry_run=true werden
+// die Zeilen nur geprüft, mit async=true läuft der Import als Job im Hintergrund.
+//
+// Antwort:
+//
+//	200 - Ergebnis mit Zählern und Fehlern je Zeile (synchron)
+//	202 - Job gestartet (async=true), Location verweist auf den Job
+//	400 - Kein Feld "file" / ungültiges Mapping, Parameter oder CSV-Kopf
+//	413 - Zu viele Zeilen für einen synchronen Import
+//	415 - Format nicht erkennbar oder nicht unterstützt
+func (h *ImportHandler) ImportTasks(c *fiber.Ctx) error {
+	opts := models.ImportOptions{}
+	var async bool
+	for _, param := range []struct {
+		name   string
+		target *bool
+	}{{"dry_run", &opts.DryRun}, {"async", &async}} {
+		if v := c.Query(param.name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   "validation error",
+					"message": fmt.Sprintf("%s must be true or false", param.name),
+				})
+			}
+			*param.target = b
+		}
+	}
+	if tz := c.Query("tz"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "validation error",
+				"message": "tz must be an IANA time zone, e.g. Europe/Berlin",
+			})
+		}
+		opts.Location = loc
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "multipart field \"file\" is required",
+		})
+	}
+	if mapping := c.FormValue("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opts.Mapping); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "validation error",
+				"message": "mapping must be a JSON object of column names to task fields",
+			})
+		}
+	}
+	format, err := importFormat(c, file)
+	if err != nil {
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return importErrorResponse(c, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return importErrorResponse(c, err)
+	}
+
+	if async {
+		job, err := h.Service.StartImport(format, data, opts)
+		if err != nil {
+			return importErrorResponse(c, err)
+		}
+		c.Location(c.Path() + "/" + job.ID)
+		return c.Status(fiber.StatusAccepted).JSON(job)
+	}
+	result, err := h.Service.Import(format, data, opts)
+	if err != nil {
+		return importErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// importFormat bestimmt das Format einer Importdatei aus format=, der Dateiendung oder dem Content-Type.
+func importFormat(c *fiber.Ctx, file *multipart.FileHeader) (export.Format, error) {
+	if name := c.Query("format"); name != "" {
+		if !importer.Supported(export.Format(name)) {
+			return "", fmt.Errorf("format must be one of: csv, ndjson")
+		}
+		return export.Format(name), nil
+	}
+	if f, ok := importExtensions[strings.ToLower(filepath.Ext(file.Filename))]; ok {
+		return f, nil
+	}
+	contentType, _, _ := strings.Cut(file.Header.Get(fiber.HeaderContentType), ";")
+	for _, f := range importer.Formats {
+		if strings.TrimSpace(contentType) == f.MediaType() {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("file format is not recognized; set format=csv or format=ndjson")
+}
+
+// importErrorResponse übersetzt Fehler aus dem ImportService in HTTP-Status und Antwort-Body.
+func importErrorResponse(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "not found",
+			"message": fmt.Sprintf("Import job %s not found", c.Params("jobId")),
+		})
+	case "too many rows":
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "file has too many rows for a synchronous import; use async=true",
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "validation error",
+		"message": err.Error(),
+	})
+}
+
+// GetImportJob verarbeitet GET /tasks/import/:jobId und liefert Status und Fortschritt eines Jobs.
+//
+// Antwort:
+//
+//	200 - Job (JSON)
+//	404 - Job existiert nicht oder ist abgelaufen
+func (h *ImportHandler) GetImportJob(c *fiber.Ctx) error {
+	job, err := h.Service.GetImportJob(c.Params("jobId"))
+	if err != nil {
+		return importErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(job)
+}
+
+// GetImportErrors verarbeitet GET /tasks/import/:jobId/errors und liefert den Fehlerbericht eines Jobs mit den
+// Spalten row, field und message. Das Format wird wie beim Export bestimmt (Default CSV); während der Job
+// läuft, enthält der Bericht die bisher gefundenen Fehler.
+//
+// Antwort:
+//
+//	200 - Fehlerbericht als Datei
+//	400 - Ungültiges format=
+//	404 - Job existiert nicht oder ist abgelaufen
+//	406 - Kein unterstütztes Format im Accept-Header
+func (h *ImportHandler) GetImportErrors(c *fiber.Ctx) error {
+	format, ok, err := exportFormat(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	}
+	if !ok {
+		return c.Status(fiber.StatusNotAcceptable).JSON(fiber.Map{
+			"error":   "not acceptable",
+			"message": "Error report is available as text/csv, application/x-ndjson or XLSX",
+		})
+	}
+
+	rowErrs, err := h.Service.GetImportErrors(c.Params("jobId"))
+	if err != nil {
+		return importErrorResponse(c, err)
+	}
+
+	var buf bytes.Buffer
+	out, err := export.NewWriter(format, &buf, []string{"row", "field", "message"})
+	if err != nil {
+		return importErrorResponse(c, err)
+	}
+	for _, e := range rowErrs {
+		if err := out.WriteRow([]any{e.Row, e.Field, e.Message}); err != nil {
+			return importErrorResponse(c, err)
+		}
+	}
+	if err := out.Close(); err != nil {
+		return importErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="import-%s-errors.%s"`, c.Params("jobId"), format))
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
@@ -0,0 +1,173 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/repository"
+	"task-api/services"
+	"testing"
+	"time"
+)
+
+// importJobRepo liefert ein MockImportJobRepository, das Jobs und Fehlerberichte in Maps speichert.
+func importJobRepo() *repository.MockImportJobRepository {
+	var mu sync.Mutex
+	jobs := map[string]models.ImportJob{}
+	rowErrors := map[string][]models.ImportRowError{}
+	save := func(job *models.ImportJob, errs []models.ImportRowError) error {
+		mu.Lock()
+		defer mu.Unlock()
+		job.UpdatedAt = time.Now()
+		jobs[job.ID] = *job
+		rowErrors[job.ID] = append(rowErrors[job.ID], errs...)
+		return nil
+	}
+	return &repository.MockImportJobRepository{
+		CreateFunc: func(job *models.ImportJob) error { return save(job, nil) },
+		UpdateFunc: save,
+		GetByIdFunc: func(id string) (*models.ImportJob, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if job, ok := jobs[id]; ok {
+				return &job, nil
+			}
+			return nil, nil
+		},
+		GetErrorsFunc: func(id string) ([]models.ImportRowError, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]models.ImportRowError{}, rowErrors[id]...), nil
+		},
+		DeleteExpiredFunc: func(time.Time) (int, error) { return 0, nil },
+	}
+}
+
+// setupImportHandler initialisiert einen Fiber-App-Server mit den Import-Routen über einem MockTaskService.
+func setupImportHandler(maxSyncRows int) *fiber.App {
+	app := fiber.New()
+	handler := handlers.ImportHandler{Service: &services.ImportService{Tasks: &services.MockTaskService{}, Repo: importJobRepo(), MaxSyncRows: maxSyncRows}}
+	app.Post("/tasks/import", handler.ImportTasks)
+	app.Get("/tasks/import/:jobId", handler.GetImportJob)
+	app.Get("/tasks/import/:jobId/errors", handler.GetImportErrors)
+	return app
+}
+
+// importRequest erstellt einen multipart-Upload mit der Datei im Feld "file" und optionalem Mapping.
+func importRequest(url, filename, content, mapping string) *http.Request {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, _ := w.CreateFormFile("file", filename)
+	part.Write([]byte(content))
+	if mapping != "" {
+		w.WriteField("mapping", mapping)
+	}
+	w.Close()
+
+	req := httptest.NewRequest("POST", url, &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+// readJSON liest den Body einer Antwort in v.
+func readJSON(t *testing.T, resp *http.Response, v any) {
+	data, _ := io.ReadAll(resp.Body)
+	assert.NoError(t, json.Unmarshal(data, v), string(data))
+}
+
+const importFile = "Summary,Prio\nRelease,high\n,low\nDocs,urgent\n"
+
+// Test_ImportTasks_Handler_Sync prüft den synchronen Import mit Mapping und Fehlern je Zeile.
+func Test_ImportTasks_Handler_Sync(t *testing.T) {
+	app := setupImportHandler(0)
+
+	resp, err := app.Test(importRequest("/tasks/import", "tasks.csv", importFile, `{"Summary": "title", "Prio": "priority"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result models.ImportResult
+	readJSON(t, resp, &result)
+	assert.Equal(t, models.ImportSummary{Rows: 3, Valid: 1, Created: 1, Failed: 2}, result.ImportSummary)
+	assert.Equal(t, []models.ImportRowError{
+		{Row: 3, Field: "title", Message: "is required"},
+		{Row: 4, Field: "priority", Message: "must be one of: low, medium, high"},
+	}, result.Errors)
+}
+
+// Test_ImportTasks_Handler_DryRunNDJSON prüft den Dry-Run und die Formaterkennung über format=.
+func Test_ImportTasks_Handler_DryRunNDJSON(t *testing.T) {
+	app := setupImportHandler(0)
+
+	resp, _ := app.Test(importRequest("/tasks/import?dry_run=true&format=ndjson", "export.txt", `{"title": "Release"}`, ""))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result models.ImportResult
+	readJSON(t, resp, &result)
+	assert.Equal(t, models.ImportSummary{DryRun: true, Rows: 1, Valid: 1}, result.ImportSummary)
+	assert.Empty(t, result.Errors)
+}
+
+// Test_ImportTasks_Handler_Async prüft Job-Start, Fortschritt und den Fehlerbericht als CSV.
+func Test_ImportTasks_Handler_Async(t *testing.T) {
+	app := setupImportHandler(1)
+
+	// Zu groß für einen synchronen Import
+	resp, _ := app.Test(importRequest("/tasks/import", "tasks.csv", importFile, `{"Summary": "title", "Prio": "priority"}`))
+	assert.Equal(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	resp, _ = app.Test(importRequest("/tasks/import?async=true", "tasks.csv", importFile, `{"Summary": "title", "Prio": "priority"}`))
+	assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+	var job models.ImportJob
+	readJSON(t, resp, &job)
+	assert.Equal(t, "/tasks/import/"+job.ID, resp.Header.Get("Location"))
+	assert.Equal(t, 3, job.TotalRows)
+
+	assert.Eventually(t, func() bool {
+		resp, _ := app.Test(httptest.NewRequest("GET", "/tasks/import/"+job.ID, nil))
+		readJSON(t, resp, &job)
+		return job.Status == models.ImportJobCompleted
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 100, job.Progress)
+	assert.Equal(t, 2, job.Failed)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/tasks/import/"+job.ID+"/errors", nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	report, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "row,field,message\n3,title,is required\n4,priority,\"must be one of: low, medium, high\"\n", string(report))
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/tasks/import/unknown", nil))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test_ImportTasks_Handler_InvalidRequests prüft fehlende Datei, unbekanntes Format, Mapping und Parameter.
+func Test_ImportTasks_Handler_InvalidRequests(t *testing.T) {
+	app := setupImportHandler(0)
+
+	req := httptest.NewRequest("POST", "/tasks/import", strings.NewReader("title\nRelease\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	resp, _ := app.Test(req)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = app.Test(importRequest("/tasks/import", "tasks.xlsx", "title\n", ""))
+	assert.Equal(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+
+	for _, r := range []*http.Request{
+		importRequest("/tasks/import", "tasks.csv", "title\n", `["title"]`),
+		importRequest("/tasks/import", "tasks.csv", "title\n", `{"Summary": "owner"}`),
+		importRequest("/tasks/import?dry_run=maybe", "tasks.csv", "title\n", ""),
+		importRequest("/tasks/import", "tasks.csv", "", ""),
+	} {
+		resp, _ = app.Test(r)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, r.URL.String())
+	}
+}
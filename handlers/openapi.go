@@ -0,0 +1,587 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"maps"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OpenAPIHandler liefert die OpenAPI-Spezifikation der REST-API und eine interaktive Dokumentation.
+// Die Spezifikation wird beim ersten Aufruf aus der Routentabelle (openapi_routes.go) und den
+// Models erzeugt, sodass Feldnamen und Typen immer den tatsächlichen JSON-Antworten entsprechen.
+type OpenAPIHandler struct {
+	once sync.Once
+	spec []byte
+}
+
+// Spec verarbeitet GET /openapi.json.
+func (h *OpenAPIHandler) Spec(c *fiber.Ctx) error {
+	h.once.Do(func() { h.spec, _ = json.Marshal(OpenAPISpec()) })
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+	return c.Send(h.spec)
+}
+
+// Docs verarbeitet GET /docs und liefert eine HTML-Seite mit Swagger UI für /openapi.json.
+func (h *OpenAPIHandler) Docs(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(docsPage)
+}
+
+// docsPage lädt Swagger UI aus dem CDN und zeigt damit /openapi.json an.
+const docsPage = `<!DOCTYPE html>
+<html lang="de">
+<head>
+  <meta charset="utf-8">
+  <title>Task API – Dokumentation</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui", persistAuthorization: true });
+  </script>
+</body>
+</html>
+`
+
+// fiberParam erkennt Pfadparameter im Fiber-Format (":id").
+var fiberParam = regexp.MustCompile(`:([A-Za-z][A-Za-z0-9_]*)`)
+
+// OpenAPIPath wandelt einen Fiber-Pfad ("/tasks/:id") in einen OpenAPI-Pfad ("/tasks/{id}") um.
+func OpenAPIPath(path string) string {
+	return fiberParam.ReplaceAllString(path, "{$1}")
+}
+
+// OpenAPISpec erzeugt die OpenAPI-3.1-Spezifikation aller Routen aus apiOperations.
+func OpenAPISpec() map[string]any {
+	b := &schemaBuilder{schemas: map[string]any{}}
+	paths := map[string]map[string]any{}
+
+	add := func(path string, op apiOperation, version string) {
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(op.Method)] = b.operation(op, version)
+	}
+	for _, op := range apiOperations {
+		if op.Unversioned {
+			add(OpenAPIPath(op.Path), op, "")
+			continue
+		}
+		for _, version := range apiVersions {
+			if op.Version == "" || op.Version == version {
+				add("/"+version+OpenAPIPath(op.Path), op, version)
+			}
+		}
+	}
+
+	b.schemas["Error"] = map[string]any{
+		"type":     "object",
+		"required": []string{"error", "message"},
+		"properties": map[string]any{
+			"error":   map[string]any{"type": "string", "description": "Fehlerkategorie, z.B. \"validation error\" oder \"not found\""},
+			"message": map[string]any{"type": "string", "description": "Beschreibung des Fehlers"},
+			"errors":  fieldErrorsSchema,
+		},
+	}
+	b.schemas["ErrorV2"] = map[string]any{
+		"type":     "object",
+		"required": []string{"error"},
+		"properties": map[string]any{
+			"error": map[string]any{
+				"type":     "object",
+				"required": []string{"code", "message"},
+				"properties": map[string]any{
+					"code":    map[string]any{"type": "string", "description": "Fehlerkategorie in snake_case, z.B. \"validation_error\" oder \"not_found\""},
+					"message": map[string]any{"type": "string", "description": "Beschreibung des Fehlers"},
+					"fields":  fieldErrorsSchema,
+				},
+			},
+		},
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   "Task API",
+			"version": "2.0.0",
+			"description": "REST-API zur Verwaltung von Tasks inkl. Subtasks, Abhängigkeiten, Tags, Kommentaren, Anhängen und Webhooks. " +
+				"Alle Ressourcen liegen unter /v1 (veraltet) und /v2; ohne Versionspräfix entscheidet der Accept-Header " +
+				"(application/vnd.taskapi.v2+json), sonst gilt v1.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": b.schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "description": "API-Key als Bearer-Token"},
+				"apiKey":     map[string]any{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+		// Die Authentifizierung ist optional; Endpoints ohne Key arbeiten anonym
+		"security": []any{map[string]any{}, map[string]any{"bearerAuth": []string{}}, map[string]any{"apiKey": []string{}}},
+	}
+}
+
+// fieldErrorsSchema beschreibt die einzelnen Validierungsfehler einer Fehlerantwort.
+var fieldErrorsSchema = map[string]any{
+	"type":        "array",
+	"description": "Einzelne Validierungsfehler (nur bei Validierungsfehlern)",
+	"items": map[string]any{
+		"type":     "object",
+		"required": []string{"in", "pointer", "message"},
+		"properties": map[string]any{
+			"in":      map[string]any{"type": "string", "enum": []string{"path", "query", "body", "response"}},
+			"pointer": map[string]any{"type": "string", "description": "JSON Pointer im Body bzw. Name des Parameters"},
+			"message": map[string]any{"type": "string"},
+		},
+	},
+}
+
+// apiOperation beschreibt einen Endpoint für die OpenAPI-Spezifikation.
+type apiOperation struct {
+	Method  string     // HTTP-Methode, z.B. fiber.MethodGet
+	Path    string     // Pfad im Fiber-Format wie in routes.go, z.B. "/tasks/:id"
+	Tag     string     // Gruppe in der Dokumentation
+	Summary string     // Kurzbeschreibung
+	Query   []apiParam // Query-Parameter
+	Body    any        // Request-Body: Model-Wert (JSON), multipartFile oder nil
+	// Pflichtfelder des Bodys nur für diesen Endpoint (z.B. "title" beim Anlegen)
+	BodyRequired []string
+	BodyOptional bool        // Body darf fehlen
+	Status       int         // Status der erfolgreichen Antwort
+	Result       any         // Antwort: Model-Wert, listOf, rawSchema, contentType(s) oder nil (kein Body)
+	MoreResults  map[int]any // Weitere erfolgreiche Antworten (Status -> Body wie Result), z.B. 202 bei Jobs
+	Errors       []int       // Mögliche Fehlerstatus mit Body {"error", "message"}
+	ErrorResult  any         // Schema der Fehlerantworten, falls abweichend vom Schema Error
+	Version      string      // Nur in dieser API-Version (APIVersion1, APIVersion2); leer = in allen Versionen
+	Unversioned  bool        // Route ohne Versionspräfix (Echtzeit, GraphQL, Kalender, Dokumentation)
+}
+
+// apiParam beschreibt einen Query-Parameter.
+type apiParam struct {
+	Name        string
+	Type        string   // JSON-Schema-Typ, z.B. "string" oder "boolean"
+	Format      string   // Optional, z.B. "date-time"
+	Enum        []string // Optional: erlaubte Werte
+	Description string
+}
+
+// listOf beschreibt eine Listenantwort der Form {"<Key>": [Item...], "total": n}.
+type listOf struct {
+	Key  string
+	Item any
+}
+
+// rawSchema ist ein direkt angegebenes JSON-Schema.
+type rawSchema map[string]any
+
+// objectOf beschreibt ein Objekt, dessen Eigenschaften (alle vorhanden) die Typen der angegebenen Werte haben.
+type objectOf map[string]any
+
+// sparseOf beschreibt ein Model, dessen Felder per fields= ausgewählt werden: Alle Felder außer Required sind
+// optional, Relations ergänzt bzw. ersetzt Eigenschaften für per include= eingebettete Beziehungen.
+// Renamed enthält Eigenschaften, die unter einem anderen (veralteten) Namen ausgegeben werden.
+// Das Schema wird als Komponente Name abgelegt, damit Relations rekursiv darauf verweisen können.
+type sparseOf struct {
+	Name        string
+	Description string
+	Model       any
+	Required    []string
+	Relations   map[string]any
+	Renamed     map[string]string
+}
+
+// contentType beschreibt eine Antwort, die kein JSON ist (z.B. Dateien oder Event-Streams).
+type contentType string
+
+// contentTypes beschreibt eine Antwort, die per Aushandlung in einem von mehreren Formaten geliefert wird.
+type contentTypes []string
+
+// multipartFile beschreibt einen multipart/form-data-Request mit einer Datei im Feld Field und optionalen
+// Textfeldern Fields.
+type multipartFile struct {
+	Field  string
+	Fields []apiParam
+}
+
+// stringPathParams sind die Pfad-Parameter, die keine numerische ID sind, mit ihrer Beschreibung.
+var stringPathParams = map[string]string{
+	"userId": "Benutzer-ID oder \"me\"",
+	"jobId":  "ID des Import-Jobs",
+}
+
+// operation erzeugt das Operation-Objekt eines Endpoints in der API-Version version ("" bei Routen
+// ohne Version). In v2 werden Antworten in {"data", "meta"} bzw. {"error"} verpackt (siehe EnvelopeResponses).
+func (b *schemaBuilder) operation(op apiOperation, version string) map[string]any {
+	result := map[string]any{
+		"summary":     op.Summary,
+		"operationId": operationID(op),
+		"tags":        []string{op.Tag},
+	}
+	if version != "" {
+		result["operationId"] = version + "_" + operationID(op)
+	}
+	if version == APIVersion1 {
+		result["deprecated"] = true
+	}
+
+	var params []any
+	for _, m := range fiberParam.FindAllStringSubmatch(op.Path, -1) {
+		param := map[string]any{"name": m[1], "in": "path", "required": true, "schema": map[string]any{"type": "integer"}}
+		if description, ok := stringPathParams[m[1]]; ok {
+			param["schema"] = map[string]any{"type": "string"}
+			param["description"] = description
+		}
+		params = append(params, param)
+	}
+	for _, p := range op.Query {
+		schema := map[string]any{"type": p.Type}
+		if p.Format != "" {
+			schema["format"] = p.Format
+		}
+		if len(p.Enum) > 0 {
+			schema["enum"] = p.Enum
+		}
+		params = append(params, map[string]any{"name": p.Name, "in": "query", "description": p.Description, "schema": schema})
+	}
+	if len(params) > 0 {
+		result["parameters"] = params
+	}
+
+	switch body := op.Body.(type) {
+	case nil:
+	case multipartFile:
+		properties := map[string]any{body.Field: map[string]any{"type": "string", "format": "binary"}}
+		for _, f := range body.Fields {
+			properties[f.Name] = map[string]any{"type": f.Type, "description": f.Description}
+		}
+		result["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{"multipart/form-data": map[string]any{"schema": map[string]any{
+				"type":       "object",
+				"required":   []string{body.Field},
+				"properties": properties,
+			}}},
+		}
+	default:
+		schema := b.schemaFor(body)
+		if len(op.BodyRequired) > 0 {
+			schema = map[string]any{"$ref": schema["$ref"], "required": op.BodyRequired}
+		}
+		result["requestBody"] = map[string]any{
+			"required": !op.BodyOptional,
+			"content":  map[string]any{"application/json": map[string]any{"schema": schema}},
+		}
+	}
+
+	responses := map[string]any{}
+	responses[strconv.Itoa(op.Status)] = b.success(op.Status, op.Result, version)
+	for status, r := range op.MoreResults {
+		responses[strconv.Itoa(status)] = b.success(status, r, version)
+	}
+	errs := op.Errors
+	// Die Middleware ValidateRequests antwortet bei ungültigen Parametern oder Bodies mit 400
+	if _, ok := result["requestBody"]; (ok || len(params) > 0) && !slices.Contains(errs, fiber.StatusBadRequest) {
+		errs = append([]int{fiber.StatusBadRequest}, errs...)
+	}
+	errorSchema := map[string]any{"$ref": "#/components/schemas/Error"}
+	if op.ErrorResult != nil {
+		errorSchema = b.schemaFor(op.ErrorResult)
+	} else if version == APIVersion2 {
+		errorSchema = map[string]any{"$ref": "#/components/schemas/ErrorV2"}
+	}
+	for _, status := range errs {
+		responses[strconv.Itoa(status)] = map[string]any{
+			"description": http.StatusText(status),
+			"content":     map[string]any{"application/json": map[string]any{"schema": errorSchema}},
+		}
+	}
+	result["responses"] = responses
+	return result
+}
+
+// success erzeugt das Response-Objekt einer erfolgreichen Antwort mit dem Status status und dem Body result.
+func (b *schemaBuilder) success(status int, result any, version string) map[string]any {
+	success := map[string]any{"description": http.StatusText(status)}
+	switch r := result.(type) {
+	case nil:
+	case contentType:
+		success["content"] = map[string]any{string(r): map[string]any{"schema": map[string]any{"type": "string"}}}
+	case contentTypes:
+		content := map[string]any{}
+		for _, t := range r {
+			content[t] = map[string]any{"schema": map[string]any{"type": "string"}}
+		}
+		success["content"] = content
+	default:
+		schema := b.schemaFor(r)
+		if version == APIVersion2 {
+			schema = envelope(r, schema)
+		}
+		success["content"] = map[string]any{"application/json": map[string]any{"schema": schema}}
+	}
+	return success
+}
+
+// envelope verpackt das Schema einer Antwort in die Darstellung von v2: Listen als
+// {"data": [...], "meta": {"total": n}}, alle anderen Antworten als {"data": ...}.
+func envelope(result any, schema map[string]any) map[string]any {
+	if list, ok := result.(listOf); ok {
+		properties := schema["properties"].(map[string]any)
+		return map[string]any{
+			"type":     "object",
+			"required": []string{"data", "meta"},
+			"properties": map[string]any{
+				"data": properties[list.Key],
+				"meta": map[string]any{
+					"type":       "object",
+					"required":   []string{"total"},
+					"properties": map[string]any{"total": properties["total"]},
+				},
+			},
+		}
+	}
+	return map[string]any{"type": "object", "required": []string{"data"}, "properties": map[string]any{"data": schema}}
+}
+
+// operationID bildet eine eindeutige ID aus Methode und Pfad, z.B. "get_tasks_id_subtasks".
+func operationID(op apiOperation) string {
+	parts := []string{strings.ToLower(op.Method)}
+	for _, seg := range strings.Split(op.Path, "/") {
+		seg = strings.TrimPrefix(seg, ":")
+		seg = strings.NewReplacer("-", "_", ".", "_").Replace(seg)
+		if seg != "" {
+			parts = append(parts, seg)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// schemaBuilder erzeugt JSON-Schemas (Draft 2020-12, wie von OpenAPI 3.1 verwendet) aus Go-Typen.
+// Structs werden als Komponenten unter ihrem Typnamen abgelegt und per $ref referenziert.
+type schemaBuilder struct {
+	schemas map[string]any
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor erzeugt das Schema für den Wert v (Model, listOf oder rawSchema).
+func (b *schemaBuilder) schemaFor(v any) map[string]any {
+	switch v := v.(type) {
+	case rawSchema:
+		return v
+	case objectOf:
+		properties := map[string]any{}
+		required := []string{}
+		for name, value := range v {
+			properties[name] = b.schemaFor(value)
+			required = append(required, name)
+		}
+		sort.Strings(required)
+		return map[string]any{"type": "object", "required": required, "properties": properties}
+	case sparseOf:
+		if _, ok := b.schemas[v.Name]; !ok {
+			ref := b.schemaFor(v.Model)["$ref"].(string)
+			model := b.schemas[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]any)
+			b.schemas[v.Name] = nil // Platzhalter für rekursive Beziehungen
+			properties := maps.Clone(model["properties"].(map[string]any))
+			for name, relation := range v.Relations {
+				properties[name] = b.schemaFor(relation)
+			}
+			for name, legacy := range v.Renamed {
+				property := maps.Clone(properties[name].(map[string]any))
+				property["deprecated"] = true
+				delete(properties, name)
+				properties[legacy] = property
+			}
+			b.schemas[v.Name] = map[string]any{
+				"type":        "object",
+				"description": v.Description,
+				"required":    v.Required,
+				"properties":  properties,
+			}
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + v.Name}
+	case listOf:
+		return map[string]any{
+			"type":     "object",
+			"required": []string{v.Key, "total"},
+			"properties": map[string]any{
+				v.Key:   map[string]any{"type": "array", "items": b.schemaFor(v.Item)},
+				"total": map[string]any{"type": "integer"},
+			},
+		}
+	}
+	return b.schema(reflect.TypeOf(v))
+}
+
+// schema erzeugt das Schema für den Typ t.
+func (b *schemaBuilder) schema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return nullable(b.schema(t.Elem()))
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == reflect.TypeOf(json.RawMessage{}):
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		return b.ref(t)
+	}
+	return map[string]any{}
+}
+
+// ref legt das Schema eines Structs als Komponente ab und gibt eine Referenz darauf zurück.
+func (b *schemaBuilder) ref(t reflect.Type) map[string]any {
+	name := t.Name()
+	if _, ok := b.schemas[name]; !ok {
+		b.schemas[name] = nil // Platzhalter für rekursive Typen (z.B. TaskNode)
+		properties := map[string]any{}
+		required := []string{}
+		b.addFields(t, properties, &required)
+		schema := map[string]any{"type": "object", "properties": properties}
+		if req, ok := requestRequired[name]; ok {
+			required = slices.Clone(req)
+		}
+		for prop, constraints := range schemaConstraints[name] {
+			merged := maps.Clone(properties[prop].(map[string]any))
+			maps.Copy(merged, constraints)
+			properties[prop] = merged
+		}
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+		b.schemas[name] = schema
+	}
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// addFields trägt die JSON-Felder von t in properties ein. Eingebettete Structs werden wie bei
+// encoding/json flach übernommen, Felder der äußeren Ebene haben Vorrang. Felder ohne "omitempty"
+// sind in Antworten immer vorhanden und werden als required markiert.
+func (b *schemaBuilder) addFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = tagConstraints(b.schema(f.Type), f)
+		if !strings.Contains(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.Anonymous {
+			continue
+		}
+		embedded := f.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		inner := map[string]any{}
+		var innerRequired []string
+		b.addFields(embedded, inner, &innerRequired)
+		for name, schema := range inner {
+			if _, ok := properties[name]; !ok {
+				properties[name] = schema
+			}
+		}
+		for _, name := range innerRequired {
+			if !slices.Contains(*required, name) {
+				*required = append(*required, name)
+			}
+		}
+	}
+}
+
+// tagConstraints übersetzt die Regeln im Tag `validate` (siehe Paket validation) in JSON-Schema-Schlüsselwörter,
+// damit Spezifikation und Services dieselben Grenzen verwenden.
+func tagConstraints(schema map[string]any, f reflect.StructField) map[string]any {
+	tag := f.Tag.Get("validate")
+	if tag == "" {
+		return schema
+	}
+	kind := f.Type.Kind()
+	if kind == reflect.Pointer {
+		kind = f.Type.Elem().Kind()
+	}
+	rules := strings.Split(tag, ",")
+
+	schema = maps.Clone(schema)
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "min", "max":
+			n, _ := strconv.Atoi(arg)
+			key := map[string]string{"min": "minimum", "max": "maximum"}[name]
+			switch kind {
+			case reflect.String:
+				key = map[string]string{"min": "minLength", "max": "maxLength"}[name]
+			case reflect.Slice:
+				key = map[string]string{"min": "minItems", "max": "maxItems"}[name]
+			}
+			schema[key] = n
+		case "oneof":
+			enum := strings.Fields(arg)
+			if !slices.Contains(rules, "required") {
+				enum = append(enum, "") // Leere Werte sind erlaubt und bedeuten "nicht gesetzt"
+			}
+			schema["enum"] = enum
+		case "format":
+			schema["format"] = arg
+		case "required":
+			if kind == reflect.String {
+				schema["minLength"] = 1
+			}
+		}
+	}
+	return schema
+}
+
+// nullable erweitert ein Schema um den Wert null.
+func nullable(schema map[string]any) map[string]any {
+	if typ, ok := schema["type"].(string); ok {
+		out := map[string]any{}
+		for k, v := range schema {
+			out[k] = v
+		}
+		out["type"] = []string{typ, "null"}
+		return out
+	}
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+}
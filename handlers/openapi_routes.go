@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"slices"
+	"strings"
+	"task-api/export"
+	"task-api/models"
+)
+
+// Pflichtfelder der Request-Bodies. Request-Models werden für Anlegen und Ändern gemeinsam verwendet,
+// daher lassen sich Pflichtfelder nicht aus den JSON-Tags ableiten; Typen ohne Eintrag haben keine.
+var requestRequired = map[string][]string{
+	"CreateTaskRequest":    {},
+	"AddDependencyRequest": {"depends_on_id"},
+	"AttachTagRequest":     {},
+	"TagRequest":           {"name"},
+	"MergeTagsRequest":     {"into_id"},
+	"UserRequest":          {},
+	"CreateUserRequest":    {"email", "name"},
+	"CommentRequest":       {"body"},
+	"WebhookRequest":       {},
+}
+
+// Einschränkungen einzelner Felder (JSON Schema) je Model, z.B. Längen und erlaubte Werte, für Models
+// ohne Tag `validate` (dessen Regeln übernimmt tagConstraints). Längen zählen Zeichen, nicht Bytes.
+// Der Status hängt vom Workflow ab und wird vom TaskService geprüft.
+var schemaConstraints = map[string]map[string]rawSchema{
+	"AddDependencyRequest": {"depends_on_id": {"minimum": 1}},
+	"AttachTagRequest":     {"tag_id": {"minimum": 0}, "name": {"maxLength": 50}},
+	"TagRequest":           {"name": {"minLength": 1, "maxLength": 50}},
+	"MergeTagsRequest":     {"into_id": {"minimum": 1}},
+	"UserRequest":          {"user_id": {"minimum": 0}},
+	"CreateUserRequest":    {"name": {"minLength": 1, "maxLength": 100}, "email": {"format": "email"}},
+	"CommentRequest":       {"body": {"minLength": 1, "maxLength": 10000}},
+	"WebhookRequest": {
+		"url":    {"format": "uri"},
+		"events": {"items": map[string]any{"type": "string", "enum": models.WebhookEvents}},
+	},
+}
+
+// taskListFields sind die Felder eines Tasks in der Antwort von GET /v1/tasks ohne fields= ("created_at" wird
+// dort als "created at" ausgegeben, siehe legacyTaskKeys).
+var taskListFields = []string{"id", "title", "status", "priority", "parent_id", "due_at", "is_overdue", "tags", "assignees", "created_at"}
+
+// Query-Parameter der Task-Filter (GET /tasks, siehe parseTaskFilter).
+var taskFilterParams = []apiParam{
+	{Name: "overdue", Type: "boolean", Description: "Nur überfällige (true) bzw. nicht überfällige (false) Tasks"},
+	{Name: "due_before", Type: "string", Description: "Fälligkeit vor diesem Zeitpunkt (RFC 3339 oder YYYY-MM-DD)"},
+	{Name: "due_after", Type: "string", Description: "Fälligkeit nach diesem Zeitpunkt (RFC 3339 oder YYYY-MM-DD)"},
+	{Name: "tz", Type: "string", Description: "Zeitzone für Datumsangaben ohne Uhrzeit, z.B. Europe/Berlin"},
+	{Name: "tags_any", Type: "string", Description: "Kommagetrennt: mindestens einer der Tags"},
+	{Name: "tags_all", Type: "string", Description: "Kommagetrennt: alle Tags"},
+	{Name: "tags_none", Type: "string", Description: "Kommagetrennt: keiner der Tags"},
+	{Name: "assignee", Type: "string", Description: "Benutzer-ID oder \"me\""},
+	{Name: "watcher", Type: "string", Description: "Benutzer-ID oder \"me\""},
+	{Name: "fields", Type: "string", Description: "Kommagetrennt: nur diese Felder laden und ausgeben (" + strings.Join(models.TaskFields, ", ") + ")"},
+	{Name: "include", Type: "string", Description: "Kommagetrennt: eingebettete Beziehungen (" + strings.Join(models.TaskIncludes, ", ") + ")"},
+}
+
+// taskListParams sind die Query-Parameter von GET /tasks: Filter und Ausschnitt der Liste.
+var taskListParams = append(slices.Clone(taskFilterParams),
+	apiParam{Name: "limit", Type: "integer", Description: fmt.Sprintf("Höchstens so viele Tasks (1 bis %d); total zählt weiterhin alle passenden Tasks", maxTaskPageSize)},
+	apiParam{Name: "offset", Type: "integer", Description: "So viele Tasks am Anfang überspringen"},
+)
+
+// exportParams sind die Query-Parameter von GET /tasks/export: Filter und fields= wie bei GET /tasks
+// (ohne include=) sowie das Format.
+var exportParams = append(
+	slices.DeleteFunc(slices.Clone(taskFilterParams), func(p apiParam) bool { return p.Name == "include" }),
+	apiParam{Name: "format", Type: "string", Enum: exportFormatNames(), Description: "Exportformat; ohne Angabe aus dem Accept-Header, sonst csv"},
+)
+
+// exportResult sind die Formate, in denen GET /tasks/export antwortet.
+var exportResult = func() contentTypes {
+	types := make(contentTypes, len(export.Formats))
+	for i, f := range export.Formats {
+		types[i] = f.MediaType()
+	}
+	return types
+}()
+
+// calendarParams sind die Query-Parameter von GET /calendar.ics: Kalender-Token, Darstellung und die
+// Filter von GET /tasks (ohne fields= und include=).
+var calendarParams = append([]apiParam{
+	{Name: "token", Type: "string", Description: "Kalender-Token aus POST /users/me/calendar-token (alternativ API-Key im Header)"},
+	{Name: "type", Type: "string", Enum: []string{calendarTodo, calendarEvent}, Description: "Tasks als Aufgaben (VTODO, Default) oder Termine (VEVENT)"},
+}, slices.DeleteFunc(slices.Clone(taskFilterParams), func(p apiParam) bool { return p.Name == "fields" || p.Name == "include" })...)
+
+// taskViewSchema ist ein Task in der Antwort von GET /tasks: nur die per fields= gewählten Felder und die
+// per include= eingebetteten Beziehungen. include=tags und include=assignees ersetzen Namen bzw. IDs durch Objekte.
+var taskViewSchema = sparseOf{
+	Name:        "TaskView",
+	Description: "Task mit den per fields= gewählten Feldern (ohne fields=: alle Felder)",
+	Model:       models.Task{},
+	Required:    []string{"id"},
+	Relations:   taskViewRelations("TaskView"),
+}
+
+// taskViewV1Schema ist ein Task in der Antwort von GET /v1/tasks. Der Erstellungszeitpunkt heißt dort
+// "created at" und ist als veraltet markiert.
+var taskViewV1Schema = sparseOf{
+	Name:        "TaskViewV1",
+	Description: "Task mit den per fields= gewählten Feldern (ohne fields=: " + strings.Join(taskListFields, ", ") + "); created_at heißt \"created at\"",
+	Model:       models.Task{},
+	Required:    []string{"id"},
+	Relations:   taskViewRelations("TaskViewV1"),
+	Renamed:     legacyTaskKeys,
+}
+
+// taskViewRelations sind die per include= eingebetteten Beziehungen; Subtasks verweisen auf das Schema name.
+func taskViewRelations(name string) map[string]any {
+	return map[string]any{
+		"subtasks":       rawSchema{"type": "array", "items": map[string]any{"$ref": "#/components/schemas/" + name}},
+		"tags":           rawSchema{"type": "array", "items": map[string]any{"anyOf": []any{map[string]any{"type": "string"}, map[string]any{"$ref": "#/components/schemas/Tag"}}}},
+		"assignees":      rawSchema{"type": "array", "items": map[string]any{"anyOf": []any{map[string]any{"type": "integer"}, map[string]any{"$ref": "#/components/schemas/User"}}}},
+		"comments_count": rawSchema{"type": "integer"},
+	}
+}
+
+// graphQLRequestSchema ist der Request-Body von POST /graphql.
+var graphQLRequestSchema = rawSchema{
+	"type":     "object",
+	"required": []string{"query"},
+	"properties": map[string]any{
+		"query":         map[string]any{"type": "string"},
+		"operationName": map[string]any{"type": "string"},
+		"variables":     map[string]any{"type": "object"},
+	},
+}
+
+// graphQLResponseSchema ist die Antwort von /graphql.
+var graphQLResponseSchema = rawSchema{
+	"type": "object",
+	"properties": map[string]any{
+		"data":   map[string]any{"type": []string{"object", "null"}},
+		"errors": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+	},
+}
+
+// graphQLErrorSchema beschreibt Fehlerantworten von /graphql: Fehler der Anfrage im GraphQL-Format,
+// ungültige Parameter oder Bodies (ValidateRequests) im Format Error.
+var graphQLErrorSchema = rawSchema{
+	"anyOf": []any{
+		map[string]any{"type": "object", "required": []string{"errors"}, "properties": graphQLResponseSchema["properties"]},
+		map[string]any{"$ref": "#/components/schemas/Error"},
+	},
+}
+
+// apiOperations beschreibt alle Routen aus routes.go für die OpenAPI-Spezifikation.
+// Neue Routen müssen hier ergänzt werden, sonst schlägt routes_test.go fehl.
+var apiOperations = []apiOperation{
+	// Tasks
+	{Method: fiber.MethodPost, Path: "/tasks", Tag: "Tasks", Summary: "Task erstellen",
+		Body: models.CreateTaskRequest{}, BodyRequired: []string{"title"}, Status: fiber.StatusCreated, Result: models.Task{}, Errors: []int{400}},
+	{Method: fiber.MethodGet, Path: "/tasks", Tag: "Tasks", Summary: "Tasks auflisten (ohne Beschreibung)", Query: taskListParams,
+		Status: fiber.StatusOK, Result: listOf{Key: "tasks", Item: taskViewV1Schema}, Errors: []int{400, 401},
+		Version: APIVersion1},
+	{Method: fiber.MethodGet, Path: "/tasks", Tag: "Tasks", Summary: "Tasks auflisten", Query: taskListParams,
+		Status: fiber.StatusOK, Result: listOf{Key: "tasks", Item: taskViewSchema}, Errors: []int{400, 401},
+		Version: APIVersion2},
+	{Method: fiber.MethodGet, Path: "/tasks/order", Tag: "Abhängigkeiten", Summary: "Tasks in Abhängigkeits-Reihenfolge",
+		Status: fiber.StatusOK, Result: listOf{Key: "tasks", Item: models.Task{}}, Errors: []int{400}},
+	{Method: fiber.MethodGet, Path: "/tasks/export", Tag: "Tasks", Summary: "Tasks als CSV, NDJSON oder XLSX exportieren",
+		Query: exportParams, Status: fiber.StatusOK, Result: exportResult, Errors: []int{400, 401, 406, 500}},
+	{Method: fiber.MethodPost, Path: "/tasks/import", Tag: "Tasks", Summary: "Tasks aus CSV oder NDJSON importieren",
+		Query: []apiParam{
+			{Name: "format", Type: "string", Enum: []string{"csv", "ndjson"}, Description: "Format der Datei; ohne Angabe aus Dateiendung bzw. Content-Type"},
+			{Name: "dry_run", Type: "boolean", Description: "Zeilen nur prüfen, keine Tasks anlegen"},
+			{Name: "async", Type: "boolean", Description: "Als Job im Hintergrund importieren (für große Dateien)"},
+			{Name: "tz", Type: "string", Description: "Zeitzone für Datumsangaben ohne Uhrzeit, z.B. Europe/Berlin"},
+		},
+		Body: multipartFile{Field: "file", Fields: []apiParam{
+			{Name: "mapping", Type: "string", Description: "JSON-Objekt Spalte -> Task-Feld (" + strings.Join(models.ImportFields, ", ") + ")"},
+		}},
+		Status: fiber.StatusOK, Result: models.ImportResult{}, MoreResults: map[int]any{fiber.StatusAccepted: models.ImportJob{}},
+		Errors: []int{400, 401, 413, 415}},
+	{Method: fiber.MethodGet, Path: "/tasks/import/:jobId", Tag: "Tasks", Summary: "Status eines Import-Jobs",
+		Status: fiber.StatusOK, Result: models.ImportJob{}, Errors: []int{404}},
+	{Method: fiber.MethodGet, Path: "/tasks/import/:jobId/errors", Tag: "Tasks", Summary: "Fehlerbericht eines Import-Jobs",
+		Query:  []apiParam{{Name: "format", Type: "string", Enum: exportFormatNames(), Description: "Format des Berichts; ohne Angabe aus dem Accept-Header, sonst csv"}},
+		Status: fiber.StatusOK, Result: exportResult, Errors: []int{400, 404, 406}},
+	{Method: fiber.MethodGet, Path: "/tasks/events", Tag: "Echtzeit", Summary: "Server-Sent-Events-Stream der Task-Änderungen",
+		Query: []apiParam{
+			{Name: "status", Type: "string", Description: "Kommagetrennte Liste von Status"},
+			{Name: "tag", Type: "string", Description: "Kommagetrennte Liste von Tags"},
+			{Name: "assignee", Type: "string", Description: "Benutzer-ID oder \"me\""},
+			{Name: "last_event_id", Type: "string", Description: "Alternative zum Header Last-Event-ID"},
+		},
+		Status: fiber.StatusOK, Result: contentType("text/event-stream"), Errors: []int{400, 401}, Unversioned: true},
+	{Method: fiber.MethodGet, Path: "/calendar.ics", Tag: "Tasks", Summary: "iCalendar-Feed der Tasks mit Fälligkeit (If-None-Match)",
+		Query: calendarParams, Status: fiber.StatusOK, Result: contentType("text/calendar"), Errors: []int{400, 401}, Unversioned: true},
+	{Method: fiber.MethodGet, Path: "/ws", Tag: "Echtzeit", Summary: "WebSocket-API für Boards",
+		Query:  []apiParam{{Name: "api_key", Type: "string", Description: "API-Key, falls kein Header gesetzt werden kann"}},
+		Status: fiber.StatusSwitchingProtocols, Errors: []int{401, 426}, Unversioned: true},
+	{Method: fiber.MethodGet, Path: "/graphql", Tag: "GraphQL", Summary: "GraphQL-Abfrage (nur Queries)",
+		Query: []apiParam{
+			{Name: "query", Type: "string", Description: "GraphQL-Dokument"},
+			{Name: "operationName", Type: "string"},
+			{Name: "variables", Type: "string", Description: "Variablen als JSON-Objekt"},
+		},
+		Status: fiber.StatusOK, Result: graphQLResponseSchema, Errors: []int{400, 405}, ErrorResult: graphQLErrorSchema, Unversioned: true},
+	{Method: fiber.MethodPost, Path: "/graphql", Tag: "GraphQL", Summary: "GraphQL-Abfrage oder -Mutation",
+		Body: graphQLRequestSchema, Status: fiber.StatusOK, Result: graphQLResponseSchema, Errors: []int{400}, ErrorResult: graphQLErrorSchema, Unversioned: true},
+	{Method: fiber.MethodGet, Path: "/tasks/:id", Tag: "Tasks", Summary: "Task abrufen",
+		Status: fiber.StatusOK, Result: models.Task{}, Errors: []int{400, 404}},
+	{Method: fiber.MethodGet, Path: "/tasks/:id/subtasks", Tag: "Tasks", Summary: "Direkte Subtasks",
+		Status: fiber.StatusOK, Result: listOf{Key: "subtasks", Item: models.Task{}}, Errors: []int{400, 404}},
+	{Method: fiber.MethodGet, Path: "/tasks/:id/tree", Tag: "Tasks", Summary: "Task mit allen Subtasks als Baum",
+		Status: fiber.StatusOK, Result: models.TaskNode{}, Errors: []int{400, 404}},
+	{Method: fiber.MethodGet, Path: "/tasks/:id/dependencies", Tag: "Abhängigkeiten", Summary: "Tasks, von denen ein Task abhängt",
+		Status: fiber.StatusOK, Result: listOf{Key: "dependencies", Item: models.Task{}}, Errors: []int{400, 404}},
+	{Method: fiber.MethodPost, Path: "/tasks/:id/dependencies", Tag: "Abhängigkeiten", Summary: "Abhängigkeit anlegen",
+		Body: models.AddDependencyRequest{}, Status: fiber.StatusCreated, Result: objectOf{"task_id": 0, "depends_on_id": 0}, Errors: []int{400, 404, 409}},
+	{Method: fiber.MethodDelete, Path: "/tasks/:id/dependencies/:dependsOnId", Tag: "Abhängigkeiten", Summary: "Abhängigkeit entfernen",
+		Status: fiber.StatusNoContent, Errors: []int{400, 404}},
+	{Method: fiber.MethodPost, Path: "/tasks/:id/tags", Tag: "Tags", Summary: "Tag zuordnen (per ID oder Name)",
+		Body: models.AttachTagRequest{}, Status: fiber.StatusCreated, Result: models.Tag{}, Errors: []int{400, 404}},
+	{Method: fiber.MethodDelete, Path: "/tasks/:id/tags/:tagId", Tag: "Tags", Summary: "Tag-Zuordnung entfernen",
+		Status: fiber.StatusNoContent, Errors: []int{400, 404}},
+	{Method: fiber.MethodPost, Path: "/tasks/:id/assignees", Tag: "Benutzer", Summary: "Benutzer zuweisen",
+		Body: models.UserRequest{}, BodyRequired: []string{"user_id"}, Status: fiber.StatusCreated, Errors: []int{400, 404}},
+	{Method: fiber.MethodDelete, Path: "/tasks/:id/assignees/:userId", Tag: "Benutzer", Summary: "Zuweisung entfernen",
+		Status: fiber.StatusNoContent, Errors: []int{400, 401, 404}},
+	{Method: fiber.MethodPost, Path: "/tasks/:id/watchers", Tag: "Benutzer", Summary: "Task beobachten (Default: angemeldeter Benutzer)",
+		Body: models.UserRequest{}, BodyOptional: true, Status: fiber.StatusCreated, Errors: []int{400, 401, 404}},
+	{Method: fiber.MethodDelete, Path: "/tasks/:id/watchers/:userId", Tag: "Benutzer", Summary: "Beobachter entfernen",
+		Status: fiber.StatusNoContent, Errors: []int{400, 401, 404}},
+	{Method: fiber.MethodGet, Path: "/tasks/:id/comments", Tag: "Kommentare", Summary: "Kommentare eines Tasks",
+		Status: fiber.StatusOK, Result: listOf{Key: "comments", Item: models.Comment{}}, Errors: []int{400, 404}},
+	{Method: fiber.MethodPost, Path: "/tasks/:id/comments", Tag: "Kommentare", Summary: "Kommentar schreiben (Markdown, @Erwähnungen)",
+		Body: models.CommentRequest{}, Status: fiber.StatusCreated, Result: models.Comment{}, Errors: []int{400, 401, 404}},
+	{Method: fiber.MethodGet, Path: "/tasks/:id/attachments", Tag: "Anhänge", Summary: "Anhänge eines Tasks",
+		Status: fiber.StatusOK, Result: listOf{Key: "attachments", Item: models.Attachment{}}, Errors: []int{400, 404}},
+	{Method: fiber.MethodPost, Path: "/tasks/:id/attachments", Tag: "Anhänge", Summary: "Anhang hochladen",
+		Body: multipartFile{Field: "file"}, Status: fiber.StatusCreated, Result: models.Attachment{}, Errors: []int{400, 401, 404, 413, 415}},
+	{Method: fiber.MethodGet, Path: "/tasks/:id/history", Tag: "Kommentare", Summary: "Audit-Historie eines Tasks",
+		Status: fiber.StatusOK, Result: listOf{Key: "history", Item: models.HistoryEntry{}}, Errors: []int{400, 404}},
+	{Method: fiber.MethodPut, Path: "/tasks/:id", Tag: "Tasks", Summary: "Task aktualisieren (leere Felder bleiben unverändert)",
+		Body: models.CreateTaskRequest{}, Status: fiber.StatusOK, Result: models.Task{}, Errors: []int{400, 404, 409}},
+	{Method: fiber.MethodDelete, Path: "/tasks/:id", Tag: "Tasks", Summary: "Task löschen",
+		Status: fiber.StatusNoContent, Errors: []int{400, 404, 409}},
+
+	// Tags
+	{Method: fiber.MethodGet, Path: "/tags", Tag: "Tags", Summary: "Alle Tags",
+		Status: fiber.StatusOK, Result: listOf{Key: "tags", Item: models.Tag{}}, Errors: []int{400}},
+	{Method: fiber.MethodPost, Path: "/tags", Tag: "Tags", Summary: "Tag erstellen",
+		Body: models.TagRequest{}, Status: fiber.StatusCreated, Result: models.Tag{}, Errors: []int{400, 409}},
+	{Method: fiber.MethodPut, Path: "/tags/:id", Tag: "Tags", Summary: "Tag umbenennen",
+		Body: models.TagRequest{}, Status: fiber.StatusOK, Result: models.Tag{}, Errors: []int{400, 404, 409}},
+	{Method: fiber.MethodDelete, Path: "/tags/:id", Tag: "Tags", Summary: "Tag löschen",
+		Status: fiber.StatusNoContent, Errors: []int{400, 404}},
+	{Method: fiber.MethodPost, Path: "/tags/:id/merge", Tag: "Tags", Summary: "Tag in einen anderen überführen",
+		Body: models.MergeTagsRequest{}, Status: fiber.StatusOK, Result: models.Tag{}, Errors: []int{400, 404}},
+
+	// Kommentare
+	{Method: fiber.MethodPatch, Path: "/comments/:id", Tag: "Kommentare", Summary: "Eigenen Kommentar bearbeiten",
+		Body: models.CommentRequest{}, Status: fiber.StatusOK, Result: models.Comment{}, Errors: []int{400, 401, 403, 404}},
+	{Method: fiber.MethodDelete, Path: "/comments/:id", Tag: "Kommentare", Summary: "Eigenen Kommentar löschen",
+		Status: fiber.StatusNoContent, Errors: []int{400, 401, 403, 404}},
+	{Method: fiber.MethodGet, Path: "/comments/:id/revisions", Tag: "Kommentare", Summary: "Frühere Fassungen eines Kommentars",
+		Status: fiber.StatusOK, Result: listOf{Key: "revisions", Item: models.CommentRevision{}}, Errors: []int{400, 404}},
+
+	// Anhänge
+	{Method: fiber.MethodGet, Path: "/attachments/:id", Tag: "Anhänge", Summary: "Metadaten eines Anhangs",
+		Status: fiber.StatusOK, Result: models.Attachment{}, Errors: []int{400, 404}},
+	{Method: fiber.MethodGet, Path: "/attachments/:id/content", Tag: "Anhänge", Summary: "Inhalt herunterladen (Range, If-None-Match)",
+		Status: fiber.StatusOK, Result: contentType("application/octet-stream"), Errors: []int{400, 404, 416}},
+	{Method: fiber.MethodDelete, Path: "/attachments/:id", Tag: "Anhänge", Summary: "Eigenen Anhang löschen",
+		Status: fiber.StatusNoContent, Errors: []int{400, 401, 403, 404}},
+
+	// Webhooks
+	{Method: fiber.MethodGet, Path: "/webhooks", Tag: "Webhooks", Summary: "Alle Webhooks",
+		Status: fiber.StatusOK, Result: listOf{Key: "webhooks", Item: models.Webhook{}}, Errors: []int{401}},
+	{Method: fiber.MethodPost, Path: "/webhooks", Tag: "Webhooks", Summary: "Webhook anlegen (Antwort enthält einmalig das Secret)",
+		Body: models.WebhookRequest{}, BodyRequired: []string{"url"}, Status: fiber.StatusCreated, Result: models.CreatedWebhook{}, Errors: []int{400, 401}},
+	{Method: fiber.MethodGet, Path: "/webhooks/dead-letters", Tag: "Webhooks", Summary: "Endgültig gescheiterte Zustellungen",
+		Status: fiber.StatusOK, Result: listOf{Key: "deliveries", Item: models.WebhookDelivery{}}, Errors: []int{401}},
+	{Method: fiber.MethodPost, Path: "/webhooks/deliveries/:id/retry", Tag: "Webhooks", Summary: "Zustellung erneut einplanen",
+		Status: fiber.StatusAccepted, Result: models.WebhookDelivery{}, Errors: []int{400, 401, 404, 409}},
+	{Method: fiber.MethodGet, Path: "/webhooks/:id", Tag: "Webhooks", Summary: "Webhook abrufen",
+		Status: fiber.StatusOK, Result: models.Webhook{}, Errors: []int{400, 401, 404}},
+	{Method: fiber.MethodPut, Path: "/webhooks/:id", Tag: "Webhooks", Summary: "Webhook ändern",
+		Body: models.WebhookRequest{}, Status: fiber.StatusOK, Result: models.Webhook{}, Errors: []int{400, 401, 404}},
+	{Method: fiber.MethodDelete, Path: "/webhooks/:id", Tag: "Webhooks", Summary: "Webhook löschen",
+		Status: fiber.StatusNoContent, Errors: []int{400, 401, 404}},
+	{Method: fiber.MethodGet, Path: "/webhooks/:id/deliveries", Tag: "Webhooks", Summary: "Letzte Zustellungen eines Webhooks",
+		Status: fiber.StatusOK, Result: listOf{Key: "deliveries", Item: models.WebhookDelivery{}}, Errors: []int{400, 401, 404}},
+
+	// Benutzer
+	{Method: fiber.MethodGet, Path: "/users", Tag: "Benutzer", Summary: "Benutzer des eigenen Mandanten",
+		Status: fiber.StatusOK, Result: listOf{Key: "users", Item: models.User{}}, Errors: []int{400}},
+	{Method: fiber.MethodPost, Path: "/users", Tag: "Benutzer", Summary: "Benutzer anlegen (Antwort enthält einmalig den API-Key)",
+		Body: models.CreateUserRequest{}, Status: fiber.StatusCreated, Result: models.CreatedUser{}, Errors: []int{400, 403, 409}},
+	{Method: fiber.MethodGet, Path: "/users/me", Tag: "Benutzer", Summary: "Angemeldeter Benutzer",
+		Status: fiber.StatusOK, Result: models.User{}, Errors: []int{401}},
+	{Method: fiber.MethodPost, Path: "/users/me/calendar-token", Tag: "Benutzer", Summary: "Kalender-Token erzeugen (ersetzt ein bestehendes)",
+		Status: fiber.StatusCreated, Result: models.CalendarToken{}, Errors: []int{400, 401}},
+	{Method: fiber.MethodDelete, Path: "/users/me/calendar-token", Tag: "Benutzer", Summary: "Kalender-Token entfernen",
+		Status: fiber.StatusNoContent, Errors: []int{400, 401}},
+	{Method: fiber.MethodGet, Path: "/users/:id", Tag: "Benutzer", Summary: "Benutzer abrufen",
+		Status: fiber.StatusOK, Result: models.User{}, Errors: []int{400, 404}},
+
+	// Sonstiges
+	{Method: fiber.MethodGet, Path: "/workflow", Tag: "Tasks", Summary: "Status, Übergänge und mögliche Folgestatus",
+		Status: fiber.StatusOK, Result: objectOf{
+			"statuses":    []models.WorkflowStatus{},
+			"transitions": []models.WorkflowTransition{},
+			"next_states": map[string][]string{},
+		}},
+	{Method: fiber.MethodGet, Path: "/health", Tag: "Sonstiges", Summary: "Health Check",
+		Status: fiber.StatusOK, Result: contentType("text/plain"), Unversioned: true},
+	{Method: fiber.MethodGet, Path: "/openapi.json", Tag: "Sonstiges", Summary: "Diese Spezifikation",
+		Status: fiber.StatusOK, Result: rawSchema{"type": "object"}, Unversioned: true},
+	{Method: fiber.MethodGet, Path: "/docs", Tag: "Sonstiges", Summary: "Interaktive API-Dokumentation",
+		Status: fiber.StatusOK, Result: contentType("text/html"), Unversioned: true},
+}
@@ -0,0 +1,124 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"task-api/handlers"
+	"testing"
+)
+
+// collectRefs sammelt alle $ref-Verweise eines JSON-Dokuments.
+func collectRefs(v any, refs map[string]bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if ref, ok := child.(string); ok && k == "$ref" {
+				refs[ref] = true
+			}
+			collectRefs(child, refs)
+		}
+	case []any:
+		for _, child := range v {
+			collectRefs(child, refs)
+		}
+	}
+}
+
+// Test_OpenAPI_Spec prüft die ausgelieferte Spezifikation: Version, aufgelöste Verweise und
+// aus den Models abgeleitete Schemas.
+func Test_OpenAPI_Spec(t *testing.T) {
+	h := &handlers.OpenAPIHandler{}
+	app := fiber.New()
+	app.Get("/openapi.json", h.Spec)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi.json", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+
+	var spec map[string]any
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&spec))
+	assert.Equal(t, "3.1.0", spec["openapi"])
+
+	schemas := spec["components"].(map[string]any)["schemas"].(map[string]any)
+	refs := map[string]bool{}
+	collectRefs(spec, refs)
+	for ref := range refs {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		assert.Contains(t, schemas, name, "unresolved reference %s", ref)
+	}
+
+	task := schemas["Task"].(map[string]any)
+	props := task["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": []any{"string", "null"}, "format": "date-time"}, props["due_at"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, props["tags"])
+	assert.Contains(t, task["required"], "created_at")
+	assert.NotContains(t, task["required"], "progress")
+
+	// Eingebettete Structs werden flach übernommen, Felder mit json:"-" ausgelassen
+	webhook := schemas["CreatedWebhook"].(map[string]any)["properties"].(map[string]any)
+	assert.Contains(t, webhook, "url")
+	assert.Contains(t, webhook, "secret")
+	assert.NotContains(t, schemas["Webhook"].(map[string]any)["properties"], "secret")
+
+	paths := spec["paths"].(map[string]any)
+	successSchema := func(path, method string) map[string]any {
+		op := paths[path].(map[string]any)[method].(map[string]any)
+		return op["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	}
+
+	// v1 ist als veraltet markiert; Listen liefern Tasks mit wählbaren Feldern und Beziehungen
+	list := paths["/v1/tasks"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, true, list["deprecated"])
+	item := successSchema("/v1/tasks", "get")["properties"].(map[string]any)["tasks"].(map[string]any)["items"].(map[string]any)
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/TaskViewV1"}, item)
+
+	// v1 behält den veralteten Schlüssel "created at", v2 verwendet "created_at"
+	v1View := schemas["TaskViewV1"].(map[string]any)["properties"].(map[string]any)
+	assert.NotContains(t, v1View, "created_at")
+	assert.Equal(t, true, v1View["created at"].(map[string]any)["deprecated"])
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/TaskViewV1"}, v1View["subtasks"].(map[string]any)["items"])
+
+	view := schemas["TaskView"].(map[string]any)
+	assert.Contains(t, view["properties"], "created_at")
+	assert.Equal(t, []any{"id"}, view["required"])
+	assert.Contains(t, view["properties"], "description")
+	assert.Contains(t, view["properties"], "comments_count")
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/TaskView"}, view["properties"].(map[string]any)["subtasks"].(map[string]any)["items"])
+
+	// v2 verpackt Listen in {"data", "meta"}, einzelne Ressourcen in {"data"} und Fehler in ErrorV2
+	v2List := successSchema("/v2/tasks", "get")["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/TaskView"}, v2List["data"].(map[string]any)["items"])
+	assert.Contains(t, v2List["meta"].(map[string]any)["properties"], "total")
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/Task"}, successSchema("/v2/tasks/{id}", "get")["properties"].(map[string]any)["data"])
+	v2Get := paths["/v2/tasks/{id}"].(map[string]any)["get"].(map[string]any)
+	assert.NotContains(t, v2Get, "deprecated")
+	notFound := v2Get["responses"].(map[string]any)["404"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"]
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/ErrorV2"}, notFound)
+
+	// Routen ohne Version stehen nur einmal in der Spezifikation
+	assert.Contains(t, paths, "/graphql")
+	assert.NotContains(t, paths, "/v1/graphql")
+	assert.NotContains(t, paths, "/tasks")
+
+	get := paths["/v1/tasks/{id}"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "id", get["parameters"].([]any)[0].(map[string]any)["name"])
+}
+
+// Test_OpenAPI_Docs prüft, dass die Dokumentationsseite die Spezifikation lädt.
+func Test_OpenAPI_Docs(t *testing.T) {
+	h := &handlers.OpenAPIHandler{}
+	app := fiber.New()
+	app.Get("/docs", h.Docs)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/docs", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), `url: "/openapi.json"`)
+}
@@ -0,0 +1,442 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"task-api/validation"
+	"unicode/utf8"
+)
+
+// fieldError ist ein einzelner Validierungsfehler.
+type fieldError struct {
+	In      string `json:"in"`      // Ort des Fehlers: "path", "query", "body" oder "response"
+	Pointer string `json:"pointer"` // JSON Pointer (RFC 6901) bzw. Name des Parameters, z.B. "/title"
+	Message string `json:"message"` // Beschreibung des Fehlers
+}
+
+// ValidationOptions steuert die Validierung gegen die OpenAPI-Spezifikation.
+type ValidationOptions struct {
+	// Responses prüft zusätzlich jede JSON-Antwort gegen die Spezifikation (für Tests).
+	// Weicht eine Antwort ab, wird sie durch 500 mit den Abweichungen ersetzt.
+	Responses bool
+}
+
+// ValidateRequests liefert eine Middleware, die Pfad- und Query-Parameter sowie JSON-Bodies gegen die
+// OpenAPI-Spezifikation prüft, bevor der Handler aufgerufen wird. Alle Abweichungen werden gesammelt
+// und gemeinsam mit Status 400 beantwortet:
+//
+//	{"error": "validation error", "message": "...", "errors": [{"in": "body", "pointer": "/title", "message": "..."}]}
+//
+// Requests auf Pfade, die nicht in der Spezifikation stehen, werden unverändert durchgereicht.
+func ValidateRequests(spec map[string]any, opts ValidationOptions) fiber.Handler {
+	v := newSpecValidator(spec)
+
+	return func(c *fiber.Ctx) error {
+		op := v.match(c.Method(), c.Path())
+		if op == nil {
+			return c.Next()
+		}
+
+		if errs := v.validateRequest(c, op); len(errs) > 0 {
+			return errorResponse(c, fiber.StatusBadRequest, fiber.Map{
+				"error":   "validation error",
+				"message": errorSummary(errs),
+				"errors":  errs,
+			})
+		}
+
+		if err := c.Next(); err != nil || !opts.Responses {
+			return err
+		}
+		if errs := v.validateResponse(c, op); len(errs) > 0 {
+			c.Response().Reset()
+			return errorResponse(c, fiber.StatusInternalServerError, fiber.Map{
+				"error":   "response validation error",
+				"message": errorSummary(errs),
+				"errors":  errs,
+			})
+		}
+		return nil
+	}
+}
+
+// errorSummary fasst Validierungsfehler für das Feld "message" zusammen.
+func errorSummary(errs []fieldError) string {
+	first := errs[0].Message
+	if errs[0].Pointer != "" {
+		first = errs[0].Pointer + ": " + first
+	}
+	if len(errs) == 1 {
+		return first
+	}
+	return fmt.Sprintf("%s (and %d more)", first, len(errs)-1)
+}
+
+// specValidator prüft Requests und Responses gegen eine OpenAPI-Spezifikation.
+type specValidator struct {
+	routes  []specRoute
+	schemas map[string]any
+}
+
+// specRoute ist eine Operation der Spezifikation mit ihrem Pfadmuster.
+type specRoute struct {
+	method  string
+	pattern *regexp.Regexp
+	params  []string // Namen der Pfadparameter in Reihenfolge
+	literal int      // Anzahl fester Pfadsegmente; spezifischere Pfade gewinnen
+	op      map[string]any
+}
+
+// openAPIParam erkennt Pfadparameter im OpenAPI-Format ("{id}").
+var openAPIParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// quotedParam erkennt Pfadparameter nach regexp.QuoteMeta.
+var quotedParam = regexp.MustCompile(`\\\{[^}]+\\\}`)
+
+// newSpecValidator bereitet die Spezifikation für die Validierung vor. Die Spezifikation wird einmal
+// per JSON umgewandelt, damit alle Werte einheitlich als map[string]any, []any, string, float64 vorliegen.
+func newSpecValidator(spec map[string]any) *specValidator {
+	raw, _ := json.Marshal(spec)
+	var normalized map[string]any
+	_ = json.Unmarshal(raw, &normalized)
+
+	v := &specValidator{}
+	if components, ok := normalized["components"].(map[string]any); ok {
+		v.schemas, _ = components["schemas"].(map[string]any)
+	}
+	paths, _ := normalized["paths"].(map[string]any)
+	for path, item := range paths {
+		var params []string
+		for _, m := range openAPIParam.FindAllStringSubmatch(path, -1) {
+			params = append(params, m[1])
+		}
+		// QuoteMeta maskiert auch die Klammern der Parameter, daher wird "\{name\}" ersetzt
+		expr := "^" + quotedParam.ReplaceAllString(regexp.QuoteMeta(path), "([^/]+)") + "/?$"
+		literal := strings.Count(path, "/") - len(params)
+		for method, op := range item.(map[string]any) {
+			v.routes = append(v.routes, specRoute{
+				method:  strings.ToUpper(method),
+				pattern: regexp.MustCompile(expr),
+				params:  params,
+				literal: literal,
+				op:      op.(map[string]any),
+			})
+		}
+	}
+	// Wie beim Router: feste Segmente vor Parametern ("/tasks/order" vor "/tasks/{id}")
+	sort.SliceStable(v.routes, func(i, j int) bool { return v.routes[i].literal > v.routes[j].literal })
+	return v
+}
+
+// matchedRoute ist eine gefundene Operation samt Werten der Pfadparameter.
+type matchedRoute struct {
+	op     map[string]any
+	params map[string]string
+}
+
+// match sucht die Operation zu Methode und Pfad. HEAD wird wie GET behandelt.
+func (v *specValidator) match(method, path string) *matchedRoute {
+	if method == fiber.MethodHead {
+		method = fiber.MethodGet
+	}
+	for _, r := range v.routes {
+		if r.method != method {
+			continue
+		}
+		m := r.pattern.FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+		params := map[string]string{}
+		for i, name := range r.params {
+			params[name], _ = url.PathUnescape(m[i+1])
+		}
+		return &matchedRoute{op: r.op, params: params}
+	}
+	return nil
+}
+
+// validateRequest prüft Pfad- und Query-Parameter sowie den JSON-Body eines Requests.
+func (v *specValidator) validateRequest(c *fiber.Ctx, route *matchedRoute) []fieldError {
+	var errs []fieldError
+
+	params, _ := route.op["parameters"].([]any)
+	for _, p := range params {
+		param := p.(map[string]any)
+		name, _ := param["name"].(string)
+		schema, _ := param["schema"].(map[string]any)
+		switch param["in"] {
+		case "path":
+			errs = append(errs, v.validateParam("path", name, route.params[name], schema)...)
+		case "query":
+			if value := c.Query(name); value != "" {
+				errs = append(errs, v.validateParam("query", name, value, schema)...)
+			}
+		}
+	}
+
+	body, _ := route.op["requestBody"].(map[string]any)
+	content, _ := body["content"].(map[string]any)
+	media, ok := content["application/json"].(map[string]any)
+	if !ok {
+		return errs
+	}
+	raw := bytes.TrimSpace(c.Body())
+	if len(raw) == 0 {
+		if body["required"] == true {
+			errs = append(errs, fieldError{In: "body", Pointer: "", Message: "request body is required"})
+		}
+		return errs
+	}
+	// Andere Formate (z.B. Formulare) prüft der Handler beim Einlesen selbst
+	if ct := string(c.Request().Header.ContentType()); ct != "" && !strings.HasPrefix(ct, fiber.MIMEApplicationJSON) {
+		return errs
+	}
+	var value any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return append(errs, fieldError{In: "body", Pointer: "", Message: "invalid JSON"})
+	}
+	schema, _ := media["schema"].(map[string]any)
+	return append(errs, v.validate("body", "", value, schema)...)
+}
+
+// validateResponse prüft Status und JSON-Body der Antwort gegen die dokumentierten Responses.
+func (v *specValidator) validateResponse(c *fiber.Ctx, route *matchedRoute) []fieldError {
+	status := c.Response().StatusCode()
+	responses, _ := route.op["responses"].(map[string]any)
+	response, ok := responses[strconv.Itoa(status)].(map[string]any)
+	if !ok {
+		return []fieldError{{In: "response", Message: fmt.Sprintf("status %d is not documented", status)}}
+	}
+	content, _ := response["content"].(map[string]any)
+	media, ok := content["application/json"].(map[string]any)
+	ct := string(c.Response().Header.ContentType())
+	if !ok || !strings.HasPrefix(ct, fiber.MIMEApplicationJSON) {
+		if len(content) == 0 && len(c.Response().Body()) > 0 && status != fiber.StatusSwitchingProtocols {
+			return []fieldError{{In: "response", Message: fmt.Sprintf("status %d must not have a body", status)}}
+		}
+		return nil
+	}
+
+	var value any
+	dec := json.NewDecoder(bytes.NewReader(c.Response().Body()))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return []fieldError{{In: "response", Message: "invalid JSON"}}
+	}
+	schema, _ := media["schema"].(map[string]any)
+	return v.validate("response", "", value, schema)
+}
+
+// validateParam prüft einen Pfad- oder Query-Parameter. Parameter sind immer Strings und werden
+// anhand des Schema-Typs umgewandelt.
+func (v *specValidator) validateParam(in, name, value string, schema map[string]any) []fieldError {
+	var parsed any = value
+	switch schema["type"] {
+	case "integer", "number":
+		parsed = json.Number(value)
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return []fieldError{{In: in, Pointer: name, Message: "must be " + articleFor(schema["type"].(string))}}
+		}
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return []fieldError{{In: in, Pointer: name, Message: "must be a boolean"}}
+		}
+		parsed = b
+	}
+	errs := v.validate(in, "", parsed, schema)
+	for i := range errs {
+		errs[i].Pointer = name
+	}
+	return errs
+}
+
+// articleFor liefert den JSON-Typ mit Artikel für Fehlermeldungen, z.B. "an integer".
+func articleFor(typ string) string {
+	switch typ {
+	case "integer", "object", "array":
+		return "an " + typ
+	}
+	return "a " + typ
+}
+
+// validate prüft value gegen schema (Teilmenge von JSON Schema 2020-12, wie sie die
+// Spezifikation verwendet) und liefert alle Abweichungen mit ihrem JSON Pointer.
+func (v *specValidator) validate(in, pointer string, value any, schema map[string]any) []fieldError {
+	if schema == nil {
+		return nil
+	}
+	fail := func(format string, args ...any) []fieldError {
+		return []fieldError{{In: in, Pointer: pointer, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	var errs []fieldError
+	if ref, ok := schema["$ref"].(string); ok {
+		target, _ := v.schemas[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]any)
+		errs = append(errs, v.validate(in, pointer, value, target)...)
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		matched := false
+		for _, alt := range anyOf {
+			if len(v.validate(in, pointer, value, alt.(map[string]any))) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			// Bei nullable-Referenzen ist der Fehler der Referenz aussagekräftiger als "kein Treffer"
+			if len(anyOf) == 2 && value != nil {
+				return append(errs, v.validate(in, pointer, value, anyOf[0].(map[string]any))...)
+			}
+			return append(errs, fail("does not match any allowed schema")...)
+		}
+	}
+
+	if typ, ok := schema["type"]; ok && !matchesType(value, typ) {
+		return append(errs, fail("must be %s", describeType(typ))...)
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return equalJSON(e, value) }) {
+		var allowed []string
+		for _, e := range enum {
+			if s, ok := e.(string); ok && s != "" {
+				allowed = append(allowed, s)
+			}
+		}
+		errs = append(errs, fail("must be one of: %s", strings.Join(allowed, ", "))...)
+	}
+
+	switch value := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(value)
+		if min, ok := schema["minLength"].(float64); ok && float64(length) < min {
+			if min == 1 {
+				errs = append(errs, fail("must not be empty")...)
+			} else {
+				errs = append(errs, fail("must be at least %d characters", int(min))...)
+			}
+		}
+		if max, ok := schema["maxLength"].(float64); ok && float64(length) > max {
+			errs = append(errs, fail("must be at most %d characters", int(max))...)
+		}
+		if format, ok := schema["format"].(string); ok && value != "" {
+			if msg := validation.CheckFormat(value, format); msg != "" {
+				errs = append(errs, fail("%s", msg)...)
+			}
+		}
+	case json.Number:
+		n, _ := value.Float64()
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			errs = append(errs, fail("must be at least %v", min)...)
+		}
+		if max, ok := schema["maximum"].(float64); ok && n > max {
+			errs = append(errs, fail("must be at most %v", max)...)
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range value {
+				errs = append(errs, v.validate(in, pointer+"/"+strconv.Itoa(i), item, items)...)
+			}
+		}
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				if _, present := value[name.(string)]; !present {
+					errs = append(errs, fieldError{In: in, Pointer: pointer + "/" + escapePointer(name.(string)), Message: "is required"})
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := pointer + "/" + escapePointer(name)
+			if prop, ok := properties[name].(map[string]any); ok {
+				errs = append(errs, v.validate(in, child, value[name], prop)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+				errs = append(errs, v.validate(in, child, value[name], additional)...)
+			}
+		}
+	}
+	return errs
+}
+
+// matchesType prüft den JSON-Typ eines Werts; typ ist ein Typname oder eine Liste von Typnamen.
+func matchesType(value any, typ any) bool {
+	if list, ok := typ.([]any); ok {
+		return slices.ContainsFunc(list, func(t any) bool { return matchesType(value, t) })
+	}
+	switch typ {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	}
+	return true
+}
+
+// describeType formuliert den erwarteten Typ für Fehlermeldungen, z.B. "a string or null".
+func describeType(typ any) string {
+	list, ok := typ.([]any)
+	if !ok {
+		return articleFor(typ.(string))
+	}
+	var parts []string
+	for _, t := range list {
+		if t == "null" {
+			parts = append(parts, "null")
+		} else {
+			parts = append(parts, articleFor(t.(string)))
+		}
+	}
+	return strings.Join(parts, " or ")
+}
+
+// equalJSON vergleicht zwei JSON-Werte (Zahlen numerisch).
+func equalJSON(a, b any) bool {
+	if n, ok := b.(json.Number); ok {
+		f, _ := n.Float64()
+		return a == f
+	}
+	return a == b
+}
+
+// escapePointer maskiert einen Eigenschaftsnamen für einen JSON Pointer (RFC 6901).
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
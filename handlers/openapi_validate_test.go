@@ -0,0 +1,145 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"task-api/handlers"
+	"task-api/services"
+	"testing"
+)
+
+// validationErrors ist die Antwort der Validierungs-Middleware.
+type validationErrors struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	Errors  []struct {
+		In      string `json:"in"`
+		Pointer string `json:"pointer"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// setupValidatedApp registriert die Task-Routen unter /v1 hinter der Validierungs-Middleware.
+func setupValidatedApp(opts handlers.ValidationOptions) *fiber.App {
+	app := setupFiberHandler(&services.MockTaskService{})
+	validated := fiber.New()
+	validated.Use(handlers.ValidateRequests(handlers.OpenAPISpec(), opts))
+	validated.Mount("/v1", app)
+	return validated
+}
+
+// doValidated sendet einen Request und liest die Antwort der Validierungs-Middleware.
+func doValidated(t *testing.T, app *fiber.App, method, path, body string) (int, validationErrors) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	var result validationErrors
+	_ = json.Unmarshal(data, &result)
+	return resp.StatusCode, result
+}
+
+// Test_ValidateRequests_Body prüft, dass alle Fehler eines Bodys auf einmal mit JSON Pointern gemeldet werden.
+func Test_ValidateRequests_Body(t *testing.T) {
+	app := setupValidatedApp(handlers.ValidationOptions{})
+
+	status, result := doValidated(t, app, "POST", "/v1/tasks",
+		`{"description": 5, "priority": "urgent", "due_at": "tomorrow", "parent_id": "1"}`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "validation error", result.Error)
+
+	got := map[string]string{}
+	for _, e := range result.Errors {
+		assert.Equal(t, "body", e.In)
+		got[e.Pointer] = e.Message
+	}
+	assert.Equal(t, map[string]string{
+		"/title":       "is required",
+		"/description": "must be a string",
+		"/priority":    "must be one of: low, medium, high",
+		"/due_at":      "must be a RFC 3339 date-time",
+		"/parent_id":   "must be an integer or null",
+	}, got)
+	assert.Contains(t, result.Message, "(and 4 more)")
+
+	// Längen werden in Zeichen gezählt, nicht in Bytes
+	status, result = doValidated(t, app, "POST", "/v1/tasks", `{"title": "`+strings.Repeat("ä", 150)+`", "description": "`+strings.Repeat("ä", 1001)+`"}`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, "/description", result.Errors[0].Pointer)
+	assert.Equal(t, "must be at most 1000 characters", result.Errors[0].Message)
+
+	status, result = doValidated(t, app, "POST", "/v1/tasks", `{"title": `)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "invalid JSON", result.Message)
+
+	status, result = doValidated(t, app, "POST", "/v1/tasks", "")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "request body is required", result.Message)
+}
+
+// Test_ValidateRequests_Params prüft Pfad- und Query-Parameter.
+func Test_ValidateRequests_Params(t *testing.T) {
+	app := setupValidatedApp(handlers.ValidationOptions{})
+
+	status, result := doValidated(t, app, "GET", "/v1/tasks/abc", "")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "path", result.Errors[0].In)
+	assert.Equal(t, "id", result.Errors[0].Pointer)
+	assert.Equal(t, "must be an integer", result.Errors[0].Message)
+
+	status, result = doValidated(t, app, "GET", "/v1/tasks?overdue=maybe", "")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "query", result.Errors[0].In)
+	assert.Equal(t, "overdue", result.Errors[0].Pointer)
+
+	// Feste Pfade haben Vorrang vor Parametern
+	status, _ = doValidated(t, app, "GET", "/v1/tasks/order", "")
+	assert.Equal(t, fiber.StatusOK, status)
+
+	status, _ = doValidated(t, app, "GET", "/v1/tasks?overdue=true", "")
+	assert.Equal(t, fiber.StatusOK, status)
+}
+
+// Test_ValidateRequests_Responses prüft, dass Antworten, die von der Spezifikation abweichen, als 500 gemeldet werden.
+func Test_ValidateRequests_Responses(t *testing.T) {
+	app := fiber.New()
+	app.Use(handlers.ValidateRequests(handlers.OpenAPISpec(), handlers.ValidationOptions{Responses: true}))
+	app.Get("/v1/tasks/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "1" {
+			return c.JSON(fiber.Map{"id": "1", "title": "Task"})
+		}
+		return c.Status(fiber.StatusTeapot).JSON(fiber.Map{"error": "teapot", "message": "teapot"})
+	})
+
+	status, result := doValidated(t, app, "GET", "/v1/tasks/1", "")
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, "response validation error", result.Error)
+	pointers := map[string]bool{}
+	for _, e := range result.Errors {
+		assert.Equal(t, "response", e.In)
+		pointers[e.Pointer] = true
+	}
+	assert.True(t, pointers["/id"], "wrong type of id should be reported")
+	assert.True(t, pointers["/status"], "missing status should be reported")
+
+	status, result = doValidated(t, app, "GET", "/v1/tasks/2", "")
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, "status 418 is not documented", result.Message)
+
+	// Ohne Responses-Option bleibt die Antwort unverändert
+	plain := fiber.New()
+	plain.Use(handlers.ValidateRequests(handlers.OpenAPISpec(), handlers.ValidationOptions{}))
+	plain.Get("/v1/tasks/:id", func(c *fiber.Ctx) error { return c.JSON(fiber.Map{"id": "1"}) })
+	status, _ = doValidated(t, plain, "GET", "/v1/tasks/1", "")
+	assert.Equal(t, fiber.StatusOK, status)
+}
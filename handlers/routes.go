@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"task-api/services"
+)
+
+// APIHandlers bündelt die Handler, deren Endpoints RegisterRoutes registriert.
+type APIHandlers struct {
+	Tasks       *TaskHandler
+	Users       *UserHandler
+	Comments    *CommentHandler
+	Attachments *AttachmentHandler
+	Tags        *TagHandler
+	Webhooks    *WebhookHandler
+	Events      *EventHandler
+	Socket      *SocketHandler
+	Imports     *ImportHandler
+	Calendar    *CalendarHandler
+	GraphQL     *GraphQLHandler
+	OpenAPI     *OpenAPIHandler
+}
+
+// AppOptions steuert NewApp.
+type AppOptions struct {
+	// BodyLimit ist die maximale Größe eines Request-Bodys (0 = Fiber-Default von 4 MiB).
+	BodyLimit int
+	// Users prüft die API-Keys für Authenticate.
+	Users services.UserServiceInterface
+	// Version steuert NegotiateVersion (Default-Version, Deprecation und Sunset von v1).
+	Version VersionOptions
+	// Validation steuert ValidateRequests.
+	Validation ValidationOptions
+}
+
+// NewApp erstellt die Fiber-App der API mit allen Middlewares und Routen, wie sie der Server startet:
+// Versionsaushandlung, Authentifizierung per API-Key und Prüfung gegen die OpenAPI-Spezifikation.
+// Tests (auch der Client in package client) bauen damit den echten Server mit Mock-Services auf.
+func NewApp(h APIHandlers, opts AppOptions) *fiber.App {
+	app := fiber.New(fiber.Config{BodyLimit: opts.BodyLimit})
+
+	// API-Version aus Pfad (/v1, /v2) oder Accept-Header; v1-Antworten erhalten Deprecation und Sunset
+	app.Use(NegotiateVersion(opts.Version))
+	// Authentifizierung per API-Key (optional): legt den angemeldeten Benutzer für alle Routen ab
+	app.Use(Authenticate(opts.Users))
+	// Prüft Parameter und JSON-Bodies gegen die OpenAPI-Spezifikation und meldet alle Fehler auf einmal
+	app.Use(ValidateRequests(OpenAPISpec(), opts.Validation))
+
+	RegisterRoutes(app, h)
+	return app
+}
+
+// RegisterRoutes registriert alle HTTP-Routen der API.
+// Jede Route muss in der OpenAPI-Spezifikation (openapi_routes.go) beschrieben sein;
+// routes_test.go schlägt fehl, sobald Routen und Spezifikation voneinander abweichen.
+//
+// Die REST-Ressourcen liegen unter /v1 (veraltet) und /v2 (Antworten in {"data", "meta"} bzw. {"error"});
+// Requests ohne Versionspräfix schreibt NegotiateVersion auf eine Version um.
+// Streams, GraphQL und Infrastruktur-Endpoints haben keine Version.
+func RegisterRoutes(app *fiber.App, h APIHandlers) {
+	// GET /tasks/events -> Server-Sent-Events-Stream aller Task-Änderungen
+	app.Get("/tasks/events", h.Events.StreamTaskEvents)
+
+	// GET /ws -> WebSocket-API für Boards: Abonnements und Task-Änderungen über eine Verbindung
+	app.Get("/ws", h.Socket.Upgrade, websocket.New(h.Socket.Serve))
+
+	// GET/POST /graphql -> GraphQL-API für Tasks inkl. Beziehungen (Mutationen nur per POST)
+	app.Get("/graphql", h.GraphQL.ServeGraphQL)
+	app.Post("/graphql", h.GraphQL.ServeGraphQL)
+
+	// GET /calendar.ics -> iCalendar-Feed der Tasks mit Fälligkeit (Kalender-Token als Query-Parameter)
+	app.Get("/calendar.ics", h.Calendar.Feed)
+
+	// GET /health -> Health Check Endpoint, liefert "OK"
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.SendString("OK")
+	})
+
+	// GET /openapi.json -> OpenAPI-3.1-Spezifikation aller Routen
+	app.Get("/openapi.json", h.OpenAPI.Spec)
+
+	// GET /docs -> Interaktive API-Dokumentation auf Basis von /openapi.json
+	app.Get("/docs", h.OpenAPI.Docs)
+
+	v1 := app.Group("/" + APIVersion1)
+	// GET /v1/tasks -> Liefert eine Liste aller Tasks (Kurzform ohne Beschreibung)
+	v1.Get("/tasks", h.Tasks.GetAllTasks)
+	registerResourceRoutes(v1, h)
+
+	v2 := app.Group("/"+APIVersion2, EnvelopeResponses())
+	// GET /v2/tasks -> Liefert eine Liste aller Tasks mit allen Feldern
+	v2.Get("/tasks", h.Tasks.ListTasks)
+	registerResourceRoutes(v2, h)
+}
+
+// registerResourceRoutes registriert die REST-Ressourcen, die in allen API-Versionen gleich sind.
+func registerResourceRoutes(r fiber.Router, h APIHandlers) {
+	// POST /tasks  -> Erstellt einen neuen Task
+	r.Post("/tasks", h.Tasks.CreateTask)
+
+	// GET /tasks/order -> Liefert alle Tasks in Abhängigkeits-Reihenfolge (vor /tasks/:id registriert)
+	r.Get("/tasks/order", h.Tasks.GetTopologicalOrder)
+
+	// POST /tasks/import -> Importiert Tasks aus CSV oder NDJSON (synchron, als Dry-Run oder als Job)
+	r.Post("/tasks/import", h.Imports.ImportTasks)
+
+	// GET /tasks/import/:jobId -> Liefert Status und Fortschritt eines Import-Jobs
+	r.Get("/tasks/import/:jobId", h.Imports.GetImportJob)
+
+	// GET /tasks/import/:jobId/errors -> Liefert den Fehlerbericht eines Import-Jobs
+	r.Get("/tasks/import/:jobId/errors", h.Imports.GetImportErrors)
+
+	// GET /tasks/export -> Exportiert die gefilterten Tasks als CSV, NDJSON oder XLSX (vor /tasks/:id registriert)
+	r.Get("/tasks/export", h.Tasks.ExportTasks)
+
+	// GET /tasks/:id -> Liefert einen Task anhand seiner ID zurück
+	r.Get("/tasks/:id", h.Tasks.GetTaskByID)
+
+	// GET /tasks/:id/subtasks -> Liefert alle direkten Subtasks eines Tasks
+	r.Get("/tasks/:id/subtasks", h.Tasks.GetSubtasks)
+
+	// GET /tasks/:id/tree -> Liefert einen Task inklusive aller Subtasks als Baum
+	r.Get("/tasks/:id/tree", h.Tasks.GetTaskTree)
+
+	// GET /tasks/:id/dependencies -> Liefert alle Tasks, von denen ein Task abhängt
+	r.Get("/tasks/:id/dependencies", h.Tasks.GetDependencies)
+
+	// POST /tasks/:id/dependencies -> Legt eine neue Abhängigkeit an
+	r.Post("/tasks/:id/dependencies", h.Tasks.AddDependency)
+
+	// DELETE /tasks/:id/dependencies/:dependsOnId -> Entfernt eine Abhängigkeit
+	r.Delete("/tasks/:id/dependencies/:dependsOnId", h.Tasks.RemoveDependency)
+
+	// POST /tasks/:id/tags -> Ordnet einem Task einen Tag zu
+	r.Post("/tasks/:id/tags", h.Tags.AttachTag)
+
+	// DELETE /tasks/:id/tags/:tagId -> Entfernt einen Tag von einem Task
+	r.Delete("/tasks/:id/tags/:tagId", h.Tags.DetachTag)
+
+	// POST /tasks/:id/assignees -> Weist einen Task einem Benutzer zu
+	r.Post("/tasks/:id/assignees", h.Tasks.AssignUser)
+
+	// DELETE /tasks/:id/assignees/:userId -> Entfernt eine Zuweisung
+	r.Delete("/tasks/:id/assignees/:userId", h.Tasks.UnassignUser)
+
+	// POST /tasks/:id/watchers -> Trägt einen Beobachter ein (Default: angemeldeter Benutzer)
+	r.Post("/tasks/:id/watchers", h.Tasks.WatchTask)
+
+	// DELETE /tasks/:id/watchers/:userId -> Entfernt einen Beobachter
+	r.Delete("/tasks/:id/watchers/:userId", h.Tasks.UnwatchTask)
+
+	// GET /tasks/:id/comments -> Liefert alle Kommentare eines Tasks
+	r.Get("/tasks/:id/comments", h.Comments.GetComments)
+
+	// POST /tasks/:id/comments -> Legt einen Kommentar an (API-Key erforderlich)
+	r.Post("/tasks/:id/comments", h.Comments.AddComment)
+
+	// GET /tasks/:id/attachments -> Liefert die Metadaten aller Anhänge eines Tasks
+	r.Get("/tasks/:id/attachments", h.Attachments.GetAttachments)
+
+	// POST /tasks/:id/attachments -> Lädt einen Anhang hoch (multipart/form-data, API-Key erforderlich)
+	r.Post("/tasks/:id/attachments", h.Attachments.UploadAttachment)
+
+	// GET /tasks/:id/history -> Liefert die Audit-Historie eines Tasks
+	r.Get("/tasks/:id/history", h.Comments.GetTaskHistory)
+
+	// PUT /tasks/:id -> Aktualisiert einen bestehenden Task
+	r.Put("/tasks/:id", h.Tasks.UpdateTask)
+
+	// DELETE /tasks/:id -> Löscht einen Task anhand der ID
+	r.Delete("/tasks/:id", h.Tasks.DeleteTask)
+
+	// GET /tags -> Liefert alle Tags
+	r.Get("/tags", h.Tags.GetAllTags)
+
+	// POST /tags -> Erstellt einen neuen Tag
+	r.Post("/tags", h.Tags.CreateTag)
+
+	// PUT /tags/:id -> Benennt einen Tag um
+	r.Put("/tags/:id", h.Tags.RenameTag)
+
+	// DELETE /tags/:id -> Löscht einen Tag
+	r.Delete("/tags/:id", h.Tags.DeleteTag)
+
+	// POST /tags/:id/merge -> Führt einen Tag in einen anderen über
+	r.Post("/tags/:id/merge", h.Tags.MergeTags)
+
+	// PATCH /comments/:id -> Bearbeitet einen eigenen Kommentar
+	r.Patch("/comments/:id", h.Comments.UpdateComment)
+
+	// DELETE /comments/:id -> Löscht einen eigenen Kommentar
+	r.Delete("/comments/:id", h.Comments.DeleteComment)
+
+	// GET /comments/:id/revisions -> Liefert frühere Fassungen eines Kommentars
+	r.Get("/comments/:id/revisions", h.Comments.GetRevisions)
+
+	// GET /attachments/:id -> Liefert die Metadaten eines Anhangs
+	r.Get("/attachments/:id", h.Attachments.GetAttachment)
+
+	// GET /attachments/:id/content -> Liefert den Inhalt eines Anhangs (unterstützt Range)
+	r.Get("/attachments/:id/content", h.Attachments.DownloadAttachment)
+
+	// DELETE /attachments/:id -> Löscht einen eigenen Anhang
+	r.Delete("/attachments/:id", h.Attachments.DeleteAttachment)
+
+	// GET /webhooks -> Liefert alle Webhooks (API-Key erforderlich)
+	r.Get("/webhooks", h.Webhooks.GetAllWebhooks)
+
+	// POST /webhooks -> Legt einen Webhook an und liefert dessen Secret
+	r.Post("/webhooks", h.Webhooks.CreateWebhook)
+
+	// GET /webhooks/dead-letters -> Liefert endgültig gescheiterte Zustellungen (vor /webhooks/:id registriert)
+	r.Get("/webhooks/dead-letters", h.Webhooks.GetDeadLetters)
+
+	// POST /webhooks/deliveries/:id/retry -> Plant eine Zustellung erneut ein
+	r.Post("/webhooks/deliveries/:id/retry", h.Webhooks.RetryDelivery)
+
+	// GET /webhooks/:id -> Liefert einen Webhook anhand seiner ID
+	r.Get("/webhooks/:id", h.Webhooks.GetWebhook)
+
+	// PUT /webhooks/:id -> Ändert URL, Ereignisse, Secret oder Aktiv-Status eines Webhooks
+	r.Put("/webhooks/:id", h.Webhooks.UpdateWebhook)
+
+	// DELETE /webhooks/:id -> Löscht einen Webhook
+	r.Delete("/webhooks/:id", h.Webhooks.DeleteWebhook)
+
+	// GET /webhooks/:id/deliveries -> Liefert das Zustellprotokoll eines Webhooks
+	r.Get("/webhooks/:id/deliveries", h.Webhooks.GetDeliveries)
+
+	// GET /users -> Liefert alle Benutzer des eigenen Mandanten
+	r.Get("/users", h.Users.GetAllUsers)
+
+	// POST /users -> Legt einen Benutzer an und liefert dessen API-Key
+	r.Post("/users", h.Users.CreateUser)
+
+	// GET /users/me -> Liefert den angemeldeten Benutzer (vor /users/:id registriert)
+	r.Get("/users/me", h.Users.GetCurrentUser)
+
+	// POST /users/me/calendar-token -> Erzeugt ein neues Kalender-Token für /calendar.ics
+	r.Post("/users/me/calendar-token", h.Users.CreateCalendarToken)
+
+	// DELETE /users/me/calendar-token -> Entfernt das Kalender-Token
+	r.Delete("/users/me/calendar-token", h.Users.RevokeCalendarToken)
+
+	// GET /users/:id -> Liefert einen Benutzer anhand seiner ID
+	r.Get("/users/:id", h.Users.GetUserByID)
+
+	// GET /workflow -> Liefert Status, erlaubte Übergänge und mögliche Folgestatus
+	r.Get("/workflow", h.Tasks.GetWorkflow)
+}
@@ -0,0 +1,126 @@
+package handlers_test
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"task-api/handlers"
+	"task-api/services"
+	"testing"
+)
+
+// Test_Routes_MatchOpenAPISpec prüft, dass jede in RegisterRoutes registrierte Route in der
+// OpenAPI-Spezifikation beschrieben ist und die Spezifikation keine Routen enthält, die es nicht gibt.
+func Test_Routes_MatchOpenAPISpec(t *testing.T) {
+	app := fiber.New()
+	handlers.RegisterRoutes(app, handlers.APIHandlers{})
+
+	registered := map[string]bool{}
+	for _, r := range app.GetRoutes(true) {
+		// Fiber registriert zu jeder GET-Route automatisch HEAD
+		if r.Method == fiber.MethodHead {
+			continue
+		}
+		registered[r.Method+" "+handlers.OpenAPIPath(r.Path)] = true
+	}
+
+	documented := map[string]bool{}
+	for path, item := range handlers.OpenAPISpec()["paths"].(map[string]map[string]any) {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for route := range registered {
+		assert.True(t, documented[route], "route %s is registered but missing in the OpenAPI spec", route)
+	}
+	for route := range documented {
+		assert.True(t, registered[route], "route %s is documented in the OpenAPI spec but not registered", route)
+	}
+}
+
+// Test_Routes_ResponsesMatchOpenAPISpec ruft die Endpoints mit Mock-Services auf und prüft jede Antwort
+// (Status und Body) gegen die OpenAPI-Spezifikation.
+func Test_Routes_ResponsesMatchOpenAPISpec(t *testing.T) {
+	taskHandler := &handlers.TaskHandler{Service: &services.MockTaskService{}}
+	commentService := &services.MockCommentService{}
+	userService := &services.MockUserService{}
+
+	app := handlers.NewApp(handlers.APIHandlers{
+		Tasks:       taskHandler,
+		Users:       &handlers.UserHandler{Service: userService},
+		Comments:    &handlers.CommentHandler{Service: commentService},
+		Attachments: &handlers.AttachmentHandler{Service: &services.MockAttachmentService{}},
+		Tags:        &handlers.TagHandler{Service: &services.MockTagService{}},
+		Webhooks:    &handlers.WebhookHandler{Service: &services.MockWebhookService{}},
+		Imports:     &handlers.ImportHandler{Service: &services.ImportService{Tasks: taskHandler.Service, Repo: importJobRepo()}},
+		Calendar:    &handlers.CalendarHandler{Tasks: taskHandler.Service, Users: userService},
+		GraphQL:     &handlers.GraphQLHandler{Tasks: taskHandler, Comments: commentService, Users: userService},
+		OpenAPI:     &handlers.OpenAPIHandler{},
+	}, handlers.AppOptions{Users: userService, Validation: handlers.ValidationOptions{Responses: true}})
+
+	requests := []struct{ method, path, body string }{
+		{"POST", "/tasks", `{"title": "Task", "priority": "high"}`},
+		{"POST", "/tasks", `{"title": ""}`},
+		{"GET", "/tasks", ""},
+		{"GET", "/tasks?fields=title,description&include=subtasks,tags,assignees,comments_count", ""},
+		{"GET", "/tasks/order", ""},
+		{"GET", "/tasks/export?format=ndjson", ""},
+		{"GET", "/tasks/import/unknown", ""},
+		{"GET", "/tasks/1", ""},
+		{"GET", "/tasks/999", ""},
+		{"GET", "/tasks/1/subtasks", ""},
+		{"GET", "/tasks/1/tree", ""},
+		{"GET", "/tasks/1/dependencies", ""},
+		{"POST", "/tasks/2/dependencies", `{"depends_on_id": 1}`},
+		{"PUT", "/tasks/1", `{"status": "done"}`},
+		{"DELETE", "/tasks/1", ""},
+		{"POST", "/tasks/1/tags", `{"name": "urgent"}`},
+		{"POST", "/tasks/1/assignees", `{"user_id": 1}`},
+		{"POST", "/tasks/1/watchers", ""},
+		{"GET", "/tasks/1/comments", ""},
+		{"POST", "/tasks/1/comments", `{"body": "Kommentar"}`},
+		{"GET", "/tasks/1/history", ""},
+		{"GET", "/tasks/1/attachments", ""},
+		{"GET", "/tags", ""},
+		{"POST", "/tags", `{"name": "backend"}`},
+		{"GET", "/users", ""},
+		{"POST", "/users", `{"name": "Anna", "email": "anna@example.com"}`},
+		{"GET", "/users/1", ""},
+		{"GET", "/users/me", ""},
+		{"POST", "/users/me/calendar-token", ""},
+		{"GET", "/webhooks", ""},
+		{"POST", "/webhooks", `{"url": "https://example.com/hook", "events": ["task.created"]}`},
+		{"GET", "/webhooks/dead-letters", ""},
+		{"GET", "/workflow", ""},
+	}
+	// Jede Ressource wird in beiden API-Versionen geprüft, die Routen ohne Version einmal
+	var versioned []struct{ method, path, body string }
+	for _, version := range []string{handlers.APIVersion1, handlers.APIVersion2} {
+		for _, r := range requests {
+			versioned = append(versioned, struct{ method, path, body string }{r.method, "/" + version + r.path, r.body})
+		}
+	}
+	requests = append(versioned, []struct{ method, path, body string }{
+		{"GET", "/health", ""},
+		{"GET", "/calendar.ics?type=event&token=unknown", ""},
+		{"POST", "/graphql", `{"query": "{ tasks { totalCount nodes { id title } } }"}`},
+		{"POST", "/graphql", `{"query": "{ tasks { total } }"}`},
+		{"GET", "/openapi.json", ""},
+		{"GET", "/docs", ""},
+	}...)
+	for _, r := range requests {
+		req := httptest.NewRequest(r.method, r.path, strings.NewReader(r.body))
+		if r.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.NotEqual(t, fiber.StatusInternalServerError, resp.StatusCode, "%s %s: %s", r.method, r.path, data)
+		assert.NotContains(t, string(data), "Cannot "+r.method, "%s %s is not routed", r.method, r.path)
+	}
+}
@@ -0,0 +1,362 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"sync"
+	"task-api/models"
+	"task-api/services"
+	"time"
+)
+
+// maxBoards begrenzt die Anzahl der Boards, die eine Verbindung gleichzeitig abonnieren kann.
+const maxBoards = 32
+
+// maxSocketMessage begrenzt die Größe einer Client-Nachricht in Bytes.
+const maxSocketMessage = 64 * 1024
+
+// socketWriteTimeout begrenzt die Dauer eines einzelnen Schreibvorgangs auf den Socket.
+const socketWriteTimeout = 10 * time.Second
+
+// SocketHandler stellt die WebSocket-API (/ws) für Kanban-Boards bereit. Über eine Verbindung
+// können mehrere Boards abonniert und Tasks erstellt, geändert und verschoben werden.
+// Änderungen gelangen wie beim Event-Stream über die Outbox und den EventBroker zu allen
+// Abonnenten, auch zu denen anderer API-Instanzen.
+type SocketHandler struct {
+	Tasks  *TaskHandler // Validierung und Fehlerbehandlung wie bei der REST-API
+	Broker services.EventBrokerInterface
+	Users  services.UserServiceInterface
+
+	// Heartbeat legt fest, wie oft ein Ping gesendet wird, damit Proxies die Verbindung
+	// nicht schließen und getrennte Clients erkannt werden. Default: 15 Sekunden.
+	Heartbeat time.Duration
+}
+
+// socketSession ist der Zustand einer einzelnen WebSocket-Verbindung.
+type socketSession struct {
+	handler *SocketHandler
+	conn    *websocket.Conn
+	user    *models.User
+
+	writeMu sync.Mutex // serialisiert Schreibzugriffe auf conn
+
+	boardsMu sync.Mutex
+	boards   map[string]*services.EventSubscription
+
+	forwarders sync.WaitGroup
+}
+
+// Upgrade prüft GET /ws vor dem Verbindungsaufbau.
+// Da Browser beim Verbindungsaufbau keine Header setzen können, wird der API-Key zusätzlich
+// zu den Headern der REST-API auch als Query-Parameter api_key akzeptiert.
+//
+// Antwort:
+//
+//	101 - Verbindung wird auf WebSocket umgestellt
+//	401 - Unbekannter API-Key
+//	426 - Kein WebSocket-Verbindungsaufbau
+func (h *SocketHandler) Upgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(fiber.Map{
+			"error":   "upgrade required",
+			"message": "this endpoint requires a WebSocket connection",
+		})
+	}
+
+	if key := c.Query("api_key"); key != "" && currentUser(c) == nil {
+		user, err := h.Users.Authenticate(key)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "unauthorized",
+				"message": "invalid api key",
+			})
+		}
+		c.Locals(userLocalsKey, user)
+	}
+	return c.Next()
+}
+
+// Serve verarbeitet eine WebSocket-Verbindung auf /ws.
+// Jede Client-Nachricht (models.SocketMessage) wird mit "ack" oder "error" beantwortet, die
+// die Korrelations-ID der Nachricht enthalten. Änderungen an Tasks abonnierter Boards werden
+// als "event" gesendet.
+//
+// Beispiel-Nachrichten:
+//
+//	{"type": "subscribe", "id": "1", "board": "backend", "filter": {"tag": ["backend"]}}
+//	{"type": "create", "id": "2", "task": {"title": "Login bauen"}}
+//	{"type": "move", "id": "3", "task_id": 7, "status": "done"}
+func (h *SocketHandler) Serve(conn *websocket.Conn) {
+	user, _ := conn.Locals(userLocalsKey).(*models.User)
+	s := &socketSession{
+		handler: h,
+		conn:    conn,
+		user:    user,
+		boards:  map[string]*services.EventSubscription{},
+	}
+	defer s.close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.heartbeat(done)
+
+	conn.SetReadLimit(maxSocketMessage)
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg models.SocketMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			s.sendError("", fiber.StatusBadRequest, fiber.Map{
+				"error":   "validation error",
+				"message": "invalid message",
+			})
+			continue
+		}
+		s.handle(msg)
+	}
+}
+
+// handle verarbeitet eine Client-Nachricht anhand ihres Typs.
+func (s *socketSession) handle(msg models.SocketMessage) {
+	switch msg.Type {
+	case models.SocketSubscribe:
+		s.subscribe(msg)
+	case models.SocketUnsubscribe:
+		s.unsubscribe(msg)
+	case models.SocketCreate:
+		s.createTask(msg)
+	case models.SocketUpdate:
+		s.updateTask(msg)
+	case models.SocketMove:
+		s.moveTask(msg)
+	default:
+		s.sendError(msg.ID, fiber.StatusBadRequest, fiber.Map{
+			"error":   "validation error",
+			"message": fmt.Sprintf("unknown message type %q", msg.Type),
+		})
+	}
+}
+
+// subscribe abonniert die Änderungen der Tasks eines Boards. Mit last_event_id werden verpasste
+// Ereignisse nachgeliefert; ist die ID nicht mehr bekannt, folgt "reset".
+func (s *socketSession) subscribe(msg models.SocketMessage) {
+	if msg.Board == "" {
+		s.validationError(msg.ID, "board is required")
+		return
+	}
+
+	var filter models.TaskEventFilter
+	if msg.Filter != nil {
+		filter.Statuses = msg.Filter.Status
+		filter.Tags = msg.Filter.Tags
+		if msg.Filter.Assignee != "" {
+			id, err := parseUserID(msg.Filter.Assignee, s.user)
+			if err != nil {
+				if err.Error() == "authentication required" {
+					s.sendError(msg.ID, fiber.StatusUnauthorized, fiber.Map{
+						"error":   "unauthorized",
+						"message": "authentication required",
+					})
+					return
+				}
+				s.validationError(msg.ID, "assignee "+err.Error())
+				return
+			}
+			filter.Assignee = &id
+		}
+	}
+
+	s.boardsMu.Lock()
+	if _, ok := s.boards[msg.Board]; ok {
+		s.boardsMu.Unlock()
+		s.sendError(msg.ID, fiber.StatusConflict, fiber.Map{
+			"error":   "conflict",
+			"message": fmt.Sprintf("board %q is already subscribed", msg.Board),
+		})
+		return
+	}
+	if len(s.boards) >= maxBoards {
+		s.boardsMu.Unlock()
+		s.validationError(msg.ID, fmt.Sprintf("at most %d boards can be subscribed per connection", maxBoards))
+		return
+	}
+	sub, backlog, resumed := s.handler.Broker.Subscribe(filter, msg.LastEventID)
+	s.boards[msg.Board] = sub
+	s.boardsMu.Unlock()
+
+	s.send(fiber.Map{"type": models.SocketAck, "id": msg.ID, "board": msg.Board})
+	if !resumed {
+		s.send(fiber.Map{"type": models.SocketReset, "board": msg.Board})
+	}
+
+	s.forwarders.Add(1)
+	go s.forward(msg.Board, sub, backlog)
+}
+
+// forward sendet den Rückstand und anschließend alle Ereignisse eines Abonnements an den Client.
+// Beendet der Broker das Abonnement (z.B. weil der Client zu langsam liest), wird der Client mit
+// "unsubscribed" informiert und kann das Board per last_event_id erneut abonnieren.
+func (s *socketSession) forward(board string, sub *services.EventSubscription, backlog []*models.EventPayload) {
+	defer s.forwarders.Done()
+
+	for _, event := range backlog {
+		s.send(fiber.Map{"type": models.SocketEvent, "board": board, "event": event})
+	}
+	for event := range sub.Events {
+		s.send(fiber.Map{"type": models.SocketEvent, "board": board, "event": event})
+	}
+
+	s.boardsMu.Lock()
+	dropped := s.boards[board] == sub
+	if dropped {
+		delete(s.boards, board)
+	}
+	s.boardsMu.Unlock()
+
+	if dropped {
+		s.send(fiber.Map{
+			"type":    models.SocketUnsubscribed,
+			"board":   board,
+			"message": "subscription closed by server; subscribe again with last_event_id",
+		})
+	}
+}
+
+// unsubscribe beendet das Abonnement eines Boards.
+func (s *socketSession) unsubscribe(msg models.SocketMessage) {
+	s.boardsMu.Lock()
+	sub, ok := s.boards[msg.Board]
+	delete(s.boards, msg.Board)
+	s.boardsMu.Unlock()
+
+	if !ok {
+		s.sendError(msg.ID, fiber.StatusNotFound, fiber.Map{
+			"error":   "not found",
+			"message": fmt.Sprintf("board %q is not subscribed", msg.Board),
+		})
+		return
+	}
+	s.handler.Broker.Unsubscribe(sub)
+	s.send(fiber.Map{"type": models.SocketAck, "id": msg.ID, "board": msg.Board})
+}
+
+// createTask erstellt einen Task wie POST /tasks.
+func (s *socketSession) createTask(msg models.SocketMessage) {
+	if msg.Task == nil {
+		s.validationError(msg.ID, "task is required")
+		return
+	}
+	task, err := s.handler.Tasks.Service.CreateTask(*msg.Task)
+	if err != nil {
+		status, body := createTaskError(err)
+		s.sendError(msg.ID, status, body)
+		return
+	}
+	s.send(fiber.Map{"type": models.SocketAck, "id": msg.ID, "task": task})
+}
+
+// updateTask aktualisiert einen Task wie PUT /tasks/:id.
+func (s *socketSession) updateTask(msg models.SocketMessage) {
+	if msg.Task == nil {
+		s.validationError(msg.ID, "task is required")
+		return
+	}
+	s.applyUpdate(msg, *msg.Task)
+}
+
+// moveTask ändert den Status eines Tasks, z.B. beim Verschieben einer Karte in eine andere Spalte.
+// Es gelten dieselben Workflow-Regeln wie bei PUT /tasks/:id.
+func (s *socketSession) moveTask(msg models.SocketMessage) {
+	if msg.Status == "" {
+		s.validationError(msg.ID, "status is required")
+		return
+	}
+	s.applyUpdate(msg, models.CreateTaskRequest{
+		Status:           msg.Status,
+		Reopen:           msg.Reopen,
+		OverrideBlockers: msg.OverrideBlockers,
+	})
+}
+
+// applyUpdate aktualisiert den Task msg.TaskID über den TaskService.
+func (s *socketSession) applyUpdate(msg models.SocketMessage, req models.CreateTaskRequest) {
+	if msg.TaskID <= 0 {
+		s.validationError(msg.ID, "task_id is required")
+		return
+	}
+	task, err := s.handler.Tasks.Service.UpdateTask(msg.TaskID, req)
+	if err != nil {
+		status, body := s.handler.Tasks.updateTaskError(msg.TaskID, req, err)
+		s.sendError(msg.ID, status, body)
+		return
+	}
+	s.send(fiber.Map{"type": models.SocketAck, "id": msg.ID, "task": task})
+}
+
+// heartbeat sendet regelmäßig einen Ping, bis done geschlossen wird.
+// Schlägt der Ping fehl, wird die Verbindung geschlossen und Serve beendet.
+func (s *socketSession) heartbeat(done <-chan struct{}) {
+	interval := s.handler.Heartbeat
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteTimeout)); err != nil {
+				s.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// validationError sendet einen Validierungsfehler zur Nachricht id.
+func (s *socketSession) validationError(id, message string) {
+	s.sendError(id, fiber.StatusBadRequest, fiber.Map{
+		"error":   "validation error",
+		"message": message,
+	})
+}
+
+// sendError sendet einen Fehler zur Nachricht id. body entspricht der Antwort der REST-API,
+// status dem HTTP-Statuscode, den die REST-API verwenden würde.
+func (s *socketSession) sendError(id string, status int, body fiber.Map) {
+	body["type"] = models.SocketError
+	body["id"] = id
+	body["status"] = status
+	s.send(body)
+}
+
+// send schreibt eine Nachricht als JSON. Fehler werden ignoriert, da eine getrennte Verbindung
+// beim nächsten Lesen in Serve erkannt wird.
+func (s *socketSession) send(msg fiber.Map) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	_ = s.conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+	_ = s.conn.WriteJSON(msg)
+}
+
+// close beendet alle Abonnements der Verbindung und wartet auf die zugehörigen Goroutinen.
+func (s *socketSession) close() {
+	s.boardsMu.Lock()
+	subs := s.boards
+	s.boards = map[string]*services.EventSubscription{}
+	s.boardsMu.Unlock()
+
+	for _, sub := range subs {
+		s.handler.Broker.Unsubscribe(sub)
+	}
+	s.forwarders.Wait()
+}
@@ -0,0 +1,199 @@
+package handlers_test
+
+import (
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http/httptest"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+	"time"
+
+	contrib "github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// setupSocketHandler initialisiert einen Fiber-App-Server mit der WebSocket-API.
+// Benutzer 1 meldet sich mit "anna-key" an.
+func setupSocketHandler(tasks *services.MockTaskService, broker *services.EventBroker) *fiber.App {
+	users := &services.MockUserService{
+		Users:   testUsers(),
+		APIKeys: map[string]int{"anna-key": 1},
+	}
+	app := fiber.New()
+	app.Use(handlers.Authenticate(users))
+
+	handler := handlers.SocketHandler{Tasks: &handlers.TaskHandler{Service: tasks}, Broker: broker, Users: users}
+	app.Get("/ws", handler.Upgrade, contrib.New(handler.Serve))
+	return app
+}
+
+// dialSocket startet app auf einem freien Port und baut eine WebSocket-Verbindung zu path auf.
+func dialSocket(t *testing.T, app *fiber.App, path string) *websocket.Conn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go app.Listener(ln)
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String()+path, nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// roundTrip sendet msg und gibt die nächste Nachricht des Servers zurück.
+func roundTrip(t *testing.T, conn *websocket.Conn, msg models.SocketMessage) map[string]any {
+	assert.NoError(t, conn.WriteJSON(msg))
+	return readSocket(t, conn)
+}
+
+// readSocket liest die nächste Nachricht des Servers.
+func readSocket(t *testing.T, conn *websocket.Conn) map[string]any {
+	var resp map[string]any
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if !assert.NoError(t, conn.ReadJSON(&resp)) {
+		t.FailNow()
+	}
+	return resp
+}
+
+// Test_Socket_Upgrade prüft die Antworten vor dem Verbindungsaufbau.
+func Test_Socket_Upgrade(t *testing.T) {
+	app := setupSocketHandler(&services.MockTaskService{}, &services.EventBroker{})
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/ws", nil))
+	assert.Equal(t, 426, resp.StatusCode)
+
+	req := httptest.NewRequest("GET", "/ws?api_key=wrong", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	resp, _ = app.Test(req)
+	assert.Equal(t, 401, resp.StatusCode)
+}
+
+// Test_Socket_Mutations prüft create, update und move inklusive Validierung und Korrelations-IDs.
+func Test_Socket_Mutations(t *testing.T) {
+	tasks := &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "Login", Status: "todo"},
+			{ID: 2, Title: "Deploy", Status: "todo"},
+		},
+		Dependencies: map[int][]int{2: {1}},
+	}
+	conn := dialSocket(t, setupSocketHandler(tasks, &services.EventBroker{}), "/ws")
+
+	resp := roundTrip(t, conn, models.SocketMessage{Type: models.SocketCreate, ID: "c1", Task: &models.CreateTaskRequest{Title: "Neu"}})
+	assert.Equal(t, "ack", resp["type"])
+	assert.Equal(t, "c1", resp["id"])
+	assert.Equal(t, "Neu", resp["task"].(map[string]any)["title"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketCreate, ID: "c2", Task: &models.CreateTaskRequest{}})
+	assert.Equal(t, "error", resp["type"])
+	assert.Equal(t, "c2", resp["id"])
+	assert.Equal(t, float64(400), resp["status"])
+	assert.Equal(t, "validation error", resp["error"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketUpdate, ID: "u1", TaskID: 1, Task: &models.CreateTaskRequest{Priority: "urgent"}})
+	assert.Equal(t, "error", resp["type"])
+	assert.Equal(t, "/priority: must be one of: low, medium, high", resp["message"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketUpdate, ID: "u2", TaskID: 1, Task: &models.CreateTaskRequest{Title: "Login bauen"}})
+	assert.Equal(t, "ack", resp["type"])
+	assert.Equal(t, "Login bauen", resp["task"].(map[string]any)["title"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketMove, ID: "m1", TaskID: 2, Status: "done"})
+	assert.Equal(t, "error", resp["type"])
+	assert.Equal(t, float64(409), resp["status"])
+	assert.Equal(t, "conflict", resp["error"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketMove, ID: "m2", TaskID: 2, Status: "done", OverrideBlockers: true})
+	assert.Equal(t, "ack", resp["type"])
+	assert.Equal(t, "done", resp["task"].(map[string]any)["status"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketMove, ID: "m3", TaskID: 99, Status: "done"})
+	assert.Equal(t, float64(404), resp["status"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketMove, ID: "m4", TaskID: 1})
+	assert.Equal(t, "status is required", resp["message"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: "delete", ID: "x1"})
+	assert.Equal(t, "error", resp["type"])
+	assert.Equal(t, "x1", resp["id"])
+
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("{")))
+	resp = readSocket(t, conn)
+	assert.Equal(t, "invalid message", resp["message"])
+}
+
+// Test_Socket_Subscriptions prüft Board-Abonnements, Filter und die Auslieferung von Ereignissen.
+func Test_Socket_Subscriptions(t *testing.T) {
+	broker := &services.EventBroker{}
+	event := func(id int64, status string, assignees ...int) *models.EventPayload {
+		return &models.EventPayload{ID: id, Event: models.EventTaskUpdated, Task: &models.Task{ID: int(id), Status: status, Assignees: assignees}}
+	}
+	broker.Publish(event(1, "todo", 1))
+	broker.Publish(event(2, "todo", 1))
+
+	conn := dialSocket(t, setupSocketHandler(&services.MockTaskService{}, broker), "/ws?api_key=anna-key")
+
+	resp := roundTrip(t, conn, models.SocketMessage{Type: models.SocketSubscribe, ID: "s1", Board: "mine",
+		Filter: &models.BoardFilter{Status: []string{"todo"}, Assignee: "me"}, LastEventID: "1"})
+	assert.Equal(t, "ack", resp["type"])
+	assert.Equal(t, "mine", resp["board"])
+
+	// Rückstand ab Last-Event-ID
+	resp = readSocket(t, conn)
+	assert.Equal(t, "event", resp["type"])
+	assert.Equal(t, float64(2), resp["event"].(map[string]any)["id"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketSubscribe, ID: "s2", Board: "mine"})
+	assert.Equal(t, float64(409), resp["status"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketSubscribe, ID: "s3", Board: "all", LastEventID: "999"})
+	assert.Equal(t, "ack", resp["type"])
+	resp = readSocket(t, conn)
+	assert.Equal(t, "reset", resp["type"])
+	assert.Equal(t, "all", resp["board"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketUnsubscribe, ID: "s4", Board: "all"})
+	assert.Equal(t, "ack", resp["type"])
+
+	// Nur das passende Ereignis erreicht das Board
+	broker.Publish(event(3, "done", 1))
+	broker.Publish(event(4, "todo", 2))
+	broker.Publish(event(5, "todo", 1))
+	resp = readSocket(t, conn)
+	assert.Equal(t, "mine", resp["board"])
+	assert.Equal(t, float64(5), resp["event"].(map[string]any)["id"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketUnsubscribe, ID: "s5", Board: "mine"})
+	assert.Equal(t, "ack", resp["type"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketUnsubscribe, ID: "s6", Board: "mine"})
+	assert.Equal(t, float64(404), resp["status"])
+
+	// Vom Broker beendete Abonnements werden gemeldet
+	roundTrip(t, conn, models.SocketMessage{Type: models.SocketSubscribe, ID: "s7", Board: "mine"})
+	broker.Close()
+	resp = readSocket(t, conn)
+	assert.Equal(t, "unsubscribed", resp["type"])
+	assert.Equal(t, "mine", resp["board"])
+}
+
+// Test_Socket_Subscribe_Anonymous prüft, dass assignee "me" eine Anmeldung erfordert.
+func Test_Socket_Subscribe_Anonymous(t *testing.T) {
+	conn := dialSocket(t, setupSocketHandler(&services.MockTaskService{}, &services.EventBroker{}), "/ws")
+
+	resp := roundTrip(t, conn, models.SocketMessage{Type: models.SocketSubscribe, ID: "s1", Board: "mine", Filter: &models.BoardFilter{Assignee: "me"}})
+	assert.Equal(t, float64(401), resp["status"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketSubscribe, ID: "s2", Board: "mine", Filter: &models.BoardFilter{Assignee: "anna"}})
+	assert.Equal(t, float64(400), resp["status"])
+
+	resp = roundTrip(t, conn, models.SocketMessage{Type: models.SocketSubscribe, ID: "s3"})
+	assert.Equal(t, "board is required", resp["message"])
+}
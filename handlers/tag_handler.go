@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"task-api/models"
+	"task-api/services"
+)
+
+// TagHandler stellt die HTTP-Endpoints für Tags und deren Zuordnung zu Tasks bereit.
+type TagHandler struct {
+	Service services.TagServiceInterface
+}
+
+// tagErrorResponse übersetzt Fehler aus dem TagService in die passende HTTP-Antwort.
+func tagErrorResponse(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "not found", "task not found", "target tag not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "not found",
+			"message": err.Error(),
+		})
+	case "tag already exists":
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   "conflict",
+			"message": err.Error(),
+		})
+	case "invalid tag name", "cannot merge tag into itself":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "internal error",
+		"message": err.Error(),
+	})
+}
+
+// invalidIDResponse beantwortet Requests mit nicht-numerischer ID im Pfad.
+func invalidIDResponse(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "validation error",
+		"message": "ID must be an integer",
+	})
+}
+
+// GetAllTags verarbeitet GET /tags.
+//
+// Antwort:
+//
+//	200 - OK + Array von Tags
+//	400 - Fehler beim Laden
+func (h *TagHandler) GetAllTags(c *fiber.Ctx) error {
+	tags, err := h.Service.GetAllTags()
+	if err != nil {
+		return tagErrorResponse(c, err)
+	}
+	if tags == nil {
+		tags = []*models.Tag{}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"tags":  tags,
+		"total": len(tags),
+	})
+}
+
+// CreateTag verarbeitet POST /tags.
+//
+// Antwort:
+//
+//	201 - Tag erstellt (JSON)
+//	400 - Ungültiger Name (leer oder länger als 50 Zeichen)
+//	409 - Tag mit diesem Namen existiert bereits
+//
+// Beispiel Request-Body:
+//
+//	{
+//	  "name": "backend"
+//	}
+func (h *TagHandler) CreateTag(c *fiber.Ctx) error {
+	var req models.TagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "invalid request body",
+		})
+	}
+
+	tag, err := h.Service.CreateTag(req)
+	if err != nil {
+		return tagErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(tag)
+}
+
+// RenameTag verarbeitet PUT /tags/:id.
+//
+// Antwort:
+//
+//	200 - Tag umbenannt (JSON)
+//	400 - Ungültiger Name
+//	404 - Tag existiert nicht
+//	409 - Name wird bereits von einem anderen Tag verwendet
+func (h *TagHandler) RenameTag(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	var req models.TagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "invalid request body",
+		})
+	}
+
+	tag, err := h.Service.RenameTag(id, req)
+	if err != nil {
+		return tagErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(tag)
+}
+
+// DeleteTag verarbeitet DELETE /tags/:id.
+// Entfernt den Tag von allen Tasks und löscht ihn.
+//
+// Antwort:
+//
+//	204 - Erfolgreich gelöscht (Kein Body)
+//	404 - Tag existiert nicht
+func (h *TagHandler) DeleteTag(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	if err := h.Service.DeleteTag(id); err != nil {
+		return tagErrorResponse(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// MergeTags verarbeitet POST /tags/:id/merge.
+// Alle Tasks mit dem Tag :id erhalten den Tag into_id, anschließend wird :id gelöscht.
+//
+// Antwort:
+//
+//	200 - Zusammengeführt + Ziel-Tag (JSON)
+//	400 - Ungültige Daten / Tag in sich selbst zusammenführen
+//	404 - Quell- oder Ziel-Tag existiert nicht
+//
+// Beispiel Request-Body:
+//
+//	{
+//	  "into_id": 2
+//	}
+func (h *TagHandler) MergeTags(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	var req models.MergeTagsRequest
+	if err := c.BodyParser(&req); err != nil || req.IntoID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "into_id is required",
+		})
+	}
+
+	tag, err := h.Service.MergeTags(id, req.IntoID)
+	if err != nil {
+		return tagErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(tag)
+}
+
+// AttachTag verarbeitet POST /tasks/:id/tags.
+// Ordnet dem Task einen Tag über tag_id oder name zu; unbekannte Namen werden als neuer Tag angelegt.
+//
+// Antwort:
+//
+//	201 - Tag zugeordnet + Tag (JSON)
+//	400 - Weder tag_id noch gültiger name angegeben
+//	404 - Task oder Tag existiert nicht
+func (h *TagHandler) AttachTag(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	var req models.AttachTagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "invalid request body",
+		})
+	}
+
+	tag, err := h.Service.AttachTag(id, req)
+	if err != nil {
+		if err.Error() == "task not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "not found",
+				"message": fmt.Sprintf("Task with ID %d not found", id),
+			})
+		}
+		return tagErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(tag)
+}
+
+// DetachTag verarbeitet DELETE /tasks/:id/tags/:tagId.
+//
+// Antwort:
+//
+//	204 - Zuordnung entfernt (Kein Body)
+//	404 - Task hat diesen Tag nicht
+func (h *TagHandler) DetachTag(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+	tagID, err := strconv.Atoi(c.Params("tagId"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	if err := h.Service.DetachTag(id, tagID); err != nil {
+		return tagErrorResponse(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
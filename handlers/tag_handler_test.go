@@ -0,0 +1,114 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setupTagHandler initialisiert einen Fiber-App-Server mit allen TagHandler-Routen unter Verwendung eines Mock-Service.
+func setupTagHandler(mockService *services.MockTagService) *fiber.App {
+	app := fiber.New()
+	handler := handlers.TagHandler{Service: mockService}
+	app.Get("/tags", handler.GetAllTags)
+	app.Post("/tags", handler.CreateTag)
+	app.Put("/tags/:id", handler.RenameTag)
+	app.Delete("/tags/:id", handler.DeleteTag)
+	app.Post("/tags/:id/merge", handler.MergeTags)
+	app.Post("/tasks/:id/tags", handler.AttachTag)
+	app.Delete("/tasks/:id/tags/:tagId", handler.DetachTag)
+	return app
+}
+
+// Test_CreateTag_Handler_Conflict prüft, dass ein Tag angelegt wird (Status 201) und ein doppelter Name
+// Status 409 liefert.
+func Test_CreateTag_Handler_Conflict(t *testing.T) {
+	app := setupTagHandler(&services.MockTagService{})
+
+	for _, expected := range []int{fiber.StatusCreated, fiber.StatusConflict} {
+		body, _ := json.Marshal(models.TagRequest{Name: "backend"})
+		req := httptest.NewRequest("POST", "/tags", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, resp.StatusCode)
+	}
+}
+
+// Test_MergeTags_Handler_Success prüft, dass nach dem Zusammenführen nur noch der Ziel-Tag existiert.
+func Test_MergeTags_Handler_Success(t *testing.T) {
+	mockService := &services.MockTagService{
+		Tags: []*models.Tag{{ID: 1, Name: "bug"}, {ID: 2, Name: "defect"}},
+	}
+	app := setupTagHandler(mockService)
+
+	body, _ := json.Marshal(models.MergeTagsRequest{IntoID: 2})
+	req := httptest.NewRequest("POST", "/tags/1/merge", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/tags", nil))
+	data, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(data), "defect")
+	assert.NotContains(t, string(data), `"bug"`)
+}
+
+// Test_AttachDetachTag_Handler prüft das Zuordnen und Entfernen eines Tags sowie 404 beim erneuten Entfernen.
+func Test_AttachDetachTag_Handler(t *testing.T) {
+	app := setupTagHandler(&services.MockTagService{})
+
+	body, _ := json.Marshal(models.AttachTagRequest{Name: "frontend"})
+	req := httptest.NewRequest("POST", "/tasks/5/tags", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/tasks/5/tags/1", nil))
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/tasks/5/tags/1", nil))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test_GetTasks_Handler_TagFilters prüft die Tag-Filter tags_any, tags_all und tags_none.
+func Test_GetTasks_Handler_TagFilters(t *testing.T) {
+	mockService := &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "A", Tags: []string{"backend", "bug"}},
+			{ID: 2, Title: "B", Tags: []string{"backend"}},
+			{ID: 3, Title: "C", Tags: []string{"frontend"}},
+		},
+	}
+	app := setupFiberHandler(mockService)
+
+	cases := map[string]int{
+		"tags_any=bug,frontend":            2,
+		"tags_all=backend,bug":             1,
+		"tags_none=backend":                1,
+		"tags_any=backend&tags_none=bug":   1,
+		"tags_all=backend,%20backend,,bug": 1,
+	}
+	for query, total := range cases {
+		resp, err := app.Test(httptest.NewRequest("GET", "/tasks?"+query, nil))
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(resp.Body)
+		var body struct {
+			Total int `json:"total"`
+		}
+		assert.NoError(t, json.Unmarshal(data, &body))
+		assert.Equal(t, total, body.Total, query)
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"task-api/models"
+)
+
+// parseUserParam liest eine Benutzer-ID; "me" steht für den angemeldeten Benutzer.
+// Gibt "authentication required" zurück, wenn "me" ohne Anmeldung verwendet wird.
+func parseUserParam(c *fiber.Ctx, v string) (int, error) {
+	return parseUserID(v, currentUser(c))
+}
+
+// parseUserID liest eine Benutzer-ID; "me" steht für user (nil ohne Anmeldung).
+func parseUserID(v string, user *models.User) (int, error) {
+	if v == "me" {
+		if user == nil {
+			return 0, fmt.Errorf("authentication required")
+		}
+		return user.ID, nil
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("must be a user ID or \"me\"")
+	}
+	return id, nil
+}
+
+// assigneeErrorResponse übersetzt Fehler bei Zuweisungen und Beobachtern in die passende HTTP-Antwort.
+func assigneeErrorResponse(c *fiber.Ctx, id int, err error) error {
+	switch err.Error() {
+	case "not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "not found",
+			"message": fmt.Sprintf("Task with ID %d not found", id),
+		})
+	case "assignment not found", "watcher not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "not found",
+			"message": err.Error(),
+		})
+	case "user not found", "user belongs to another tenant":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	case "authentication required":
+		return unauthorizedResponse(c)
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "internal error",
+		"message": err.Error(),
+	})
+}
+
+// AssignUser verarbeitet POST /tasks/:id/assignees.
+//
+// Antwort:
+//
+//	201 - Benutzer zugewiesen
+//	400 - Benutzer existiert nicht / gehört zu einem anderen Mandanten
+//	404 - Task existiert nicht
+//
+// Beispiel Request-Body:
+//
+//	{
+//	  "user_id": 3
+//	}
+func (h *TaskHandler) AssignUser(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	var req models.UserRequest
+	if err := c.BodyParser(&req); err != nil || req.UserID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "user_id is required",
+		})
+	}
+
+	if err := h.Service.AssignUser(id, req.UserID, currentUser(c)); err != nil {
+		return assigneeErrorResponse(c, id, err)
+	}
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// UnassignUser verarbeitet DELETE /tasks/:id/assignees/:userId ("me" für den angemeldeten Benutzer).
+//
+// Antwort:
+//
+//	204 - Zuweisung entfernt (Kein Body)
+//	404 - Benutzer ist dem Task nicht zugewiesen
+func (h *TaskHandler) UnassignUser(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+	userID, err := parseUserParam(c, c.Params("userId"))
+	if err != nil {
+		return assigneeErrorResponse(c, id, err)
+	}
+
+	if err := h.Service.UnassignUser(id, userID); err != nil {
+		return assigneeErrorResponse(c, id, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// WatchTask verarbeitet POST /tasks/:id/watchers.
+// Ohne user_id beobachtet der angemeldete Benutzer den Task.
+//
+// Antwort:
+//
+//	201 - Beobachter eingetragen
+//	400 - Benutzer existiert nicht / gehört zu einem anderen Mandanten
+//	401 - Weder user_id noch API-Key angegeben
+//	404 - Task existiert nicht
+func (h *TaskHandler) WatchTask(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	var req models.UserRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "validation error",
+				"message": "invalid request body",
+			})
+		}
+	}
+	if req.UserID == 0 {
+		user := currentUser(c)
+		if user == nil {
+			return unauthorizedResponse(c)
+		}
+		req.UserID = user.ID
+	}
+
+	if err := h.Service.WatchTask(id, req.UserID, currentUser(c)); err != nil {
+		return assigneeErrorResponse(c, id, err)
+	}
+	return c.SendStatus(fiber.StatusCreated)
+}
+
+// UnwatchTask verarbeitet DELETE /tasks/:id/watchers/:userId ("me" für den angemeldeten Benutzer).
+//
+// Antwort:
+//
+//	204 - Beobachter entfernt (Kein Body)
+//	404 - Benutzer beobachtet den Task nicht
+func (h *TaskHandler) UnwatchTask(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+	userID, err := parseUserParam(c, c.Params("userId"))
+	if err != nil {
+		return assigneeErrorResponse(c, id, err)
+	}
+
+	if err := h.Service.UnwatchTask(id, userID); err != nil {
+		return assigneeErrorResponse(c, id, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
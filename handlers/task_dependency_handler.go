@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"task-api/models"
+)
+
+// Fehler aus dem Service, die auf eine ungültige Abhängigkeit hinweisen
+// und daher als Validierungsfehler beantwortet werden
+var dependencyErrors = map[string]bool{
+	"dependency not found":         true,
+	"task cannot depend on itself": true,
+	"dependency cycle detected":    true,
+}
+
+// GetDependencies verarbeitet GET /tasks/:id/dependencies.
+// Gibt alle Tasks zurück, die erledigt sein müssen, bevor der Task begonnen werden kann.
+//
+// Antwort:
+//
+//	200 - OK + Array von Tasks
+//	400 - Ungültige ID / Fehler beim Laden
+//	404 - Task existiert nicht
+func (h *TaskHandler) GetDependencies(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "ID must be an integer",
+		})
+	}
+
+	deps, err := h.Service.GetDependencies(id)
+	if err != nil {
+		if err.Error() == "not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "not found",
+				"message": fmt.Sprintf("Task with ID %d not found", id),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+	if deps == nil {
+		deps = []*models.Task{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"dependencies": deps,
+		"total":        len(deps),
+	})
+}
+
+// AddDependency verarbeitet POST /tasks/:id/dependencies.
+// Der Task :id kann danach erst begonnen werden, wenn der Task depends_on_id erledigt ist.
+//
+// Antwort:
+//
+//	201 - Abhängigkeit angelegt
+//	400 - Ungültige Daten / Zyklus / abhängiger Task existiert nicht
+//	404 - Task existiert nicht
+//	409 - Abhängigkeit existiert bereits
+//
+// Beispiel Request-Body:
+//
+//	{
+//	  "depends_on_id": 3
+//	}
+func (h *TaskHandler) AddDependency(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "ID must be an integer",
+		})
+	}
+
+	var req models.AddDependencyRequest
+	if err := c.BodyParser(&req); err != nil || req.DependsOnID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "depends_on_id is required",
+		})
+	}
+
+	if err := h.Service.AddDependency(id, req.DependsOnID); err != nil {
+		switch {
+		case err.Error() == "not found":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "not found",
+				"message": fmt.Sprintf("Task with ID %d not found", id),
+			})
+		case err.Error() == "dependency already exists":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":   "conflict",
+				"message": err.Error(),
+			})
+		case dependencyErrors[err.Error()]:
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "validation error",
+				"message": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"task_id":       id,
+		"depends_on_id": req.DependsOnID,
+	})
+}
+
+// RemoveDependency verarbeitet DELETE /tasks/:id/dependencies/:dependsOnId.
+//
+// Antwort:
+//
+//	204 - Abhängigkeit entfernt (Kein Body)
+//	400 - Ungültige ID / Fehler beim Löschen
+//	404 - Abhängigkeit existiert nicht
+func (h *TaskHandler) RemoveDependency(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "ID must be an integer",
+		})
+	}
+	dependsOnID, err := strconv.Atoi(c.Params("dependsOnId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "ID must be an integer",
+		})
+	}
+
+	if err := h.Service.RemoveDependency(id, dependsOnID); err != nil {
+		if err.Error() == "dependency not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "not found",
+				"message": fmt.Sprintf("Task with ID %d does not depend on task %d", id, dependsOnID),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetTopologicalOrder verarbeitet GET /tasks/order.
+// Gibt alle Tasks in einer Reihenfolge zurück, in der jeder Task erst nach
+// seinen Abhängigkeiten erscheint (z.B. für die Planung).
+//
+// Antwort:
+//
+//	200 - OK + geordnetes Array von Tasks
+//	400 - Fehler beim Laden
+func (h *TaskHandler) GetTopologicalOrder(c *fiber.Ctx) error {
+	tasks, err := h.Service.GetTopologicalOrder()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+	if tasks == nil {
+		tasks = []*models.Task{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"tasks": tasks,
+		"total": len(tasks),
+	})
+}
@@ -0,0 +1,87 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Test_AddDependency_Handler_Success prüft, dass eine Abhängigkeit angelegt (Status 201) und anschließend
+// über GET /tasks/:id/dependencies zurückgegeben wird.
+func Test_AddDependency_Handler_Success(t *testing.T) {
+	mockService := &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "Datenbank aufsetzen", Status: "todo"},
+			{ID: 2, Title: "API bauen", Status: "todo"},
+		},
+	}
+	app := setupFiberHandler(mockService)
+
+	body, _ := json.Marshal(models.AddDependencyRequest{DependsOnID: 1})
+	req := httptest.NewRequest("POST", "/tasks/2/dependencies", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/tasks/2/dependencies", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	data, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(data), "Datenbank aufsetzen")
+}
+
+// Test_UpdateTask_Handler_Blocked prüft, dass ein Statuswechsel bei offenen Abhängigkeiten mit Status 409
+// abgelehnt wird.
+func Test_UpdateTask_Handler_Blocked(t *testing.T) {
+	mockService := &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "Datenbank aufsetzen", Status: "todo"},
+			{ID: 2, Title: "API bauen", Status: "todo"},
+		},
+		Dependencies: map[int][]int{2: {1}},
+	}
+	app := setupFiberHandler(mockService)
+
+	body, _ := json.Marshal(models.CreateTaskRequest{Status: "in progress"})
+	req := httptest.NewRequest("PUT", "/tasks/2", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
+
+// Test_RemoveDependency_Handler_NotFound prüft, dass das Entfernen einer nicht existierenden Abhängigkeit
+// Status 404 liefert.
+func Test_RemoveDependency_Handler_NotFound(t *testing.T) {
+	app := setupFiberHandler(&services.MockTaskService{})
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/tasks/2/dependencies/1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test_GetTopologicalOrder_Handler_Success prüft, dass GET /tasks/order nicht von /tasks/:id abgefangen wird.
+func Test_GetTopologicalOrder_Handler_Success(t *testing.T) {
+	mockService := &services.MockTaskService{
+		Tasks: []*models.Task{{ID: 1, Title: "Planen"}},
+	}
+	app := setupFiberHandler(mockService)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/tasks/order", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	data, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(data), "Planen")
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"task-api/export"
+	"task-api/models"
+)
+
+// exportFlushRows ist die Anzahl Zeilen, nach der ein Export an den Client weitergegeben wird.
+const exportFlushRows = 500
+
+// exportFormat bestimmt das Exportformat aus format= oder, ohne Parameter, aus dem Accept-Header.
+// Ohne Accept-Header (oder mit */*) wird CSV geliefert; ok ist false, wenn kein Format akzeptiert wird.
+func exportFormat(c *fiber.Ctx) (f export.Format, ok bool, err error) {
+	if name := c.Query("format"); name != "" {
+		if !slices.Contains(export.Formats, export.Format(name)) {
+			return "", false, fmt.Errorf("format must be one of: %s", strings.Join(exportFormatNames(), ", "))
+		}
+		return export.Format(name), true, nil
+	}
+
+	offers := make([]string, len(export.Formats))
+	for i, f := range export.Formats {
+		offers[i] = f.MediaType()
+	}
+	accepted := c.Accepts(offers...)
+	for _, f := range export.Formats {
+		if f.MediaType() == accepted {
+			return f, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// exportFormatNames gibt die Namen aller Exportformate für format= zurück.
+func exportFormatNames() []string {
+	names := make([]string, len(export.Formats))
+	for i, f := range export.Formats {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// ExportTasks verarbeitet GET /tasks/export.
+// Exportiert alle Tasks mit denselben Filtern wie GET /tasks als CSV, NDJSON oder XLSX. Das Format kommt
+// aus format= oder dem Accept-Header, die Spalten aus fields= (ohne: alle Felder). Die Zeilen werden direkt
+// aus einem Datenbank-Cursor an den Client geschrieben.
+// Antwort:
+//
+//	200 - Export als Datei (Content-Disposition: attachment)
+//	400 - Ungültige Filter, fields=, format= oder include=
+//	406 - Kein unterstütztes Format im Accept-Header
+//	500 - Datenbankfehler beim Öffnen des Cursors
+func (h *TaskHandler) ExportTasks(c *fiber.Ctx) error {
+	format, ok, err := exportFormat(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	}
+	if !ok {
+		return c.Status(fiber.StatusNotAcceptable).JSON(fiber.Map{
+			"error":   "not acceptable",
+			"message": "Export is available as text/csv, application/x-ndjson or XLSX",
+		})
+	}
+
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "authentication required") {
+			return unauthorizedResponse(c)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	}
+	projection, err := parseTaskProjection(c, nil)
+	if err == nil && len(projection.Include) > 0 {
+		err = fmt.Errorf("include is not supported for exports")
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		})
+	}
+
+	// Der Cursor wird vor dem Streamen geöffnet, damit Datenbankfehler noch als Fehlerantwort ankommen
+	cursor, err := h.Service.ExportTasks(filter, projection)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+
+	columns := projection.Fields
+	if len(columns) == 0 {
+		columns = models.TaskFields
+	}
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cursor.Close()
+
+		// Fehler während des Streamens können nicht mehr gemeldet werden; der Export bricht dann ab
+		out, err := export.NewWriter(format, w, columns)
+		if err != nil {
+			return
+		}
+		values := make([]any, len(columns))
+		for rows := 1; ; rows++ {
+			t, err := cursor.Next()
+			if err != nil {
+				return
+			}
+			if t == nil {
+				break
+			}
+			v := reflect.ValueOf(t).Elem()
+			for i, col := range columns {
+				values[i] = v.Field(taskFieldIndex()[col]).Interface()
+			}
+			if err := out.WriteRow(values); err != nil {
+				return
+			}
+			if rows%exportFlushRows == 0 {
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}
+		if err := out.Close(); err != nil {
+			return
+		}
+		w.Flush()
+	})
+	return nil
+}
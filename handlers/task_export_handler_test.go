@@ -0,0 +1,99 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-api/services"
+	"testing"
+)
+
+// exportRequest ruft GET /tasks/export mit optionalem Accept-Header ab.
+func exportRequest(t *testing.T, app *fiber.App, path, accept string) (*http.Response, []byte) {
+	req := httptest.NewRequest("GET", path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	return resp, data
+}
+
+// Test_ExportTasks_Handler_CSVByDefault prüft, dass ohne Format CSV mit allen Feldern als Datei geliefert wird.
+func Test_ExportTasks_Handler_CSVByDefault(t *testing.T) {
+	app := setupFiberHandler(projectionService())
+
+	resp, data := exportRequest(t, app, "/tasks/export", "")
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="tasks.csv"`, resp.Header.Get("Content-Disposition"))
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "id,title,description,status,"))
+	assert.True(t, strings.HasPrefix(lines[1], "1,Release,Version 2,todo,"))
+}
+
+// Test_ExportTasks_Handler_FieldsAndFilters prüft fields= und die Filter von GET /tasks im NDJSON-Export.
+func Test_ExportTasks_Handler_FieldsAndFilters(t *testing.T) {
+	app := setupFiberHandler(projectionService())
+
+	resp, data := exportRequest(t, app, "/tasks/export?format=ndjson&fields=title,tags&tags_any=backend", "")
+
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `{"id":1,"title":"Release","tags":["backend"]}`+"\n", string(data))
+}
+
+// Test_ExportTasks_Handler_AcceptNegotiation prüft die Formatwahl über den Accept-Header.
+func Test_ExportTasks_Handler_AcceptNegotiation(t *testing.T) {
+	app := setupFiberHandler(projectionService())
+
+	resp, data := exportRequest(t, app, "/tasks/export?fields=title", "application/x-ndjson")
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	var first map[string]any
+	assert.NoError(t, json.Unmarshal(bytes.SplitN(data, []byte("\n"), 2)[0], &first))
+	assert.Equal(t, "Release", first["title"])
+
+	resp, data = exportRequest(t, app, "/tasks/export", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, `attachment; filename="tasks.xlsx"`, resp.Header.Get("Content-Disposition"))
+	_, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+
+	resp, _ = exportRequest(t, app, "/tasks/export", "application/pdf")
+	assert.Equal(t, fiber.StatusNotAcceptable, resp.StatusCode)
+}
+
+// Test_ExportTasks_Handler_InvalidParams prüft die Validierungsfehler für format=, fields= und include=.
+func Test_ExportTasks_Handler_InvalidParams(t *testing.T) {
+	app := setupFiberHandler(projectionService())
+
+	for _, path := range []string{
+		"/tasks/export?format=pdf",
+		"/tasks/export?fields=unknown",
+		"/tasks/export?include=tags",
+		"/tasks/export?overdue=maybe",
+	} {
+		resp, data := exportRequest(t, app, path, "")
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, path)
+		assert.Contains(t, string(data), "validation error", path)
+	}
+}
+
+// Test_ExportTasks_Handler_ServiceError prüft, dass Fehler beim Öffnen des Cursors vor dem Streamen gemeldet werden.
+func Test_ExportTasks_Handler_ServiceError(t *testing.T) {
+	app := setupFiberHandler(&services.MockTaskService{ShouldFail: true})
+
+	resp, data := exportRequest(t, app, "/tasks/export", "")
+
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	assert.Contains(t, string(data), "internal error")
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"strings"
+	"task-api/models"
+	"time"
+)
+
+// parseTaskFilter liest die Filter-Parameter von GET /tasks aus der Query.
+// Gibt einen Fehler mit verständlicher Meldung zurück, wenn ein Parameter ungültig ist.
+func parseTaskFilter(c *fiber.Ctx) (models.TaskFilter, error) {
+	var filter models.TaskFilter
+
+	loc := time.UTC
+	if tz := c.Query("tz"); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return filter, fmt.Errorf("tz must be a valid IANA time zone, e.g. Europe/Berlin")
+		}
+		loc = l
+	}
+
+	if v := c.Query("overdue"); v != "" {
+		overdue, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("overdue must be true or false")
+		}
+		filter.Overdue = &overdue
+	}
+
+	var err error
+	if filter.DueBefore, err = parseTimeParam(c.Query("due_before"), loc); err != nil {
+		return filter, fmt.Errorf("due_before %s", err.Error())
+	}
+	if filter.DueAfter, err = parseTimeParam(c.Query("due_after"), loc); err != nil {
+		return filter, fmt.Errorf("due_after %s", err.Error())
+	}
+
+	filter.TagsAny = parseListParam(c.Query("tags_any"))
+	filter.TagsAll = parseListParam(c.Query("tags_all"))
+	filter.TagsNone = parseListParam(c.Query("tags_none"))
+
+	for _, p := range []struct {
+		name   string
+		target **int
+	}{{"assignee", &filter.Assignee}, {"watcher", &filter.Watcher}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		id, err := parseUserParam(c, v)
+		if err != nil {
+			return filter, fmt.Errorf("%s %s", p.name, err.Error())
+		}
+		*p.target = &id
+	}
+
+	return filter, nil
+}
+
+// maxTaskPageSize ist der größte erlaubte Wert von limit= bei GET /tasks.
+const maxTaskPageSize = 100
+
+// parseTaskPage liest limit= (1 bis maxTaskPageSize) und offset= aus der Query.
+func parseTaskPage(c *fiber.Ctx) (models.TaskPage, error) {
+	var page models.TaskPage
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxTaskPageSize {
+			return page, fmt.Errorf("limit must be between 1 and %d", maxTaskPageSize)
+		}
+		page.Limit = limit
+	}
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("offset must be a non-negative integer")
+		}
+		page.Offset = offset
+	}
+	return page, nil
+}
+
+// parseListParam zerlegt eine kommagetrennte Liste, entfernt Leerzeichen, leere Einträge und Duplikate.
+// Gibt nil zurück, wenn die Liste leer ist.
+func parseListParam(v string) []string {
+	var list []string
+	seen := map[string]bool{}
+	for _, item := range strings.Split(v, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		list = append(list, item)
+	}
+	return list
+}
+
+// parseTimeParam parst einen Zeitpunkt als RFC 3339 oder als Datum (YYYY-MM-DD, Tagesbeginn in loc).
+// Gibt nil zurück, wenn der Wert leer ist.
+func parseTimeParam(v string, loc *time.Location) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", v, loc); err == nil {
+		return &t, nil
+	}
+	return nil, fmt.Errorf("must be an RFC 3339 timestamp or a date (YYYY-MM-DD)")
+}
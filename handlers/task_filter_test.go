@@ -0,0 +1,91 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Test_GetTasks_Handler_DueFilters prüft die Filter overdue und due_before inklusive Zeitzone.
+func Test_GetTasks_Handler_DueFilters(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	early := time.Date(2025, 3, 1, 8, 0, 0, 0, berlin)
+	late := time.Date(2025, 3, 2, 8, 0, 0, 0, berlin)
+
+	mockService := &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "Früh", DueAt: &early, IsOverdue: true},
+			{ID: 2, Title: "Spät", DueAt: &late},
+			{ID: 3, Title: "Ohne Termin"},
+		},
+	}
+	app := setupFiberHandler(mockService)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/tasks?overdue=true", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	data, _ := io.ReadAll(resp.Body)
+	var body struct {
+		Tasks []map[string]any `json:"tasks"`
+		Total int              `json:"total"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &body))
+	assert.Equal(t, 1, body.Total)
+	assert.Equal(t, true, body.Tasks[0]["is_overdue"])
+
+	// 2025-03-02 in Berlin beginnt um 23:00 UTC am Vortag, daher ist nur "Früh" davor fällig
+	resp, err = app.Test(httptest.NewRequest("GET", "/tasks?due_before=2025-03-02&tz=Europe/Berlin", nil))
+	assert.NoError(t, err)
+	data, _ = io.ReadAll(resp.Body)
+	assert.NoError(t, json.Unmarshal(data, &body))
+	assert.Equal(t, 1, body.Total)
+	assert.Equal(t, "Früh", body.Tasks[0]["title"])
+}
+
+// Test_GetTasks_Handler_InvalidFilter prüft, dass ungültige Filterwerte mit Status 400 abgelehnt werden.
+func Test_GetTasks_Handler_InvalidFilter(t *testing.T) {
+	app := setupFiberHandler(&services.MockTaskService{})
+
+	for _, query := range []string{"overdue=vielleicht", "due_before=morgen", "due_after=2025-03-01&tz=Mars/Olympus", "limit=0", "limit=101", "offset=-1"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/tasks?"+query, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
+// Test_GetTasks_Handler_Page prüft limit= und offset=: total zählt alle Tasks, nicht nur den Ausschnitt.
+func Test_GetTasks_Handler_Page(t *testing.T) {
+	mockService := &services.MockTaskService{
+		Tasks: []*models.Task{{ID: 1, Title: "A"}, {ID: 2, Title: "B"}, {ID: 3, Title: "C"}},
+	}
+	app := setupFiberHandler(mockService)
+
+	var body struct {
+		Tasks []map[string]any `json:"tasks"`
+		Total int              `json:"total"`
+	}
+	for query, ids := range map[string][]float64{
+		"limit=2":          {1, 2},
+		"limit=2&offset=2": {3},
+		"offset=5":         {},
+	} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/tasks?"+query, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode, query)
+		data, _ := io.ReadAll(resp.Body)
+		assert.NoError(t, json.Unmarshal(data, &body))
+		assert.Equal(t, 3, body.Total, query)
+		got := []float64{}
+		for _, task := range body.Tasks {
+			got = append(got, task["id"].(float64))
+		}
+		assert.Equal(t, ids, got, query)
+	}
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"strings"
+	"task-api/models"
+	"task-api/proto/taskpb"
+	"task-api/services"
+	"time"
+)
+
+// TaskGRPCServer stellt die Task-API per gRPC bereit (proto/task.proto).
+// Validierung und Fehlerbehandlung werden mit dem TaskHandler geteilt, sodass gRPC und REST
+// dieselben Eingaben akzeptieren und dieselben Fehlermeldungen liefern.
+type TaskGRPCServer struct {
+	taskpb.UnimplementedTaskServiceServer
+
+	Tasks  *TaskHandler
+	Broker services.EventBrokerInterface
+}
+
+// GRPCAuthenticate liefert Interceptoren, die den API-Key aus den Metadata "authorization: Bearer <key>"
+// oder "x-api-key" lesen und den Benutzer im Context ablegen. Aufrufe ohne Key bleiben wie bei der
+// REST-API anonym; ein unbekannter Key wird mit UNAUTHENTICATED abgelehnt.
+func GRPCAuthenticate(users services.UserServiceInterface) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	authenticate := func(ctx context.Context) (context.Context, error) {
+		key := apiKeyFromMetadata(ctx)
+		if key == "" {
+			return ctx, nil
+		}
+		user, err := users.Authenticate(key)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		}
+		return context.WithValue(ctx, userContextKey{}, user), nil
+	}
+
+	unary := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+	return unary, stream
+}
+
+// authenticatedStream ersetzt den Context eines Streams durch den Context mit dem angemeldeten Benutzer.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context gibt den Context mit dem angemeldeten Benutzer zurück.
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// apiKeyFromMetadata liest den API-Key aus den Metadata "authorization" oder "x-api-key".
+func apiKeyFromMetadata(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, auth := range md.Get("authorization") {
+		if key, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return strings.TrimSpace(key)
+		}
+	}
+	if keys := md.Get("x-api-key"); len(keys) > 0 {
+		return strings.TrimSpace(keys[0])
+	}
+	return ""
+}
+
+// grpcError übersetzt eine Fehlerantwort der REST-API (HTTP-Status und Body) in einen gRPC-Status.
+func grpcError(httpStatus int, body fiber.Map) error {
+	message, _ := body["message"].(string)
+	if body["error"] == "internal error" {
+		return status.Error(codes.Internal, message)
+	}
+	switch httpStatus {
+	case fiber.StatusBadRequest:
+		return invalidArgumentDetails(message, body)
+	case fiber.StatusUnauthorized:
+		return status.Error(codes.Unauthenticated, message)
+	case fiber.StatusNotFound:
+		return status.Error(codes.NotFound, message)
+	case fiber.StatusConflict:
+		return status.Error(codes.FailedPrecondition, message)
+	}
+	return status.Error(codes.Internal, message)
+}
+
+// invalidArgumentDetails liefert einen Validierungsfehler; einzelne Feldfehler aus body["errors"]
+// werden als google.rpc.BadRequest-Details angehängt (Feld als JSON Pointer, z.B. "/title").
+func invalidArgumentDetails(message string, body fiber.Map) error {
+	st := status.New(codes.InvalidArgument, message)
+	fields, _ := body["errors"].([]fieldError)
+	if len(fields) == 0 {
+		return st.Err()
+	}
+	details := &errdetails.BadRequest{}
+	for _, fe := range fields {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fe.Pointer,
+			Description: fe.Message,
+		})
+	}
+	if withDetails, err := st.WithDetails(details); err == nil {
+		return withDetails.Err()
+	}
+	return st.Err()
+}
+
+// invalidArgument liefert einen Validierungsfehler.
+func invalidArgument(message string) error {
+	return status.Error(codes.InvalidArgument, message)
+}
+
+// CreateTask erstellt einen Task (wie POST /tasks).
+func (s *TaskGRPCServer) CreateTask(_ context.Context, in *taskpb.CreateTaskRequest) (*taskpb.Task, error) {
+	req := models.CreateTaskRequest{
+		Title:          in.GetTitle(),
+		Description:    in.GetDescription(),
+		Status:         in.GetStatus(),
+		Priority:       in.GetPriority(),
+		ParentID:       intPtr(in.ParentId),
+		StartAt:        timePtr(in.GetStartAt()),
+		DueAt:          timePtr(in.GetDueAt()),
+		RecurrenceRule: in.RecurrenceRule,
+	}
+
+	task, err := s.Tasks.Service.CreateTask(req)
+	if err != nil {
+		return nil, grpcError(createTaskError(err))
+	}
+	return taskToProto(task), nil
+}
+
+// GetTask liefert einen Task anhand seiner ID (wie GET /tasks/:id).
+func (s *TaskGRPCServer) GetTask(_ context.Context, in *taskpb.GetTaskRequest) (*taskpb.Task, error) {
+	task, err := s.Tasks.Service.GetTaskByID(int(in.GetId()))
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, status.Errorf(codes.NotFound, "Task with ID %d not found", in.GetId())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return taskToProto(task), nil
+}
+
+// ListTasks liefert eine Seite der Tasks, die den Filtern entsprechen (wie GET /tasks), und deren Gesamtanzahl.
+// page_size ist wie limit= auf 1 bis maxTaskPageSize begrenzt; ohne page_size wird die größte Seite geliefert.
+func (s *TaskGRPCServer) ListTasks(ctx context.Context, in *taskpb.ListTasksRequest) (*taskpb.ListTasksResponse, error) {
+	if in.GetPageSize() < 0 || in.GetPageSize() > maxTaskPageSize {
+		return nil, invalidArgument(fmt.Sprintf("page_size must be between 1 and %d", maxTaskPageSize))
+	}
+	if in.GetOffset() < 0 {
+		return nil, invalidArgument("offset must be a non-negative integer")
+	}
+	page := models.TaskPage{Limit: int(in.GetPageSize()), Offset: int(in.GetOffset())}
+	if page.Limit == 0 {
+		page.Limit = maxTaskPageSize
+	}
+
+	filter := models.TaskFilter{
+		Overdue:   in.Overdue,
+		DueBefore: timePtr(in.GetDueBefore()),
+		DueAfter:  timePtr(in.GetDueAfter()),
+		TagsAny:   in.GetTagsAny(),
+		TagsAll:   in.GetTagsAll(),
+		TagsNone:  in.GetTagsNone(),
+	}
+	for _, p := range []struct {
+		name   string
+		value  string
+		target **int
+	}{{"assignee", in.GetAssignee(), &filter.Assignee}, {"watcher", in.GetWatcher(), &filter.Watcher}} {
+		if p.value == "" {
+			continue
+		}
+		id, err := parseUserID(p.value, userFromContext(ctx))
+		if err != nil {
+			if err.Error() == "authentication required" {
+				return nil, status.Error(codes.Unauthenticated, "authentication required")
+			}
+			return nil, invalidArgument(fmt.Sprintf("%s %s", p.name, err.Error()))
+		}
+		*p.target = &id
+	}
+
+	tasks, total, err := s.Tasks.Service.ListTasks(filter, models.TaskProjection{}, page)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &taskpb.ListTasksResponse{Total: int32(total)}
+	for _, t := range tasks {
+		resp.Tasks = append(resp.Tasks, taskToProto(t))
+	}
+	return resp, nil
+}
+
+// UpdateTask aktualisiert einen Task (wie PUT /tasks/:id).
+func (s *TaskGRPCServer) UpdateTask(_ context.Context, in *taskpb.UpdateTaskRequest) (*taskpb.Task, error) {
+	req := models.CreateTaskRequest{
+		Title:            in.GetTitle(),
+		Description:      in.GetDescription(),
+		Status:           in.GetStatus(),
+		Priority:         in.GetPriority(),
+		ParentID:         intPtr(in.ParentId),
+		StartAt:          timePtr(in.GetStartAt()),
+		DueAt:            timePtr(in.GetDueAt()),
+		RecurrenceRule:   in.RecurrenceRule,
+		Scope:            in.GetScope(),
+		OverrideBlockers: in.GetOverrideBlockers(),
+		Reopen:           in.GetReopen(),
+	}
+
+	id := int(in.GetId())
+	task, err := s.Tasks.Service.UpdateTask(id, req)
+	if err != nil {
+		return nil, grpcError(s.Tasks.updateTaskError(id, req, err))
+	}
+	return taskToProto(task), nil
+}
+
+// DeleteTask löscht einen Task (wie DELETE /tasks/:id).
+func (s *TaskGRPCServer) DeleteTask(_ context.Context, in *taskpb.DeleteTaskRequest) (*emptypb.Empty, error) {
+	id := int(in.GetId())
+	if err := s.Tasks.Service.DeleteTask(id); err != nil {
+		return nil, grpcError(deleteTaskError(id, err))
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// WatchTasks liefert alle Änderungen an Tasks als Stream (wie GET /tasks/events), bis der Client
+// den Aufruf beendet oder der Broker das Abonnement schließt.
+func (s *TaskGRPCServer) WatchTasks(in *taskpb.WatchTasksRequest, stream taskpb.TaskService_WatchTasksServer) error {
+	filter := models.TaskEventFilter{Statuses: in.GetStatuses(), Tags: in.GetTags()}
+	if v := in.GetAssignee(); v != "" {
+		id, err := parseUserID(v, userFromContext(stream.Context()))
+		if err != nil {
+			if err.Error() == "authentication required" {
+				return status.Error(codes.Unauthenticated, "authentication required")
+			}
+			return invalidArgument("assignee " + err.Error())
+		}
+		filter.Assignee = &id
+	}
+
+	sub, backlog, resumed := s.Broker.Subscribe(filter, in.GetLastEventId())
+	defer s.Broker.Unsubscribe(sub)
+
+	if !resumed {
+		if err := stream.Send(&taskpb.TaskEvent{Event: "reset"}); err != nil {
+			return err
+		}
+	}
+	for _, event := range backlog {
+		if err := stream.Send(eventToProto(event)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-sub.Events:
+			if !ok {
+				return status.Error(codes.Unavailable, "subscription closed; watch again with last_event_id")
+			}
+			if err := stream.Send(eventToProto(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// taskToProto wandelt einen Task in die Protobuf-Nachricht um.
+func taskToProto(t *models.Task) *taskpb.Task {
+	pb := &taskpb.Task{
+		Id:             int64(t.ID),
+		Title:          t.Title,
+		Description:    t.Description,
+		Status:         t.Status,
+		Priority:       t.Priority,
+		ParentId:       int64Ptr(t.ParentID),
+		StartAt:        timestampOf(t.StartAt),
+		DueAt:          timestampOf(t.DueAt),
+		IsOverdue:      t.IsOverdue,
+		RecurrenceRule: t.RecurrenceRule,
+		SeriesId:       int64Ptr(t.SeriesID),
+		Tags:           t.Tags,
+		CreatedAt:      timestamppb.New(t.CreatedAt),
+		UpdatedAt:      timestamppb.New(t.UpdatedAt),
+	}
+	if t.Progress != nil {
+		progress := int32(*t.Progress)
+		pb.Progress = &progress
+	}
+	for _, id := range t.Assignees {
+		pb.Assignees = append(pb.Assignees, int64(id))
+	}
+	for _, id := range t.Watchers {
+		pb.Watchers = append(pb.Watchers, int64(id))
+	}
+	return pb
+}
+
+// eventToProto wandelt ein Ereignis des EventBrokers in die Protobuf-Nachricht um.
+func eventToProto(e *models.EventPayload) *taskpb.TaskEvent {
+	pb := &taskpb.TaskEvent{Id: e.ID, Event: e.Event, OccurredAt: timestamppb.New(e.OccurredAt)}
+	if e.Task != nil {
+		pb.Task = taskToProto(e.Task)
+	}
+	return pb
+}
+
+// intPtr wandelt einen optionalen int64 aus Protobuf in *int um.
+func intPtr(v *int64) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+// int64Ptr wandelt *int in einen optionalen int64 für Protobuf um.
+func int64Ptr(v *int) *int64 {
+	if v == nil {
+		return nil
+	}
+	i := int64(*v)
+	return &i
+}
+
+// timePtr wandelt einen optionalen Timestamp in *time.Time um.
+func timePtr(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// timestampOf wandelt *time.Time in einen optionalen Timestamp um.
+func timestampOf(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,211 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/proto/taskpb"
+	"task-api/services"
+	"testing"
+	"time"
+)
+
+// setupGRPCServer startet den gRPC-Server im Speicher und gibt einen verbundenen Client zurück.
+// Benutzer 1 meldet sich mit "anna-key" an.
+func setupGRPCServer(t *testing.T, tasks *services.MockTaskService, broker *services.EventBroker) taskpb.TaskServiceClient {
+	users := &services.MockUserService{
+		Users:   testUsers(),
+		APIKeys: map[string]int{"anna-key": 1},
+	}
+	unary, stream := handlers.GRPCAuthenticate(users)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream))
+	taskpb.RegisterTaskServiceServer(srv, &handlers.TaskGRPCServer{Tasks: &handlers.TaskHandler{Service: tasks}, Broker: broker})
+
+	ln := bufconn.Listen(1 << 20)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { conn.Close() })
+	return taskpb.NewTaskServiceClient(conn)
+}
+
+// withAPIKey hängt den API-Key als Metadata an den Context.
+func withAPIKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+key)
+}
+
+// Test_GRPC_CreateTask prüft Erstellung und Validierung wie bei POST /tasks.
+func Test_GRPC_CreateTask(t *testing.T) {
+	client := setupGRPCServer(t, &services.MockTaskService{}, &services.EventBroker{})
+
+	task, err := client.CreateTask(context.Background(), &taskpb.CreateTaskRequest{Title: "Einkaufen", Priority: "high"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Einkaufen", task.GetTitle())
+	assert.Equal(t, "high", task.GetPriority())
+
+	_, err = client.CreateTask(context.Background(), &taskpb.CreateTaskRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "/title: is required", status.Convert(err).Message())
+
+	_, err = client.CreateTask(context.Background(), &taskpb.CreateTaskRequest{Title: "X", Status: "archived", Priority: "urgent"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Alle Feldfehler stehen als google.rpc.BadRequest in den Details
+	details := status.Convert(err).Details()
+	if assert.Len(t, details, 1) {
+		violations := details[0].(*errdetails.BadRequest).GetFieldViolations()
+		if assert.Len(t, violations, 2) {
+			assert.Equal(t, "/priority", violations[0].GetField())
+			assert.Equal(t, "/status", violations[1].GetField())
+		}
+	}
+}
+
+// Test_GRPC_GetUpdateDelete prüft die Fehlerabbildung auf gRPC-Statuscodes.
+func Test_GRPC_GetUpdateDelete(t *testing.T) {
+	tasks := &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "Login", Status: "todo", Tags: []string{"backend"}, Assignees: []int{1}},
+			{ID: 2, Title: "Deploy", Status: "todo"},
+		},
+		Dependencies: map[int][]int{2: {1}},
+	}
+	client := setupGRPCServer(t, tasks, &services.EventBroker{})
+	ctx := context.Background()
+
+	task, err := client.GetTask(ctx, &taskpb.GetTaskRequest{Id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backend"}, task.GetTags())
+	assert.Equal(t, []int64{1}, task.GetAssignees())
+
+	_, err = client.GetTask(ctx, &taskpb.GetTaskRequest{Id: 99})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{Id: 2, Status: "done"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	task, err = client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{Id: 2, Status: "done", OverrideBlockers: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "done", task.GetStatus())
+
+	_, err = client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{Id: 1, Priority: "urgent"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.UpdateTask(ctx, &taskpb.UpdateTaskRequest{Id: 99, Title: "X"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.DeleteTask(ctx, &taskpb.DeleteTaskRequest{Id: 2})
+	assert.NoError(t, err)
+
+	_, err = client.DeleteTask(ctx, &taskpb.DeleteTaskRequest{Id: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// Test_GRPC_ListTasks prüft Filter und Authentifizierung per Metadata.
+func Test_GRPC_ListTasks(t *testing.T) {
+	tasks := &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "Login", Status: "todo", Assignees: []int{1}},
+			{ID: 2, Title: "Deploy", Status: "todo", Assignees: []int{2}},
+		},
+	}
+	client := setupGRPCServer(t, tasks, &services.EventBroker{})
+
+	resp, err := client.ListTasks(context.Background(), &taskpb.ListTasksRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), resp.GetTotal())
+
+	resp, err = client.ListTasks(withAPIKey("anna-key"), &taskpb.ListTasksRequest{Assignee: "me"})
+	assert.NoError(t, err)
+	if assert.Len(t, resp.GetTasks(), 1) {
+		assert.Equal(t, int64(1), resp.GetTasks()[0].GetId())
+	}
+
+	_, err = client.ListTasks(context.Background(), &taskpb.ListTasksRequest{Assignee: "me"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.ListTasks(context.Background(), &taskpb.ListTasksRequest{Watcher: "anna"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.ListTasks(withAPIKey("wrong"), &taskpb.ListTasksRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// Test_GRPC_ListTasks_Paging prüft page_size und offset sowie die Gesamtanzahl über alle Seiten.
+func Test_GRPC_ListTasks_Paging(t *testing.T) {
+	tasks := &services.MockTaskService{}
+	for i := 1; i <= 120; i++ {
+		tasks.Tasks = append(tasks.Tasks, &models.Task{ID: i, Title: fmt.Sprintf("Task %d", i), Status: "todo"})
+	}
+	client := setupGRPCServer(t, tasks, &services.EventBroker{})
+
+	resp, err := client.ListTasks(context.Background(), &taskpb.ListTasksRequest{PageSize: 10, Offset: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(120), resp.GetTotal())
+	if assert.Len(t, resp.GetTasks(), 10) {
+		assert.Equal(t, int64(21), resp.GetTasks()[0].GetId())
+	}
+
+	resp, err = client.ListTasks(context.Background(), &taskpb.ListTasksRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, resp.GetTasks(), 100)
+	assert.Equal(t, int32(120), resp.GetTotal())
+
+	for _, in := range []*taskpb.ListTasksRequest{{PageSize: 101}, {PageSize: -1}, {Offset: -1}} {
+		_, err = client.ListTasks(context.Background(), in)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), in.String())
+	}
+}
+
+// Test_GRPC_WatchTasks prüft Filter und Wiederaufnahme des Ereignis-Streams.
+func Test_GRPC_WatchTasks(t *testing.T) {
+	broker := &services.EventBroker{}
+	event := func(id int64, status string) *models.EventPayload {
+		return &models.EventPayload{ID: id, Event: models.EventTaskUpdated, Task: &models.Task{ID: int(id), Status: status}}
+	}
+	broker.Publish(event(1, "todo"))
+	broker.Publish(event(2, "todo"))
+	client := setupGRPCServer(t, &services.MockTaskService{}, broker)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	stream, err := client.WatchTasks(ctx, &taskpb.WatchTasksRequest{Statuses: []string{"todo"}, LastEventId: "1"})
+	assert.NoError(t, err)
+
+	// Rückstand ab Last-Event-ID
+	e, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), e.GetId())
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		broker.Publish(event(3, "done"))
+		broker.Publish(event(4, "todo"))
+	}()
+	e, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), e.GetId())
+	assert.Equal(t, "task.updated", e.GetEvent())
+
+	reset, err := client.WatchTasks(ctx, &taskpb.WatchTasksRequest{LastEventId: "999"})
+	assert.NoError(t, err)
+	e, err = reset.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "reset", e.GetEvent())
+}
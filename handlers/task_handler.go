@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"strconv"
+	"strings"
 	"task-api/models"
 	"task-api/services"
+	"task-api/validation"
 )
 
 // TaskHandler stellt die HTTP-Schicht dar und verbindet eingehende Requests
@@ -14,22 +17,99 @@ type TaskHandler struct {
 	Service services.TaskServiceInterface
 }
 
-// Erlaubte Priorities für die Validierung der Task
-// "" bedeutet kein gesetzter Wert
-var allowedPriorities = map[string]bool{
-	"low":    true,
-	"medium": true,
-	"high":   true,
-	"":       true,
+// validationErrorBody übersetzt validation.Errors aus dem TaskService in den Antwort-Body eines
+// Validierungsfehlers mit allen Verstößen, im selben Format wie die Middleware ValidateRequests.
+// Gibt false zurück, wenn err keine validation.Errors enthält.
+func validationErrorBody(err error) (fiber.Map, bool) {
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		return nil, false
+	}
+	fields := make([]fieldError, len(errs))
+	for i, fe := range errs {
+		fields[i] = fieldError{In: "body", Pointer: "/" + escapePointer(fe.Field), Message: fe.Message}
+	}
+	return fiber.Map{
+		"error":   "validation error",
+		"message": errorSummary(fields),
+		"errors":  fields,
+	}, true
+}
+
+// createTaskError übersetzt Fehler aus TaskService.CreateTask in HTTP-Status und Antwort-Body.
+func createTaskError(err error) (int, fiber.Map) {
+	if body, ok := validationErrorBody(err); ok {
+		return fiber.StatusBadRequest, body
+	}
+	return fiber.StatusBadRequest, fiber.Map{
+		"error":   "validation error",
+		"message": err.Error(),
+	}
+}
+
+// updateTaskError übersetzt Fehler aus TaskService.UpdateTask in HTTP-Status und Antwort-Body.
+func (h *TaskHandler) updateTaskError(id int, req models.CreateTaskRequest, err error) (int, fiber.Map) {
+	if body, ok := validationErrorBody(err); ok {
+		return fiber.StatusBadRequest, body
+	}
+	switch {
+	case err.Error() == "not found":
+		return fiber.StatusNotFound, fiber.Map{
+			"error":   "not found",
+			"message": fmt.Sprintf("Task with ID %d not found", id),
+		}
+	case err.Error() == "invalid status transition" || err.Error() == "reopen required":
+		workflow := h.Service.GetWorkflow()
+		return fiber.StatusConflict, fiber.Map{
+			"error":       "conflict",
+			"message":     fmt.Sprintf("Status transition to %q is not allowed (%s)", req.Status, err.Error()),
+			"next_states": workflow.NextStatuses(h.currentStatus(id)),
+		}
+	case err.Error() == "task is blocked":
+		return fiber.StatusConflict, fiber.Map{
+			"error":   "conflict",
+			"message": fmt.Sprintf("Task with ID %d has unfinished dependencies; set override_blockers to force the status change", id),
+		}
+	case serviceValidationErrors[err.Error()]:
+		return fiber.StatusBadRequest, fiber.Map{
+			"error":   "validation error",
+			"message": err.Error(),
+		}
+	}
+	return fiber.StatusBadRequest, fiber.Map{
+		"error":   "internal error",
+		"message": err.Error(),
+	}
+}
+
+// deleteTaskError übersetzt Fehler aus TaskService.DeleteTask in HTTP-Status und Antwort-Body.
+func deleteTaskError(id int, err error) (int, fiber.Map) {
+	switch err.Error() {
+	case "not found":
+		return fiber.StatusNotFound, fiber.Map{
+			"error":   "not found",
+			"message": fmt.Sprintf("Task with ID %d not found", id),
+		}
+	case "task has subtasks":
+		return fiber.StatusConflict, fiber.Map{
+			"error":   "conflict",
+			"message": fmt.Sprintf("Task with ID %d has subtasks and cannot be deleted", id),
+		}
+	}
+	return fiber.StatusBadRequest, fiber.Map{
+		"error":   "internal error",
+		"message": err.Error(),
+	}
 }
 
-// Erlaubte Status für die Validierung der Task
-// "" bedeutet kein gesetzter Wert
-var allowedStatus = map[string]bool{
-	"todo":        true,
-	"in progress": true,
-	"done":        true,
-	"":            true,
+// Fehler aus dem Service, die auf eine ungültige Parent-Zuordnung oder eine doppelte Fälligkeit in einer
+// Serie hinweisen und daher als Validierungsfehler beantwortet werden (Feldfehler kommen als validation.Errors)
+var serviceValidationErrors = map[string]bool{
+	"parent not found":              true,
+	"task cannot be its own parent": true,
+	"cycle detected":                true,
+
+	"series already has an occurrence at this due date": true,
 }
 
 // CreateTask verarbeitet POST /tasks.
@@ -55,37 +135,107 @@ func (h *TaskHandler) CreateTask(c *fiber.Ctx) error {
 		})
 	}
 
-	// Validierung
-	if req.Title == "" || len(req.Title) > 200 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "validation error",
-			"message": "Title is required and must be max 200 characters",
-		})
+	// Service übernimmt Validierung und persistente Logik (Clean Architecture)
+	task, err := h.Service.CreateTask(req)
+	if err != nil {
+		status, body := createTaskError(err)
+		return c.Status(status).JSON(body)
 	}
 
-	if len(req.Description) > 1000 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "validation error",
-			"message": "Description must be max 1000 characters",
+	return c.Status(fiber.StatusCreated).JSON(task)
+}
+
+// GetAllTasks verarbeitet GET /tasks.
+// Gibt eine Liste aller gespeicherten Tasks zurück.
+// Query-Parameter (alle optional):
+//
+//	overdue=true|false   - nur überfällige bzw. nicht überfällige Tasks
+//	due_before=<zeit>    - Fälligkeit vor dem Zeitpunkt
+//	due_after=<zeit>     - Fälligkeit nach dem Zeitpunkt
+//	tz=<IANA-Zeitzone>   - Zeitzone für Datumsangaben ohne Uhrzeit (Default: UTC)
+//	tags_any=a,b         - mindestens einer der Tags
+//	tags_all=a,b         - alle Tags
+//	tags_none=a,b        - keiner der Tags
+//	assignee=<id>|me     - dem Benutzer zugewiesen ("me" erfordert einen API-Key)
+//	watcher=<id>|me      - vom Benutzer beobachtet
+//	fields=a,b           - nur diese Felder (siehe models.TaskFields, die ID ist immer enthalten)
+//	include=a,b          - eingebettete Beziehungen: subtasks, tags, assignees, comments_count
+//	limit=<n>            - höchstens n Tasks (1 bis maxTaskPageSize)
+//	offset=<n>           - die ersten n Tasks überspringen
+//
+// total ist die Anzahl aller passenden Tasks, auch wenn limit/offset nur einen Ausschnitt liefern.
+// Zeitpunkte werden als RFC 3339 ("2025-03-01T12:00:00+01:00") oder als Datum ("2025-03-01",
+// Tagesbeginn in tz) angegeben. Ohne fields= enthält die Antwort die Kurzform (taskListFields, ohne Description).
+// Der Erstellungszeitpunkt heißt in v1 weiterhin "created at" (siehe legacyTaskKeys).
+//
+// Antwort:
+//
+//	200 - OK + Array von Tasks
+//	400 - Ungültige Filter / Fehler beim Laden aus der Datenbank
+func (h *TaskHandler) GetAllTasks(c *fiber.Ctx) error {
+	return h.listTasks(c, taskListFields, func(tasks []*models.Task, total int, projection models.TaskProjection) error {
+		// Wandelt Task-Model in API-Response konformes JSON-Objekt mit den gewählten Feldern um
+		respTasks := []fiber.Map{}
+		for _, t := range tasks {
+			respTasks = append(respTasks, legacyTaskView(taskView(t, projection)))
+		}
+
+		// Erfolgreiche Antwort → gibt Liste aller Tasks + Gesamtanzahl zurück
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"tasks": respTasks,
+			"total": total,
 		})
-	}
+	})
+}
 
-	if !allowedPriorities[req.Priority] {
+// ListTasks verarbeitet GET /v2/tasks.
+// Filter, fields=, include=, limit=, offset= und Fehler wie GetAllTasks, ohne fields= aber mit vollständigen Tasks (inkl.
+// Description, Beginn, Wiederholung und Beobachtern). EnvelopeResponses macht daraus
+// {"data": [...], "meta": {"total": n}}.
+func (h *TaskHandler) ListTasks(c *fiber.Ctx) error {
+	return h.listTasks(c, nil, func(tasks []*models.Task, total int, projection models.TaskProjection) error {
+		if len(projection.Fields) > 0 || len(projection.Include) > 0 {
+			respTasks := []fiber.Map{}
+			for _, t := range tasks {
+				respTasks = append(respTasks, taskView(t, projection))
+			}
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"tasks": respTasks,
+				"total": total,
+			})
+		}
+
+		if tasks == nil {
+			tasks = []*models.Task{}
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"tasks": tasks,
+			"total": total,
+		})
+	})
+}
+
+// listTasks liest Filter, Ausschnitt und Projektion (Default-Felder defaultFields) aus der Query, lädt die
+// passenden Tasks mit genau diesen Feldern und übergibt den Ausschnitt mit der Gesamtanzahl an respond.
+func (h *TaskHandler) listTasks(c *fiber.Ctx, defaultFields []string, respond func([]*models.Task, int, models.TaskProjection) error) error {
+	filter, err := parseTaskFilter(c)
+	if err != nil {
+		if strings.HasSuffix(err.Error(), "authentication required") {
+			return unauthorizedResponse(c)
+		}
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "validation error",
-			"message": "Priority must be one of: low, medium, high or nothing",
+			"message": err.Error(),
 		})
 	}
-
-	if !allowedStatus[req.Status] {
+	page, err := parseTaskPage(c)
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "validation error",
-			"message": "Status must be one of: todo, in progress, done or nothing",
+			"message": err.Error(),
 		})
 	}
-
-	// Service übernimmt persistente Logik (Clean Architecture)
-	task, err := h.Service.CreateTask(req)
+	projection, err := parseTaskProjection(c, defaultFields)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "validation error",
@@ -93,42 +243,15 @@ func (h *TaskHandler) CreateTask(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(task)
-}
-
-// GetAllTasks verarbeitet GET /tasks.
-// Gibt eine Liste aller gespeicherten Tasks zurück.
-// Antwort:
-//
-//	200 - OK + Array von Tasks (Ohne die Description)
-//	400 - Fehler beim Laden aus der Datenbank
-func (h *TaskHandler) GetAllTasks(c *fiber.Ctx) error {
-	// Ruft alle Tasks über den Service ab
-	tasks, err := h.Service.GetAllTasks()
+	// Ruft die Tasks der Seite über den Service ab; nur die gewählten Felder werden aus der Datenbank geladen
+	tasks, total, err := h.Service.ListTasks(filter, projection, page)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "internal error",
 			"message": err.Error(),
 		})
 	}
-
-	// Wandelt Task-Model in API-Response konformes JSON-Objekt um
-	var respTasks []fiber.Map
-	for _, t := range tasks {
-		respTasks = append(respTasks, fiber.Map{
-			"id":         t.ID,
-			"title":      t.Title,
-			"status":     t.Status,
-			"priority":   t.Priority,
-			"created at": t.CreatedAt,
-		})
-	}
-
-	// Erfolgreiche Antwort → gibt Liste aller Tasks + Gesamtanzahl zurück
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"tasks": respTasks,
-		"total": len(respTasks),
-	})
+	return respond(tasks, total, projection)
 }
 
 // GetTaskByID verarbeitet GET /tasks/:id.
@@ -174,6 +297,8 @@ func (h *TaskHandler) GetTaskByID(c *fiber.Ctx) error {
 //	200 - Erfolgreich aktualisiert + neuer Task
 //	400 - Ungültige Daten / Fehler beim Update
 //	404 - Task nicht gefunden
+//	409 - Statuswechsel im Workflow nicht erlaubt, erfordert "reopen": true oder ist durch
+//	      unerledigte Abhängigkeiten blockiert
 //
 // Beispiel Request-Body:
 //
@@ -191,7 +316,6 @@ func (h *TaskHandler) UpdateTask(c *fiber.Ctx) error {
 		})
 	}
 
-	// Validierung
 	var req models.CreateTaskRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -200,36 +324,74 @@ func (h *TaskHandler) UpdateTask(c *fiber.Ctx) error {
 		})
 	}
 
-	if len(req.Title) > 200 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "validation error",
-			"message": "Title is required and must be max 200 characters",
-		})
+	// Validierung und Update der Task über den Service
+	updatedTask, err := h.Service.UpdateTask(id, req)
+	if err != nil {
+		status, body := h.updateTaskError(id, req, err)
+		return c.Status(status).JSON(body)
 	}
+	return c.Status(fiber.StatusOK).JSON(updatedTask)
+}
 
-	if len(req.Description) > 1000 {
+// GetSubtasks verarbeitet GET /tasks/:id/subtasks.
+// Gibt alle direkten Subtasks eines Tasks zurück.
+//
+// Antwort:
+//
+//	200 - OK + Array von Subtasks
+//	400 - Ungültige ID / Fehler beim Laden
+//	404 - Task existiert nicht
+func (h *TaskHandler) GetSubtasks(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "validation error",
-			"message": "Description must be max 1000 characters",
+			"message": "ID must be an integer",
 		})
 	}
 
-	if !allowedPriorities[req.Priority] {
+	subtasks, err := h.Service.GetSubtasks(id)
+	if err != nil {
+		if err.Error() == "not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "not found",
+				"message": fmt.Sprintf("Task with ID %d not found", id),
+			})
+		}
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "validation error",
-			"message": "Priority must be one of: low, medium, high or nothing",
+			"error":   "internal error",
+			"message": err.Error(),
 		})
 	}
+	if subtasks == nil {
+		subtasks = []*models.Task{}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"subtasks": subtasks,
+		"total":    len(subtasks),
+	})
+}
 
-	if !allowedStatus[req.Status] {
+// GetTaskTree verarbeitet GET /tasks/:id/tree.
+// Gibt den Task mit allen Subtasks (beliebig tief) als verschachtelte Struktur zurück.
+// Jeder Knoten enthält im Feld "progress" den Anteil erledigter direkter Subtasks.
+//
+// Antwort:
+//
+//	200 - OK + Task-Baum
+//	400 - Ungültige ID / Fehler beim Laden
+//	404 - Task existiert nicht
+func (h *TaskHandler) GetTaskTree(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error":   "validation error",
-			"message": "Status must be one of: todo, in progress, done or nothing",
+			"message": "ID must be an integer",
 		})
 	}
 
-	// Update der Task über den Service
-	updatedTask, err := h.Service.UpdateTask(id, req)
+	tree, err := h.Service.GetTaskTree(id)
 	if err != nil {
 		if err.Error() == "not found" {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -242,17 +404,20 @@ func (h *TaskHandler) UpdateTask(c *fiber.Ctx) error {
 			"message": err.Error(),
 		})
 	}
-	return c.Status(fiber.StatusOK).JSON(updatedTask)
+
+	return c.Status(fiber.StatusOK).JSON(tree)
 }
 
 // DeleteTask verarbeitet DELETE /tasks/:id.
-// Löscht einen Task anhand seiner ID.
+// Löscht einen Task anhand seiner ID. Wie mit Subtasks verfahren wird,
+// hängt vom konfigurierten PARENT_DELETE_MODE ab (reject, orphan, cascade).
 //
 // Antwort:
 //
 //	204 - Erfolgreich gelöscht (Kein Body)
 //	400 - Fehler beim Löschen
 //	404 - Task existiert nicht
+//	409 - Task besitzt Subtasks und PARENT_DELETE_MODE ist "reject"
 func (h *TaskHandler) DeleteTask(c *fiber.Ctx) error {
 	// Liest ID aus der URL und validiert sie als Integer
 	idParam := c.Params("id")
@@ -265,18 +430,9 @@ func (h *TaskHandler) DeleteTask(c *fiber.Ctx) error {
 	}
 
 	// Service ruft Löschvorgang für den Task auf
-	err = h.Service.DeleteTask(id)
-	if err != nil {
-		if err.Error() == "not found" {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error":   "not found",
-				"message": "Task with ID " + idParam + " not found",
-			})
-		}
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "internal error",
-			"message": err.Error(),
-		})
+	if err := h.Service.DeleteTask(id); err != nil {
+		status, body := deleteTaskError(id, err)
+		return c.Status(status).JSON(body)
 	}
 
 	// Erfolgreich gelöscht → 204 No Content
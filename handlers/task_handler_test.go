@@ -18,13 +18,21 @@ import (
 )
 
 // setupFiberHandler initialisiert einen Fiber-App-Server mit allen TaskHandler-Routen
-// (POST /tasks, GET /tasks, GET /tasks/:id, PUT /tasks/:id, DELETE /tasks/:id) unter Verwendung eines Mock-Service.
+// (POST /tasks, GET /tasks, GET /tasks/order, GET /tasks/:id, GET /tasks/:id/subtasks, GET /tasks/:id/tree,
+// /tasks/:id/dependencies, PUT /tasks/:id, DELETE /tasks/:id) unter Verwendung eines Mock-Service.
 func setupFiberHandler(mockService *services.MockTaskService) *fiber.App {
 	app := fiber.New()
 	handler := handlers.TaskHandler{Service: mockService}
 	app.Post("/tasks", handler.CreateTask)
+	app.Get("/tasks/order", handler.GetTopologicalOrder)
+	app.Get("/tasks/export", handler.ExportTasks)
 	app.Get("/tasks/:id", handler.GetTaskByID)
 	app.Get("/tasks", handler.GetAllTasks)
+	app.Get("/tasks/:id/subtasks", handler.GetSubtasks)
+	app.Get("/tasks/:id/tree", handler.GetTaskTree)
+	app.Get("/tasks/:id/dependencies", handler.GetDependencies)
+	app.Post("/tasks/:id/dependencies", handler.AddDependency)
+	app.Delete("/tasks/:id/dependencies/:dependsOnId", handler.RemoveDependency)
 	app.Put("/tasks/:id", handler.UpdateTask)
 	app.Delete("/tasks/:id", handler.DeleteTask)
 	return app
@@ -65,11 +73,11 @@ func Test_CreateTask_Handler_ValidationError(t *testing.T) {
 		Body models.CreateTaskRequest
 		Err  string
 	}{
-		{"Empty Title", models.CreateTaskRequest{Title: ""}, "Title is required"},
-		{"Too Long Title", models.CreateTaskRequest{Title: strings.Repeat("a", 201)}, "Title is required"},
-		{"Too Long Description", models.CreateTaskRequest{Title: "ok", Description: strings.Repeat("d", 1001)}, "Description must be max 1000 characters"},
-		{"Invalid Priority", models.CreateTaskRequest{Title: "ok", Priority: "urgent"}, "Priority must be one of: low, medium, high or nothing"},
-		{"Invalid Status", models.CreateTaskRequest{Title: "ok", Status: "waiting"}, "Status must be one of: todo, in progress, done or nothing"},
+		{"Empty Title", models.CreateTaskRequest{Title: ""}, "/title: is required"},
+		{"Too Long Title", models.CreateTaskRequest{Title: strings.Repeat("a", 201)}, "/title: must be at most 200 characters"},
+		{"Too Long Description", models.CreateTaskRequest{Title: "ok", Description: strings.Repeat("d", 1001)}, "/description: must be at most 1000 characters"},
+		{"Invalid Priority", models.CreateTaskRequest{Title: "ok", Priority: "urgent"}, "/priority: must be one of: low, medium, high"},
+		{"Invalid Status", models.CreateTaskRequest{Title: "ok", Status: "waiting"}, "/status: must be one of: todo, in progress, done"},
 	}
 
 	for _, tc := range testCases {
@@ -85,10 +93,50 @@ func Test_CreateTask_Handler_ValidationError(t *testing.T) {
 			data, _ := io.ReadAll(resp.Body)
 			resp.Body.Close()
 			assert.Contains(t, string(data), "validation error", "response should contain validation error")
+			assert.Contains(t, string(data), tc.Err)
 		})
 	}
 }
 
+// Test_CreateTask_Handler_AllFieldErrors prüft, dass alle Verstöße auf einmal gemeldet werden und
+// Längen in Zeichen statt Bytes gezählt werden.
+func Test_CreateTask_Handler_AllFieldErrors(t *testing.T) {
+	app := setupFiberHandler(&services.MockTaskService{})
+
+	start := time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC)
+	due := start.Add(-time.Hour)
+	body, _ := json.Marshal(models.CreateTaskRequest{Priority: "urgent", Status: "waiting", StartAt: &start, DueAt: &due})
+	req := httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	var result struct {
+		Errors []struct {
+			In      string `json:"in"`
+			Pointer string `json:"pointer"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	var pointers []string
+	for _, e := range result.Errors {
+		assert.Equal(t, "body", e.In)
+		pointers = append(pointers, e.Pointer)
+	}
+	assert.Equal(t, []string{"/title", "/priority", "/status", "/due_at"}, pointers)
+
+	// 200 Umlaute sind 400 Bytes, aber nur 200 Zeichen
+	body, _ = json.Marshal(models.CreateTaskRequest{Title: strings.Repeat("ü", 200)})
+	req = httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+}
+
 // Test_CreateTask_Handler_ServiceError prüft, dass ein Fehler im Service korrekt als Status 400 zurückgegeben wird.
 func Test_CreateTask_Handler_ServiceError(t *testing.T) {
 	app := setupFiberHandler(&services.MockTaskService{ShouldFail: true})
@@ -267,3 +315,65 @@ func Test_DeleteTask_Handler_NotFound(t *testing.T) {
 	body, _ := io.ReadAll(resp.Body)
 	assert.Contains(t, string(body), "not found")
 }
+
+// Test_GetSubtasks_Handler_Success prüft, dass nur die direkten Subtasks eines Tasks zurückgegeben werden.
+func Test_GetSubtasks_Handler_Success(t *testing.T) {
+	parentID, childID := 1, 2
+	mockService := &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "Parent"},
+			{ID: 2, Title: "Child", ParentID: &parentID},
+			{ID: 3, Title: "Grandchild", ParentID: &childID},
+		},
+	}
+
+	app := setupFiberHandler(mockService)
+
+	req := httptest.NewRequest("GET", "/tasks/1/subtasks", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), "Child")
+	assert.NotContains(t, string(body), "Grandchild")
+	assert.Contains(t, string(body), `"total":1`)
+}
+
+// Test_GetTaskTree_Handler_Success prüft, dass der Tree-Endpoint die Subtasks verschachtelt zurückgibt.
+func Test_GetTaskTree_Handler_Success(t *testing.T) {
+	parentID, childID := 1, 2
+	mockService := &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "Parent"},
+			{ID: 2, Title: "Child", ParentID: &parentID},
+			{ID: 3, Title: "Grandchild", ParentID: &childID},
+		},
+	}
+
+	app := setupFiberHandler(mockService)
+
+	req := httptest.NewRequest("GET", "/tasks/1/tree", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	data, _ := io.ReadAll(resp.Body)
+	var tree models.TaskNode
+	assert.NoError(t, json.Unmarshal(data, &tree))
+	assert.Equal(t, 1, tree.ID)
+	assert.Len(t, tree.Subtasks, 1)
+	assert.Equal(t, "Child", tree.Subtasks[0].Title)
+	assert.Len(t, tree.Subtasks[0].Subtasks, 1)
+	assert.Equal(t, "Grandchild", tree.Subtasks[0].Subtasks[0].Title)
+}
+
+// Test_GetTaskTree_Handler_NotFound prüft, dass für einen nicht existierenden Task Status 404 geliefert wird.
+func Test_GetTaskTree_Handler_NotFound(t *testing.T) {
+	app := setupFiberHandler(&services.MockTaskService{})
+
+	req := httptest.NewRequest("GET", "/tasks/7/tree", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"task-api/models"
+)
+
+// parseTaskProjection liest fields= und include= von GET /tasks aus der Query.
+// Ohne fields= gelten defaultFields (nil = alle Felder). Die ID ist immer enthalten.
+func parseTaskProjection(c *fiber.Ctx, defaultFields []string) (models.TaskProjection, error) {
+	projection := models.TaskProjection{Fields: defaultFields}
+
+	if fields := parseListParam(c.Query("fields")); fields != nil {
+		for _, f := range fields {
+			if !slices.Contains(models.TaskFields, f) {
+				return projection, fmt.Errorf("fields must be a comma-separated list of: %s", strings.Join(models.TaskFields, ", "))
+			}
+		}
+		if !slices.Contains(fields, "id") {
+			fields = append([]string{"id"}, fields...)
+		}
+		projection.Fields = fields
+	}
+
+	projection.Include = parseListParam(c.Query("include"))
+	for _, include := range projection.Include {
+		if !slices.Contains(models.TaskIncludes, include) {
+			return projection, fmt.Errorf("include must be a comma-separated list of: %s", strings.Join(models.TaskIncludes, ", "))
+		}
+	}
+	return projection, nil
+}
+
+// taskView erzeugt die Darstellung eines Tasks mit den Feldern und Beziehungen der Projektion.
+// Eingebettete Beziehungen ersetzen gleichnamige Felder: include=tags liefert Tag-Objekte statt Namen,
+// include=assignees Benutzer statt IDs.
+func taskView(t *models.Task, projection models.TaskProjection) fiber.Map {
+	fields := projection.Fields
+	if len(fields) == 0 {
+		fields = models.TaskFields
+	}
+
+	v := reflect.ValueOf(t).Elem()
+	view := fiber.Map{"id": t.ID}
+	for _, f := range fields {
+		view[f] = v.Field(taskFieldIndex()[f]).Interface()
+	}
+
+	rel := t.Relations
+	if rel == nil {
+		rel = &models.TaskRelations{}
+	}
+	for _, include := range projection.Include {
+		switch include {
+		case "subtasks":
+			// Subtasks werden mit denselben Feldern, aber ohne weitere Ebene dargestellt
+			sub := models.TaskProjection{Fields: projection.Fields, Include: slices.DeleteFunc(slices.Clone(projection.Include),
+				func(name string) bool { return name == "subtasks" })}
+			subtasks := []fiber.Map{}
+			for _, s := range rel.Subtasks {
+				subtasks = append(subtasks, taskView(s, sub))
+			}
+			view["subtasks"] = subtasks
+		case "tags":
+			view["tags"] = nonNil(rel.Tags)
+		case "assignees":
+			view["assignees"] = nonNil(rel.Assignees)
+		case "comments_count":
+			view["comments_count"] = rel.CommentsCount
+		}
+	}
+	return view
+}
+
+// legacyTaskKeys sind Felder, die GET /v1/tasks unter ihrem ursprünglichen Namen ausgibt. "created at" (mit
+// Leerzeichen) ist veraltet, bleibt in v1 aber für bestehende Clients erhalten; v2 verwendet "created_at".
+var legacyTaskKeys = map[string]string{"created_at": "created at"}
+
+// legacyTaskView benennt die Felder einer Darstellung aus taskView (inkl. eingebetteter Subtasks) nach
+// legacyTaskKeys um.
+func legacyTaskView(view fiber.Map) fiber.Map {
+	for name, legacy := range legacyTaskKeys {
+		if value, ok := view[name]; ok {
+			delete(view, name)
+			view[legacy] = value
+		}
+	}
+	if subtasks, ok := view["subtasks"].([]fiber.Map); ok {
+		for _, s := range subtasks {
+			legacyTaskView(s)
+		}
+	}
+	return view
+}
+
+// nonNil ersetzt eine nil-Liste durch eine leere Liste, damit sie als [] statt null ausgegeben wird.
+func nonNil[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+	return list
+}
+
+var (
+	taskFieldIndexOnce sync.Once
+	taskFieldIndexMap  map[string]int
+)
+
+// taskFieldIndex ordnet jedem JSON-Namen eines Tasks den Index des Struct-Felds zu.
+func taskFieldIndex() map[string]int {
+	taskFieldIndexOnce.Do(func() {
+		taskFieldIndexMap = map[string]int{}
+		t := reflect.TypeOf(models.Task{})
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name != "" && name != "-" {
+				taskFieldIndexMap[name] = i
+			}
+		}
+	})
+	return taskFieldIndexMap
+}
@@ -0,0 +1,106 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+)
+
+// projectionService liefert einen Task mit Subtask, Tags, Verantwortlichen und Kommentaren.
+func projectionService() *services.MockTaskService {
+	parent := 1
+	return &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "Release", Description: "Version 2", Status: "todo", Tags: []string{"backend"}, Assignees: []int{2}},
+			{ID: 2, Title: "Changelog", Description: "Notizen", Status: "done", ParentID: &parent, Tags: []string{}, Assignees: []int{}},
+		},
+		Users:         testUsers(),
+		CommentCounts: map[int]int{1: 3},
+	}
+}
+
+// getTaskList ruft eine Task-Liste ab und gibt Status und Tasks zurück.
+func getTaskList(t *testing.T, app *fiber.App, path string) (int, []map[string]any) {
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	var body struct {
+		Tasks []map[string]any `json:"tasks"`
+	}
+	_ = json.Unmarshal(data, &body)
+	return resp.StatusCode, body.Tasks
+}
+
+// Test_GetTasks_Handler_Fields prüft, dass fields= nur die gewählten Felder (immer mit ID) liefert.
+func Test_GetTasks_Handler_Fields(t *testing.T) {
+	app := setupFiberHandler(projectionService())
+
+	status, tasks := getTaskList(t, app, "/tasks?fields=title,description")
+	assert.Equal(t, fiber.StatusOK, status)
+	if assert.Len(t, tasks, 2) {
+		assert.Equal(t, map[string]any{"id": float64(1), "title": "Release", "description": "Version 2"}, tasks[0])
+	}
+
+	// Ohne fields= bleibt die Kurzform ohne Description; v1 behält den Schlüssel "created at"
+	_, tasks = getTaskList(t, app, "/tasks")
+	assert.Contains(t, tasks[0], "created at")
+	assert.NotContains(t, tasks[0], "created_at")
+	assert.NotContains(t, tasks[0], "description")
+}
+
+// Test_GetTasks_Handler_Include prüft die eingebetteten Beziehungen.
+func Test_GetTasks_Handler_Include(t *testing.T) {
+	app := setupFiberHandler(projectionService())
+
+	status, tasks := getTaskList(t, app, "/tasks?fields=title&include=subtasks,tags,assignees,comments_count")
+	assert.Equal(t, fiber.StatusOK, status)
+	release := tasks[0]
+	assert.Equal(t, float64(3), release["comments_count"])
+	if tags := release["tags"].([]any); assert.Len(t, tags, 1) {
+		assert.Equal(t, "backend", tags[0].(map[string]any)["name"])
+	}
+	if assignees := release["assignees"].([]any); assert.Len(t, assignees, 1) {
+		assert.Equal(t, "Ben", assignees[0].(map[string]any)["name"])
+	}
+
+	// Subtasks haben dieselben Felder und Beziehungen, aber keine weitere Ebene
+	if subtasks := release["subtasks"].([]any); assert.Len(t, subtasks, 1) {
+		sub := subtasks[0].(map[string]any)
+		assert.Equal(t, "Changelog", sub["title"])
+		assert.Equal(t, float64(0), sub["comments_count"])
+		assert.Equal(t, []any{}, sub["tags"])
+		assert.NotContains(t, sub, "subtasks")
+		assert.NotContains(t, sub, "status")
+	}
+}
+
+// Test_GetTasks_Handler_InvalidProjection prüft, dass unbekannte Felder und Beziehungen mit 400 abgelehnt werden.
+func Test_GetTasks_Handler_InvalidProjection(t *testing.T) {
+	app := setupFiberHandler(&services.MockTaskService{})
+
+	for _, query := range []string{"fields=title,secret", "include=comments", "fields=title&include=subtasks,owner"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/tasks?"+query, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
+// Test_ListTasks_Handler_Projection prüft GET /v2/tasks: ohne fields= vollständige Tasks, sonst die Auswahl.
+func Test_ListTasks_Handler_Projection(t *testing.T) {
+	handler := handlers.TaskHandler{Service: projectionService()}
+	app := fiber.New()
+	app.Get("/tasks", handler.ListTasks)
+
+	_, tasks := getTaskList(t, app, "/tasks")
+	assert.Equal(t, "Version 2", tasks[0]["description"])
+	assert.Contains(t, tasks[0], "watchers")
+
+	_, tasks = getTaskList(t, app, "/tasks?fields=status&include=comments_count")
+	assert.Equal(t, map[string]any{"id": float64(1), "status": "todo", "comments_count": float64(3)}, tasks[0])
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"task-api/models"
+	"task-api/services"
+)
+
+// UserHandler stellt die HTTP-Endpoints für Benutzer bereit.
+type UserHandler struct {
+	Service services.UserServiceInterface
+}
+
+// tenantOf gibt den Mandanten des angemeldeten Benutzers zurück bzw. den Default-Mandanten.
+func tenantOf(c *fiber.Ctx) string {
+	if user := currentUser(c); user != nil {
+		return user.Tenant
+	}
+	return services.DefaultTenant
+}
+
+// GetAllUsers verarbeitet GET /users.
+// Liefert alle Benutzer des eigenen Mandanten (anonym: Default-Mandant).
+//
+// Antwort:
+//
+//	200 - OK + Array von Benutzern
+//	400 - Fehler beim Laden
+func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
+	users, err := h.Service.GetAllUsers(tenantOf(c))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+	if users == nil {
+		users = []*models.User{}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"users": users,
+		"total": len(users),
+	})
+}
+
+// CreateUser verarbeitet POST /users.
+// Der API-Key des neuen Benutzers ist nur in dieser Antwort enthalten.
+//
+// Antwort:
+//
+//	201 - Benutzer erstellt (JSON inkl. api_key)
+//	400 - Ungültiger Name / ungültige E-Mail-Adresse
+//	403 - Anlegen in einem fremden Mandanten
+//	409 - E-Mail-Adresse bereits vergeben
+//
+// Beispiel Request-Body:
+//
+//	{
+//	  "name": "Erika Mustermann",
+//	  "email": "erika@example.com"
+//	}
+func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
+	var req models.CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "invalid request body",
+		})
+	}
+
+	user, err := h.Service.CreateUser(req, currentUser(c))
+	if err != nil {
+		switch err.Error() {
+		case "invalid name", "invalid email":
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "validation error",
+				"message": err.Error(),
+			})
+		case "user belongs to another tenant":
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   "forbidden",
+				"message": err.Error(),
+			})
+		case "email already exists":
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":   "conflict",
+				"message": err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(user)
+}
+
+// GetCurrentUser verarbeitet GET /users/me.
+//
+// Antwort:
+//
+//	200 - Angemeldeter Benutzer (JSON)
+//	401 - Kein API-Key angegeben
+func (h *UserHandler) GetCurrentUser(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return unauthorizedResponse(c)
+	}
+	return c.Status(fiber.StatusOK).JSON(user)
+}
+
+// CreateCalendarToken verarbeitet POST /users/me/calendar-token.
+// Erzeugt ein Kalender-Token für GET /calendar.ics; ein zuvor erzeugtes Token wird ungültig.
+// Das Token ist nur in dieser Antwort enthalten.
+//
+// Antwort:
+//
+//	201 - Token erstellt (JSON mit token und url)
+//	400 - Fehler beim Speichern
+//	401 - Kein API-Key angegeben
+func (h *UserHandler) CreateCalendarToken(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return unauthorizedResponse(c)
+	}
+	token, err := h.Service.CreateCalendarToken(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusCreated).JSON(models.CalendarToken{Token: token, URL: calendarURL(token)})
+}
+
+// RevokeCalendarToken verarbeitet DELETE /users/me/calendar-token.
+// Danach liefert GET /calendar.ics mit dem bisherigen Token 401.
+//
+// Antwort:
+//
+//	204 - Token entfernt (auch wenn keines existierte)
+//	400 - Fehler beim Speichern
+//	401 - Kein API-Key angegeben
+func (h *UserHandler) RevokeCalendarToken(c *fiber.Ctx) error {
+	user := currentUser(c)
+	if user == nil {
+		return unauthorizedResponse(c)
+	}
+	if err := h.Service.RevokeCalendarToken(user.ID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetUserByID verarbeitet GET /users/:id.
+// Benutzer anderer Mandanten werden wie nicht existierende Benutzer behandelt.
+//
+// Antwort:
+//
+//	200 - Benutzer gefunden (JSON)
+//	404 - Kein Benutzer mit dieser ID im eigenen Mandanten
+func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	user, err := h.Service.GetUserByID(id)
+	if err == nil && user.Tenant != tenantOf(c) {
+		err = fmt.Errorf("not found")
+	}
+	if err != nil {
+		if err.Error() == "not found" {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error":   "not found",
+				"message": fmt.Sprintf("User with ID %d not found", id),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "internal error",
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(user)
+}
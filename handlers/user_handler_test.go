@@ -0,0 +1,202 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setupAuthHandler initialisiert einen Fiber-App-Server mit Authentifizierung, Benutzer- und
+// Zuweisungs-Routen unter Verwendung von Mock-Services.
+func setupAuthHandler(users *services.MockUserService, tasks *services.MockTaskService) *fiber.App {
+	app := fiber.New()
+	app.Use(handlers.Authenticate(users))
+
+	userHandler := handlers.UserHandler{Service: users}
+	app.Get("/users", userHandler.GetAllUsers)
+	app.Post("/users", userHandler.CreateUser)
+	app.Get("/users/me", userHandler.GetCurrentUser)
+	app.Get("/users/:id", userHandler.GetUserByID)
+
+	taskHandler := handlers.TaskHandler{Service: tasks}
+	app.Get("/tasks", taskHandler.GetAllTasks)
+	app.Post("/tasks/:id/assignees", taskHandler.AssignUser)
+	app.Delete("/tasks/:id/assignees/:userId", taskHandler.UnassignUser)
+	app.Post("/tasks/:id/watchers", taskHandler.WatchTask)
+	app.Delete("/tasks/:id/watchers/:userId", taskHandler.UnwatchTask)
+	return app
+}
+
+// testUsers liefert zwei Benutzer des Default-Mandanten und einen Benutzer eines fremden Mandanten.
+func testUsers() []*models.User {
+	return []*models.User{
+		{ID: 1, Name: "Anna", Email: "anna@example.com", Tenant: "default"},
+		{ID: 2, Name: "Ben", Email: "ben@example.com", Tenant: "default"},
+		{ID: 3, Name: "Cem", Email: "cem@example.com", Tenant: "other"},
+	}
+}
+
+// Test_Authenticate_InvalidKey prüft, dass ein unbekannter API-Key mit Status 401 abgelehnt wird,
+// während Requests ohne Key anonym durchgelassen werden.
+func Test_Authenticate_InvalidKey(t *testing.T) {
+	app := setupAuthHandler(&services.MockUserService{Users: testUsers()}, &services.MockTaskService{})
+
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/users", nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/users/me", nil))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+// Test_CreateUser_Handler_ReturnsAPIKey prüft, dass der neue Benutzer seinen API-Key erhält
+// und sich damit anmelden kann.
+func Test_CreateUser_Handler_ReturnsAPIKey(t *testing.T) {
+	app := setupAuthHandler(&services.MockUserService{}, &services.MockTaskService{})
+
+	body, _ := json.Marshal(models.CreateUserRequest{Name: "Anna", Email: "anna@example.com"})
+	req := httptest.NewRequest("POST", "/users", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	var created models.CreatedUser
+	data, _ := io.ReadAll(resp.Body)
+	assert.NoError(t, json.Unmarshal(data, &created))
+	assert.NotEmpty(t, created.APIKey)
+
+	req = httptest.NewRequest("GET", "/users/me", nil)
+	req.Header.Set("X-API-Key", created.APIKey)
+	resp, _ = app.Test(req)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	data, _ = io.ReadAll(resp.Body)
+	assert.Contains(t, string(data), "anna@example.com")
+}
+
+// Test_CreateUser_Handler_AnonymousTenant prüft, dass anonyme Aufrufer keine Benutzer in fremden
+// Mandanten anlegen können.
+func Test_CreateUser_Handler_AnonymousTenant(t *testing.T) {
+	users := &services.MockUserService{}
+	app := setupAuthHandler(users, &services.MockTaskService{})
+
+	body, _ := json.Marshal(models.CreateUserRequest{Name: "Eve", Email: "eve@example.com", Tenant: "other"})
+	req := httptest.NewRequest("POST", "/users", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	assert.Empty(t, users.Users)
+
+	body, _ = json.Marshal(models.CreateUserRequest{Name: "Eve", Email: "eve@example.com", Tenant: "default"})
+	req = httptest.NewRequest("POST", "/users", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+}
+
+// Test_GetUserByID_Handler_OtherTenant prüft, dass Benutzer fremder Mandanten nicht sichtbar sind.
+func Test_GetUserByID_Handler_OtherTenant(t *testing.T) {
+	app := setupAuthHandler(&services.MockUserService{Users: testUsers()}, &services.MockTaskService{})
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/users/2", nil))
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/users/3", nil))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test_GetTasks_Handler_AssigneeMe prüft den Filter assignee=me mit und ohne Anmeldung.
+func Test_GetTasks_Handler_AssigneeMe(t *testing.T) {
+	users := &services.MockUserService{Users: testUsers(), APIKeys: map[string]int{"anna-key": 1}}
+	tasks := &services.MockTaskService{
+		Tasks: []*models.Task{
+			{ID: 1, Title: "A", Assignees: []int{1}},
+			{ID: 2, Title: "B", Assignees: []int{2}},
+			{ID: 3, Title: "C", Assignees: []int{1, 2}},
+		},
+	}
+	app := setupAuthHandler(users, tasks)
+
+	resp, _ := app.Test(httptest.NewRequest("GET", "/tasks?assignee=me", nil))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	req := httptest.NewRequest("GET", "/tasks?assignee=me", nil)
+	req.Header.Set("Authorization", "Bearer anna-key")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Total int `json:"total"`
+	}
+	data, _ := io.ReadAll(resp.Body)
+	assert.NoError(t, json.Unmarshal(data, &body))
+	assert.Equal(t, 2, body.Total)
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/tasks?assignee=abc", nil))
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test_AssignUser_Handler prüft Zuweisung, Ablehnung fremder Mandanten und das Entfernen der Zuweisung.
+func Test_AssignUser_Handler(t *testing.T) {
+	users := &services.MockUserService{Users: testUsers(), APIKeys: map[string]int{"anna-key": 1}}
+	tasks := &services.MockTaskService{Tasks: []*models.Task{{ID: 1, Title: "A"}}, Users: testUsers()}
+	app := setupAuthHandler(users, tasks)
+
+	assign := func(userID int) int {
+		body, _ := json.Marshal(models.UserRequest{UserID: userID})
+		req := httptest.NewRequest("POST", "/tasks/1/assignees", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer anna-key")
+		resp, _ := app.Test(req)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusCreated, assign(2))
+	assert.Equal(t, fiber.StatusBadRequest, assign(3))
+	assert.Equal(t, fiber.StatusBadRequest, assign(99))
+	assert.Equal(t, []int{2}, tasks.Tasks[0].Assignees)
+
+	resp, _ := app.Test(httptest.NewRequest("DELETE", "/tasks/1/assignees/2", nil))
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	resp, _ = app.Test(httptest.NewRequest("DELETE", "/tasks/1/assignees/2", nil))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test_WatchTask_Handler_CurrentUser prüft, dass ohne user_id der angemeldete Benutzer beobachtet
+// und mit "me" wieder entfernt wird.
+func Test_WatchTask_Handler_CurrentUser(t *testing.T) {
+	users := &services.MockUserService{Users: testUsers(), APIKeys: map[string]int{"anna-key": 1}}
+	tasks := &services.MockTaskService{Tasks: []*models.Task{{ID: 1, Title: "A"}}, Users: testUsers()}
+	app := setupAuthHandler(users, tasks)
+
+	resp, _ := app.Test(httptest.NewRequest("POST", "/tasks/1/watchers", nil))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	req := httptest.NewRequest("POST", "/tasks/1/watchers", nil)
+	req.Header.Set("Authorization", "Bearer anna-key")
+	resp, _ = app.Test(req)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, []int{1}, tasks.Tasks[0].Watchers)
+
+	req = httptest.NewRequest("DELETE", "/tasks/1/watchers/me", nil)
+	req.Header.Set("Authorization", "Bearer anna-key")
+	resp, _ = app.Test(req)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	assert.Empty(t, tasks.Tasks[0].Watchers)
+}
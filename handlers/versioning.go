@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Versionen der REST-API. Jede Route liegt unter /v1 und /v2; Requests ohne Versionspräfix werden per
+// Accept-Header ausgehandelt (siehe NegotiateVersion).
+const (
+	APIVersion1 = "v1"
+	APIVersion2 = "v2"
+)
+
+// apiVersions sind alle Versionen der REST-API in aufsteigender Reihenfolge.
+var apiVersions = []string{APIVersion1, APIVersion2}
+
+// versionLocalsKey ist der Schlüssel, unter dem die Version des Requests in c.Locals abgelegt wird.
+const versionLocalsKey = "api_version"
+
+// VersionOptions konfiguriert die Aushandlung der API-Version.
+type VersionOptions struct {
+	// Default ist die Version für Requests ohne Versionspräfix und ohne Version im Accept-Header
+	// (leer = APIVersion1, damit bestehende Clients unverändert funktionieren).
+	Default string
+
+	// Deprecation und Sunset werden bei v1 als Header Deprecation (RFC 9745) und Sunset (RFC 8594)
+	// gesendet. Nullwerte lassen den jeweiligen Header weg.
+	Deprecation time.Time
+	Sunset      time.Time
+}
+
+// NegotiateVersion liefert eine Middleware, die die API-Version eines Requests bestimmt:
+//
+//   - Pfad mit Präfix ("/v1/tasks", "/v2/tasks") bestimmt die Version direkt.
+//   - Ohne Präfix wird die Version aus dem Accept-Header gelesen ("application/vnd.taskapi.v2+json"
+//     oder "application/json; version=2") und der Pfad intern auf die Version umgeschrieben.
+//     Eine unbekannte Version wird mit 406 abgelehnt.
+//   - Routen ohne Version (/graphql, /ws, /tasks/events, /calendar.ics, /health,
+//     /openapi.json, /docs) bleiben unverändert.
+//
+// Jede versionierte Antwort erhält den Header API-Version; v1-Antworten zusätzlich Deprecation, Sunset
+// und einen Link auf dieselbe Ressource in v2. Die Middleware muss vor allen anderen registriert werden.
+func NegotiateVersion(opts VersionOptions) fiber.Handler {
+	if opts.Default == "" {
+		opts.Default = APIVersion1
+	}
+	unversioned := map[string]bool{}
+	for _, op := range apiOperations {
+		if op.Unversioned {
+			unversioned[op.Path] = true
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		// Kopie, da c.Path() auf den Puffer des Requests zeigt, den c.Path(neu) überschreibt
+		path := strings.Clone(c.Path())
+		if unversioned[strings.TrimSuffix(path, "/")] {
+			return c.Next()
+		}
+
+		version, rest := splitVersion(path)
+		if version == "" {
+			c.Vary(fiber.HeaderAccept)
+			var ok bool
+			if version, ok = versionFromAccept(c.Get(fiber.HeaderAccept), opts.Default); !ok {
+				return c.Status(fiber.StatusNotAcceptable).JSON(fiber.Map{
+					"error":   "not acceptable",
+					"message": "unsupported API version, supported: " + strings.Join(apiVersions, ", "),
+				})
+			}
+			rest = path
+			c.Path("/" + version + path)
+		}
+		c.Locals(versionLocalsKey, version)
+		c.Set("API-Version", version)
+
+		if version == APIVersion1 {
+			if !opts.Deprecation.IsZero() {
+				c.Set("Deprecation", "@"+strconv.FormatInt(opts.Deprecation.Unix(), 10))
+			}
+			if !opts.Sunset.IsZero() {
+				c.Set("Sunset", opts.Sunset.UTC().Format(http.TimeFormat))
+			}
+			c.Append(fiber.HeaderLink, "</"+APIVersion2+rest+`>; rel="successor-version"`)
+		}
+
+		err := c.Next()
+		// Fehler des Routers (z.B. unbekannte Route) in v2 im Fehlerformat von v2 beantworten
+		var fiberErr *fiber.Error
+		if err != nil && version == APIVersion2 && errors.As(err, &fiberErr) {
+			return errorResponse(c, fiberErr.Code, fiber.Map{
+				"error":   strings.ToLower(http.StatusText(fiberErr.Code)),
+				"message": fiberErr.Message,
+			})
+		}
+		return err
+	}
+}
+
+// splitVersion trennt ein Versionspräfix vom Pfad ("/v2/tasks" -> "v2", "/tasks").
+// Ohne bekanntes Präfix ist version leer.
+func splitVersion(path string) (version, rest string) {
+	for _, v := range apiVersions {
+		prefix := "/" + v
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return v, strings.TrimPrefix(path, prefix)
+		}
+	}
+	return "", path
+}
+
+// versionFromAccept liest die gewünschte Version aus dem Accept-Header. Ohne Versionsangabe gilt def;
+// ok ist false, wenn ausschließlich unbekannte Versionen angefragt werden.
+func versionFromAccept(accept, def string) (version string, ok bool) {
+	requested := false
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(mediaRange), ";")
+		v := ""
+		if rest, found := strings.CutPrefix(mediaType, "application/vnd.taskapi."); found {
+			v, _, _ = strings.Cut(rest, "+")
+		}
+		for _, param := range strings.Split(params, ";") {
+			if value, found := strings.CutPrefix(strings.TrimSpace(param), "version="); found {
+				v = "v" + strings.TrimPrefix(value, "v")
+			}
+		}
+		if v == "" {
+			continue
+		}
+		requested = true
+		if slices.Contains(apiVersions, v) {
+			return v, true
+		}
+	}
+	return def, !requested
+}
+
+// requestVersion gibt die API-Version des Requests zurück ("" bei Routen ohne Version).
+func requestVersion(c *fiber.Ctx) string {
+	version, _ := c.Locals(versionLocalsKey).(string)
+	return version
+}
+
+// errorResponse beantwortet einen Request mit einem Fehler im Format der API-Version des Requests:
+// {"error", "message", ...} in v1 bzw. {"error": {"code", "message", ...}} in v2 (siehe v2Error).
+// Middlewares, die vor dem Routing antworten (Authentifizierung, Validierung), verwenden sie, damit
+// auch ihre Fehler dem Format der angefragten Version entsprechen.
+func errorResponse(c *fiber.Ctx, status int, body fiber.Map) error {
+	if requestVersion(c) == APIVersion2 {
+		return c.Status(status).JSON(v2Error(body))
+	}
+	return c.Status(status).JSON(body)
+}
+
+// v2Error wandelt einen Fehler-Body von v1 in das Format von v2 um: "error" wird zum Code in snake_case
+// ("not found" -> "not_found"), Feldfehler ("errors") heißen "fields", weitere Angaben bleiben erhalten.
+func v2Error(body map[string]any) fiber.Map {
+	inner := fiber.Map{}
+	for k, v := range body {
+		switch k {
+		case "error":
+			code, _ := v.(string)
+			inner["code"] = strings.ReplaceAll(strings.ToLower(code), " ", "_")
+		case "errors":
+			inner["fields"] = v
+		default:
+			inner[k] = v
+		}
+	}
+	return fiber.Map{"error": inner}
+}
+
+// EnvelopeResponses liefert die Middleware der Routengruppe /v2. Sie bringt die JSON-Antworten der
+// Handler in die Darstellung von v2:
+//
+//	Einzelne Ressource: {"data": {...}}
+//	Liste:              {"data": [...], "meta": {"total": n}}
+//	Fehler:             {"error": {"code": "not_found", "message": "...", ...}}
+//
+// Ob eine Antwort eine Liste ist, ergibt sich aus der Routentabelle (Result listOf in openapi_routes.go).
+func EnvelopeResponses() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		resp := c.Response()
+		if !strings.HasPrefix(string(resp.Header.ContentType()), fiber.MIMEApplicationJSON) || len(resp.Body()) == 0 {
+			return nil
+		}
+		var body any
+		dec := json.NewDecoder(bytes.NewReader(resp.Body()))
+		dec.UseNumber()
+		if err := dec.Decode(&body); err != nil {
+			return nil
+		}
+
+		if resp.StatusCode() >= fiber.StatusBadRequest {
+			// Fehler, die bereits im Format von v2 vorliegen, bleiben unverändert
+			if m, ok := body.(map[string]any); ok {
+				if _, converted := m["error"].(map[string]any); !converted {
+					return c.JSON(v2Error(m))
+				}
+			}
+			return nil
+		}
+
+		_, path := splitVersion(c.Route().Path)
+		if key, ok := listKeys()[c.Method()+" "+path]; ok {
+			if m, ok := body.(map[string]any); ok {
+				meta := fiber.Map{}
+				for k, v := range m {
+					if k != key {
+						meta[k] = v
+					}
+				}
+				return c.JSON(fiber.Map{"data": m[key], "meta": meta})
+			}
+		}
+		return c.JSON(fiber.Map{"data": body})
+	}
+}
+
+var (
+	listKeysOnce sync.Once
+	listKeysMap  map[string]string
+)
+
+// listKeys liefert zu jeder Route mit Listenantwort ("GET /tasks") den Schlüssel der Liste ("tasks").
+func listKeys() map[string]string {
+	listKeysOnce.Do(func() {
+		listKeysMap = map[string]string{}
+		for _, op := range apiOperations {
+			if list, ok := op.Result.(listOf); ok && op.Version != APIVersion1 {
+				listKeysMap[op.Method+" "+op.Path] = list.Key
+			}
+		}
+	})
+	return listKeysMap
+}
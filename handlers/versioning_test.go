@@ -0,0 +1,173 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+	"time"
+)
+
+// setupVersionedApp registriert die Task-Routen unter /v1 und /v2 hinter Versionsaushandlung,
+// Authentifizierung und Validierung wie in main.go.
+func setupVersionedApp(opts handlers.VersionOptions) *fiber.App {
+	handler := handlers.TaskHandler{Service: &services.MockTaskService{
+		Tasks: []*models.Task{{ID: 1, Title: "Login", Description: "OAuth", Status: "todo"}},
+	}}
+	users := &services.MockUserService{Users: testUsers(), APIKeys: map[string]int{"anna-key": 1}}
+
+	app := fiber.New()
+	app.Use(handlers.NegotiateVersion(opts))
+	app.Use(handlers.Authenticate(users))
+	app.Use(handlers.ValidateRequests(handlers.OpenAPISpec(), handlers.ValidationOptions{}))
+	v1 := app.Group("/v1")
+	v1.Get("/tasks", handler.GetAllTasks)
+	v1.Get("/tasks/:id", handler.GetTaskByID)
+	v1.Post("/tasks", handler.CreateTask)
+	v2 := app.Group("/v2", handlers.EnvelopeResponses())
+	v2.Get("/tasks", handler.ListTasks)
+	v2.Get("/tasks/:id", handler.GetTaskByID)
+	v2.Post("/tasks", handler.CreateTask)
+	app.Get("/health", func(c *fiber.Ctx) error { return c.SendString("OK") })
+	return app
+}
+
+// doVersioned sendet einen Request mit optionalem Accept-Header und dekodiert die JSON-Antwort.
+func doVersioned(t *testing.T, app *fiber.App, method, path, accept, body string) (map[string]any, int, http.Header) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	result := map[string]any{}
+	_ = json.Unmarshal(data, &result)
+	return result, resp.StatusCode, resp.Header
+}
+
+// Test_Versioning_Negotiation prüft die Version aus Pfad und Accept-Header sowie die Ablehnung unbekannter Versionen.
+func Test_Versioning_Negotiation(t *testing.T) {
+	app := setupVersionedApp(handlers.VersionOptions{})
+
+	tests := []struct {
+		name, path, accept, version string
+	}{
+		{"Pfad v1", "/v1/tasks/1", "", "v1"},
+		{"Pfad v2", "/v2/tasks/1", "application/vnd.taskapi.v1+json", "v2"},
+		{"Default ohne Präfix", "/tasks/1", "", "v1"},
+		{"Vendor-Medientyp", "/tasks/1", "application/vnd.taskapi.v2+json", "v2"},
+		{"Parameter version", "/tasks/1", "application/json; version=2", "v2"},
+		{"Unbekannte und bekannte Version", "/tasks/1", "application/vnd.taskapi.v9+json, application/vnd.taskapi.v2+json", "v2"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body, status, header := doVersioned(t, app, "GET", tc.path, tc.accept, "")
+			assert.Equal(t, fiber.StatusOK, status)
+			assert.Equal(t, tc.version, header.Get("API-Version"))
+			if tc.version == "v2" {
+				assert.Contains(t, body, "data")
+			} else {
+				assert.Equal(t, "Login", body["title"])
+			}
+		})
+	}
+
+	body, status, _ := doVersioned(t, app, "GET", "/tasks/1", "application/vnd.taskapi.v9+json", "")
+	assert.Equal(t, fiber.StatusNotAcceptable, status)
+	assert.Equal(t, "not acceptable", body["error"])
+
+	// Routen ohne Version bleiben unverändert
+	_, status, header := doVersioned(t, app, "GET", "/health", "application/vnd.taskapi.v9+json", "")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Empty(t, header.Get("API-Version"))
+
+	// Default v2 für Clients ohne Versionsangabe
+	app = setupVersionedApp(handlers.VersionOptions{Default: handlers.APIVersion2})
+	_, _, header = doVersioned(t, app, "GET", "/tasks", "", "")
+	assert.Equal(t, "v2", header.Get("API-Version"))
+}
+
+// Test_Versioning_DeprecationHeaders prüft Deprecation, Sunset und Link auf v1 und deren Fehlen in v2.
+func Test_Versioning_DeprecationHeaders(t *testing.T) {
+	app := setupVersionedApp(handlers.VersionOptions{
+		Deprecation: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		Sunset:      time.Date(2027, 4, 18, 0, 0, 0, 0, time.UTC),
+	})
+
+	_, _, header := doVersioned(t, app, "GET", "/v1/tasks/1", "", "")
+	assert.Equal(t, "@1792281600", header.Get("Deprecation"))
+	assert.Equal(t, "Sun, 18 Apr 2027 00:00:00 GMT", header.Get("Sunset"))
+	assert.Equal(t, `</v2/tasks/1>; rel="successor-version"`, header.Get("Link"))
+
+	_, _, header = doVersioned(t, app, "GET", "/tasks/1", "", "")
+	assert.Equal(t, `</v2/tasks/1>; rel="successor-version"`, header.Get("Link"))
+	assert.Equal(t, "Accept", header.Get("Vary"))
+
+	_, _, header = doVersioned(t, app, "GET", "/v2/tasks/1", "", "")
+	assert.Empty(t, header.Get("Deprecation"))
+	assert.Empty(t, header.Get("Sunset"))
+}
+
+// Test_Versioning_V2Representation prüft Envelopes für Ressourcen, Listen und Fehler in v2.
+func Test_Versioning_V2Representation(t *testing.T) {
+	app := setupVersionedApp(handlers.VersionOptions{})
+
+	body, status, _ := doVersioned(t, app, "GET", "/v2/tasks", "", "")
+	assert.Equal(t, fiber.StatusOK, status)
+	data := body["data"].([]any)
+	if assert.Len(t, data, 1) {
+		// v2 liefert vollständige Tasks, v1 nur die Kurzform
+		assert.Equal(t, "OAuth", data[0].(map[string]any)["description"])
+	}
+	assert.Equal(t, map[string]any{"total": float64(1)}, body["meta"])
+
+	body, _, _ = doVersioned(t, app, "GET", "/v1/tasks", "", "")
+	assert.NotContains(t, body["tasks"].([]any)[0], "description")
+
+	body, status, _ = doVersioned(t, app, "POST", "/v2/tasks", "", `{"title": "Deploy"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Equal(t, "Deploy", body["data"].(map[string]any)["title"])
+
+	// Fehler des Handlers
+	body, status, _ = doVersioned(t, app, "GET", "/v2/tasks/99", "", "")
+	assert.Equal(t, fiber.StatusNotFound, status)
+	assert.Equal(t, "not_found", body["error"].(map[string]any)["code"])
+
+	// Fehler der Validierung vor dem Routing
+	body, status, _ = doVersioned(t, app, "POST", "/v2/tasks", "", `{"priority": "urgent"}`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	errBody := body["error"].(map[string]any)
+	assert.Equal(t, "validation_error", errBody["code"])
+	assert.Len(t, errBody["fields"], 2)
+
+	// Fehler der Authentifizierung und unbekannte Routen
+	req := httptest.NewRequest("GET", "/v2/tasks", nil)
+	req.Header.Set("X-API-Key", "wrong")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	var unauthorized map[string]map[string]any
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&unauthorized))
+	assert.Equal(t, "unauthorized", unauthorized["error"]["code"])
+
+	body, status, _ = doVersioned(t, app, "GET", "/v2/unknown", "", "")
+	assert.Equal(t, fiber.StatusNotFound, status)
+	assert.Equal(t, "not_found", body["error"].(map[string]any)["code"])
+
+	// v1 behält das bisherige Fehlerformat
+	body, _, _ = doVersioned(t, app, "GET", "/v1/tasks/99", "", "")
+	assert.Equal(t, "not found", body["error"])
+}
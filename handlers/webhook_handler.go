@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"task-api/models"
+	"task-api/services"
+)
+
+// WebhookHandler stellt die HTTP-Endpoints für Webhook-Abonnements, das Zustellprotokoll
+// und die Dead-Letter-Liste bereit. Alle Endpoints erfordern einen API-Key.
+type WebhookHandler struct {
+	Service services.WebhookServiceInterface
+}
+
+// webhookErrorResponse übersetzt Fehler aus dem WebhookService in die passende HTTP-Antwort.
+func webhookErrorResponse(c *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "not found", "delivery not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "not found",
+			"message": err.Error(),
+		})
+	case "authentication required":
+		return unauthorizedResponse(c)
+	case "delivery already pending":
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   "conflict",
+			"message": err.Error(),
+		})
+	case "invalid url":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "url must be an absolute http or https URL",
+		})
+	case "invalid event":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "events must be a subset of the supported events",
+			"events":  models.WebhookEvents,
+		})
+	case "invalid secret":
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "secret must be at least 16 characters",
+		})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"error":   "internal error",
+		"message": err.Error(),
+	})
+}
+
+// GetAllWebhooks verarbeitet GET /webhooks.
+//
+// Antwort:
+//
+//	200 - OK + Array von Webhooks (ohne Secret)
+//	401 - Kein API-Key angegeben
+func (h *WebhookHandler) GetAllWebhooks(c *fiber.Ctx) error {
+	webhooks, err := h.Service.GetAllWebhooks(currentUser(c))
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+	if webhooks == nil {
+		webhooks = []*models.Webhook{}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"webhooks": webhooks,
+		"total":    len(webhooks),
+	})
+}
+
+// CreateWebhook verarbeitet POST /webhooks.
+// Das Secret für die Signaturprüfung wird nur in dieser Antwort ausgegeben.
+//
+// Antwort:
+//
+//	201 - Webhook erstellt (JSON inkl. secret)
+//	400 - Ungültige URL, unbekanntes Ereignis oder zu kurzes Secret
+//	401 - Kein API-Key angegeben
+//
+// Beispiel Request-Body:
+//
+//	{
+//	  "url": "https://ci.example.com/hooks/tasks",
+//	  "events": ["task.created", "task.status_changed"]
+//	}
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	var req models.WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "invalid request body",
+		})
+	}
+
+	webhook, err := h.Service.CreateWebhook(req, currentUser(c))
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(webhook)
+}
+
+// GetWebhook verarbeitet GET /webhooks/:id.
+//
+// Antwort:
+//
+//	200 - OK + Webhook (ohne Secret)
+//	401 - Kein API-Key angegeben
+//	404 - Webhook existiert nicht
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	webhook, err := h.Service.GetWebhook(id, currentUser(c))
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(webhook)
+}
+
+// UpdateWebhook verarbeitet PUT /webhooks/:id.
+// Nur angegebene Felder werden geändert; "active": false pausiert die Zustellung.
+//
+// Antwort:
+//
+//	200 - Webhook aktualisiert (JSON)
+//	400 - Ungültige URL, unbekanntes Ereignis oder zu kurzes Secret
+//	401 - Kein API-Key angegeben
+//	404 - Webhook existiert nicht
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	var req models.WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "validation error",
+			"message": "invalid request body",
+		})
+	}
+
+	webhook, err := h.Service.UpdateWebhook(id, req, currentUser(c))
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(webhook)
+}
+
+// DeleteWebhook verarbeitet DELETE /webhooks/:id.
+//
+// Antwort:
+//
+//	204 - Erfolgreich gelöscht (Kein Body)
+//	401 - Kein API-Key angegeben
+//	404 - Webhook existiert nicht
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	if err := h.Service.DeleteWebhook(id, currentUser(c)); err != nil {
+		return webhookErrorResponse(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetDeliveries verarbeitet GET /webhooks/:id/deliveries.
+//
+// Antwort:
+//
+//	200 - OK + letzte Zustellungen (neueste zuerst) inkl. Protokoll der Versuche
+//	401 - Kein API-Key angegeben
+//	404 - Webhook existiert nicht
+func (h *WebhookHandler) GetDeliveries(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	deliveries, err := h.Service.GetDeliveries(id, currentUser(c))
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+	return deliveriesResponse(c, deliveries)
+}
+
+// GetDeadLetters verarbeitet GET /webhooks/dead-letters.
+//
+// Antwort:
+//
+//	200 - OK + endgültig gescheiterte Zustellungen aller Webhooks
+//	401 - Kein API-Key angegeben
+func (h *WebhookHandler) GetDeadLetters(c *fiber.Ctx) error {
+	deliveries, err := h.Service.GetDeadLetters(currentUser(c))
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+	return deliveriesResponse(c, deliveries)
+}
+
+// RetryDelivery verarbeitet POST /webhooks/deliveries/:id/retry.
+//
+// Antwort:
+//
+//	202 - Zustellung erneut eingeplant (JSON)
+//	401 - Kein API-Key angegeben
+//	404 - Zustellung existiert nicht
+//	409 - Zustellung wartet bereits auf einen Versuch
+func (h *WebhookHandler) RetryDelivery(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return invalidIDResponse(c)
+	}
+
+	delivery, err := h.Service.RetryDelivery(id, currentUser(c))
+	if err != nil {
+		return webhookErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(delivery)
+}
+
+// deliveriesResponse liefert eine Liste von Zustellungen samt Anzahl.
+func deliveriesResponse(c *fiber.Ctx, deliveries []*models.WebhookDelivery) error {
+	if deliveries == nil {
+		deliveries = []*models.WebhookDelivery{}
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"deliveries": deliveries,
+		"total":      len(deliveries),
+	})
+}
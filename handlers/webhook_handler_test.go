@@ -0,0 +1,120 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// setupWebhookHandler initialisiert einen Fiber-App-Server mit Authentifizierung und allen
+// Webhook-Routen. Benutzer 1 meldet sich mit "anna-key" an.
+func setupWebhookHandler(mockService *services.MockWebhookService) *fiber.App {
+	app := fiber.New()
+	app.Use(handlers.Authenticate(&services.MockUserService{
+		Users:   testUsers(),
+		APIKeys: map[string]int{"anna-key": 1},
+	}))
+
+	handler := handlers.WebhookHandler{Service: mockService}
+	app.Get("/webhooks", handler.GetAllWebhooks)
+	app.Post("/webhooks", handler.CreateWebhook)
+	app.Get("/webhooks/dead-letters", handler.GetDeadLetters)
+	app.Post("/webhooks/deliveries/:id/retry", handler.RetryDelivery)
+	app.Get("/webhooks/:id", handler.GetWebhook)
+	app.Put("/webhooks/:id", handler.UpdateWebhook)
+	app.Delete("/webhooks/:id", handler.DeleteWebhook)
+	app.Get("/webhooks/:id/deliveries", handler.GetDeliveries)
+	return app
+}
+
+// sendWebhook führt einen Request gegen app aus und gibt Statuscode und Body zurück.
+func sendWebhook(app *fiber.App, method, url, body, key string) (int, string) {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	resp, _ := app.Test(req)
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+// Test_CreateWebhook_Handler prüft Anlegen, Validierung und dass das Secret nur beim Anlegen ausgegeben wird.
+func Test_CreateWebhook_Handler(t *testing.T) {
+	app := setupWebhookHandler(&services.MockWebhookService{})
+
+	status, _ := sendWebhook(app, "POST", "/webhooks", `{"url":"https://example.com/hook"}`, "")
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+
+	status, body := sendWebhook(app, "POST", "/webhooks", `{"url":"https://example.com/hook","events":["task.exploded"]}`, "anna-key")
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Contains(t, body, "task.status_changed")
+
+	status, body = sendWebhook(app, "POST", "/webhooks", `{"url":"https://example.com/hook","events":["task.created"]}`, "anna-key")
+	assert.Equal(t, fiber.StatusCreated, status)
+	var created map[string]any
+	json.Unmarshal([]byte(body), &created)
+	assert.Equal(t, "whsec_mock_1", created["secret"])
+	assert.Equal(t, []any{"task.created"}, created["events"])
+	assert.Equal(t, true, created["active"])
+
+	status, body = sendWebhook(app, "GET", "/webhooks/1", "", "anna-key")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.NotContains(t, body, "secret")
+
+	status, body = sendWebhook(app, "PUT", "/webhooks/1", `{"active":false}`, "anna-key")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Contains(t, body, `"active":false`)
+
+	status, _ = sendWebhook(app, "DELETE", "/webhooks/1", "", "anna-key")
+	assert.Equal(t, fiber.StatusNoContent, status)
+
+	status, _ = sendWebhook(app, "GET", "/webhooks/1", "", "anna-key")
+	assert.Equal(t, fiber.StatusNotFound, status)
+}
+
+// Test_WebhookDeliveries_Handler prüft Zustellprotokoll, Dead-Letter-Liste und erneutes Einplanen.
+func Test_WebhookDeliveries_Handler(t *testing.T) {
+	code := 500
+	app := setupWebhookHandler(&services.MockWebhookService{
+		Webhooks: []*models.Webhook{{ID: 1, URL: "https://example.com/hook", Active: true}},
+		Deliveries: []*models.WebhookDelivery{
+			{ID: 1, WebhookID: 1, Event: models.EventTaskCreated, Status: models.DeliverySucceeded, Attempts: 1},
+			{ID: 2, WebhookID: 1, Event: models.EventTaskDeleted, Status: models.DeliveryDead, Attempts: 8,
+				LastStatusCode: &code, Log: []models.DeliveryAttempt{{Attempt: 8, StatusCode: &code, Error: "unexpected status 500"}}},
+		},
+	})
+
+	status, body := sendWebhook(app, "GET", "/webhooks/1/deliveries", "", "anna-key")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Contains(t, body, `"total":2`)
+	assert.Contains(t, body, `"error":"unexpected status 500"`)
+
+	status, body = sendWebhook(app, "GET", "/webhooks/dead-letters", "", "anna-key")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Contains(t, body, `"total":1`)
+	assert.Contains(t, body, `"event":"task.deleted"`)
+
+	status, _ = sendWebhook(app, "GET", "/webhooks/dead-letters", "", "")
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+
+	status, body = sendWebhook(app, "POST", "/webhooks/deliveries/2/retry", "", "anna-key")
+	assert.Equal(t, fiber.StatusAccepted, status)
+	assert.Contains(t, body, `"status":"pending"`)
+
+	status, _ = sendWebhook(app, "POST", "/webhooks/deliveries/2/retry", "", "anna-key")
+	assert.Equal(t, fiber.StatusConflict, status)
+
+	status, _ = sendWebhook(app, "POST", "/webhooks/deliveries/9/retry", "", "anna-key")
+	assert.Equal(t, fiber.StatusNotFound, status)
+}
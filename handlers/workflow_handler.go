@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetWorkflow verarbeitet GET /workflow.
+// Gibt alle Status, die erlaubten Übergänge sowie pro Status die möglichen
+// Folgestatus zurück, damit Clients nur gültige Statuswechsel anbieten.
+//
+// Antwort:
+//
+//	200 - OK + Workflow-Definition
+//
+// Beispiel Response-Body:
+//
+//	{
+//	  "statuses": [{"name": "todo", "label": "To Do", "initial": true, "final": false}, ...],
+//	  "transitions": [{"from": "todo", "to": "in progress", "requires_reopen": false}, ...],
+//	  "next_states": {"todo": ["in progress", "done"], ...}
+//	}
+func (h *TaskHandler) GetWorkflow(c *fiber.Ctx) error {
+	workflow := h.Service.GetWorkflow()
+
+	next := make(map[string][]string, len(workflow.Statuses))
+	for _, s := range workflow.Statuses {
+		next[s.Name] = workflow.NextStatuses(s.Name)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"statuses":    workflow.Statuses,
+		"transitions": workflow.Transitions,
+		"next_states": next,
+	})
+}
+
+// currentStatus liefert den aktuellen Status eines Tasks oder "", wenn dieser nicht geladen werden kann.
+func (h *TaskHandler) currentStatus(id int) string {
+	task, err := h.Service.GetTaskByID(id)
+	if err != nil {
+		return ""
+	}
+	return task.Status
+}
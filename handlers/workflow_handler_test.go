@@ -0,0 +1,51 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"task-api/handlers"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Test_GetWorkflow_Handler_Success prüft, dass GET /workflow die Status und die möglichen Folgestatus liefert.
+func Test_GetWorkflow_Handler_Success(t *testing.T) {
+	app := fiber.New()
+	handler := handlers.TaskHandler{Service: &services.MockTaskService{}}
+	app.Get("/workflow", handler.GetWorkflow)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/workflow", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	data, _ := io.ReadAll(resp.Body)
+	var body struct {
+		Statuses   []models.WorkflowStatus `json:"statuses"`
+		NextStates map[string][]string     `json:"next_states"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &body))
+	assert.Len(t, body.Statuses, 3)
+	assert.Equal(t, []string{"in progress", "done"}, body.NextStates["todo"])
+}
+
+// Test_CreateTask_Handler_InvalidStatusMessage prüft, dass die Fehlermeldung die Status des Workflows aufzählt.
+func Test_CreateTask_Handler_InvalidStatusMessage(t *testing.T) {
+	app := setupFiberHandler(&services.MockTaskService{})
+
+	body, _ := json.Marshal(models.CreateTaskRequest{Title: "ok", Status: "waiting"})
+	req := httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	data, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(data), "/status: must be one of: todo, in progress, done")
+}
@@ -0,0 +1,132 @@
+// Package ical schreibt iCalendar-Daten nach RFC 5545 (text/calendar).
+// Der Encoder kümmert sich um Zeilenenden (CRLF), das Escaping von Texten und das Falten langer Zeilen;
+// welche Komponenten und Eigenschaften geschrieben werden, bestimmt der Aufrufer.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType ist der MIME-Typ von iCalendar-Daten.
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineOctets ist die maximale Länge einer Zeile ohne CRLF (RFC 5545, Abschnitt 3.1).
+const maxLineOctets = 75
+
+// timeFormat ist das Format von DATE-TIME-Werten in UTC.
+const timeFormat = "20060102T150405Z"
+
+// textEscaper escaped Zeichen, die in TEXT-Werten eine Bedeutung haben (RFC 5545, Abschnitt 3.3.11).
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// Encoder schreibt Komponenten und Eigenschaften zeilenweise. Der erste Schreibfehler wird gespeichert
+// und von Close zurückgegeben; alle weiteren Aufrufe schreiben dann nichts mehr.
+type Encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewEncoder erzeugt einen Encoder, der nach w schreibt.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+// Begin beginnt eine Komponente, z.B. "VCALENDAR" oder "VTODO".
+func (e *Encoder) Begin(component string) {
+	e.Value("BEGIN", component)
+}
+
+// End beendet eine Komponente.
+func (e *Encoder) End(component string) {
+	e.Value("END", component)
+}
+
+// Value schreibt eine Eigenschaft mit unverändertem Wert. name darf Parameter enthalten,
+// z.B. "REFRESH-INTERVAL;VALUE=DURATION".
+func (e *Encoder) Value(name, value string) {
+	e.writeLine(name + ":" + value)
+}
+
+// Text schreibt eine Eigenschaft vom Typ TEXT. Leere Texte werden weggelassen.
+func (e *Encoder) Text(name, value string) {
+	if value == "" {
+		return
+	}
+	e.Value(name, escapeText(value))
+}
+
+// Texts schreibt eine Eigenschaft mit einer Liste von Texten, z.B. CATEGORIES. Leere Listen werden weggelassen.
+func (e *Encoder) Texts(name string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = escapeText(v)
+	}
+	e.Value(name, strings.Join(escaped, ","))
+}
+
+// Time schreibt einen Zeitpunkt als DATE-TIME in UTC.
+func (e *Encoder) Time(name string, t time.Time) {
+	e.Value(name, t.UTC().Format(timeFormat))
+}
+
+// Int schreibt eine Eigenschaft vom Typ INTEGER.
+func (e *Encoder) Int(name string, v int) {
+	e.Value(name, strconv.Itoa(v))
+}
+
+// Close schreibt gepufferte Daten und gibt den ersten Fehler zurück.
+func (e *Encoder) Close() error {
+	if e.err == nil {
+		e.err = e.w.Flush()
+	}
+	return e.err
+}
+
+// writeLine schreibt eine Inhaltszeile mit CRLF und faltet sie nach maxLineOctets Bytes, ohne ein
+// UTF-8-Zeichen zu trennen. Folgezeilen beginnen mit einem Leerzeichen, das zur Zeilenlänge zählt.
+func (e *Encoder) writeLine(line string) {
+	if e.err != nil {
+		return
+	}
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		e.write(line[:cut], "\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1
+	}
+	e.write(line, "\r\n")
+}
+
+// write schreibt die Teile nacheinander und merkt sich den ersten Fehler.
+func (e *Encoder) write(parts ...string) {
+	for _, p := range parts {
+		if e.err != nil {
+			return
+		}
+		_, e.err = e.w.WriteString(p)
+	}
+}
+
+// escapeText escaped einen TEXT-Wert und entfernt Steuerzeichen, die in TEXT nicht erlaubt sind
+// (außer Tabulator und Zeilenumbruch, der als \n kodiert wird).
+func escapeText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+	return textEscaper.Replace(s)
+}
@@ -0,0 +1,63 @@
+package ical
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// encode ruft write mit einem Encoder auf und gibt die Ausgabe zurück.
+func encode(t *testing.T, write func(e *Encoder)) string {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	write(e)
+	assert.NoError(t, e.Close())
+	return buf.String()
+}
+
+// Test_Encoder_WritesComponentsWithCRLF prüft Komponenten, Zeitpunkte in UTC und CRLF als Zeilenende.
+func Test_Encoder_WritesComponentsWithCRLF(t *testing.T) {
+	berlin := time.FixedZone("CEST", 2*60*60)
+	out := encode(t, func(e *Encoder) {
+		e.Begin("VTODO")
+		e.Value("UID", "task-1@example")
+		e.Time("DUE", time.Date(2026, 5, 1, 14, 0, 0, 0, berlin))
+		e.Int("PRIORITY", 1)
+		e.Text("DESCRIPTION", "")
+		e.End("VTODO")
+	})
+
+	assert.Equal(t, "BEGIN:VTODO\r\nUID:task-1@example\r\nDUE:20260501T120000Z\r\nPRIORITY:1\r\nEND:VTODO\r\n", out)
+}
+
+// Test_Encoder_EscapesText prüft das Escaping von Sonderzeichen, Zeilenumbrüchen und Listen.
+func Test_Encoder_EscapesText(t *testing.T) {
+	out := encode(t, func(e *Encoder) {
+		e.Text("SUMMARY", "Release; Teil 1, Backend \\ API\r\nzweite Zeile\x00")
+		e.Texts("CATEGORIES", []string{"backend", "a,b"})
+	})
+
+	assert.Equal(t, `SUMMARY:Release\; Teil 1\, Backend \\ API\nzweite Zeile`+"\r\n"+
+		`CATEGORIES:backend,a\,b`+"\r\n", out)
+}
+
+// Test_Encoder_FoldsLongLines prüft, dass keine Zeile länger als 75 Bytes ist, Folgezeilen mit einem
+// Leerzeichen beginnen und Mehrbyte-Zeichen nicht getrennt werden.
+func Test_Encoder_FoldsLongLines(t *testing.T) {
+	text := strings.Repeat("ä", 100)
+	out := encode(t, func(e *Encoder) { e.Text("SUMMARY", text) })
+
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	assert.Greater(t, len(lines), 1)
+	unfolded := lines[0]
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 75)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(line, " "))
+			unfolded += line[1:]
+		}
+	}
+	assert.Equal(t, "SUMMARY:"+text, unfolded)
+}
@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// csvReader liest RFC-4180-CSV; die Kopfzeile bestimmt die Spaltennamen.
+type csvReader struct {
+	r      *csv.Reader
+	header []string
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("csv header is missing")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+	// Tabellenkalkulationen schreiben UTF-8 oft mit Byte Order Mark
+	header[0] = strings.TrimPrefix(header[0], "\uFEFF")
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	return &csvReader{r: cr, header: header}, nil
+}
+
+// Next liest die nächste Zeile. Zeilen mit abweichender Spaltenanzahl oder fehlerhaftem Quoting werden als
+// fehlerhafte Zeile gemeldet.
+func (cr *csvReader) Next() (*Record, error) {
+	fields, err := cr.r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &Record{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := cr.r.FieldPos(0)
+	if len(fields) != len(cr.header) {
+		return &Record{Line: line, Err: fmt.Errorf("row has %d columns, header has %d", len(fields), len(cr.header))}, nil
+	}
+	values := make(map[string]any, len(fields))
+	for i, v := range fields {
+		values[cr.header[i]] = v
+	}
+	return &Record{Line: line, Values: values}, nil
+}
@@ -0,0 +1,43 @@
+// Package importer liest Tabellen zeilenweise aus CSV oder NDJSON, das Gegenstück zu package export.
+// Fehlerhafte Zeilen brechen das Lesen nicht ab, sondern werden mit ihrer Zeilennummer gemeldet.
+package importer
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"task-api/export"
+)
+
+// Formats sind die Formate, die gelesen werden können.
+var Formats = []export.Format{export.CSV, export.NDJSON}
+
+// Record ist eine Zeile der Datei.
+type Record struct {
+	Line   int            // Zeilennummer in der Datei (ab 1; bei CSV ist Zeile 1 die Kopfzeile)
+	Values map[string]any // Spalte -> Wert; CSV liefert Texte, NDJSON JSON-Werte (Zahlen als json.Number)
+	Err    error          // Fehler in dieser Zeile (z.B. ungültiges JSON); Values ist dann nil
+}
+
+// Reader liest die Zeilen einer Datei.
+type Reader interface {
+	// Next gibt die nächste Zeile zurück, nil, nil nach der letzten Zeile. Ein Fehler bedeutet, dass die Datei
+	// nicht weiter gelesen werden kann; Fehler einzelner Zeilen stehen in Record.Err.
+	Next() (*Record, error)
+}
+
+// NewReader erstellt einen Reader für das Format f. CSV erwartet eine Kopfzeile mit den Spaltennamen.
+func NewReader(f export.Format, r io.Reader) (Reader, error) {
+	switch f {
+	case export.CSV:
+		return newCSVReader(r)
+	case export.NDJSON:
+		return newNDJSONReader(r), nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", f)
+}
+
+// Supported meldet, ob das Format f gelesen werden kann.
+func Supported(f export.Format) bool {
+	return slices.Contains(Formats, f)
+}
@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"task-api/export"
+	"testing"
+)
+
+// readAll liest alle Zeilen einer Datei im Format f.
+func readAll(t *testing.T, f export.Format, data string) []*Record {
+	r, err := NewReader(f, strings.NewReader(data))
+	assert.NoError(t, err)
+	var records []*Record
+	for {
+		rec, err := r.Next()
+		assert.NoError(t, err)
+		if rec == nil {
+			return records
+		}
+		records = append(records, rec)
+	}
+}
+
+// Test_CSV_ReadsRowsByHeader prüft Spaltennamen aus der Kopfzeile (inkl. BOM), Zeilennummern und Quoting.
+func Test_CSV_ReadsRowsByHeader(t *testing.T) {
+	records := readAll(t, export.CSV, "\uFEFFtitle, priority\nRelease,high\n\"Say \"\"hi\"\"\nnow\",low\n")
+
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, &Record{Line: 2, Values: map[string]any{"title": "Release", "priority": "high"}}, records[0])
+		assert.Equal(t, 3, records[1].Line)
+		assert.Equal(t, "Say \"hi\"\nnow", records[1].Values["title"])
+	}
+}
+
+// Test_CSV_ReportsBadRows prüft, dass fehlerhafte Zeilen gemeldet und die folgenden weiter gelesen werden.
+func Test_CSV_ReportsBadRows(t *testing.T) {
+	records := readAll(t, export.CSV, "title,priority\nOnly one column\nDocs,low\n")
+
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, 2, records[0].Line)
+		assert.EqualError(t, records[0].Err, "row has 1 columns, header has 2")
+		assert.Equal(t, "Docs", records[1].Values["title"])
+	}
+}
+
+// Test_CSV_MissingHeader prüft den Fehler bei einer leeren Datei.
+func Test_CSV_MissingHeader(t *testing.T) {
+	_, err := NewReader(export.CSV, strings.NewReader(""))
+	assert.EqualError(t, err, "csv header is missing")
+}
+
+// Test_NDJSON_ReadsObjects prüft JSON-Werte, übersprungene Leerzeilen und ungültige Zeilen.
+func Test_NDJSON_ReadsObjects(t *testing.T) {
+	records := readAll(t, export.NDJSON, "{\"title\":\"Release\",\"parent_id\":3}\n\n[1,2]\n{\"title\":\"Docs\"}")
+
+	if assert.Len(t, records, 3) {
+		assert.Equal(t, map[string]any{"title": "Release", "parent_id": json.Number("3")}, records[0].Values)
+		assert.Equal(t, 3, records[1].Line)
+		assert.EqualError(t, records[1].Err, "line is not a JSON object")
+		assert.Equal(t, 4, records[2].Line)
+		assert.Equal(t, "Docs", records[2].Values["title"])
+	}
+}
+
+// Test_NewReader_UnsupportedFormat prüft, dass XLSX nicht gelesen werden kann.
+func Test_NewReader_UnsupportedFormat(t *testing.T) {
+	_, err := NewReader(export.XLSX, strings.NewReader(""))
+	assert.Error(t, err)
+	assert.False(t, Supported(export.XLSX))
+}
@@ -0,0 +1,46 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// ndjsonReader liest ein JSON-Objekt pro Zeile; leere Zeilen werden übersprungen.
+type ndjsonReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	return &ndjsonReader{r: bufio.NewReader(r)}
+}
+
+// Next liest die nächste nicht leere Zeile. Zeilen, die kein JSON-Objekt enthalten, werden als fehlerhafte
+// Zeile gemeldet.
+func (nr *ndjsonReader) Next() (*Record, error) {
+	for {
+		data, err := nr.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(data) == 0 && err == io.EOF {
+			return nil, nil
+		}
+		nr.line++
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		var values map[string]any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if decErr := dec.Decode(&values); decErr != nil || values == nil || dec.More() {
+			return &Record{Line: nr.line, Err: errors.New("line is not a JSON object")}, nil
+		}
+		return &Record{Line: nr.line, Values: values}, nil
+	}
+}
@@ -1,73 +1,92 @@
+// Command server startet die Task-API und enthält Admin-Befehle für den Betrieb.
+//
+//	server                 startet die API (wie "server serve")
+//	server migrate         wendet ausstehende Migrationen an
+//	server seed            legt einen Demo-Benutzer mit Beispiel-Tasks an
+//	server purge           löscht gelöschte Tasks (und mit --events alte Outbox-Ereignisse) endgültig
+//	server keys            listet Benutzer, legt Benutzer an und rotiert API-Keys
+//	server doctor          prüft Konfiguration, Datenbank, Schema-Version und Rechte
+//
+// Alle Befehle lesen dieselbe Konfiguration aus den Umgebungsvariablen (siehe loadConfig).
 package main
 
 import (
-	"database/sql"
 	"fmt"
-	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/cobra"
 	"log"
 	"os"
-	"task-api/handlers"
-	"task-api/repository"
-	"task-api/services"
+	"strings"
+	// Zeitzonendatenbank einbetten: Das Alpine-Image enthält kein tzdata, time.LoadLocation würde dort
+	// für tz= (GET /tasks, POST /tasks/import) mit jeder Zone außer UTC fehlschlagen
+	_ "time/tzdata"
 )
 
-// main ist der Einstiegspunkt der Anwendung.
-// - Stellt die PostgreSQL-Datenbankverbindung her
-// - Initialisiert Repository-, Service- und Handler-Layer
-// - Registriert alle HTTP-Routen
-// - Startet den Fiber Webserver unter Port 8080
 func main() {
-	app := fiber.New()
-
-	// Erstellen des Connection-Strings für Postgres.
-	// Werte werden über Umgebungsvariablen eingelesen.
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		os.Getenv("POSTGRES_HOST"),
-		os.Getenv("POSTGRES_PORT"),
-		os.Getenv("POSTGRES_USER"),
-		os.Getenv("POSTGRES_PASSWORD"),
-		os.Getenv("POSTGRES_DB"),
-	)
-
-	// Öffnet die Datenbankverbindung
-	db, err := sql.Open("postgres", connStr)
-	if err != nil {
-		log.Fatal(err)
+	if err := newRootCmd(os.Getenv).Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
 	}
+}
 
-	// Testet, ob die Verbindung möglich ist
-	if err := db.Ping(); err != nil {
-		log.Fatal(err)
+// newRootCmd baut den Befehlsbaum auf. Ohne Unterbefehl wird der Server gestartet, damit bestehende
+// Deployments ("./server") unverändert funktionieren.
+func newRootCmd(getenv func(string) string) *cobra.Command {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start the REST, GraphQL and gRPC API (default command)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := requireConfig(getenv)
+			if err != nil {
+				return err
+			}
+			return serve(cfg)
+		},
 	}
 
-	// Dependency-Injection:
-	// Repository -> Service -> Handler
-	repo := &repository.PostgresTaskRepository{DB: db}
-	service := &services.TaskService{Repo: repo}
-	handler := &handlers.TaskHandler{Service: service}
-
-	// ---------------------- ROUTES ----------------------
-	// POST /tasks  -> Erstellt einen neuen Task
-	app.Post("/tasks", handler.CreateTask)
-
-	// GET /tasks -> Liefert eine Liste aller Tasks zurück
-	app.Get("/tasks", handler.GetAllTasks)
-
-	// GET /tasks/:id -> Liefert einen Task anhand seiner ID zurück
-	app.Get("/tasks/:id", handler.GetTaskByID)
-
-	// PUT /tasks/:id -> Aktualisiert einen bestehenden Task
-	app.Put("/tasks/:id", handler.UpdateTask)
-
-	// DELETE /tasks/:id -> Löscht einen Task anhand der ID
-	app.Delete("/tasks/:id", handler.DeleteTask)
+	root := &cobra.Command{
+		Use:           "server",
+		Short:         "Task API server and admin commands",
+		Args:          cobra.NoArgs,
+		RunE:          serveCmd.RunE,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.CompletionOptions.DisableDefaultCmd = true
+	root.AddCommand(
+		serveCmd,
+		newMigrateCmd(getenv),
+		newSeedCmd(getenv),
+		newPurgeCmd(getenv),
+		newKeysCmd(getenv),
+		newDoctorCmd(getenv),
+	)
+	return root
+}
 
-	// GET /health -> Health Check Endpoint, liefert "OK"
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.SendString("OK")
-	})
+// requireConfig lädt die Konfiguration, protokolliert Warnungen und bricht bei Fehlern ab.
+func requireConfig(getenv func(string) string) (*config, error) {
+	cfg := loadConfig(getenv)
+	for _, w := range cfg.Warnings {
+		log.Println("config:", w)
+	}
+	if len(cfg.Errors) > 0 {
+		return nil, fmt.Errorf("invalid configuration: %s (run \"server doctor\" for details)", strings.Join(cfg.Errors, "; "))
+	}
+	return cfg, nil
+}
 
-	// Startet Server unter Port 8080 (Blockierend)
-	log.Fatal(app.Listen(":8080"))
+// withRepositories lädt die Konfiguration, verbindet sich mit der Datenbank und ruft fn mit den
+// Repositories auf. Die Verbindung wird danach geschlossen.
+func withRepositories(getenv func(string) string, fn func(cfg *config, repos *repositories) error) error {
+	cfg, err := requireConfig(getenv)
+	if err != nil {
+		return err
+	}
+	db, err := openDB(cfg)
+	if err != nil {
+		return fmt.Errorf("database: %w", err)
+	}
+	defer db.Close()
+	return fn(cfg, newRepositories(db, cfg))
 }
@@ -0,0 +1,5 @@
+-- Subtasks: Ein Task kann einem übergeordneten Task (parent_id) zugeordnet werden.
+-- Beim Löschen des Parents werden Subtasks standardmäßig zu eigenständigen Tasks (SET NULL).
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
@@ -0,0 +1,36 @@
+-- Workflow-Definition: erlaubte Status und Übergänge zwischen ihnen.
+CREATE TABLE IF NOT EXISTS workflow_statuses (
+    name VARCHAR(50) PRIMARY KEY,
+    label VARCHAR(100) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_initial BOOLEAN NOT NULL DEFAULT FALSE,
+    is_final BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS workflow_transitions (
+    from_status VARCHAR(50) NOT NULL REFERENCES workflow_statuses(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    to_status VARCHAR(50) NOT NULL REFERENCES workflow_statuses(name) ON UPDATE CASCADE ON DELETE CASCADE,
+    requires_reopen BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (from_status, to_status)
+);
+
+INSERT INTO workflow_statuses (name, label, position, is_initial, is_final) VALUES
+    ('todo', 'To Do', 1, TRUE, FALSE),
+    ('in progress', 'In Progress', 2, FALSE, FALSE),
+    ('done', 'Done', 3, FALSE, TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO workflow_transitions (from_status, to_status, requires_reopen) VALUES
+    ('todo', 'in progress', FALSE),
+    ('todo', 'done', FALSE),
+    ('in progress', 'todo', FALSE),
+    ('in progress', 'done', FALSE),
+    ('done', 'todo', TRUE),
+    ('done', 'in progress', TRUE)
+ON CONFLICT (from_status, to_status) DO NOTHING;
+
+-- Altbestände mit abweichender Schreibweise vereinheitlichen, bevor der Fremdschlüssel greift
+UPDATE tasks SET status = 'in progress' WHERE status = 'in_progress';
+
+ALTER TABLE tasks
+    ADD CONSTRAINT fk_tasks_status FOREIGN KEY (status) REFERENCES workflow_statuses(name) ON UPDATE CASCADE;
@@ -0,0 +1,7 @@
+-- Fälligkeiten: due_at (Deadline), start_at (geplanter Beginn) und Zeitpunkt der gesendeten Erinnerung.
+-- TIMESTAMPTZ speichert absolute Zeitpunkte, die Zeitzone des Clients geht dadurch nicht verloren.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS start_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS reminder_sent_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks(due_at) WHERE due_at IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Wiederkehrende Tasks: Wiederholungsregel nach RFC 5545 (inkl. DTSTART) und Verknüpfung aller
+-- Vorkommen einer Serie über series_id (ID des ersten Vorkommens, bleibt auch nach dessen Löschung bestehen).
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_rule TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_id INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_series_id ON tasks(series_id, due_at);
@@ -0,0 +1,15 @@
+-- Tags: frei definierbare Labels, die Tasks über task_tags (m:n) zugeordnet werden.
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, tag_id)
+);
+
+-- Für Filter "alle Tasks mit Tag X" wird über tag_id gesucht
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag_id ON task_tags(tag_id, task_id);
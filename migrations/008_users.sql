@@ -0,0 +1,27 @@
+-- Benutzer: gehören genau einem Mandanten (tenant) an und authentifizieren sich per API-Key.
+-- Gespeichert wird nur der SHA-256-Hash des Keys.
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    tenant VARCHAR(50) NOT NULL DEFAULT 'default',
+    api_key_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Verantwortliche (assignees) und Beobachter (watchers) eines Tasks (jeweils m:n)
+CREATE TABLE IF NOT EXISTS task_assignees (
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS task_watchers (
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, user_id)
+);
+
+-- Für Filter "alle Tasks von Benutzer X" wird über user_id gesucht
+CREATE INDEX IF NOT EXISTS idx_task_assignees_user_id ON task_assignees(user_id, task_id);
+CREATE INDEX IF NOT EXISTS idx_task_watchers_user_id ON task_watchers(user_id, task_id);
@@ -0,0 +1,4 @@
+-- Kalender-Token: berechtigt nur zum Abruf des iCalendar-Feeds (GET /calendar.ics?token=...),
+-- damit Kalender-Apps keinen API-Key speichern müssen. Gespeichert wird nur der SHA-256-Hash;
+-- NULL bedeutet, dass der Benutzer keinen Feed freigegeben hat.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS calendar_token_hash CHAR(64) UNIQUE;
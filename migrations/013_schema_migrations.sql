@@ -0,0 +1,13 @@
+-- Schema-Version: jede angewendete Migration wird mit ihrer Nummer eingetragen ("server migrate").
+-- Beim ersten Start von Postgres laufen alle Dateien über docker-entrypoint-initdb.d. Die Migrationen
+-- 001 bis 012 stammen aus der Zeit vor dieser Tabelle und werden hier eingetragen; ab dieser Migration
+-- trägt jede Datei am Ende ihre eigene Version ein (von migrations.All geprüft).
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO schema_migrations (version) VALUES (1), (2), (3), (4), (5), (6), (7), (8), (9), (10), (11), (12)
+ON CONFLICT (version) DO NOTHING;
+
+INSERT INTO schema_migrations (version) VALUES (13) ON CONFLICT (version) DO NOTHING;
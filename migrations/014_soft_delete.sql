@@ -0,0 +1,7 @@
+-- Soft Delete: DELETE /tasks/:id setzt deleted_at, statt die Zeile zu entfernen. Gelöschte Tasks sind für
+-- die API unsichtbar und werden erst von "server purge" endgültig gelöscht (samt Tags, Kommentaren und Anhängen).
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at) WHERE deleted_at IS NOT NULL;
+
+INSERT INTO schema_migrations (version) VALUES (14) ON CONFLICT (version) DO NOTHING;
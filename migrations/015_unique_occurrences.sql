@@ -0,0 +1,12 @@
+-- Jede Serie darf zu einem Fälligkeitstermin nur ein Vorkommen haben. Der Index umfasst auch gelöschte
+-- Vorkommen, damit parallele Worker oder ein gelöschtes Vorkommen keine Duplikate erzeugen
+-- (INSERT ... ON CONFLICT DO NOTHING). Bereits vorhandene Duplikate werden vorher aus der Serie gelöst;
+-- nicht gelöschte Duplikate werden dabei als gelöscht markiert.
+UPDATE tasks t
+   SET series_id = NULL, deleted_at = COALESCE(t.deleted_at, NOW())
+ WHERE EXISTS (SELECT 1 FROM tasks o WHERE o.series_id = t.series_id AND o.due_at = t.due_at AND o.id < t.id);
+
+DROP INDEX IF EXISTS idx_tasks_series_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_series_due ON tasks(series_id, due_at);
+
+INSERT INTO schema_migrations (version) VALUES (15) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,29 @@
+-- Asynchrone Import-Jobs und ihre Fehlerberichte. Die Zeilen verarbeitet die Instanz, die die Datei
+-- empfangen hat; Status und Fehler liegen hier, damit jede Instanz sie ausliefern kann.
+-- updated_at dient als Lebenszeichen: Ein laufender Job ohne Aktualisierung gilt als abgebrochen.
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id VARCHAR(32) PRIMARY KEY,
+    status VARCHAR(20) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    rows_total INTEGER NOT NULL DEFAULT 0,
+    rows_processed INTEGER NOT NULL DEFAULT 0,
+    rows_valid INTEGER NOT NULL DEFAULT 0,
+    rows_created INTEGER NOT NULL DEFAULT 0,
+    rows_failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS import_job_errors (
+    id SERIAL PRIMARY KEY,
+    job_id VARCHAR(32) NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+    line INTEGER NOT NULL,
+    field VARCHAR(50) NOT NULL DEFAULT '',
+    message TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_job_errors_job ON import_job_errors(job_id, line, id);
+
+INSERT INTO schema_migrations (version) VALUES (16) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,79 @@
+// Package migrations enthält die SQL-Migrationen der Datenbank. Sie werden in das Binary eingebettet,
+// damit "server migrate" ohne das Verzeichnis migrations/ auskommt.
+//
+// Ab Version VersionTable trägt jede Migration am Ende ihre eigene Version in schema_migrations ein
+// (siehe RecordStatement). Nur so kennen auch Datenbanken, die docker-entrypoint-initdb.d beim ersten
+// Start aus allen Dateien anlegt, ihren Stand.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// VersionTable ist die Version der Migration, die schema_migrations anlegt (013_schema_migrations.sql).
+const VersionTable = 13
+
+// RecordStatement gibt die Anweisung zurück, mit der eine Migration ihre Version einträgt.
+func RecordStatement(version int) string {
+	return fmt.Sprintf("INSERT INTO schema_migrations (version) VALUES (%d) ON CONFLICT (version) DO NOTHING;", version)
+}
+
+// Migration ist eine Datei "<Version>_<Name>.sql", z.B. "007_tags.sql".
+type Migration struct {
+	Version int
+	Name    string // Dateiname
+	SQL     string
+}
+
+// All gibt alle Migrationen aufsteigend nach Version zurück.
+func All() ([]Migration, error) {
+	return parse(files)
+}
+
+// parse liest die Migrationen aus fsys. Dateinamen ohne Versionsnummer, doppelte Versionen und
+// Migrationen ab VersionTable, die ihre Version nicht mit RecordStatement eintragen, sind Fehler.
+func parse(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	var list []Migration
+	seen := map[int]string{}
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: file name must start with a version number", name)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migration %s: version %d already used by %s", name, version, other)
+		}
+		seen[version] = name
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if version >= VersionTable && !strings.Contains(string(data), RecordStatement(version)) {
+			return nil, fmt.Errorf("migration %s: must record its version with %q", name, RecordStatement(version))
+		}
+		list = append(list, Migration{Version: version, Name: name, SQL: string(data)})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// Latest gibt die höchste Version aus list zurück (0, wenn list leer ist).
+func Latest(list []Migration) int {
+	if len(list) == 0 {
+		return 0
+	}
+	return list[len(list)-1].Version
+}
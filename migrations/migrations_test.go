@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/fstest"
+)
+
+// Test_All_EmbeddedMigrations prüft, dass alle eingebetteten Migrationen lückenlos nummeriert sind.
+func Test_All_EmbeddedMigrations(t *testing.T) {
+	list, err := All()
+	assert.NoError(t, err)
+	for i, m := range list {
+		assert.Equal(t, i+1, m.Version, m.Name)
+		assert.NotEmpty(t, m.SQL, m.Name)
+	}
+	assert.Equal(t, "001_init.sql", list[0].Name)
+	assert.Equal(t, VersionTable, list[VersionTable-1].Version)
+	assert.Contains(t, list[VersionTable-1].SQL, "CREATE TABLE IF NOT EXISTS schema_migrations")
+}
+
+// Test_Parse_SortsAndRejectsInvalidNames prüft Sortierung sowie ungültige und doppelte Versionen.
+func Test_Parse_SortsAndRejectsInvalidNames(t *testing.T) {
+	list, err := parse(fstest.MapFS{
+		"010_b.sql": {Data: []byte("B")},
+		"002_a.sql": {Data: []byte("A")},
+		"README.md": {Data: []byte("ignoriert")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Migration{{Version: 2, Name: "002_a.sql", SQL: "A"}, {Version: 10, Name: "010_b.sql", SQL: "B"}}, list)
+
+	_, err = parse(fstest.MapFS{"init.sql": {}})
+	assert.EqualError(t, err, "migration init.sql: file name must start with a version number")
+
+	_, err = parse(fstest.MapFS{"001_a.sql": {}, "1_b.sql": {}})
+	assert.EqualError(t, err, "migration 1_b.sql: version 1 already used by 001_a.sql")
+
+	// Ab VersionTable muss jede Migration ihre eigene Version eintragen
+	_, err = parse(fstest.MapFS{"014_c.sql": {Data: []byte("ALTER TABLE tasks ADD COLUMN c TEXT;\n" + RecordStatement(13))}})
+	assert.EqualError(t, err, `migration 014_c.sql: must record its version with "INSERT INTO schema_migrations (version) VALUES (14) ON CONFLICT (version) DO NOTHING;"`)
+	list, err = parse(fstest.MapFS{"014_c.sql": {Data: []byte("ALTER TABLE tasks ADD COLUMN c TEXT;\n" + RecordStatement(14))}})
+	assert.NoError(t, err)
+	assert.Equal(t, 14, Latest(list))
+
+	assert.Equal(t, 0, Latest(nil))
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Attachment repräsentiert die Metadaten eines Dateianhangs an einem Task.
+// Der Inhalt selbst liegt im Blob-Storage und wird über GET /attachments/:id/content geladen.
+type Attachment struct {
+	ID          int       `json:"id"`           // Eindeutige ID des Anhangs
+	TaskID      int       `json:"task_id"`      // ID des Tasks
+	Filename    string    `json:"filename"`     // Ursprünglicher Dateiname, max 255 Zeichen
+	ContentType string    `json:"content_type"` // Anhand des Inhalts erkannter MIME-Typ
+	Size        int64     `json:"size"`         // Größe in Bytes
+	Checksum    string    `json:"checksum"`     // SHA-256 des Inhalts (hex)
+	StorageKey  string    `json:"-"`            // Key im Blob-Storage
+	UploaderID  *int      `json:"uploader_id"`  // ID des hochladenden Benutzers
+	CreatedAt   time.Time `json:"created_at"`   // Zeitpunkt des Uploads
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Comment repräsentiert einen Kommentar zu einem Task.
+// Body enthält das Markdown wie eingegeben, BodyHTML die gerenderte und bereinigte Fassung.
+type Comment struct {
+	ID        int        `json:"id"`         // Eindeutige ID des Kommentars
+	TaskID    int        `json:"task_id"`    // ID des kommentierten Tasks
+	AuthorID  *int       `json:"author_id"`  // ID des Autors, nil wenn der Benutzer gelöscht wurde
+	Body      string     `json:"body"`       // Markdown-Text, max 10000 Zeichen
+	BodyHTML  string     `json:"body_html"`  // Gerendertes, bereinigtes HTML inkl. Links für Erwähnungen
+	Mentions  []Mention  `json:"mentions"`   // Erwähnte Benutzer
+	CreatedAt time.Time  `json:"created_at"` // Erstellungszeitpunkt
+	UpdatedAt time.Time  `json:"updated_at"` // Letzter Änderungszeitpunkt
+	EditedAt  *time.Time `json:"edited_at"`  // Zeitpunkt der letzten Bearbeitung, nil wenn nie bearbeitet
+}
+
+// Mention beschreibt die Erwähnung eines Benutzers ("@erika@example.com") in einem Kommentar.
+type Mention struct {
+	UserID int    `json:"user_id"` // ID des erwähnten Benutzers
+	Email  string `json:"email"`   // E-Mail-Adresse, über die erwähnt wurde
+}
+
+// CommentRequest ist der Request-Body für POST /tasks/:id/comments und PATCH /comments/:id.
+type CommentRequest struct {
+	Body string `json:"body"` // Pflichtfeld, Markdown, max 10000 Zeichen
+}
+
+// CommentRevision ist eine frühere Fassung eines bearbeiteten Kommentars.
+type CommentRevision struct {
+	ID        int       `json:"id"`         // Eindeutige ID der Revision
+	CommentID int       `json:"comment_id"` // ID des Kommentars
+	Body      string    `json:"body"`       // Markdown-Text vor der Bearbeitung
+	EditedBy  *int      `json:"edited_by"`  // ID des bearbeitenden Benutzers
+	EditedAt  time.Time `json:"edited_at"`  // Zeitpunkt der Bearbeitung
+}
+
+// Aktionen in der Audit-Historie eines Tasks.
+const (
+	HistoryCommentAdded   = "comment_added"
+	HistoryCommentEdited  = "comment_edited"
+	HistoryCommentDeleted = "comment_deleted"
+)
+
+// HistoryEntry ist ein Eintrag in der Audit-Historie eines Tasks.
+type HistoryEntry struct {
+	ID        int       `json:"id"`         // Eindeutige ID des Eintrags
+	TaskID    int       `json:"task_id"`    // ID des Tasks
+	UserID    *int      `json:"user_id"`    // Auslösender Benutzer, nil bei anonymen Änderungen
+	Action    string    `json:"action"`     // Art der Änderung, z.B. "comment_added"
+	CommentID *int      `json:"comment_id"` // Betroffener Kommentar (auch nach dem Löschen)
+	CreatedAt time.Time `json:"created_at"` // Zeitpunkt der Änderung
+}
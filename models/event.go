@@ -0,0 +1,29 @@
+package models
+
+import "slices"
+
+// TaskEventFilter schränkt den Event-Stream (GET /tasks/events) auf bestimmte Tasks ein.
+// Die Filter werden auf den Task nach der Änderung angewendet (bei task.deleted: vor dem Löschen).
+type TaskEventFilter struct {
+	Statuses []string // Nur Tasks mit einem dieser Status
+	Tags     []string // Nur Tasks mit mindestens einem dieser Tags
+	Assignee *int     // Nur Tasks, die diesem Benutzer zugewiesen sind
+}
+
+// Matches prüft, ob das Ereignis alle gesetzten Filter erfüllt.
+func (f TaskEventFilter) Matches(event *EventPayload) bool {
+	task := event.Task
+	if task == nil {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, task.Status) {
+		return false
+	}
+	if len(f.Tags) > 0 && !slices.ContainsFunc(task.Tags, func(tag string) bool { return slices.Contains(f.Tags, tag) }) {
+		return false
+	}
+	if f.Assignee != nil && !slices.Contains(task.Assignees, *f.Assignee) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// ImportFields sind die Felder eines Tasks, die ein Import setzen kann (die Felder von CreateTaskRequest
+// beim Anlegen).
+var ImportFields = []string{"title", "description", "status", "priority", "parent_id", "start_at", "due_at", "recurrence_rule"}
+
+// Status eines Import-Jobs.
+const (
+	ImportJobRunning   = "running"
+	ImportJobCompleted = "completed"
+	ImportJobFailed    = "failed"
+)
+
+// ImportOptions steuert einen Import.
+type ImportOptions struct {
+	// Mapping ordnet Spalten der Datei Task-Feldern zu (Spalte -> Feld aus ImportFields). Spalten, die nicht
+	// aufgeführt sind, werden übernommen, wenn sie wie ein Feld heißen, das kein Ziel des Mappings ist.
+	Mapping  map[string]string
+	DryRun   bool           // Zeilen nur prüfen, keine Tasks anlegen
+	Location *time.Location // Zeitzone für Datumsangaben ohne Uhrzeit (nil = UTC)
+}
+
+// ImportRowError ist ein Fehler in einer Zeile der Importdatei.
+type ImportRowError struct {
+	Row     int    `json:"row"`             // Zeilennummer in der Datei (bei CSV ist Zeile 1 die Kopfzeile)
+	Field   string `json:"field,omitempty"` // Betroffenes Task-Feld; leer bei Fehlern der ganzen Zeile
+	Message string `json:"message"`         // Beschreibung des Fehlers
+}
+
+// ImportSummary zählt die verarbeiteten Zeilen eines Imports.
+type ImportSummary struct {
+	DryRun  bool `json:"dry_run"` // Zeilen wurden nur geprüft
+	Rows    int  `json:"rows"`    // Verarbeitete Datenzeilen
+	Valid   int  `json:"valid"`   // Zeilen ohne Fehler
+	Created int  `json:"created"` // Angelegte Tasks (0 bei dry_run)
+	Failed  int  `json:"failed"`  // Zeilen mit mindestens einem Fehler
+}
+
+// ImportResult ist das Ergebnis eines synchronen Imports (POST /tasks/import).
+type ImportResult struct {
+	ImportSummary
+	Errors []ImportRowError `json:"errors"` // Alle Fehler, sortiert nach Zeile
+}
+
+// ImportJob ist ein asynchroner Import (POST /tasks/import?async=true). Die Fehler werden nicht mit dem Job
+// ausgeliefert, sondern als Fehlerbericht über GET /tasks/import/:id/errors.
+type ImportJob struct {
+	ID        string `json:"id"`         // Zufällige ID des Jobs
+	Status    string `json:"status"`     // running, completed oder failed
+	TotalRows int    `json:"total_rows"` // Anzahl der Datenzeilen in der Datei
+	Progress  int    `json:"progress"`   // Fortschritt in Prozent
+	ImportSummary
+	Error      string     `json:"error,omitempty"` // Grund, wenn der Job abgebrochen ist (Status failed)
+	CreatedAt  time.Time  `json:"created_at"`      // Start des Jobs
+	UpdatedAt  time.Time  `json:"-"`               // Letzte Speicherung des Fortschritts
+	FinishedAt *time.Time `json:"finished_at"`     // Ende des Jobs; nil, solange er läuft
+}
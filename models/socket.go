@@ -0,0 +1,44 @@
+package models
+
+// Nachrichtentypen, die ein Client über den WebSocket (/ws) sendet.
+const (
+	SocketSubscribe   = "subscribe"   // Board abonnieren
+	SocketUnsubscribe = "unsubscribe" // Board-Abonnement beenden
+	SocketCreate      = "create"      // Task erstellen
+	SocketUpdate      = "update"      // Task aktualisieren
+	SocketMove        = "move"        // Status eines Tasks ändern (Karte in eine andere Spalte ziehen)
+)
+
+// Nachrichtentypen, die der Server über den WebSocket sendet.
+const (
+	SocketAck          = "ack"          // Bestätigung einer Client-Nachricht
+	SocketError        = "error"        // Fehler zu einer Client-Nachricht
+	SocketEvent        = "event"        // Task-Änderung auf einem abonnierten Board
+	SocketReset        = "reset"        // Verpasste Ereignisse sind nicht mehr verfügbar, das Board muss neu geladen werden
+	SocketUnsubscribed = "unsubscribed" // Abonnement wurde vom Server beendet (z.B. Client zu langsam)
+)
+
+// SocketMessage ist eine Nachricht vom Client an den WebSocket-Endpoint.
+// Welche Felder ausgewertet werden, hängt von Type ab.
+type SocketMessage struct {
+	Type string `json:"type"`         // Pflichtfeld, einer der Socket*-Nachrichtentypen für Clients
+	ID   string `json:"id,omitempty"` // Korrelations-ID, wird in ack/error unverändert zurückgegeben
+
+	Board       string       `json:"board,omitempty"`         // subscribe/unsubscribe: vom Client gewählter Name des Boards
+	Filter      *BoardFilter `json:"filter,omitempty"`        // subscribe: Tasks des Boards (ohne Filter: alle Tasks)
+	LastEventID string       `json:"last_event_id,omitempty"` // subscribe: verpasste Ereignisse ab dieser Ereignis-ID nachliefern
+
+	TaskID int                `json:"task_id,omitempty"` // update/move: ID des Tasks
+	Task   *CreateTaskRequest `json:"task,omitempty"`    // create/update: Task-Daten wie bei POST bzw. PUT /tasks
+
+	Status           string `json:"status,omitempty"`            // move: neuer Status
+	Reopen           bool   `json:"reopen,omitempty"`            // move: bestätigt Übergänge, die ein Wiedereröffnen erfordern
+	OverrideBlockers bool   `json:"override_blockers,omitempty"` // move: erlaubt Statuswechsel trotz unerledigter Abhängigkeiten
+}
+
+// BoardFilter beschreibt die Tasks eines Boards, analog zu den Query-Parametern von GET /tasks/events.
+type BoardFilter struct {
+	Status   []string `json:"status"`   // Nur Tasks mit einem dieser Status
+	Tags     []string `json:"tag"`      // Nur Tasks mit mindestens einem dieser Tags
+	Assignee string   `json:"assignee"` // Benutzer-ID oder "me"
+}
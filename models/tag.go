@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Tag repräsentiert ein Label, das beliebig vielen Tasks zugeordnet werden kann.
+type Tag struct {
+	ID        int       `json:"id"`         // Eindeutige ID des Tags
+	Name      string    `json:"name"`       // Eindeutiger Name, max 50 Zeichen
+	CreatedAt time.Time `json:"created_at"` // Erstellungszeitpunkt
+}
+
+// TagRequest ist der Request-Body zum Erstellen und Umbenennen eines Tags.
+type TagRequest struct {
+	Name string `json:"name"` // Pflichtfeld, max 50 Zeichen
+}
+
+// MergeTagsRequest ist der Request-Body für POST /tags/:id/merge.
+// Alle Zuordnungen des Tags :id werden auf IntoID übertragen, danach wird :id gelöscht.
+type MergeTagsRequest struct {
+	IntoID int `json:"into_id"` // ID des Ziel-Tags
+}
+
+// AttachTagRequest ist der Request-Body für POST /tasks/:id/tags.
+// Es kann entweder die ID eines bestehenden Tags oder ein Name angegeben werden;
+// ein Tag mit unbekanntem Namen wird dabei angelegt.
+type AttachTagRequest struct {
+	TagID int    `json:"tag_id"` // ID eines bestehenden Tags
+	Name  string `json:"name"`   // Alternativ: Name des Tags
+}
@@ -1,25 +1,121 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Task repräsentiert eine Aufgabe in der API.
 // Wird sowohl in Responses als auch intern verwendet.
 type Task struct {
-	ID          int       `json:"id"`          // Eindeutige ID der Task (automatisch vom System vergeben)
-	Title       string    `json:"title"`       // Pflichtfeld, max 200 Zeichen
-	Description string    `json:"description"` // Optional, max 1000 Zeichen
-	Status      string    `json:"status"`      // Status der Task; erlaubt: "todo", "in_progress", "done"
-	Priority    string    `json:"priority"`    // Priorität der Task; erlaubt: "low", "medium", "high"
-	CreatedAt   time.Time `json:"created_at"`  // Erstellungszeitpunkt
-	UpdatedAt   time.Time `json:"updated_at"`  // Letzter Änderungszeitpunkt
+	ID             int        `json:"id"`                        // Eindeutige ID der Task (automatisch vom System vergeben)
+	Title          string     `json:"title"`                     // Pflichtfeld, max 200 Zeichen
+	Description    string     `json:"description"`               // Optional, max 1000 Zeichen
+	Status         string     `json:"status"`                    // Status der Task; erlaubt sind die Status des Workflows (Default: "todo", "in progress", "done")
+	Priority       string     `json:"priority"`                  // Priorität der Task; erlaubt: "low", "medium", "high"
+	ParentID       *int       `json:"parent_id"`                 // ID des übergeordneten Tasks, nil bei Top-Level-Tasks
+	Progress       *int       `json:"progress,omitempty"`        // Anteil erledigter Subtasks in Prozent, nil ohne Subtasks
+	StartAt        *time.Time `json:"start_at"`                  // Geplanter Beginn, optional
+	DueAt          *time.Time `json:"due_at"`                    // Fälligkeit, optional
+	IsOverdue      bool       `json:"is_overdue"`                // Berechnet: DueAt liegt in der Vergangenheit und der Task ist nicht erledigt
+	RecurrenceRule string     `json:"recurrence_rule,omitempty"` // Wiederholungsregel nach RFC 5545 inkl. DTSTART, leer bei einmaligen Tasks
+	SeriesID       *int       `json:"series_id"`                 // Verknüpft alle Vorkommen einer Serie (ID des ersten Vorkommens)
+	Tags           []string   `json:"tags"`                      // Namen aller zugeordneten Tags, alphabetisch sortiert
+	Assignees      []int      `json:"assignees"`                 // IDs der verantwortlichen Benutzer
+	Watchers       []int      `json:"watchers"`                  // IDs der Benutzer, die den Task beobachten
+	CreatedAt      time.Time  `json:"created_at"`                // Erstellungszeitpunkt
+	UpdatedAt      time.Time  `json:"updated_at"`                // Letzter Änderungszeitpunkt
+
+	Relations *TaskRelations `json:"-"` // Per include= geladene Beziehungen, nil ohne include
+}
+
+// TaskRelations enthält die Beziehungen eines Tasks, die GET /tasks per include= einbettet.
+// Nicht angeforderte Beziehungen bleiben leer.
+type TaskRelations struct {
+	Subtasks      []*Task // include=subtasks: direkte Subtasks mit denselben Feldern
+	Tags          []*Tag  // include=tags: Tags als Objekte statt Namen
+	Assignees     []*User // include=assignees: Verantwortliche als Benutzer statt IDs
+	CommentsCount int     // include=comments_count: Anzahl der Kommentare
+}
+
+// TaskFields sind die JSON-Namen aller Felder eines Tasks, die per fields= ausgewählt werden können.
+var TaskFields = []string{"id", "title", "description", "status", "priority", "parent_id", "progress",
+	"start_at", "due_at", "is_overdue", "recurrence_rule", "series_id", "tags", "assignees", "watchers",
+	"created_at", "updated_at"}
+
+// TaskIncludes sind die Beziehungen, die per include= eingebettet werden können.
+var TaskIncludes = []string{"subtasks", "tags", "assignees", "comments_count"}
+
+// TaskProjection legt fest, welche Felder eines Tasks geladen und welche Beziehungen eingebettet werden.
+// Das Repository lädt nur die ausgewählten Spalten; die ID wird immer geladen.
+type TaskProjection struct {
+	Fields  []string // JSON-Namen aus TaskFields; leer = alle Felder
+	Include []string // Beziehungen aus TaskIncludes
+}
+
+// Includes gibt zurück, ob die Beziehung name angefordert ist.
+func (p TaskProjection) Includes(name string) bool {
+	return slices.Contains(p.Include, name)
+}
+
+// TaskPage ist ein Ausschnitt einer Task-Liste (limit= und offset= von GET /tasks).
+type TaskPage struct {
+	Limit  int // Höchstens so viele Tasks; 0 = alle
+	Offset int // So viele Tasks am Anfang überspringen
+}
+
+// Apply gibt den Ausschnitt von tasks zurück (für bereits geladene Listen, z.B. in Mocks).
+func (p TaskPage) Apply(tasks []*Task) []*Task {
+	tasks = tasks[min(p.Offset, len(tasks)):]
+	if p.Limit > 0 {
+		tasks = tasks[:min(p.Limit, len(tasks))]
+	}
+	return tasks
 }
 
 // CreateTaskRequest repräsentiert die Struktur, die beim Erstellen oder Aktualisieren
 // einer Task vom Client an die API geschickt wird.
-// Pflichtfeld: Title, optional: Description, Status, Priority.
+// Pflichtfeld: Title, optional: Description, Status, Priority, ParentID.
 type CreateTaskRequest struct {
-	Title       string `json:"title"`       // Pflichtfeld, max 200 Zeichen
-	Description string `json:"description"` // Optional, max 1000 Zeichen
-	Status      string `json:"status"`      // Optional, erlaubt: "todo", "in_progress", "done"
-	Priority    string `json:"priority"`    // Optional, erlaubt: "low", "medium", "high"
+	Title       string `json:"title" validate:"max=200"`                  // Pflichtfeld beim Erstellen, max 200 Zeichen
+	Description string `json:"description" validate:"max=1000"`           // Optional, max 1000 Zeichen
+	Status      string `json:"status"`                                    // Optional, erlaubt sind die Status des Workflows (Default: "todo", "in progress", "done")
+	Priority    string `json:"priority" validate:"oneof=low medium high"` // Optional, erlaubt: "low", "medium", "high"
+	ParentID    *int   `json:"parent_id" validate:"min=0"`                // Optional, ID des Parent-Tasks; 0 entfernt beim Update die Zuordnung
+
+	StartAt *time.Time `json:"start_at"` // Optional, RFC 3339 inkl. Zeitzone, z.B. "2025-03-01T09:00:00+01:00"
+	DueAt   *time.Time `json:"due_at"`   // Optional, RFC 3339 inkl. Zeitzone; muss nach StartAt liegen
+
+	RecurrenceRule *string `json:"recurrence_rule"`                    // Optional, RRULE nach RFC 5545 (z.B. "FREQ=WEEKLY;BYDAY=MO"); "" entfernt die Wiederholung
+	Scope          string  `json:"scope" validate:"oneof=this future"` // Nur Update bei Serien: "this" (Default) oder "future" für alle folgenden Vorkommen
+
+	OverrideBlockers bool `json:"override_blockers"` // Nur Update: erlaubt Statuswechsel trotz unerledigter Abhängigkeiten
+	Reopen           bool `json:"reopen"`            // Nur Update: bestätigt Übergänge, die ein Wiedereröffnen erfordern (z.B. done -> todo)
+}
+
+// AddDependencyRequest ist der Request-Body für POST /tasks/:id/dependencies.
+type AddDependencyRequest struct {
+	DependsOnID int `json:"depends_on_id"` // ID des Tasks, der vorher erledigt sein muss
+}
+
+// TaskNode repräsentiert einen Task innerhalb eines Task-Baums.
+// Wird vom Tree-Endpoint verwendet und enthält die Subtasks rekursiv.
+type TaskNode struct {
+	*Task
+	Subtasks []*TaskNode `json:"subtasks"` // Direkte Subtasks des Tasks
+}
+
+// TaskFilter beschreibt die Filter für GET /tasks.
+// Nicht gesetzte Felder (nil) schränken das Ergebnis nicht ein.
+type TaskFilter struct {
+	Overdue   *bool      // Nur überfällige (true) bzw. nicht überfällige (false) Tasks
+	DueBefore *time.Time // Nur Tasks mit Fälligkeit vor diesem Zeitpunkt
+	DueAfter  *time.Time // Nur Tasks mit Fälligkeit nach diesem Zeitpunkt
+	TagsAny   []string   // Nur Tasks mit mindestens einem dieser Tags
+	TagsAll   []string   // Nur Tasks mit allen diesen Tags
+	TagsNone  []string   // Nur Tasks ohne jeden dieser Tags
+	Assignee  *int       // Nur Tasks, die diesem Benutzer zugewiesen sind
+	Watcher   *int       // Nur Tasks, die dieser Benutzer beobachtet
+
+	HasDueDate bool // Nur Tasks mit Fälligkeit (kein Query-Parameter, setzt z.B. der Kalender-Feed)
 }
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// User repräsentiert einen Benutzer der API.
+// Benutzer gehören genau einem Mandanten (Tenant) an; Tasks können nur Benutzern
+// desselben Mandanten zugewiesen werden.
+type User struct {
+	ID        int       `json:"id"`         // Eindeutige ID des Benutzers
+	Name      string    `json:"name"`       // Anzeigename, max 100 Zeichen
+	Email     string    `json:"email"`      // Eindeutige E-Mail-Adresse
+	Tenant    string    `json:"tenant"`     // Mandant, dem der Benutzer angehört
+	CreatedAt time.Time `json:"created_at"` // Erstellungszeitpunkt
+}
+
+// CreateUserRequest ist der Request-Body für POST /users.
+type CreateUserRequest struct {
+	Name   string `json:"name"`   // Pflichtfeld, max 100 Zeichen
+	Email  string `json:"email"`  // Pflichtfeld, eindeutig
+	Tenant string `json:"tenant"` // Optional, nur der Mandant des anfragenden Benutzers (ohne Anmeldung: "default")
+}
+
+// CreatedUser ist die Antwort auf POST /users.
+// Der API-Key wird nur bei der Erstellung im Klartext zurückgegeben.
+type CreatedUser struct {
+	*User
+	APIKey string `json:"api_key"` // API-Key für den Header "Authorization: Bearer <key>"
+}
+
+// UserRequest ist der Request-Body für POST /tasks/:id/assignees und POST /tasks/:id/watchers.
+type UserRequest struct {
+	UserID int `json:"user_id"` // ID des Benutzers; bei Watchern optional (Default: anfragender Benutzer)
+}
+
+// CalendarToken ist die Antwort auf POST /users/me/calendar-token.
+// Das Token wird nur bei der Erstellung im Klartext zurückgegeben.
+type CalendarToken struct {
+	Token string `json:"token"` // Token für GET /calendar.ics?token=<token>
+	URL   string `json:"url"`   // Pfad des Feeds inklusive Token, z.B. zum Abonnieren in einer Kalender-App
+}
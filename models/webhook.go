@@ -0,0 +1,102 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Ereignistypen im Lebenszyklus eines Tasks, die an Webhooks ausgeliefert werden.
+const (
+	EventTaskCreated       = "task.created"
+	EventTaskUpdated       = "task.updated"
+	EventTaskStatusChanged = "task.status_changed"
+	EventTaskDeleted       = "task.deleted"
+	EventTaskReminder      = "task.reminder" // Ausgelöst vom ReminderScheduler, wenn ein Task bald fällig ist
+)
+
+// WebhookEvents enthält alle Ereignistypen, die ein Webhook abonnieren kann.
+var WebhookEvents = []string{EventTaskCreated, EventTaskUpdated, EventTaskStatusChanged, EventTaskDeleted, EventTaskReminder}
+
+// Status einer Webhook-Zustellung.
+const (
+	// DeliveryPending wartet auf den (nächsten) Zustellversuch.
+	DeliveryPending = "pending"
+	// DeliverySucceeded wurde vom Empfänger mit 2xx bestätigt.
+	DeliverySucceeded = "succeeded"
+	// DeliveryDead ist nach der maximalen Anzahl an Versuchen gescheitert (Dead-Letter).
+	DeliveryDead = "dead"
+)
+
+// TaskEvent beschreibt ein Ereignis, das der TaskService zusammen mit einer Task-Änderung
+// an das Repository übergibt. Das Repository schreibt es in derselben Transaktion in die Outbox.
+type TaskEvent struct {
+	Type           string // Ereignistyp, z.B. EventTaskCreated
+	PreviousStatus string // Vorheriger Status, nur bei EventTaskStatusChanged
+}
+
+// EventPayload ist der Inhalt eines Outbox-Eintrags und der Body einer Webhook-Zustellung.
+type EventPayload struct {
+	ID             int64     `json:"id"`                        // ID des Outbox-Eintrags, eindeutig je Ereignis
+	Event          string    `json:"event"`                     // Ereignistyp
+	OccurredAt     time.Time `json:"occurred_at"`               // Zeitpunkt der Änderung
+	Task           *Task     `json:"task"`                      // Task nach der Änderung (bei task.deleted: vor dem Löschen)
+	PreviousStatus string    `json:"previous_status,omitempty"` // Vorheriger Status bei task.status_changed
+}
+
+// Webhook ist ein Abonnement, an dessen URL Task-Ereignisse per HTTP POST gesendet werden.
+type Webhook struct {
+	ID        int       `json:"id"`         // Eindeutige ID des Webhooks
+	URL       string    `json:"url"`        // Ziel-URL (http oder https)
+	Secret    string    `json:"-"`          // Schlüssel für die HMAC-SHA256-Signatur, wird nur beim Anlegen ausgegeben
+	Events    []string  `json:"events"`     // Abonnierte Ereignistypen, leer bedeutet alle
+	Active    bool      `json:"active"`     // Inaktive Webhooks erhalten keine Zustellungen
+	CreatedAt time.Time `json:"created_at"` // Erstellungszeitpunkt
+	UpdatedAt time.Time `json:"updated_at"` // Letzter Änderungszeitpunkt
+}
+
+// CreatedWebhook ist die Antwort auf POST /webhooks und enthält einmalig das Secret.
+type CreatedWebhook struct {
+	*Webhook
+	Secret string `json:"secret"` // Schlüssel zur Prüfung der Signatur beim Empfänger
+}
+
+// WebhookRequest ist der Request-Body für POST /webhooks und PUT /webhooks/:id.
+type WebhookRequest struct {
+	URL    string   `json:"url"`    // Pflicht beim Anlegen
+	Secret string   `json:"secret"` // Optional, wird sonst zufällig erzeugt (mind. 16 Zeichen)
+	Events []string `json:"events"` // Optional, leer bedeutet alle Ereignisse
+	Active *bool    `json:"active"` // Optional, Default beim Anlegen: true
+}
+
+// WebhookDelivery ist die Zustellung eines Ereignisses an einen Webhook.
+type WebhookDelivery struct {
+	ID             int               `json:"id"`               // Eindeutige ID der Zustellung
+	WebhookID      int               `json:"webhook_id"`       // ID des Webhooks
+	EventID        int64             `json:"event_id"`         // ID des Outbox-Eintrags
+	Event          string            `json:"event"`            // Ereignistyp
+	Payload        json.RawMessage   `json:"payload"`          // Gesendeter Body
+	Status         string            `json:"status"`           // pending, succeeded oder dead
+	Attempts       int               `json:"attempts"`         // Anzahl bisheriger Zustellversuche
+	NextAttemptAt  *time.Time        `json:"next_attempt_at"`  // Nächster Versuch, nil wenn abgeschlossen
+	LastStatusCode *int              `json:"last_status_code"` // HTTP-Status des letzten Versuchs
+	LastError      string            `json:"last_error"`       // Fehlermeldung des letzten Versuchs
+	CreatedAt      time.Time         `json:"created_at"`       // Erstellungszeitpunkt
+	UpdatedAt      time.Time         `json:"updated_at"`       // Letzter Änderungszeitpunkt
+	Log            []DeliveryAttempt `json:"log,omitempty"`    // Einzelne Zustellversuche
+}
+
+// DeliveryAttempt protokolliert einen einzelnen Zustellversuch.
+type DeliveryAttempt struct {
+	Attempt    int       `json:"attempt"`     // Laufende Nummer des Versuchs
+	StatusCode *int      `json:"status_code"` // HTTP-Status der Antwort, nil bei Verbindungsfehlern
+	Error      string    `json:"error"`       // Fehlermeldung, leer bei Erfolg
+	DurationMs int64     `json:"duration_ms"` // Dauer des Requests in Millisekunden
+	CreatedAt  time.Time `json:"created_at"`  // Zeitpunkt des Versuchs
+}
+
+// DeliveryJob ist eine fällige Zustellung samt Ziel-URL und Secret des Webhooks.
+type DeliveryJob struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
@@ -0,0 +1,99 @@
+package models
+
+// WorkflowStatus beschreibt einen Status, den ein Task annehmen kann.
+type WorkflowStatus struct {
+	Name    string `json:"name"`    // Technischer Name, wird im Feld "status" eines Tasks gespeichert
+	Label   string `json:"label"`   // Anzeigename für Clients
+	Initial bool   `json:"initial"` // Startstatus neuer Tasks; Wechsel in andere Status setzt erledigte Abhängigkeiten voraus
+	Final   bool   `json:"final"`   // Gilt als erledigt (z.B. für Fortschritt und Abhängigkeiten)
+}
+
+// WorkflowTransition beschreibt einen erlaubten Statuswechsel.
+type WorkflowTransition struct {
+	From           string `json:"from"`            // Ausgangsstatus
+	To             string `json:"to"`              // Zielstatus
+	RequiresReopen bool   `json:"requires_reopen"` // Wechsel muss explizit mit "reopen": true angefordert werden
+}
+
+// Workflow fasst alle Status und die erlaubten Übergänge zwischen ihnen zusammen.
+type Workflow struct {
+	Statuses    []WorkflowStatus     `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// DefaultWorkflow liefert den Standard-Workflow (todo -> in progress -> done),
+// der verwendet wird, wenn keine Workflow-Definition geladen wurde.
+// Entspricht den Einträgen aus migrations/004_workflow.sql.
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []WorkflowStatus{
+			{Name: "todo", Label: "To Do", Initial: true},
+			{Name: "in progress", Label: "In Progress"},
+			{Name: "done", Label: "Done", Final: true},
+		},
+		Transitions: []WorkflowTransition{
+			{From: "todo", To: "in progress"},
+			{From: "todo", To: "done"},
+			{From: "in progress", To: "todo"},
+			{From: "in progress", To: "done"},
+			{From: "done", To: "todo", RequiresReopen: true},
+			{From: "done", To: "in progress", RequiresReopen: true},
+		},
+	}
+}
+
+// Status gibt den Status mit dem übergebenen Namen zurück, oder nil, wenn er nicht existiert.
+func (w *Workflow) Status(name string) *WorkflowStatus {
+	for i := range w.Statuses {
+		if w.Statuses[i].Name == name {
+			return &w.Statuses[i]
+		}
+	}
+	return nil
+}
+
+// StatusNames gibt die Namen aller Status in definierter Reihenfolge zurück.
+func (w *Workflow) StatusNames() []string {
+	names := make([]string, 0, len(w.Statuses))
+	for _, s := range w.Statuses {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+// InitialStatus gibt den Namen des ersten Startstatus zurück.
+func (w *Workflow) InitialStatus() string {
+	for _, s := range w.Statuses {
+		if s.Initial {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+// IsFinal prüft, ob der Status als erledigt gilt.
+func (w *Workflow) IsFinal(name string) bool {
+	s := w.Status(name)
+	return s != nil && s.Final
+}
+
+// Transition gibt den Übergang von from nach to zurück, oder nil, wenn er nicht erlaubt ist.
+func (w *Workflow) Transition(from, to string) *WorkflowTransition {
+	for i := range w.Transitions {
+		if w.Transitions[i].From == from && w.Transitions[i].To == to {
+			return &w.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// NextStatuses gibt alle Status zurück, in die ausgehend von from gewechselt werden darf.
+func (w *Workflow) NextStatuses(from string) []string {
+	next := []string{}
+	for _, t := range w.Transitions {
+		if t.From == from {
+			next = append(next, t.To)
+		}
+	}
+	return next
+}
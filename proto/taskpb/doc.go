@@ -0,0 +1,4 @@
+// Package taskpb enthält den aus proto/task.proto generierten Code der gRPC-API.
+package taskpb
+
+//go:generate protoc -I .. --go_out=../.. --go_opt=module=task-api --go-grpc_out=../.. --go-grpc_opt=module=task-api ../task.proto
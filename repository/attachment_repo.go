@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"database/sql"
+	"task-api/models"
+)
+
+// PostgresAttachmentRepository implementiert die Persistenzschicht für die Metadaten von Dateianhängen.
+type PostgresAttachmentRepository struct {
+	DB *sql.DB
+}
+
+// attachmentSelect ist die gemeinsame SELECT-Klausel für alle Anhang-Abfragen.
+const attachmentSelect = `SELECT id, task_id, filename, content_type, size, checksum, storage_key, uploader_id, created_at
+	  FROM attachments`
+
+// scanAttachment liest eine Zeile im Format von attachmentSelect in einen Anhang ein.
+func scanAttachment(row rowScanner) (*models.Attachment, error) {
+	a := &models.Attachment{}
+	var uploaderID sql.NullInt64
+	err := row.Scan(&a.ID, &a.TaskID, &a.Filename, &a.ContentType, &a.Size, &a.Checksum, &a.StorageKey,
+		&uploaderID, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	a.UploaderID = nullIntPtr(uploaderID)
+	return a, nil
+}
+
+// Create speichert die Metadaten eines neuen Anhangs.
+func (r *PostgresAttachmentRepository) Create(a *models.Attachment) (*models.Attachment, error) {
+	err := r.DB.QueryRow(`INSERT INTO attachments (task_id, filename, content_type, size, checksum, storage_key, uploader_id)
+	                      VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, created_at`,
+		a.TaskID, a.Filename, a.ContentType, a.Size, a.Checksum, a.StorageKey, a.UploaderID).
+		Scan(&a.ID, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// GetByID gibt einen Anhang anhand der ID zurück.
+// Gibt nil zurück, wenn kein Anhang mit der ID existiert oder sein Task gelöscht wurde.
+func (r *PostgresAttachmentRepository) GetByID(id int) (*models.Attachment, error) {
+	a, err := scanAttachment(r.DB.QueryRow(attachmentSelect+` WHERE id = $1 AND task_id IN (SELECT id FROM tasks WHERE deleted_at IS NULL)`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// GetByTask gibt alle Anhänge eines Tasks sortiert nach ID zurück.
+func (r *PostgresAttachmentRepository) GetByTask(taskID int) ([]*models.Attachment, error) {
+	rows, err := r.DB.Query(attachmentSelect+` WHERE task_id = $1 ORDER BY id`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*models.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+// Delete entfernt die Metadaten eines Anhangs.
+func (r *PostgresAttachmentRepository) Delete(id int) error {
+	_, err := r.DB.Exec(`DELETE FROM attachments WHERE id = $1`, id)
+	return err
+}
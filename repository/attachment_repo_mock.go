@@ -0,0 +1,39 @@
+package repository
+
+import "task-api/models"
+
+// MockAttachmentRepository ist ein Mock des AttachmentRepositoryInterface für Tests.
+// Jede Methode wird durch eine Funktion ersetzt, die individuell gesetzt werden kann.
+type MockAttachmentRepository struct {
+	// CreateFunc simuliert das Speichern eines Anhangs.
+	CreateFunc func(attachment *models.Attachment) (*models.Attachment, error)
+
+	// GetByIdFunc simuliert das Abrufen eines Anhangs anhand der ID.
+	GetByIdFunc func(id int) (*models.Attachment, error)
+
+	// GetByTaskFunc simuliert das Abrufen aller Anhänge eines Tasks.
+	GetByTaskFunc func(taskID int) ([]*models.Attachment, error)
+
+	// DeleteFunc simuliert das Löschen eines Anhangs.
+	DeleteFunc func(id int) error
+}
+
+// Create ruft CreateFunc auf und gibt das Ergebnis zurück.
+func (m *MockAttachmentRepository) Create(attachment *models.Attachment) (*models.Attachment, error) {
+	return m.CreateFunc(attachment)
+}
+
+// GetByID ruft GetByIdFunc auf und gibt das Ergebnis zurück.
+func (m *MockAttachmentRepository) GetByID(id int) (*models.Attachment, error) {
+	return m.GetByIdFunc(id)
+}
+
+// GetByTask ruft GetByTaskFunc auf und gibt das Ergebnis zurück.
+func (m *MockAttachmentRepository) GetByTask(taskID int) ([]*models.Attachment, error) {
+	return m.GetByTaskFunc(taskID)
+}
+
+// Delete ruft DeleteFunc auf und gibt das Ergebnis zurück.
+func (m *MockAttachmentRepository) Delete(id int) error {
+	return m.DeleteFunc(id)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import "task-api/models"
+
+// AttachmentRepositoryInterface definiert die Methoden zur Verwaltung der Metadaten von Dateianhängen.
+type AttachmentRepositoryInterface interface {
+	// Create speichert die Metadaten eines neuen Anhangs.
+	Create(attachment *models.Attachment) (*models.Attachment, error)
+
+	// GetByID gibt einen Anhang anhand seiner ID zurück.
+	// Gibt nil, nil zurück, wenn kein Anhang gefunden wird.
+	GetByID(id int) (*models.Attachment, error)
+
+	// GetByTask gibt alle Anhänge eines Tasks sortiert nach ID zurück.
+	GetByTask(taskID int) ([]*models.Attachment, error)
+
+	// Delete entfernt die Metadaten eines Anhangs.
+	Delete(id int) error
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"task-api/models"
+)
+
+// PostgresCommentRepository implementiert die Persistenzschicht für Kommentare,
+// Revisionen, Erwähnungen und die Audit-Historie von Tasks.
+type PostgresCommentRepository struct {
+	DB *sql.DB
+}
+
+// commentSelect ist die gemeinsame SELECT-Klausel für alle Kommentar-Abfragen.
+// Erwähnungen werden als zwei parallele Arrays (IDs und E-Mail-Adressen) geladen.
+const commentSelect = `SELECT c.id, c.task_id, c.author_id, c.body, c.created_at, c.updated_at, c.edited_at,
+	       ARRAY(SELECT m.user_id FROM comment_mentions m WHERE m.comment_id = c.id ORDER BY m.user_id),
+	       ARRAY(SELECT u.email FROM comment_mentions m JOIN users u ON u.id = m.user_id
+	              WHERE m.comment_id = c.id ORDER BY m.user_id)
+	  FROM comments c`
+
+// scanComment liest eine Zeile im Format von commentSelect in einen Kommentar ein.
+func scanComment(row rowScanner) (*models.Comment, error) {
+	c := &models.Comment{}
+	var authorID sql.NullInt64
+	var editedAt sql.NullTime
+	var mentionIDs pq.Int64Array
+	var mentionEmails []string
+	err := row.Scan(&c.ID, &c.TaskID, &authorID, &c.Body, &c.CreatedAt, &c.UpdatedAt, &editedAt,
+		&mentionIDs, pq.Array(&mentionEmails))
+	if err != nil {
+		return nil, err
+	}
+	c.AuthorID = nullIntPtr(authorID)
+	c.EditedAt = nullTimePtr(editedAt)
+	c.Mentions = make([]models.Mention, len(mentionIDs))
+	for i, id := range mentionIDs {
+		c.Mentions[i] = models.Mention{UserID: int(id), Email: mentionEmails[i]}
+	}
+	return c, nil
+}
+
+// Create speichert einen neuen Kommentar. Kommentar, Erwähnungen und Historien-Eintrag
+// werden in einer Transaktion geschrieben.
+func (r *PostgresCommentRepository) Create(comment *models.Comment, mentionIDs []int) (*models.Comment, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`INSERT INTO comments (task_id, author_id, body) VALUES ($1, $2, $3) RETURNING id`,
+		comment.TaskID, comment.AuthorID, comment.Body).Scan(&comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := insertMentions(tx, comment.ID, mentionIDs); err != nil {
+		return nil, err
+	}
+	if err := insertHistory(tx, comment.TaskID, comment.AuthorID, models.HistoryCommentAdded, comment.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(comment.ID)
+}
+
+// insertMentions speichert die Erwähnungen eines Kommentars.
+func insertMentions(tx *sql.Tx, commentID int, userIDs []int) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(`INSERT INTO comment_mentions (comment_id, user_id)
+	                   SELECT $1, unnest($2::int[]) ON CONFLICT DO NOTHING`, commentID, pq.Array(userIDs))
+	return err
+}
+
+// insertHistory schreibt einen Eintrag in die Audit-Historie eines Tasks.
+func insertHistory(tx *sql.Tx, taskID int, userID *int, action string, commentID int) error {
+	_, err := tx.Exec(`INSERT INTO task_history (task_id, user_id, action, comment_id) VALUES ($1, $2, $3, $4)`,
+		taskID, userID, action, commentID)
+	return err
+}
+
+// GetByID gibt einen Kommentar anhand der ID zurück.
+// Gibt nil zurück, wenn kein Kommentar mit der ID existiert oder sein Task gelöscht wurde.
+func (r *PostgresCommentRepository) GetByID(id int) (*models.Comment, error) {
+	comment, err := scanComment(r.DB.QueryRow(commentSelect+` WHERE c.id = $1
+	                                          AND c.task_id IN (SELECT id FROM tasks WHERE deleted_at IS NULL)`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// GetByTask gibt alle Kommentare eines Tasks sortiert nach ID zurück.
+func (r *PostgresCommentRepository) GetByTask(taskID int) ([]*models.Comment, error) {
+	return r.queryComments(commentSelect+` WHERE c.task_id = $1 ORDER BY c.id`, taskID)
+}
+
+// GetByTasks gibt alle Kommentare der angegebenen Tasks in einer Abfrage zurück, sortiert nach ID.
+func (r *PostgresCommentRepository) GetByTasks(taskIDs []int) ([]*models.Comment, error) {
+	return r.queryComments(commentSelect+` WHERE c.task_id = ANY($1) ORDER BY c.id`, pq.Array(taskIDs))
+}
+
+// queryComments führt eine Abfrage aus und liest alle Zeilen als Kommentare ein.
+func (r *PostgresCommentRepository) queryComments(query string, args ...any) ([]*models.Comment, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*models.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+// Update ersetzt den Body eines Kommentars. Die vorherige Fassung wird als Revision gesichert,
+// die Erwähnungen werden neu gesetzt und ein Historien-Eintrag geschrieben – alles in einer Transaktion.
+func (r *PostgresCommentRepository) Update(id int, body string, mentionIDs []int, editorID int) (*models.Comment, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var taskID int
+	if err := tx.QueryRow(`SELECT task_id FROM comments WHERE id = $1 FOR UPDATE`, id).Scan(&taskID); err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`INSERT INTO comment_revisions (comment_id, body, edited_by)
+	                  SELECT id, body, $2 FROM comments WHERE id = $1`, id, editorID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(`UPDATE comments SET body = $1, edited_at = NOW(), updated_at = NOW() WHERE id = $2`, body, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM comment_mentions WHERE comment_id = $1`, id); err != nil {
+		return nil, err
+	}
+	if err := insertMentions(tx, id, mentionIDs); err != nil {
+		return nil, err
+	}
+	if err := insertHistory(tx, taskID, &editorID, models.HistoryCommentEdited, id); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+// Delete entfernt einen Kommentar inklusive Revisionen und Erwähnungen.
+// Der Historien-Eintrag bleibt erhalten.
+func (r *PostgresCommentRepository) Delete(id int, userID int) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var taskID int
+	if err := tx.QueryRow(`DELETE FROM comments WHERE id = $1 RETURNING task_id`, id).Scan(&taskID); err != nil {
+		return err
+	}
+	if err := insertHistory(tx, taskID, &userID, models.HistoryCommentDeleted, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetRevisions gibt alle früheren Fassungen eines Kommentars zurück, älteste zuerst.
+func (r *PostgresCommentRepository) GetRevisions(commentID int) ([]*models.CommentRevision, error) {
+	rows, err := r.DB.Query(`SELECT id, comment_id, body, edited_by, edited_at
+	                         FROM comment_revisions WHERE comment_id = $1 ORDER BY id`, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*models.CommentRevision
+	for rows.Next() {
+		rev := &models.CommentRevision{}
+		var editedBy sql.NullInt64
+		if err := rows.Scan(&rev.ID, &rev.CommentID, &rev.Body, &editedBy, &rev.EditedAt); err != nil {
+			return nil, err
+		}
+		rev.EditedBy = nullIntPtr(editedBy)
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
+
+// GetHistory gibt die Audit-Historie eines Tasks zurück, älteste zuerst.
+func (r *PostgresCommentRepository) GetHistory(taskID int) ([]*models.HistoryEntry, error) {
+	rows, err := r.DB.Query(`SELECT id, task_id, user_id, action, comment_id, created_at
+	                         FROM task_history WHERE task_id = $1 ORDER BY id`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.HistoryEntry
+	for rows.Next() {
+		entry := &models.HistoryEntry{}
+		var userID, commentID sql.NullInt64
+		if err := rows.Scan(&entry.ID, &entry.TaskID, &userID, &entry.Action, &commentID, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entry.UserID = nullIntPtr(userID)
+		entry.CommentID = nullIntPtr(commentID)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,71 @@
+package repository
+
+import "task-api/models"
+
+// MockCommentRepository ist ein Mock des CommentRepositoryInterface für Tests.
+// Jede Methode wird durch eine Funktion ersetzt, die individuell gesetzt werden kann.
+type MockCommentRepository struct {
+	// CreateFunc simuliert das Erstellen eines Kommentars.
+	CreateFunc func(comment *models.Comment, mentionIDs []int) (*models.Comment, error)
+
+	// GetByIdFunc simuliert das Abrufen eines Kommentars anhand der ID.
+	GetByIdFunc func(id int) (*models.Comment, error)
+
+	// GetByTaskFunc simuliert das Abrufen aller Kommentare eines Tasks.
+	GetByTaskFunc func(taskID int) ([]*models.Comment, error)
+
+	// GetByTasksFunc simuliert das Abrufen aller Kommentare mehrerer Tasks.
+	GetByTasksFunc func(taskIDs []int) ([]*models.Comment, error)
+
+	// UpdateFunc simuliert das Bearbeiten eines Kommentars.
+	UpdateFunc func(id int, body string, mentionIDs []int, editorID int) (*models.Comment, error)
+
+	// DeleteFunc simuliert das Löschen eines Kommentars.
+	DeleteFunc func(id int, userID int) error
+
+	// GetRevisionsFunc simuliert das Abrufen der Bearbeitungshistorie.
+	GetRevisionsFunc func(commentID int) ([]*models.CommentRevision, error)
+
+	// GetHistoryFunc simuliert das Abrufen der Audit-Historie eines Tasks.
+	GetHistoryFunc func(taskID int) ([]*models.HistoryEntry, error)
+}
+
+// Create ruft CreateFunc auf und gibt das Ergebnis zurück.
+func (m *MockCommentRepository) Create(comment *models.Comment, mentionIDs []int) (*models.Comment, error) {
+	return m.CreateFunc(comment, mentionIDs)
+}
+
+// GetByID ruft GetByIdFunc auf und gibt das Ergebnis zurück.
+func (m *MockCommentRepository) GetByID(id int) (*models.Comment, error) {
+	return m.GetByIdFunc(id)
+}
+
+// GetByTask ruft GetByTaskFunc auf und gibt das Ergebnis zurück.
+func (m *MockCommentRepository) GetByTask(taskID int) ([]*models.Comment, error) {
+	return m.GetByTaskFunc(taskID)
+}
+
+// GetByTasks ruft GetByTasksFunc auf und gibt das Ergebnis zurück.
+func (m *MockCommentRepository) GetByTasks(taskIDs []int) ([]*models.Comment, error) {
+	return m.GetByTasksFunc(taskIDs)
+}
+
+// Update ruft UpdateFunc auf und gibt das Ergebnis zurück.
+func (m *MockCommentRepository) Update(id int, body string, mentionIDs []int, editorID int) (*models.Comment, error) {
+	return m.UpdateFunc(id, body, mentionIDs, editorID)
+}
+
+// Delete ruft DeleteFunc auf und gibt das Ergebnis zurück.
+func (m *MockCommentRepository) Delete(id int, userID int) error {
+	return m.DeleteFunc(id, userID)
+}
+
+// GetRevisions ruft GetRevisionsFunc auf und gibt das Ergebnis zurück.
+func (m *MockCommentRepository) GetRevisions(commentID int) ([]*models.CommentRevision, error) {
+	return m.GetRevisionsFunc(commentID)
+}
+
+// GetHistory ruft GetHistoryFunc auf und gibt das Ergebnis zurück.
+func (m *MockCommentRepository) GetHistory(taskID int) ([]*models.HistoryEntry, error) {
+	return m.GetHistoryFunc(taskID)
+}
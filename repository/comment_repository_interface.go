@@ -0,0 +1,33 @@
+package repository
+
+import "task-api/models"
+
+// CommentRepositoryInterface definiert die Methoden zur Verwaltung von Kommentaren,
+// ihrer Bearbeitungshistorie und der Audit-Historie von Tasks.
+type CommentRepositoryInterface interface {
+	// Create speichert einen neuen Kommentar samt Erwähnungen und Historien-Eintrag.
+	Create(comment *models.Comment, mentionIDs []int) (*models.Comment, error)
+
+	// GetByID gibt einen Kommentar anhand seiner ID zurück.
+	// Gibt nil, nil zurück, wenn kein Kommentar gefunden wird.
+	GetByID(id int) (*models.Comment, error)
+
+	// GetByTask gibt alle Kommentare eines Tasks in zeitlicher Reihenfolge zurück.
+	GetByTask(taskID int) ([]*models.Comment, error)
+
+	// GetByTasks gibt alle Kommentare der angegebenen Tasks in einer Abfrage zurück.
+	GetByTasks(taskIDs []int) ([]*models.Comment, error)
+
+	// Update ersetzt den Body eines Kommentars, sichert die vorherige Fassung als Revision
+	// und schreibt einen Historien-Eintrag.
+	Update(id int, body string, mentionIDs []int, editorID int) (*models.Comment, error)
+
+	// Delete entfernt einen Kommentar und schreibt einen Historien-Eintrag.
+	Delete(id int, userID int) error
+
+	// GetRevisions gibt alle früheren Fassungen eines Kommentars zurück, älteste zuerst.
+	GetRevisions(commentID int) ([]*models.CommentRevision, error)
+
+	// GetHistory gibt die Audit-Historie eines Tasks zurück, älteste zuerst.
+	GetHistory(taskID int) ([]*models.HistoryEntry, error)
+}
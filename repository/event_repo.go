@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/lib/pq"
+	"log"
+	"strconv"
+	"task-api/models"
+	"time"
+)
+
+// TaskEventsChannel ist der Postgres-Kanal, über den per NOTIFY die IDs neuer Outbox-Einträge
+// verschickt werden. Die Benachrichtigung wird erst mit dem Commit der Task-Änderung zugestellt.
+const TaskEventsChannel = "task_events"
+
+// PostgresEventRepository liest Ereignisse aus der Outbox und lauscht per LISTEN auf neue Ereignisse.
+type PostgresEventRepository struct {
+	DB *sql.DB
+
+	// ConnStr wird für die separate LISTEN-Verbindung benötigt.
+	ConnStr string
+}
+
+// queryEvents führt eine Abfrage auf outbox.payload aus und liest alle Zeilen als Ereignisse ein.
+func (r *PostgresEventRepository) queryEvents(query string, args ...any) ([]*models.EventPayload, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.EventPayload
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, err
+		}
+		event := &models.EventPayload{}
+		if err := json.Unmarshal(payload, event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// GetEvent gibt ein Ereignis anhand der ID zurück oder nil, wenn es nicht existiert.
+func (r *PostgresEventRepository) GetEvent(id int64) (*models.EventPayload, error) {
+	events, err := r.queryEvents(`SELECT payload FROM outbox WHERE id = $1`, id)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	return events[0], nil
+}
+
+// GetEventsAfter gibt höchstens limit Ereignisse mit einer ID größer afterID aufsteigend zurück.
+func (r *PostgresEventRepository) GetEventsAfter(afterID int64, limit int) ([]*models.EventPayload, error) {
+	return r.queryEvents(`SELECT payload FROM outbox WHERE id > $1 ORDER BY id LIMIT $2`, afterID, limit)
+}
+
+// GetLatestEvents gibt die letzten limit Ereignisse aufsteigend sortiert zurück.
+func (r *PostgresEventRepository) GetLatestEvents(limit int) ([]*models.EventPayload, error) {
+	return r.queryEvents(`SELECT payload FROM (SELECT id, payload FROM outbox ORDER BY id DESC LIMIT $1) e
+	                       ORDER BY id`, limit)
+}
+
+// Listen öffnet eine eigene Verbindung mit LISTEN auf TaskEventsChannel und ruft notify für jede
+// Benachrichtigung auf. pq baut abgebrochene Verbindungen selbstständig wieder auf.
+func (r *PostgresEventRepository) Listen(ctx context.Context, notify func(id int64)) error {
+	listener := pq.NewListener(r.ConnStr, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("event listener: %v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(TaskEventsChannel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			// nil signalisiert eine neu aufgebaute Verbindung
+			if n == nil {
+				notify(0)
+				continue
+			}
+			id, err := strconv.ParseInt(n.Extra, 10, 64)
+			if err != nil {
+				log.Printf("event listener: invalid payload %q", n.Extra)
+				continue
+			}
+			notify(id)
+		case <-time.After(90 * time.Second):
+			// Prüft regelmäßig, ob die Verbindung noch besteht
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"task-api/models"
+)
+
+// MockEventRepository ist ein Mock des EventRepositoryInterface für Tests.
+// Jede Methode wird durch eine Funktion ersetzt, die individuell gesetzt werden kann.
+type MockEventRepository struct {
+	// GetEventFunc simuliert das Abrufen eines Ereignisses anhand der ID.
+	GetEventFunc func(id int64) (*models.EventPayload, error)
+
+	// GetEventsAfterFunc simuliert das Abrufen aller Ereignisse nach einer ID.
+	GetEventsAfterFunc func(afterID int64, limit int) ([]*models.EventPayload, error)
+
+	// GetLatestEventsFunc simuliert das Abrufen der letzten Ereignisse.
+	GetLatestEventsFunc func(limit int) ([]*models.EventPayload, error)
+
+	// ListenFunc simuliert das Lauschen auf neue Ereignisse.
+	ListenFunc func(ctx context.Context, notify func(id int64)) error
+}
+
+// GetEvent ruft GetEventFunc auf und gibt das Ergebnis zurück.
+func (m *MockEventRepository) GetEvent(id int64) (*models.EventPayload, error) {
+	return m.GetEventFunc(id)
+}
+
+// GetEventsAfter ruft GetEventsAfterFunc auf und gibt das Ergebnis zurück.
+func (m *MockEventRepository) GetEventsAfter(afterID int64, limit int) ([]*models.EventPayload, error) {
+	return m.GetEventsAfterFunc(afterID, limit)
+}
+
+// GetLatestEvents ruft GetLatestEventsFunc auf und gibt das Ergebnis zurück.
+// Ist GetLatestEventsFunc nicht gesetzt, ist die Outbox leer.
+func (m *MockEventRepository) GetLatestEvents(limit int) ([]*models.EventPayload, error) {
+	if m.GetLatestEventsFunc == nil {
+		return nil, nil
+	}
+	return m.GetLatestEventsFunc(limit)
+}
+
+// Listen ruft ListenFunc auf. Ist ListenFunc nicht gesetzt, blockiert Listen bis ctx beendet wird.
+func (m *MockEventRepository) Listen(ctx context.Context, notify func(id int64)) error {
+	if m.ListenFunc == nil {
+		<-ctx.Done()
+		return nil
+	}
+	return m.ListenFunc(ctx, notify)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"task-api/models"
+)
+
+// EventRepositoryInterface definiert den Zugriff auf das Ereignis-Log (Outbox) und die
+// Benachrichtigung über neue Ereignisse, auch wenn sie von einer anderen API-Instanz stammen.
+type EventRepositoryInterface interface {
+	// GetEvent gibt ein Ereignis anhand seiner ID zurück.
+	// Gibt nil, nil zurück, wenn kein Ereignis gefunden wird.
+	GetEvent(id int64) (*models.EventPayload, error)
+
+	// GetEventsAfter gibt höchstens limit Ereignisse mit einer ID größer afterID aufsteigend zurück.
+	GetEventsAfter(afterID int64, limit int) ([]*models.EventPayload, error)
+
+	// GetLatestEvents gibt die letzten limit Ereignisse aufsteigend sortiert zurück.
+	GetLatestEvents(limit int) ([]*models.EventPayload, error)
+
+	// Listen blockiert, bis ctx beendet wird, und ruft notify für jedes neu geschriebene Ereignis auf.
+	// Nach einem Verbindungsabbruch wird notify mit der ID 0 aufgerufen, da Ereignisse verpasst sein können.
+	Listen(ctx context.Context, notify func(id int64)) error
+}
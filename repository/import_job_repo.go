@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"task-api/models"
+	"time"
+)
+
+// PostgresImportJobRepository implementiert die Persistenzschicht für Import-Jobs und ihre Fehlerberichte.
+type PostgresImportJobRepository struct {
+	DB *sql.DB
+}
+
+// Create speichert einen neuen Job.
+func (r *PostgresImportJobRepository) Create(job *models.ImportJob) error {
+	_, err := r.DB.Exec(`INSERT INTO import_jobs (id, status, dry_run, rows_total, created_at, updated_at)
+	                     VALUES ($1, $2, $3, $4, $5, $5)`,
+		job.ID, job.Status, job.DryRun, job.TotalRows, job.CreatedAt)
+	return err
+}
+
+// Update speichert den Stand des Jobs und hängt rowErrors an den Fehlerbericht an.
+func (r *PostgresImportJobRepository) Update(job *models.ImportJob, rowErrors []models.ImportRowError) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`UPDATE import_jobs
+	                     SET status = $1, rows_processed = $2, rows_valid = $3, rows_created = $4, rows_failed = $5,
+	                         error = $6, finished_at = $7, updated_at = NOW()
+	                   WHERE id = $8`,
+		job.Status, job.Rows, job.Valid, job.Created, job.Failed, job.Error, job.FinishedAt, job.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(rowErrors) > 0 {
+		lines := make(pq.Int64Array, len(rowErrors))
+		fields := make(pq.StringArray, len(rowErrors))
+		messages := make(pq.StringArray, len(rowErrors))
+		for i, e := range rowErrors {
+			lines[i], fields[i], messages[i] = int64(e.Row), e.Field, e.Message
+		}
+		_, err = tx.Exec(`INSERT INTO import_job_errors (job_id, line, field, message)
+		                  SELECT $1, * FROM unnest($2::INTEGER[], $3::TEXT[], $4::TEXT[])`,
+			job.ID, lines, fields, messages)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetByID gibt einen Job anhand seiner ID zurück.
+// Gibt nil zurück, wenn kein Job mit der ID existiert.
+func (r *PostgresImportJobRepository) GetByID(id string) (*models.ImportJob, error) {
+	job := &models.ImportJob{}
+	err := r.DB.QueryRow(`SELECT id, status, dry_run, rows_total, rows_processed, rows_valid, rows_created,
+	                             rows_failed, error, created_at, updated_at, finished_at
+	                        FROM import_jobs WHERE id = $1`, id).
+		Scan(&job.ID, &job.Status, &job.DryRun, &job.TotalRows, &job.Rows, &job.Valid, &job.Created,
+			&job.Failed, &job.Error, &job.CreatedAt, &job.UpdatedAt, &job.FinishedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetErrors gibt den Fehlerbericht eines Jobs sortiert nach Zeile zurück.
+func (r *PostgresImportJobRepository) GetErrors(id string) ([]models.ImportRowError, error) {
+	rows, err := r.DB.Query(`SELECT line, field, message FROM import_job_errors
+	                          WHERE job_id = $1 ORDER BY line, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rowErrors := []models.ImportRowError{}
+	for rows.Next() {
+		var e models.ImportRowError
+		if err := rows.Scan(&e.Row, &e.Field, &e.Message); err != nil {
+			return nil, err
+		}
+		rowErrors = append(rowErrors, e)
+	}
+	return rowErrors, rows.Err()
+}
+
+// DeleteExpired löscht Jobs, die vor before beendet oder (falls nie beendet) zuletzt aktualisiert wurden.
+// Die Fehlerberichte entfallen per ON DELETE CASCADE.
+func (r *PostgresImportJobRepository) DeleteExpired(before time.Time) (int, error) {
+	res, err := r.DB.Exec(`DELETE FROM import_jobs WHERE COALESCE(finished_at, updated_at) < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
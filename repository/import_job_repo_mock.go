@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"task-api/models"
+	"time"
+)
+
+// MockImportJobRepository ist ein Mock des ImportJobRepositoryInterface für Tests.
+// Jede Methode wird durch eine Funktion ersetzt, die individuell gesetzt werden kann.
+type MockImportJobRepository struct {
+	// CreateFunc simuliert das Speichern eines neuen Jobs.
+	CreateFunc func(job *models.ImportJob) error
+
+	// UpdateFunc simuliert das Speichern des Stands eines Jobs samt neuer Zeilenfehler.
+	UpdateFunc func(job *models.ImportJob, rowErrors []models.ImportRowError) error
+
+	// GetByIdFunc simuliert das Abrufen eines Jobs anhand der ID.
+	GetByIdFunc func(id string) (*models.ImportJob, error)
+
+	// GetErrorsFunc simuliert das Abrufen des Fehlerberichts eines Jobs.
+	GetErrorsFunc func(id string) ([]models.ImportRowError, error)
+
+	// DeleteExpiredFunc simuliert das Löschen abgelaufener Jobs.
+	DeleteExpiredFunc func(before time.Time) (int, error)
+}
+
+// Create ruft CreateFunc auf und gibt das Ergebnis zurück.
+func (m *MockImportJobRepository) Create(job *models.ImportJob) error {
+	return m.CreateFunc(job)
+}
+
+// Update ruft UpdateFunc auf und gibt das Ergebnis zurück.
+func (m *MockImportJobRepository) Update(job *models.ImportJob, rowErrors []models.ImportRowError) error {
+	return m.UpdateFunc(job, rowErrors)
+}
+
+// GetByID ruft GetByIdFunc auf und gibt das Ergebnis zurück.
+func (m *MockImportJobRepository) GetByID(id string) (*models.ImportJob, error) {
+	return m.GetByIdFunc(id)
+}
+
+// GetErrors ruft GetErrorsFunc auf und gibt das Ergebnis zurück.
+func (m *MockImportJobRepository) GetErrors(id string) ([]models.ImportRowError, error) {
+	return m.GetErrorsFunc(id)
+}
+
+// DeleteExpired ruft DeleteExpiredFunc auf und gibt das Ergebnis zurück.
+func (m *MockImportJobRepository) DeleteExpired(before time.Time) (int, error) {
+	return m.DeleteExpiredFunc(before)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"task-api/models"
+	"time"
+)
+
+// ImportJobRepositoryInterface definiert die Methoden zur Speicherung asynchroner Import-Jobs und ihrer
+// Fehlerberichte, damit jede API-Instanz den Stand eines Jobs abrufen kann.
+type ImportJobRepositoryInterface interface {
+	// Create speichert einen neuen Job mit der ID job.ID.
+	Create(job *models.ImportJob) error
+
+	// Update speichert Status, Zähler, Fehler und Ende des Jobs und hängt rowErrors in derselben
+	// Transaktion an seinen Fehlerbericht an.
+	Update(job *models.ImportJob, rowErrors []models.ImportRowError) error
+
+	// GetByID gibt einen Job anhand seiner ID zurück.
+	// Gibt nil, nil zurück, wenn kein Job gefunden wird.
+	GetByID(id string) (*models.ImportJob, error)
+
+	// GetErrors gibt den Fehlerbericht eines Jobs sortiert nach Zeile zurück.
+	GetErrors(id string) ([]models.ImportRowError, error)
+
+	// DeleteExpired löscht Jobs samt Fehlerbericht, die vor before beendet oder zuletzt aktualisiert wurden.
+	// Gibt die Anzahl der gelöschten Jobs zurück.
+	DeleteExpired(before time.Time) (int, error)
+}
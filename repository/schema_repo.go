@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+)
+
+// PostgresSchemaRepository verwaltet die Tabelle schema_migrations und fragt Metadaten der Datenbank ab.
+type PostgresSchemaRepository struct {
+	DB *sql.DB
+}
+
+// ServerVersion gibt die Version des Datenbankservers zurück.
+func (r *PostgresSchemaRepository) ServerVersion() (string, error) {
+	var version string
+	err := r.DB.QueryRow(`SHOW server_version`).Scan(&version)
+	return version, err
+}
+
+// EnsureVersionTable legt schema_migrations an (wie migrations/013_schema_migrations.sql).
+func (r *PostgresSchemaRepository) EnsureVersionTable() error {
+	_, err := r.DB.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+	                         version INTEGER PRIMARY KEY,
+	                         applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	                     )`)
+	return err
+}
+
+// AppliedVersions gibt die eingetragenen Versionen zurück; nil, wenn schema_migrations fehlt.
+func (r *PostgresSchemaRepository) AppliedVersions() ([]int, error) {
+	exists, err := r.HasTable("schema_migrations")
+	if err != nil || !exists {
+		return nil, err
+	}
+	rows, err := r.DB.Query(`SELECT version FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []int{}
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// HasTable prüft über to_regclass, ob die Tabelle im Suchpfad existiert.
+func (r *PostgresSchemaRepository) HasTable(name string) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow(`SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists)
+	return exists, err
+}
+
+// Apply führt sql und den Eintrag der Version in derselben Transaktion aus. Schlägt die Migration fehl,
+// bleibt die Datenbank unverändert.
+func (r *PostgresSchemaRepository) Apply(version int, sql string) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(sql); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES ($1) ON CONFLICT (version) DO NOTHING`, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MarkApplied trägt versions in schema_migrations ein.
+func (r *PostgresSchemaRepository) MarkApplied(versions []int) error {
+	ids := make(pq.Int64Array, len(versions))
+	for i, v := range versions {
+		ids[i] = int64(v)
+	}
+	_, err := r.DB.Exec(`INSERT INTO schema_migrations (version) SELECT unnest($1::int[]) ON CONFLICT (version) DO NOTHING`, ids)
+	return err
+}
+
+// MissingPrivileges prüft mit has_table_privilege bzw. has_sequence_privilege alle Tabellen und Sequenzen
+// des aktuellen Schemas.
+func (r *PostgresSchemaRepository) MissingPrivileges() ([]string, error) {
+	rows, err := r.DB.Query(`SELECT c.relname || ': ' || p.privilege
+	                           FROM pg_class c
+	                           JOIN pg_namespace n ON n.oid = c.relnamespace AND n.nspname = current_schema()
+	                           JOIN (VALUES ('r', 'SELECT'), ('r', 'INSERT'), ('r', 'UPDATE'), ('r', 'DELETE'), ('S', 'USAGE'))
+	                                AS p(relkind, privilege) ON p.relkind = c.relkind
+	                          WHERE CASE c.relkind
+	                                    WHEN 'S' THEN NOT has_sequence_privilege(c.oid, p.privilege)
+	                                    ELSE NOT has_table_privilege(c.oid, p.privilege)
+	                                END
+	                          ORDER BY c.relname, p.privilege`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	missing := []string{}
+	for rows.Next() {
+		var m string
+		if err := rows.Scan(&m); err != nil {
+			return nil, err
+		}
+		missing = append(missing, m)
+	}
+	return missing, rows.Err()
+}
@@ -0,0 +1,61 @@
+package repository
+
+// MockSchemaRepository ist ein Mock des SchemaRepositoryInterface für Tests.
+// Jede Methode wird durch eine Funktion ersetzt, die individuell gesetzt werden kann.
+type MockSchemaRepository struct {
+	// ServerVersionFunc simuliert das Abfragen der Serverversion.
+	ServerVersionFunc func() (string, error)
+
+	// EnsureVersionTableFunc simuliert das Anlegen von schema_migrations.
+	EnsureVersionTableFunc func() error
+
+	// AppliedVersionsFunc simuliert das Abrufen der angewendeten Migrationen.
+	AppliedVersionsFunc func() ([]int, error)
+
+	// HasTableFunc simuliert die Prüfung, ob eine Tabelle existiert.
+	HasTableFunc func(name string) (bool, error)
+
+	// ApplyFunc simuliert das Anwenden einer Migration.
+	ApplyFunc func(version int, sql string) error
+
+	// MarkAppliedFunc simuliert das Eintragen von Versionen ohne Ausführung.
+	MarkAppliedFunc func(versions []int) error
+
+	// MissingPrivilegesFunc simuliert die Prüfung der Rechte.
+	MissingPrivilegesFunc func() ([]string, error)
+}
+
+// ServerVersion ruft ServerVersionFunc auf und gibt das Ergebnis zurück.
+func (m *MockSchemaRepository) ServerVersion() (string, error) {
+	return m.ServerVersionFunc()
+}
+
+// EnsureVersionTable ruft EnsureVersionTableFunc auf und gibt das Ergebnis zurück.
+func (m *MockSchemaRepository) EnsureVersionTable() error {
+	return m.EnsureVersionTableFunc()
+}
+
+// AppliedVersions ruft AppliedVersionsFunc auf und gibt das Ergebnis zurück.
+func (m *MockSchemaRepository) AppliedVersions() ([]int, error) {
+	return m.AppliedVersionsFunc()
+}
+
+// HasTable ruft HasTableFunc auf und gibt das Ergebnis zurück.
+func (m *MockSchemaRepository) HasTable(name string) (bool, error) {
+	return m.HasTableFunc(name)
+}
+
+// Apply ruft ApplyFunc auf und gibt das Ergebnis zurück.
+func (m *MockSchemaRepository) Apply(version int, sql string) error {
+	return m.ApplyFunc(version, sql)
+}
+
+// MarkApplied ruft MarkAppliedFunc auf und gibt das Ergebnis zurück.
+func (m *MockSchemaRepository) MarkApplied(versions []int) error {
+	return m.MarkAppliedFunc(versions)
+}
+
+// MissingPrivileges ruft MissingPrivilegesFunc auf und gibt das Ergebnis zurück.
+func (m *MockSchemaRepository) MissingPrivileges() ([]string, error) {
+	return m.MissingPrivilegesFunc()
+}
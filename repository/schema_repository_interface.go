@@ -0,0 +1,28 @@
+package repository
+
+// SchemaRepositoryInterface definiert die Methoden, mit denen Migrationen angewendet und Schema-Version
+// sowie Rechte der Datenbank geprüft werden (Admin-Befehle migrate und doctor).
+type SchemaRepositoryInterface interface {
+	// ServerVersion gibt die Version des Datenbankservers zurück, z.B. "15.4".
+	ServerVersion() (string, error)
+
+	// EnsureVersionTable legt die Tabelle schema_migrations an, falls sie fehlt.
+	EnsureVersionTable() error
+
+	// AppliedVersions gibt die Versionen aller angewendeten Migrationen aufsteigend zurück.
+	// Gibt nil, nil zurück, wenn schema_migrations nicht existiert.
+	AppliedVersions() ([]int, error)
+
+	// HasTable gibt zurück, ob die Tabelle name im aktuellen Schema existiert.
+	HasTable(name string) (bool, error)
+
+	// Apply führt eine Migration in einer Transaktion aus und trägt ihre Version ein.
+	Apply(version int, sql string) error
+
+	// MarkApplied trägt Versionen als angewendet ein, ohne die Migrationen auszuführen.
+	MarkApplied(versions []int) error
+
+	// MissingPrivileges gibt die fehlenden Rechte des aktuellen Benutzers auf Tabellen (SELECT, INSERT,
+	// UPDATE, DELETE) und Sequenzen (USAGE) des aktuellen Schemas zurück, z.B. "tasks: DELETE".
+	MissingPrivileges() ([]string, error)
+}
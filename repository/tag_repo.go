@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"database/sql"
+	"task-api/models"
+)
+
+// PostgresTagRepository implementiert die Persistenzschicht für Tags
+// und deren Zuordnung zu Tasks (Tabelle task_tags).
+type PostgresTagRepository struct {
+	DB *sql.DB
+}
+
+// Create speichert einen neuen Tag in der Datenbank.
+func (r *PostgresTagRepository) Create(name string) (*models.Tag, error) {
+	tag := &models.Tag{Name: name}
+	err := r.DB.QueryRow(`INSERT INTO tags (name) VALUES ($1) RETURNING id, created_at`, name).
+		Scan(&tag.ID, &tag.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// GetAll gibt alle Tags alphabetisch sortiert zurück.
+func (r *PostgresTagRepository) GetAll() ([]*models.Tag, error) {
+	rows, err := r.DB.Query(`SELECT id, name, created_at FROM tags ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*models.Tag
+	for rows.Next() {
+		tag := &models.Tag{}
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// GetByID gibt einen Tag anhand der ID zurück.
+// Gibt nil zurück, wenn kein Tag mit der ID existiert.
+func (r *PostgresTagRepository) GetByID(id int) (*models.Tag, error) {
+	return r.getOne(`SELECT id, name, created_at FROM tags WHERE id = $1`, id)
+}
+
+// GetByName gibt einen Tag anhand des Namens zurück.
+// Gibt nil zurück, wenn kein Tag mit dem Namen existiert.
+func (r *PostgresTagRepository) GetByName(name string) (*models.Tag, error) {
+	return r.getOne(`SELECT id, name, created_at FROM tags WHERE name = $1`, name)
+}
+
+// getOne führt eine Abfrage aus, die höchstens einen Tag liefert.
+func (r *PostgresTagRepository) getOne(query string, arg any) (*models.Tag, error) {
+	tag := &models.Tag{}
+	err := r.DB.QueryRow(query, arg).Scan(&tag.ID, &tag.Name, &tag.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// Rename ändert den Namen eines Tags und gibt den aktualisierten Tag zurück.
+func (r *PostgresTagRepository) Rename(id int, name string) (*models.Tag, error) {
+	tag := &models.Tag{}
+	err := r.DB.QueryRow(`UPDATE tags SET name = $1 WHERE id = $2 RETURNING id, name, created_at`, name, id).
+		Scan(&tag.ID, &tag.Name, &tag.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// Delete entfernt einen Tag. Zuordnungen zu Tasks werden per ON DELETE CASCADE entfernt.
+func (r *PostgresTagRepository) Delete(id int) error {
+	_, err := r.DB.Exec(`DELETE FROM tags WHERE id = $1`, id)
+	return err
+}
+
+// Merge überträgt alle Zuordnungen von sourceID auf targetID und löscht anschließend sourceID.
+// Beide Schritte laufen in einer Transaktion; doppelte Zuordnungen werden übersprungen.
+func (r *PostgresTagRepository) Merge(sourceID, targetID int) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO task_tags (task_id, tag_id)
+	                  SELECT task_id, $2 FROM task_tags WHERE tag_id = $1
+	                  ON CONFLICT DO NOTHING`, sourceID, targetID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM tags WHERE id = $1`, sourceID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Attach ordnet einen Tag einem Task zu. Existiert die Zuordnung bereits, passiert nichts.
+// Übergebene Events werden bei einer neuen Zuordnung in derselben Transaktion in die Outbox geschrieben.
+func (r *PostgresTagRepository) Attach(taskID, tagID int, events ...models.TaskEvent) error {
+	_, err := execTaskChange(r.DB, taskID, events,
+		`INSERT INTO task_tags (task_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, taskID, tagID)
+	return err
+}
+
+// Detach entfernt die Zuordnung eines Tags zu einem Task.
+// Übergebene Events werden in derselben Transaktion in die Outbox geschrieben.
+// Gibt false zurück, wenn die Zuordnung nicht existiert hat.
+func (r *PostgresTagRepository) Detach(taskID, tagID int, events ...models.TaskEvent) (bool, error) {
+	return execTaskChange(r.DB, taskID, events, `DELETE FROM task_tags WHERE task_id = $1 AND tag_id = $2`, taskID, tagID)
+}
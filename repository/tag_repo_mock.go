@@ -0,0 +1,92 @@
+package repository
+
+import "task-api/models"
+
+// MockTagRepository ist ein Mock des TagRepositoryInterface für Tests.
+// Jede Methode wird durch eine Funktion ersetzt, die individuell gesetzt werden kann.
+type MockTagRepository struct {
+	// CreateFunc simuliert das Erstellen eines Tags.
+	CreateFunc func(name string) (*models.Tag, error)
+
+	// GetAllFunc simuliert das Abrufen aller Tags.
+	GetAllFunc func() ([]*models.Tag, error)
+
+	// GetByIdFunc simuliert das Abrufen eines Tags anhand der ID.
+	GetByIdFunc func(id int) (*models.Tag, error)
+
+	// GetByNameFunc simuliert das Abrufen eines Tags anhand des Namens.
+	GetByNameFunc func(name string) (*models.Tag, error)
+
+	// RenameFunc simuliert das Umbenennen eines Tags.
+	RenameFunc func(id int, name string) (*models.Tag, error)
+
+	// DeleteFunc simuliert das Löschen eines Tags.
+	DeleteFunc func(id int) error
+
+	// MergeFunc simuliert das Zusammenführen zweier Tags.
+	MergeFunc func(sourceID, targetID int) error
+
+	// AttachFunc simuliert das Zuordnen eines Tags zu einem Task.
+	AttachFunc func(taskID, tagID int) error
+
+	// DetachFunc simuliert das Entfernen einer Zuordnung.
+	DetachFunc func(taskID, tagID int) (bool, error)
+
+	// Events sammelt alle Events, die an Attach und Detach übergeben wurden.
+	Events []models.TaskEvent
+}
+
+// Create ruft CreateFunc auf und gibt das Ergebnis zurück.
+func (m *MockTagRepository) Create(name string) (*models.Tag, error) {
+	return m.CreateFunc(name)
+}
+
+// GetAll ruft GetAllFunc auf und gibt das Ergebnis zurück.
+func (m *MockTagRepository) GetAll() ([]*models.Tag, error) {
+	return m.GetAllFunc()
+}
+
+// GetByID ruft GetByIdFunc auf und gibt das Ergebnis zurück.
+func (m *MockTagRepository) GetByID(id int) (*models.Tag, error) {
+	return m.GetByIdFunc(id)
+}
+
+// GetByName ruft GetByNameFunc auf und gibt das Ergebnis zurück.
+func (m *MockTagRepository) GetByName(name string) (*models.Tag, error) {
+	return m.GetByNameFunc(name)
+}
+
+// Rename ruft RenameFunc auf und gibt das Ergebnis zurück.
+func (m *MockTagRepository) Rename(id int, name string) (*models.Tag, error) {
+	return m.RenameFunc(id, name)
+}
+
+// Delete ruft DeleteFunc auf und gibt das Ergebnis zurück.
+func (m *MockTagRepository) Delete(id int) error {
+	return m.DeleteFunc(id)
+}
+
+// Merge ruft MergeFunc auf und gibt das Ergebnis zurück.
+func (m *MockTagRepository) Merge(sourceID, targetID int) error {
+	return m.MergeFunc(sourceID, targetID)
+}
+
+// Attach ruft AttachFunc auf und gibt das Ergebnis zurück.
+// Die Events werden nur bei Erfolg in Events gesammelt.
+func (m *MockTagRepository) Attach(taskID, tagID int, events ...models.TaskEvent) error {
+	err := m.AttachFunc(taskID, tagID)
+	if err == nil {
+		m.Events = append(m.Events, events...)
+	}
+	return err
+}
+
+// Detach ruft DetachFunc auf und gibt das Ergebnis zurück.
+// Die Events werden nur gesammelt, wenn eine Zuordnung entfernt wurde.
+func (m *MockTagRepository) Detach(taskID, tagID int, events ...models.TaskEvent) (bool, error) {
+	removed, err := m.DetachFunc(taskID, tagID)
+	if err == nil && removed {
+		m.Events = append(m.Events, events...)
+	}
+	return removed, err
+}
@@ -0,0 +1,38 @@
+package repository
+
+import "task-api/models"
+
+// TagRepositoryInterface definiert die Methoden zur Verwaltung von Tags und ihrer Zuordnung zu Tasks.
+type TagRepositoryInterface interface {
+	// Create speichert einen neuen Tag und gibt ihn inklusive ID zurück.
+	Create(name string) (*models.Tag, error)
+
+	// GetAll gibt alle Tags alphabetisch sortiert zurück.
+	GetAll() ([]*models.Tag, error)
+
+	// GetByID gibt einen Tag anhand seiner ID zurück.
+	// Gibt nil, nil zurück, wenn kein Tag gefunden wird.
+	GetByID(id int) (*models.Tag, error)
+
+	// GetByName gibt einen Tag anhand seines Namens zurück.
+	// Gibt nil, nil zurück, wenn kein Tag gefunden wird.
+	GetByName(name string) (*models.Tag, error)
+
+	// Rename ändert den Namen eines Tags.
+	Rename(id int, name string) (*models.Tag, error)
+
+	// Delete entfernt einen Tag inklusive aller Zuordnungen.
+	Delete(id int) error
+
+	// Merge überträgt alle Zuordnungen von sourceID auf targetID und löscht sourceID.
+	Merge(sourceID, targetID int) error
+
+	// Attach ordnet einen Tag einem Task zu. Eine bestehende Zuordnung bleibt unverändert.
+	// Die Events werden bei einer neuen Zuordnung transaktional in die Outbox geschrieben.
+	Attach(taskID, tagID int, events ...models.TaskEvent) error
+
+	// Detach entfernt die Zuordnung eines Tags zu einem Task.
+	// Die Events werden transaktional in die Outbox geschrieben.
+	// Gibt false zurück, wenn die Zuordnung nicht existiert hat.
+	Detach(taskID, tagID int, events ...models.TaskEvent) (bool, error)
+}
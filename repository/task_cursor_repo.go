@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"task-api/models"
+)
+
+// taskCursorBatch ist die Anzahl Zeilen, die ein TaskCursor pro FETCH aus der Datenbank holt.
+const taskCursorBatch = 500
+
+// TaskCursor liefert Tasks nacheinander, ohne das Ergebnis vollständig in den Speicher zu laden.
+type TaskCursor interface {
+	// Next gibt den nächsten Task zurück, nil, nil nach dem letzten Task.
+	Next() (*models.Task, error)
+
+	// Close gibt den Cursor und die zugehörige Transaktion frei. Muss immer aufgerufen werden.
+	Close() error
+}
+
+// postgresTaskCursor liest einen serverseitigen Cursor (DECLARE … CURSOR) in Blöcken von taskCursorBatch
+// Zeilen. Der Cursor lebt in einer eigenen, nur lesenden Transaktion und sieht deren Snapshot.
+type postgresTaskCursor struct {
+	tx     *sql.Tx
+	fields []string
+	batch  []*models.Task
+	done   bool
+}
+
+// OpenCursor öffnet einen Cursor über alle Tasks, die den Filtern entsprechen, sortiert nach ID.
+// Geladen werden nur die Felder aus projection.Fields; Beziehungen (projection.Include) werden nicht
+// unterstützt.
+func (r *PostgresTaskRepository) OpenCursor(filter models.TaskFilter, projection models.TaskProjection) (TaskCursor, error) {
+	if len(projection.Include) > 0 {
+		return nil, errors.New("cursor does not support includes")
+	}
+	where, args := buildTaskFilter(filter)
+	query, fields, _, err := projectedQuery(projection, where+` ORDER BY t.id`)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.DB.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DECLARE task_cursor NO SCROLL CURSOR FOR `+query, args...); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return &postgresTaskCursor{tx: tx, fields: fields}, nil
+}
+
+// Next gibt den nächsten Task zurück und holt bei Bedarf den nächsten Block aus der Datenbank.
+func (cur *postgresTaskCursor) Next() (*models.Task, error) {
+	if len(cur.batch) == 0 {
+		if cur.done {
+			return nil, nil
+		}
+		if err := cur.fetch(); err != nil {
+			return nil, err
+		}
+		if len(cur.batch) == 0 {
+			return nil, nil
+		}
+	}
+	t := cur.batch[0]
+	cur.batch = cur.batch[1:]
+	return t, nil
+}
+
+// fetch holt den nächsten Block; ein unvollständiger Block bedeutet, dass der Cursor am Ende ist.
+func (cur *postgresTaskCursor) fetch() error {
+	rows, err := cur.tx.Query(`FETCH FORWARD ` + strconv.Itoa(taskCursorBatch) + ` FROM task_cursor`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	cur.batch = make([]*models.Task, 0, taskCursorBatch)
+	for rows.Next() {
+		t, err := scanProjectedTask(rows, cur.fields, nil, false)
+		if err != nil {
+			return err
+		}
+		cur.batch = append(cur.batch, t)
+	}
+	cur.done = len(cur.batch) < taskCursorBatch
+	return rows.Err()
+}
+
+// Close beendet die Transaktion; der Cursor wird dabei von PostgreSQL geschlossen.
+func (cur *postgresTaskCursor) Close() error {
+	if err := cur.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return err
+	}
+	return nil
+}
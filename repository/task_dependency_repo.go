@@ -0,0 +1,54 @@
+package repository
+
+import "task-api/models"
+
+// AddDependency speichert, dass der Task taskID erst nach dependsOnID bearbeitet werden kann.
+func (r *PostgresTaskRepository) AddDependency(taskID, dependsOnID int) error {
+	query := `INSERT INTO task_dependencies (task_id, depends_on_id) VALUES ($1, $2)`
+	_, err := r.DB.Exec(query, taskID, dependsOnID)
+	return err
+}
+
+// RemoveDependency entfernt eine Abhängigkeit.
+// Gibt false zurück, wenn die Abhängigkeit nicht existiert hat.
+func (r *PostgresTaskRepository) RemoveDependency(taskID, dependsOnID int) (bool, error) {
+	query := `DELETE FROM task_dependencies WHERE task_id = $1 AND depends_on_id = $2`
+	res, err := r.DB.Exec(query, taskID, dependsOnID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// GetDependencies gibt alle Tasks zurück, von denen der Task taskID direkt abhängt.
+func (r *PostgresTaskRepository) GetDependencies(taskID int) ([]*models.Task, error) {
+	return r.queryTasks(taskSelect+`
+	          JOIN task_dependencies d ON d.depends_on_id = t.id
+	          WHERE d.task_id = $1
+	          ORDER BY t.id`, taskID)
+}
+
+// GetDependencyGraph gibt alle Abhängigkeiten als Adjazenzliste zurück:
+// Schlüssel ist die Task-ID, Wert die IDs der Tasks, von denen sie abhängt.
+func (r *PostgresTaskRepository) GetDependencyGraph() (map[int][]int, error) {
+	rows, err := r.DB.Query(`SELECT task_id, depends_on_id FROM task_dependencies ORDER BY task_id, depends_on_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	graph := map[int][]int{}
+	for rows.Next() {
+		var taskID, dependsOnID int
+		if err := rows.Scan(&taskID, &dependsOnID); err != nil {
+			return nil, err
+		}
+		graph[taskID] = append(graph[taskID], dependsOnID)
+	}
+
+	return graph, rows.Err()
+}
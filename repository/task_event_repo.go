@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/lib/pq"
+	"strconv"
+	"task-api/models"
+	"time"
+)
+
+// insertTaskEvents liest den aktuellen Stand des Tasks innerhalb der Transaktion und schreibt
+// für jedes Event einen Eintrag in die Outbox. Ohne Events passiert nichts.
+func insertTaskEvents(tx *sql.Tx, taskID int, events []models.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	task, err := scanTask(tx.QueryRow(taskSelect+` WHERE t.id=$1`, taskID))
+	if err != nil {
+		return err
+	}
+	return insertOutbox(tx, task, events)
+}
+
+// execTaskChange führt query (z.B. das Zuordnen eines Tags) in einer Transaktion aus. Hat query eine
+// Zeile geändert, werden die Events mit dem neuen Stand des Tasks taskID in derselben Transaktion in
+// die Outbox geschrieben. Gibt zurück, ob eine Zeile geändert wurde.
+func execTaskChange(db *sql.DB, taskID int, events []models.TaskEvent, query string, args ...any) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+	if err := insertTaskEvents(tx, taskID, events); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// deleteTasks markiert die Tasks ids innerhalb der Transaktion als gelöscht (deleted_at). Subtasks
+// außerhalb von ids verlieren ihre Zuordnung und Abhängigkeiten von und zu den Tasks entfallen wie bisher
+// beim Löschen; Tags, Kommentare und Anhänge bleiben bis PurgeDeleted erhalten. Für jeden Task werden
+// vorher die Events mit dem Stand vor dem Löschen in die Outbox geschrieben.
+func deleteTasks(tx *sql.Tx, ids []int, events []models.TaskEvent) error {
+	if len(events) > 0 {
+		tasks, err := queryTasksWith(tx, taskSelect+` WHERE t.id = ANY($1) ORDER BY t.id`, pq.Array(ids))
+		if err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			if err := insertOutbox(tx, t, events); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE tasks SET parent_id = NULL WHERE parent_id = ANY($1) AND NOT (id = ANY($1))`, pq.Array(ids)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM task_dependencies WHERE task_id = ANY($1) OR depends_on_id = ANY($1)`, pq.Array(ids)); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`UPDATE tasks SET deleted_at = NOW() WHERE id = ANY($1)`, pq.Array(ids))
+	return err
+}
+
+// insertOutbox schreibt je Event einen Outbox-Eintrag mit dem Task als Payload und meldet
+// dessen ID per NOTIFY auf TaskEventsChannel.
+// Die ID wird vorab aus der Sequenz gezogen, damit sie im Payload enthalten ist.
+func insertOutbox(tx *sql.Tx, task *models.Task, events []models.TaskEvent) error {
+	now := time.Now().UTC()
+	for _, e := range events {
+		payload := models.EventPayload{Event: e.Type, OccurredAt: now, Task: task, PreviousStatus: e.PreviousStatus}
+		if err := tx.QueryRow(`SELECT nextval(pg_get_serial_sequence('outbox', 'id'))`).Scan(&payload.ID); err != nil {
+			return err
+		}
+
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO outbox (id, event_type, task_id, payload, created_at) VALUES ($1, $2, $3, $4, $5)`,
+			payload.ID, e.Type, task.ID, body, now)
+		if err != nil {
+			return err
+		}
+
+		// Benachrichtigt alle API-Instanzen (LISTEN); Postgres stellt die Nachricht erst beim Commit zu
+		_, err = tx.Exec(`SELECT pg_notify($1, $2)`, TaskEventsChannel, strconv.FormatInt(payload.ID, 10))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
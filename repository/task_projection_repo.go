@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
+	"slices"
+	"strings"
+	"task-api/models"
+)
+
+// taskColumns ordnet jedem Feld aus models.TaskFields den SQL-Ausdruck zu, der es lädt.
+// Nicht ausgewählte Felder werden nicht abgefragt, teure Unterabfragen (Tags, Fortschritt) entfallen dann.
+var taskColumns = map[string]string{
+	"id":              "t.id",
+	"title":           "t.title",
+	"description":     "t.description",
+	"status":          "t.status",
+	"priority":        "t.priority",
+	"parent_id":       "t.parent_id",
+	"progress":        progressExpr,
+	"start_at":        "t.start_at",
+	"due_at":          "t.due_at",
+	"is_overdue":      overdueExpr,
+	"recurrence_rule": "t.recurrence_rule",
+	"series_id":       "t.series_id",
+	"tags":            tagsExpr,
+	"assignees":       assigneesExpr,
+	"watchers":        watchersExpr,
+	"created_at":      "t.created_at",
+	"updated_at":      "t.updated_at",
+}
+
+// jsonTime formatiert einen Zeitstempel für json_build_object als RFC 3339 in UTC,
+// so wie lib/pq Spalten vom Typ TIMESTAMP liefert.
+func jsonTime(column string) string {
+	return `to_char(` + column + `, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')`
+}
+
+// taskIncludeJoins enthält für jede Beziehung aus models.TaskIncludes die geladene Spalte und den Join.
+// Subtasks werden nicht per Join, sondern in einer zweiten Abfrage für alle Tasks zusammen geladen.
+var taskIncludeJoins = map[string]struct{ column, join string }{
+	"tags": {"inc_tags.items", `
+	  LEFT JOIN LATERAL (
+	       SELECT json_agg(json_build_object('id', tg.id, 'name', tg.name, 'created_at', ` + jsonTime("tg.created_at") + `)
+	                       ORDER BY tg.name) AS items
+	         FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id
+	        WHERE tt.task_id = t.id) inc_tags ON TRUE`},
+	"assignees": {"inc_assignees.items", `
+	  LEFT JOIN LATERAL (
+	       SELECT json_agg(json_build_object('id', u.id, 'name', u.name, 'email', u.email, 'tenant', u.tenant,
+	                                         'created_at', ` + jsonTime("u.created_at") + `) ORDER BY u.id) AS items
+	         FROM task_assignees ta JOIN users u ON u.id = ta.user_id
+	        WHERE ta.task_id = t.id) inc_assignees ON TRUE`},
+	"comments_count": {"inc_comments.n", `
+	  LEFT JOIN LATERAL (SELECT COUNT(*) AS n FROM comments c WHERE c.task_id = t.id) inc_comments ON TRUE`},
+}
+
+// GetAllProjected gibt den Ausschnitt page der Tasks zurück, die den Filtern entsprechen, lädt dabei aber
+// nur die Felder aus projection.Fields und bettet die Beziehungen aus projection.Include ein (siehe
+// models.TaskRelations). LIMIT/OFFSET und die Gesamtanzahl (COUNT(*) OVER()) ermittelt die Datenbank, sodass
+// Joins und Subtasks nur für die Tasks der Seite geladen werden.
+func (r *PostgresTaskRepository) GetAllProjected(filter models.TaskFilter, projection models.TaskProjection, page models.TaskPage) ([]*models.Task, int, error) {
+	where, args := buildTaskFilter(filter)
+	rest := where + ` ORDER BY t.id`
+	if page.Limit > 0 {
+		args = append(args, page.Limit)
+		rest += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+	if page.Offset > 0 {
+		args = append(args, page.Offset)
+		rest += fmt.Sprintf(` OFFSET $%d`, len(args))
+	}
+
+	var total int
+	tasks, err := r.queryProjected(projection, &total, rest, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(tasks) == 0 && page.Offset > 0 {
+		// Hinter dem Ende liefert die Fensterfunktion keine Zeile; die Anzahl wird dann separat gezählt
+		where, args := buildTaskFilter(filter)
+		if err := r.DB.QueryRow(`SELECT COUNT(*)`+taskFrom+where, args...).Scan(&total); err != nil {
+			return nil, 0, err
+		}
+	}
+	if !projection.Includes("subtasks") || len(tasks) == 0 {
+		return tasks, total, nil
+	}
+
+	// Subtasks aller Tasks in einer Abfrage mit denselben Feldern laden (ohne weitere Ebenen)
+	ids := make([]int, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+		t.Relations.Subtasks = []*models.Task{}
+	}
+	sub := models.TaskProjection{Fields: projection.Fields, Include: slices.DeleteFunc(slices.Clone(projection.Include),
+		func(name string) bool { return name == "subtasks" })}
+	if len(sub.Fields) > 0 && !slices.Contains(sub.Fields, "parent_id") {
+		sub.Fields = append(slices.Clone(sub.Fields), "parent_id")
+	}
+	subtasks, err := r.queryProjected(sub, nil, ` WHERE t.parent_id = ANY($1) ORDER BY t.id`, pq.Array(ids))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	byID := make(map[int]*models.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	for _, s := range subtasks {
+		if parent := byID[*s.ParentID]; parent != nil {
+			parent.Relations.Subtasks = append(parent.Relations.Subtasks, s)
+		}
+	}
+	return tasks, total, nil
+}
+
+// queryProjected führt eine Abfrage mit den Spalten und Joins der Projektion aus;
+// rest enthält WHERE-, ORDER-BY- und LIMIT-Klausel. Ist total gesetzt, wird zusätzlich die Anzahl aller
+// Zeilen ohne LIMIT/OFFSET (COUNT(*) OVER()) geladen.
+func (r *PostgresTaskRepository) queryProjected(projection models.TaskProjection, total *int, rest string, args ...any) ([]*models.Task, error) {
+	query, fields, includes, err := projectedQuery(projection, rest)
+	if err != nil {
+		return nil, err
+	}
+	if total != nil {
+		query = `SELECT COUNT(*) OVER(), ` + strings.TrimPrefix(query, `SELECT `)
+	}
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*models.Task
+	for rows.Next() {
+		var row rowScanner = rows
+		if total != nil {
+			row = totalScanner{rows, total}
+		}
+		t, err := scanProjectedTask(row, fields, includes, len(projection.Include) > 0)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
+
+// totalScanner liest die erste Spalte (COUNT(*) OVER()) einer Zeile in total, die übrigen in dest.
+type totalScanner struct {
+	rowScanner
+	total *int
+}
+
+func (s totalScanner) Scan(dest ...any) error {
+	return s.rowScanner.Scan(append([]any{s.total}, dest...)...)
+}
+
+// projectedQuery baut die Abfrage mit den Spalten und Joins der Projektion; rest enthält WHERE- und
+// ORDER-BY-Klausel. Zurückgegeben werden auch die Felder und Beziehungen in der Reihenfolge der Spalten.
+func projectedQuery(projection models.TaskProjection, rest string) (query string, fields, includes []string, err error) {
+	fields, includes, err = projectedColumns(projection)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	columns := make([]string, 0, len(fields)+len(includes))
+	for _, f := range fields {
+		columns = append(columns, taskColumns[f])
+	}
+	var joins strings.Builder
+	for _, include := range includes {
+		columns = append(columns, taskIncludeJoins[include].column)
+		joins.WriteString(taskIncludeJoins[include].join)
+	}
+	return `SELECT ` + strings.Join(columns, ", ") + taskFrom + joins.String() + rest, fields, includes, nil
+}
+
+// projectedColumns bestimmt die zu ladenden Felder (immer inkl. "id") und die per Join geladenen
+// Beziehungen in fester Reihenfolge. Felder, die eine Beziehung ersetzt (tags, assignees), werden nicht
+// zusätzlich geladen. Unbekannte Namen werden als Fehler gemeldet.
+func projectedColumns(projection models.TaskProjection) (fields, includes []string, err error) {
+	fields = []string{"id"}
+	selected := projection.Fields
+	if len(selected) == 0 {
+		selected = models.TaskFields
+	}
+	for _, f := range selected {
+		if _, ok := taskColumns[f]; !ok {
+			return nil, nil, fmt.Errorf("unknown field %q", f)
+		}
+		if _, joined := taskIncludeJoins[f]; joined && projection.Includes(f) {
+			continue
+		}
+		if !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	for _, include := range projection.Include {
+		if !slices.Contains(models.TaskIncludes, include) {
+			return nil, nil, fmt.Errorf("unknown include %q", include)
+		}
+	}
+	for _, include := range models.TaskIncludes {
+		if _, ok := taskIncludeJoins[include]; ok && projection.Includes(include) {
+			includes = append(includes, include)
+		}
+	}
+	return fields, includes, nil
+}
+
+// scanProjectedTask liest eine Zeile mit den Spalten fields und den Beziehungen includes ein.
+// Nicht geladene Felder behalten ihren Nullwert; mit withRelations wird Task.Relations gesetzt.
+func scanProjectedTask(row rowScanner, fields, includes []string, withRelations bool) (*models.Task, error) {
+	t := &models.Task{}
+	var parentID, seriesID, progress sql.NullInt64
+	var startAt, dueAt sql.NullTime
+	var assignees, watchers pq.Int64Array
+	targets := map[string]any{
+		"id": &t.ID, "title": &t.Title, "description": &t.Description, "status": &t.Status,
+		"priority": &t.Priority, "parent_id": &parentID, "progress": &progress, "start_at": &startAt,
+		"due_at": &dueAt, "is_overdue": &t.IsOverdue, "recurrence_rule": &t.RecurrenceRule,
+		"series_id": &seriesID, "tags": pq.Array(&t.Tags), "assignees": &assignees, "watchers": &watchers,
+		"created_at": &t.CreatedAt, "updated_at": &t.UpdatedAt,
+	}
+	rel := &models.TaskRelations{}
+	var tagsJSON, assigneesJSON []byte
+	includeTargets := map[string]any{"tags": &tagsJSON, "assignees": &assigneesJSON, "comments_count": &rel.CommentsCount}
+
+	dest := make([]any, 0, len(fields)+len(includes))
+	for _, f := range fields {
+		dest = append(dest, targets[f])
+	}
+	for _, include := range includes {
+		dest = append(dest, includeTargets[include])
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(fields, "assignees") {
+		t.Assignees = intSlice(assignees)
+	}
+	if slices.Contains(fields, "watchers") {
+		t.Watchers = intSlice(watchers)
+	}
+	t.ParentID = nullIntPtr(parentID)
+	t.SeriesID = nullIntPtr(seriesID)
+	t.Progress = nullIntPtr(progress)
+	t.StartAt = nullTimePtr(startAt)
+	t.DueAt = nullTimePtr(dueAt)
+
+	if !withRelations {
+		return t, nil
+	}
+	if slices.Contains(includes, "tags") {
+		rel.Tags = []*models.Tag{}
+		if err := unmarshalNullable(tagsJSON, &rel.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if slices.Contains(includes, "assignees") {
+		rel.Assignees = []*models.User{}
+		if err := unmarshalNullable(assigneesJSON, &rel.Assignees); err != nil {
+			return nil, err
+		}
+	}
+	t.Relations = rel
+	return t, nil
+}
+
+// unmarshalNullable dekodiert ein JSON-Aggregat; NULL (keine Zeilen) lässt v unverändert.
+func unmarshalNullable(data []byte, v any) error {
+	if data == nil {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"database/sql"
-	_ "github.com/lib/pq"
+	"fmt"
+	"github.com/lib/pq"
+	"strings"
 	"task-api/models"
+	"time"
 )
 
 // PostgresTaskRepository implementiert die Persistenzschicht für Tasks
@@ -12,26 +15,117 @@ type PostgresTaskRepository struct {
 	DB *sql.DB
 }
 
-// Create speichert einen neuen Task in der Datenbank.
-// Gibt den vollständigen Task inklusive ID, CreatedAt und UpdatedAt zurück.
-func (r *PostgresTaskRepository) Create(task *models.Task) (*models.Task, error) {
-	query := `INSERT INTO tasks (title, description, status, priority)
-	          VALUES ($1, $2, $3, $4)
-	          RETURNING id, created_at, updated_at`
+// overdueExpr berechnet, ob ein Task überfällig ist: Fälligkeit in der Vergangenheit
+// und der Status ist kein finaler Workflow-Status.
+const overdueExpr = `(t.due_at IS NOT NULL AND t.due_at < NOW() AND NOT COALESCE(tws.is_final, FALSE))`
 
-	err := r.DB.QueryRow(query, task.Title, task.Description, task.Status, task.Priority).
-		Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt)
+// Berechnete Spalten eines Tasks: Tag-Namen, IDs der Verantwortlichen und Beobachter sowie der Fortschritt.
+// Der Fortschritt (progress) wird als Anteil direkter Subtasks in einem finalen Workflow-Status berechnet
+// und ist NULL, wenn ein Task keine Subtasks besitzt.
+const (
+	tagsExpr = `ARRAY(SELECT tg.name FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id
+	              WHERE tt.task_id = t.id ORDER BY tg.name)`
+	assigneesExpr = `ARRAY(SELECT ta.user_id FROM task_assignees ta WHERE ta.task_id = t.id ORDER BY ta.user_id)`
+	watchersExpr  = `ARRAY(SELECT tw.user_id FROM task_watchers tw WHERE tw.task_id = t.id ORDER BY tw.user_id)`
+	progressExpr  = `(SELECT ROUND(100.0 * COUNT(*) FILTER (WHERE ws.is_final) / NULLIF(COUNT(*), 0))::int
+	          FROM tasks s LEFT JOIN workflow_statuses ws ON ws.name = s.status
+	         WHERE s.parent_id = t.id AND s.deleted_at IS NULL)`
+)
+
+// liveTasks enthält nur die nicht gelöschten Tasks. Gelöschte Tasks bleiben bis "server purge" als
+// Tombstone (deleted_at) erhalten und dürfen in keiner Abfrage der API auftauchen.
+const liveTasks = `(SELECT * FROM tasks WHERE deleted_at IS NULL)`
+
+// taskFrom ist die FROM-Klausel aller Task-Abfragen; der Workflow-Status wird für overdueExpr benötigt.
+const taskFrom = `
+	  FROM ` + liveTasks + ` t
+	  LEFT JOIN workflow_statuses tws ON tws.name = t.status`
+
+// taskColumnList sind die Spalten im Format von scanTask.
+const taskColumnList = `SELECT t.id, t.title, t.description, t.status, t.priority, t.parent_id,
+	       t.start_at, t.due_at, ` + overdueExpr + ` AS is_overdue,
+	       t.recurrence_rule, t.series_id,
+	       ` + tagsExpr + ` AS tags,
+	       ` + assigneesExpr + ` AS assignees,
+	       ` + watchersExpr + ` AS watchers,
+	       t.created_at, t.updated_at,
+	       ` + progressExpr + ` AS progress`
+
+// taskSelect ist die gemeinsame SELECT-Klausel für alle Task-Abfragen.
+const taskSelect = taskColumnList + taskFrom
+
+// taskSelectWithDeleted liest wie taskSelect, aber einschließlich gelöschter Tasks. Nur für interne
+// Entscheidungen wie das Fortsetzen von Serien, nie für Antworten der API.
+const taskSelectWithDeleted = taskColumnList + `
+	  FROM tasks t
+	  LEFT JOIN workflow_statuses tws ON tws.name = t.status`
+
+// rowScanner abstrahiert *sql.Row und *sql.Rows, damit scanTask für beide genutzt werden kann.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask liest eine Zeile im Format von taskSelect in einen Task ein.
+func scanTask(row rowScanner) (*models.Task, error) {
+	t := &models.Task{}
+	var parentID, seriesID, progress sql.NullInt64
+	var startAt, dueAt sql.NullTime
+	var assignees, watchers pq.Int64Array
+	err := row.Scan(&t.ID, &t.Title, &t.Description, &t.Status, &t.Priority, &parentID,
+		&startAt, &dueAt, &t.IsOverdue, &t.RecurrenceRule, &seriesID, pq.Array(&t.Tags),
+		&assignees, &watchers, &t.CreatedAt, &t.UpdatedAt, &progress)
 	if err != nil {
 		return nil, err
 	}
+	t.Assignees = intSlice(assignees)
+	t.Watchers = intSlice(watchers)
+	t.ParentID = nullIntPtr(parentID)
+	t.SeriesID = nullIntPtr(seriesID)
+	t.Progress = nullIntPtr(progress)
+	t.StartAt = nullTimePtr(startAt)
+	t.DueAt = nullTimePtr(dueAt)
+	return t, nil
+}
 
-	return task, nil
+// nullIntPtr wandelt einen nullable Integer aus der Datenbank in einen *int um.
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
 }
 
-// GetAll gibt alle Tasks aus der Datenbank zurück.
-// Liefert ein Slice von Task-Pointern oder einen Fehler.
-func (r *PostgresTaskRepository) GetAll() ([]*models.Task, error) {
-	rows, err := r.DB.Query(`SELECT id, title, description, status, priority, created_at, updated_at FROM tasks`)
+// intSlice wandelt ein Integer-Array aus der Datenbank in ein []int um.
+func intSlice(v pq.Int64Array) []int {
+	ids := make([]int, len(v))
+	for i, id := range v {
+		ids[i] = int(id)
+	}
+	return ids
+}
+
+// nullTimePtr wandelt einen nullable Zeitstempel aus der Datenbank in einen *time.Time um.
+func nullTimePtr(v sql.NullTime) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Time
+}
+
+// queryer abstrahiert *sql.DB und *sql.Tx, damit Abfragen auch innerhalb einer Transaktion laufen können.
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// queryTasks führt eine Abfrage aus und liest alle Zeilen als Tasks ein.
+func (r *PostgresTaskRepository) queryTasks(query string, args ...any) ([]*models.Task, error) {
+	return queryTasksWith(r.DB, query, args...)
+}
+
+// queryTasksWith führt eine Abfrage über q aus und liest alle Zeilen als Tasks ein.
+func queryTasksWith(q queryer, query string, args ...any) ([]*models.Task, error) {
+	rows, err := q.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -39,28 +133,135 @@ func (r *PostgresTaskRepository) GetAll() ([]*models.Task, error) {
 
 	var tasks []*models.Task
 	for rows.Next() {
-		t := &models.Task{}
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.Status, &t.Priority, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		t, err := scanTask(rows)
+		if err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, t)
 	}
 
-	return tasks, nil
+	return tasks, rows.Err()
+}
+
+// Create speichert einen neuen Task in der Datenbank.
+// Ein wiederkehrender Task ohne SeriesID beginnt eine neue Serie: series_id wird in derselben Transaktion
+// auf seine ID gesetzt. Übergebene Events werden ebenfalls in dieser Transaktion in die Outbox geschrieben.
+// Gibt den vollständigen Task inklusive ID, CreatedAt und UpdatedAt zurück.
+func (r *PostgresTaskRepository) Create(task *models.Task, events ...models.TaskEvent) (*models.Task, error) {
+	return r.insert(task, "", events)
+}
+
+// CreateOccurrence speichert ein neues Vorkommen der Serie task.SeriesID wie Create. Hat die Serie bereits
+// ein Vorkommen mit derselben Fälligkeit (auch ein gelöschtes), wird nichts gespeichert und nil zurückgegeben;
+// der eindeutige Index idx_tasks_series_due verhindert so doppelte Vorkommen auch bei parallelen Aufrufen.
+func (r *PostgresTaskRepository) CreateOccurrence(task *models.Task, events ...models.TaskEvent) (*models.Task, error) {
+	return r.insert(task, ` ON CONFLICT (series_id, due_at) DO NOTHING`, events)
+}
+
+// insert speichert task mit der ON-CONFLICT-Klausel onConflict. Fügt die Klausel keine Zeile ein,
+// wird nil zurückgegeben.
+func (r *PostgresTaskRepository) insert(task *models.Task, onConflict string, events []models.TaskEvent) (*models.Task, error) {
+	query := `INSERT INTO tasks (title, description, status, priority, parent_id, start_at, due_at,
+	                             recurrence_rule, series_id)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)` + onConflict + `
+	          RETURNING id, created_at, updated_at`
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(query, task.Title, task.Description, task.Status, task.Priority, task.ParentID,
+		task.StartAt, task.DueAt, task.RecurrenceRule, task.SeriesID).
+		Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt)
+	if err == sql.ErrNoRows && onConflict != "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if task.RecurrenceRule != "" && task.SeriesID == nil {
+		if _, err := tx.Exec(`UPDATE tasks SET series_id = id WHERE id = $1`, task.ID); err != nil {
+			return nil, err
+		}
+		task.SeriesID = &task.ID
+	}
+	if err := insertTaskEvents(tx, task.ID, events); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// GetAll gibt alle Tasks aus der Datenbank zurück, die den Filtern entsprechen.
+// Liefert ein Slice von Task-Pointern oder einen Fehler.
+func (r *PostgresTaskRepository) GetAll(filter models.TaskFilter) ([]*models.Task, error) {
+	where, args := buildTaskFilter(filter)
+	return r.queryTasks(taskSelect+where+` ORDER BY t.id`, args...)
+}
+
+// buildTaskFilter übersetzt einen TaskFilter in eine WHERE-Klausel mit Platzhaltern.
+// Gibt einen leeren String zurück, wenn kein Filter gesetzt ist.
+func buildTaskFilter(filter models.TaskFilter) (string, []any) {
+	var conds []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Overdue != nil {
+		if *filter.Overdue {
+			conds = append(conds, overdueExpr)
+		} else {
+			conds = append(conds, "NOT "+overdueExpr)
+		}
+	}
+	if filter.DueBefore != nil {
+		conds = append(conds, "t.due_at < "+arg(*filter.DueBefore))
+	}
+	if filter.DueAfter != nil {
+		conds = append(conds, "t.due_at > "+arg(*filter.DueAfter))
+	}
+	if filter.HasDueDate {
+		conds = append(conds, "t.due_at IS NOT NULL")
+	}
+
+	// Tag-Filter prüfen über task_tags, ohne die Task-Zeilen zu vervielfachen
+	const hasTag = `SELECT 1 FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id WHERE tt.task_id = t.id AND tg.name = ANY(%s)`
+	if len(filter.TagsAny) > 0 {
+		conds = append(conds, "EXISTS ("+fmt.Sprintf(hasTag, arg(pq.Array(filter.TagsAny)))+")")
+	}
+	if len(filter.TagsAll) > 0 {
+		p := arg(pq.Array(filter.TagsAll))
+		conds = append(conds, fmt.Sprintf(`(SELECT COUNT(DISTINCT tg.name) FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id
+		                                     WHERE tt.task_id = t.id AND tg.name = ANY(%s)) = cardinality(%s::text[])`, p, p))
+	}
+	if len(filter.TagsNone) > 0 {
+		conds = append(conds, "NOT EXISTS ("+fmt.Sprintf(hasTag, arg(pq.Array(filter.TagsNone)))+")")
+	}
+
+	if filter.Assignee != nil {
+		conds = append(conds, "EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = t.id AND ta.user_id = "+arg(*filter.Assignee)+")")
+	}
+	if filter.Watcher != nil {
+		conds = append(conds, "EXISTS (SELECT 1 FROM task_watchers tw WHERE tw.task_id = t.id AND tw.user_id = "+arg(*filter.Watcher)+")")
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
 }
 
 // GetByID gibt einen Task anhand der ID zurück.
 // Gibt nil zurück, wenn kein Task mit der ID existiert.
 func (r *PostgresTaskRepository) GetByID(id int) (*models.Task, error) {
-	query := `SELECT id, title, description, status, priority, created_at, updated_at
-	          FROM tasks WHERE id=$1`
-
-	task := &models.Task{}
-	err := r.DB.QueryRow(query, id).Scan(
-		&task.ID, &task.Title, &task.Description, &task.Status,
-		&task.Priority, &task.CreatedAt, &task.UpdatedAt,
-	)
-
+	task, err := scanTask(r.DB.QueryRow(taskSelect+` WHERE t.id=$1`, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -71,26 +272,257 @@ func (r *PostgresTaskRepository) GetByID(id int) (*models.Task, error) {
 	return task, nil
 }
 
+// GetByIDs gibt alle Tasks mit den angegebenen IDs zurück, sortiert nach ID.
+// Nicht existierende IDs werden ignoriert.
+func (r *PostgresTaskRepository) GetByIDs(ids []int) ([]*models.Task, error) {
+	return r.queryTasks(taskSelect+` WHERE t.id = ANY($1) ORDER BY t.id`, pq.Array(ids))
+}
+
+// GetSubtasksOf gibt alle direkten Subtasks der angegebenen Tasks in einer Abfrage zurück,
+// sortiert nach ID.
+func (r *PostgresTaskRepository) GetSubtasksOf(parentIDs []int) ([]*models.Task, error) {
+	return r.queryTasks(taskSelect+` WHERE t.parent_id = ANY($1) ORDER BY t.id`, pq.Array(parentIDs))
+}
+
+// GetSubtasks gibt alle direkten Subtasks eines Tasks zurück, sortiert nach ID.
+func (r *PostgresTaskRepository) GetSubtasks(parentID int) ([]*models.Task, error) {
+	return r.queryTasks(taskSelect+` WHERE t.parent_id=$1 ORDER BY t.id`, parentID)
+}
+
+// GetTree lädt einen Task samt aller Subtasks (beliebig tief) über eine rekursive CTE
+// und baut daraus eine verschachtelte Struktur auf.
+// Gibt nil zurück, wenn kein Task mit der ID existiert.
+func (r *PostgresTaskRepository) GetTree(id int) (*models.TaskNode, error) {
+	query := `WITH RECURSIVE tree AS (
+	              SELECT id, 0 AS depth FROM ` + liveTasks + ` r WHERE id = $1
+	              UNION ALL
+	              SELECT c.id, tree.depth + 1 FROM ` + liveTasks + ` c JOIN tree ON c.parent_id = tree.id
+	          ) CYCLE id SET is_cycle USING path
+	          ` + taskSelect + `
+	          JOIN tree ON tree.id = t.id AND NOT tree.is_cycle
+	          ORDER BY tree.depth, t.id`
+
+	tasks, err := r.queryTasks(query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	// Da die Zeilen nach Tiefe sortiert sind, existiert der Parent-Knoten immer bereits
+	nodes := make(map[int]*models.TaskNode, len(tasks))
+	root := &models.TaskNode{Task: tasks[0], Subtasks: []*models.TaskNode{}}
+	nodes[root.ID] = root
+	for _, t := range tasks[1:] {
+		node := &models.TaskNode{Task: t, Subtasks: []*models.TaskNode{}}
+		nodes[t.ID] = node
+		if parent, ok := nodes[*t.ParentID]; ok {
+			parent.Subtasks = append(parent.Subtasks, node)
+		}
+	}
+
+	return root, nil
+}
+
 // Update ändert die Felder eines bestehenden Tasks in der Datenbank.
-// Gibt den aktualisierten Task zurück oder einen Fehler.
-func (r *PostgresTaskRepository) Update(task *models.Task) (*models.Task, error) {
+// Übergebene Events werden in derselben Transaktion in die Outbox geschrieben.
+// Gibt den aktualisierten Task zurück oder einen Fehler; "series already has an occurrence at this due date",
+// wenn die neue Fälligkeit mit einem anderen Vorkommen derselben Serie zusammenfällt.
+func (r *PostgresTaskRepository) Update(task *models.Task, events ...models.TaskEvent) (*models.Task, error) {
+	// Ändert sich die Fälligkeit, wird die Erinnerung zurückgesetzt und erneut versendet
 	query := `UPDATE tasks 
-              SET title=$1, status=$2, priority=$3, updated_at=NOW()
-              WHERE id=$4
-              RETURNING id, title, status, priority, created_at, updated_at`
+              SET title=$1, description=$2, status=$3, priority=$4, parent_id=$5,
+                  start_at=$6, due_at=$7,
+                  reminder_sent_at=CASE WHEN due_at IS DISTINCT FROM $7 THEN NULL ELSE reminder_sent_at END,
+                  recurrence_rule=$8, series_id=$9,
+                  updated_at=NOW()
+              WHERE id=$10 AND deleted_at IS NULL
+              RETURNING id, title, description, status, priority, created_at, updated_at`
 
-	err := r.DB.QueryRow(query, task.Title, task.Status, task.Priority, task.ID).
-		Scan(&task.ID, &task.Title, &task.Status, &task.Priority, &task.CreatedAt, &task.UpdatedAt)
+	tx, err := r.DB.Begin()
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(query, task.Title, task.Description, task.Status, task.Priority, task.ParentID,
+		task.StartAt, task.DueAt, task.RecurrenceRule, task.SeriesID, task.ID).
+		Scan(&task.ID, &task.Title, &task.Description, &task.Status, &task.Priority, &task.CreatedAt, &task.UpdatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Constraint == "idx_tasks_series_due" {
+		return nil, fmt.Errorf("series already has an occurrence at this due date")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := insertTaskEvents(tx, task.ID, events); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return task, nil
 }
 
-// Delete entfernt einen Task anhand der ID aus der Datenbank.
+// Delete markiert einen Task als gelöscht (siehe deleteTasks); endgültig entfernt ihn PurgeDeleted.
+// Vorhandene Subtasks verlieren ihre Zuordnung (parent_id wird NULL).
+// Übergebene Events werden in derselben Transaktion in die Outbox geschrieben.
 // Gibt einen Fehler zurück, falls die Löschung fehlschlägt.
-func (r *PostgresTaskRepository) Delete(id int) error {
-	query := `DELETE FROM tasks WHERE id = $1`
-	_, err := r.DB.Exec(query, id)
-	return err
+func (r *PostgresTaskRepository) Delete(id int, events ...models.TaskEvent) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deleteTasks(tx, []int{id}, events); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteWithSubtasks markiert einen Task inklusive aller (auch indirekten) Subtasks als gelöscht.
+// Die Events werden für jeden gelöschten Task in die Outbox geschrieben.
+func (r *PostgresTaskRepository) DeleteWithSubtasks(id int, events ...models.TaskEvent) error {
+	query := `WITH RECURSIVE tree AS (
+	              SELECT id FROM ` + liveTasks + ` r WHERE id = $1
+	              UNION
+	              SELECT c.id FROM ` + liveTasks + ` c JOIN tree ON c.parent_id = tree.id
+	          )
+	          SELECT id FROM tree`
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(query, id)
+	if err != nil {
+		return err
+	}
+	var ids []int
+	for rows.Next() {
+		var taskID int
+		if err := rows.Scan(&taskID); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, taskID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if err := deleteTasks(tx, ids, events); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PurgeDeleted löscht Tasks endgültig, die vor before gelöscht wurden. Tags, Zuweisungen, Kommentare und
+// Anhänge entfallen per ON DELETE CASCADE; zurückgegeben werden die Anzahl der Tasks und die Storage-Keys
+// ihrer Anhänge, deren Blobs der Aufrufer entfernen muss. Mit dryRun wird die Transaktion zurückgerollt.
+// Gelöschte Vorkommen einer Serie bleiben erhalten, solange die Serie noch aktive Vorkommen hat, da sie
+// sonst erneut angelegt würden.
+func (r *PostgresTaskRepository) PurgeDeleted(before time.Time, dryRun bool) (int, []string, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	var keys []string
+	const purgeable = `t.deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM tasks l
+	                   WHERE l.series_id = t.series_id AND l.deleted_at IS NULL)`
+	err = tx.QueryRow(`SELECT ARRAY(SELECT a.storage_key FROM attachments a JOIN tasks t ON t.id = a.task_id
+	                                WHERE `+purgeable+` ORDER BY a.id)`, before).Scan(pq.Array(&keys))
+	if err != nil {
+		return 0, nil, err
+	}
+	res, err := tx.Exec(`DELETE FROM tasks t WHERE `+purgeable, before)
+	if err != nil {
+		return 0, nil, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, nil, err
+	}
+	if dryRun {
+		return int(n), keys, nil
+	}
+	return int(n), keys, tx.Commit()
+}
+
+// ClaimDueReminders vermerkt für alle nicht erledigten Tasks, die bis zum Zeitpunkt until fällig werden
+// und noch keine Erinnerung erhalten haben, den Versand und schreibt in derselben Transaktion je Task die
+// Events in die Outbox. Das bedingte UPDATE mit SKIP LOCKED stellt sicher, dass bei mehreren Instanzen
+// jede Erinnerung genau einmal ausgelöst wird. Gibt die IDs der erinnerten Tasks zurück.
+func (r *PostgresTaskRepository) ClaimDueReminders(until time.Time, events ...models.TaskEvent) ([]int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`UPDATE tasks SET reminder_sent_at = NOW()
+	          WHERE reminder_sent_at IS NULL AND id IN (
+	              SELECT t.id FROM tasks t
+	                LEFT JOIN workflow_statuses tws ON tws.name = t.status
+	               WHERE t.deleted_at IS NULL AND t.due_at IS NOT NULL AND t.due_at <= $1
+	                 AND t.reminder_sent_at IS NULL
+	                 AND NOT COALESCE(tws.is_final, FALSE)
+	               FOR UPDATE OF t SKIP LOCKED
+	          )
+	          RETURNING id`, until)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if err := insertTaskEvents(tx, id, events); err != nil {
+			return nil, err
+		}
+	}
+	return ids, tx.Commit()
+}
+
+// HasOccurrenceFrom prüft, ob die Serie ein Vorkommen (auch ein gelöschtes) mit Fälligkeit ab from hat.
+func (r *PostgresTaskRepository) HasOccurrenceFrom(seriesID int, from time.Time) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM tasks WHERE series_id = $1 AND due_at >= $2)`, seriesID, from).Scan(&exists)
+	return exists, err
+}
+
+// GetSeries gibt alle Vorkommen einer Serie zurück, sortiert nach Fälligkeit.
+func (r *PostgresTaskRepository) GetSeries(seriesID int) ([]*models.Task, error) {
+	return r.queryTasks(taskSelect+` WHERE t.series_id=$1 ORDER BY t.due_at, t.id`, seriesID)
+}
+
+// GetLatestOccurrences gibt pro wiederkehrender Serie das Vorkommen mit der spätesten Fälligkeit zurück.
+// Gelöschte Vorkommen zählen mit, damit die Serie nach dem Löschen des neuesten Vorkommens dahinter
+// fortgesetzt wird, statt es erneut anzulegen. Serien ohne verbliebenes Vorkommen sind beendet.
+func (r *PostgresTaskRepository) GetLatestOccurrences() ([]*models.Task, error) {
+	return r.queryTasks(taskSelectWithDeleted + `
+	          WHERE t.id IN (
+	              SELECT DISTINCT ON (series_id) id FROM tasks s
+	               WHERE series_id IS NOT NULL AND recurrence_rule <> ''
+	                 AND EXISTS (SELECT 1 FROM tasks l WHERE l.series_id = s.series_id AND l.deleted_at IS NULL)
+	               ORDER BY series_id, due_at DESC NULLS LAST, id DESC
+	          )
+	          ORDER BY t.series_id`)
 }
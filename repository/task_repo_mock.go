@@ -1,6 +1,9 @@
 package repository
 
-import "task-api/models"
+import (
+	"task-api/models"
+	"time"
+)
 
 // MockTaskRepository ist ein Mock des TaskRepositoryInterface für Tests.
 // Jede Methode wird durch eine Funktion ersetzt, die individuell gesetzt werden kann.
@@ -9,27 +12,110 @@ type MockTaskRepository struct {
 	// CreateFunc simuliert das Erstellen eines Tasks.
 	CreateFunc func(task *models.Task) (*models.Task, error)
 
+	// CreateOccurrenceFunc simuliert das Erstellen eines Serienvorkommens; nil bedeutet, dass es bereits existiert.
+	CreateOccurrenceFunc func(task *models.Task) (*models.Task, error)
+
 	// GetAllFunc simuliert das Abrufen aller Tasks.
-	GetAllFunc func() ([]*models.Task, error)
+	GetAllFunc func(filter models.TaskFilter) ([]*models.Task, error)
+
+	// GetAllProjectedFunc simuliert das Abrufen aller Tasks mit Feldauswahl und Beziehungen.
+	GetAllProjectedFunc func(filter models.TaskFilter, projection models.TaskProjection, page models.TaskPage) ([]*models.Task, int, error)
+
+	// OpenCursorFunc simuliert das Öffnen eines Cursors über die gefilterten Tasks.
+	OpenCursorFunc func(filter models.TaskFilter, projection models.TaskProjection) (TaskCursor, error)
 
 	// GetByIdFunc simuliert das Abrufen eines Tasks anhand der ID.
 	GetByIdFunc func(id int) (*models.Task, error)
 
+	// GetSubtasksFunc simuliert das Abrufen der Subtasks eines Tasks.
+	GetSubtasksFunc func(parentID int) ([]*models.Task, error)
+
+	// GetByIDsFunc simuliert das Abrufen mehrerer Tasks anhand ihrer IDs.
+	GetByIDsFunc func(ids []int) ([]*models.Task, error)
+
+	// GetSubtasksOfFunc simuliert das Abrufen der Subtasks mehrerer Tasks.
+	GetSubtasksOfFunc func(parentIDs []int) ([]*models.Task, error)
+
+	// GetTreeFunc simuliert das Abrufen eines Task-Baums.
+	GetTreeFunc func(id int) (*models.TaskNode, error)
+
 	// UpdateFunc simuliert das Aktualisieren eines Tasks.
 	UpdateFunc func(task *models.Task) (*models.Task, error)
 
 	// DeleteFunc simuliert das Löschen eines Tasks anhand der ID.
 	DeleteFunc func(id int) error
+
+	// DeleteWithSubtasksFunc simuliert das Löschen eines Tasks samt Subtasks.
+	DeleteWithSubtasksFunc func(id int) error
+
+	// PurgeDeletedFunc simuliert das endgültige Löschen von Tasks, die vor before gelöscht wurden.
+	PurgeDeletedFunc func(before time.Time, dryRun bool) (int, []string, error)
+
+	// AddDependencyFunc simuliert das Anlegen einer Abhängigkeit.
+	AddDependencyFunc func(taskID, dependsOnID int) error
+
+	// RemoveDependencyFunc simuliert das Entfernen einer Abhängigkeit.
+	RemoveDependencyFunc func(taskID, dependsOnID int) (bool, error)
+
+	// GetDependenciesFunc simuliert das Abrufen der Tasks, von denen ein Task abhängt.
+	GetDependenciesFunc func(taskID int) ([]*models.Task, error)
+
+	// ClaimDueRemindersFunc simuliert das Reservieren bald fälliger Erinnerungen.
+	ClaimDueRemindersFunc func(until time.Time) ([]int, error)
+
+	// GetSeriesFunc simuliert das Abrufen aller Vorkommen einer Serie.
+	GetSeriesFunc func(seriesID int) ([]*models.Task, error)
+
+	// HasOccurrenceFromFunc simuliert die Prüfung auf spätere Vorkommen einer Serie.
+	HasOccurrenceFromFunc func(seriesID int, from time.Time) (bool, error)
+
+	// GetLatestOccurrencesFunc simuliert das Abrufen des letzten Vorkommens je Serie.
+	GetLatestOccurrencesFunc func() ([]*models.Task, error)
+
+	// GetDependencyGraphFunc simuliert das Abrufen aller Abhängigkeiten.
+	GetDependencyGraphFunc func() (map[int][]int, error)
+
+	// Events sammelt alle Events, die an Create, Update, Delete und DeleteWithSubtasks übergeben wurden.
+	Events []models.TaskEvent
+}
+
+// Create ruft CreateFunc auf und gibt das Ergebnis zurück. Wie das Postgres-Repository setzt der Mock
+// bei einem wiederkehrenden Task ohne SeriesID die Serie auf dessen ID.
+// Die Events werden nur bei Erfolg in Events gesammelt.
+func (m *MockTaskRepository) Create(task *models.Task, events ...models.TaskEvent) (*models.Task, error) {
+	created, err := m.CreateFunc(task)
+	if err == nil {
+		if created.RecurrenceRule != "" && created.SeriesID == nil {
+			created.SeriesID = &created.ID
+		}
+		m.Events = append(m.Events, events...)
+	}
+	return created, err
 }
 
-// Create ruft CreateFunc auf und gibt das Ergebnis zurück.
-func (m *MockTaskRepository) Create(task *models.Task) (*models.Task, error) {
-	return m.CreateFunc(task)
+// CreateOccurrence ruft CreateOccurrenceFunc auf und gibt das Ergebnis zurück.
+// Die Events werden nur gesammelt, wenn tatsächlich ein Vorkommen angelegt wurde.
+func (m *MockTaskRepository) CreateOccurrence(task *models.Task, events ...models.TaskEvent) (*models.Task, error) {
+	created, err := m.CreateOccurrenceFunc(task)
+	if err == nil && created != nil {
+		m.Events = append(m.Events, events...)
+	}
+	return created, err
 }
 
 // GetAll ruft GetAllFunc auf und gibt das Ergebnis zurück.
-func (m *MockTaskRepository) GetAll() ([]*models.Task, error) {
-	return m.GetAllFunc()
+func (m *MockTaskRepository) GetAll(filter models.TaskFilter) ([]*models.Task, error) {
+	return m.GetAllFunc(filter)
+}
+
+// GetAllProjected ruft GetAllProjectedFunc auf und gibt das Ergebnis zurück.
+func (m *MockTaskRepository) GetAllProjected(filter models.TaskFilter, projection models.TaskProjection, page models.TaskPage) ([]*models.Task, int, error) {
+	return m.GetAllProjectedFunc(filter, projection, page)
+}
+
+// OpenCursor ruft OpenCursorFunc auf und gibt das Ergebnis zurück.
+func (m *MockTaskRepository) OpenCursor(filter models.TaskFilter, projection models.TaskProjection) (TaskCursor, error) {
+	return m.OpenCursorFunc(filter, projection)
 }
 
 // GetByID ruft GetByIdFunc auf und gibt das Ergebnis zurück.
@@ -37,12 +123,163 @@ func (m *MockTaskRepository) GetByID(id int) (*models.Task, error) {
 	return m.GetByIdFunc(id)
 }
 
+// GetSubtasks ruft GetSubtasksFunc auf und gibt das Ergebnis zurück.
+// Ist GetSubtasksFunc nicht gesetzt, hat der Task keine Subtasks.
+func (m *MockTaskRepository) GetSubtasks(parentID int) ([]*models.Task, error) {
+	if m.GetSubtasksFunc == nil {
+		return nil, nil
+	}
+	return m.GetSubtasksFunc(parentID)
+}
+
+// GetByIDs ruft GetByIDsFunc auf und gibt das Ergebnis zurück.
+// Ist GetByIDsFunc nicht gesetzt, wird GetByIdFunc für jede ID aufgerufen.
+func (m *MockTaskRepository) GetByIDs(ids []int) ([]*models.Task, error) {
+	if m.GetByIDsFunc != nil {
+		return m.GetByIDsFunc(ids)
+	}
+	var tasks []*models.Task
+	for _, id := range ids {
+		task, err := m.GetByIdFunc(id)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// GetSubtasksOf ruft GetSubtasksOfFunc auf und gibt das Ergebnis zurück.
+// Ist GetSubtasksOfFunc nicht gesetzt, haben die Tasks keine Subtasks.
+func (m *MockTaskRepository) GetSubtasksOf(parentIDs []int) ([]*models.Task, error) {
+	if m.GetSubtasksOfFunc == nil {
+		return nil, nil
+	}
+	return m.GetSubtasksOfFunc(parentIDs)
+}
+
+// GetTree ruft GetTreeFunc auf und gibt das Ergebnis zurück.
+func (m *MockTaskRepository) GetTree(id int) (*models.TaskNode, error) {
+	return m.GetTreeFunc(id)
+}
+
 // Update ruft UpdateFunc auf und gibt das Ergebnis zurück.
-func (m *MockTaskRepository) Update(task *models.Task) (*models.Task, error) {
-	return m.UpdateFunc(task)
+// Die Events werden nur bei Erfolg in Events gesammelt.
+func (m *MockTaskRepository) Update(task *models.Task, events ...models.TaskEvent) (*models.Task, error) {
+	updated, err := m.UpdateFunc(task)
+	if err == nil {
+		m.Events = append(m.Events, events...)
+	}
+	return updated, err
 }
 
 // Delete ruft DeleteFunc auf und gibt das Ergebnis zurück.
-func (m *MockTaskRepository) Delete(id int) error {
-	return m.DeleteFunc(id)
+// Die Events werden nur bei Erfolg in Events gesammelt.
+func (m *MockTaskRepository) Delete(id int, events ...models.TaskEvent) error {
+	err := m.DeleteFunc(id)
+	if err == nil {
+		m.Events = append(m.Events, events...)
+	}
+	return err
+}
+
+// DeleteWithSubtasks ruft DeleteWithSubtasksFunc auf und gibt das Ergebnis zurück.
+// Die Events werden nur bei Erfolg in Events gesammelt.
+func (m *MockTaskRepository) DeleteWithSubtasks(id int, events ...models.TaskEvent) error {
+	err := m.DeleteWithSubtasksFunc(id)
+	if err == nil {
+		m.Events = append(m.Events, events...)
+	}
+	return err
+}
+
+// PurgeDeleted ruft PurgeDeletedFunc auf und gibt das Ergebnis zurück.
+func (m *MockTaskRepository) PurgeDeleted(before time.Time, dryRun bool) (int, []string, error) {
+	return m.PurgeDeletedFunc(before, dryRun)
+}
+
+// AddDependency ruft AddDependencyFunc auf und gibt das Ergebnis zurück.
+func (m *MockTaskRepository) AddDependency(taskID, dependsOnID int) error {
+	return m.AddDependencyFunc(taskID, dependsOnID)
+}
+
+// RemoveDependency ruft RemoveDependencyFunc auf und gibt das Ergebnis zurück.
+func (m *MockTaskRepository) RemoveDependency(taskID, dependsOnID int) (bool, error) {
+	return m.RemoveDependencyFunc(taskID, dependsOnID)
+}
+
+// GetDependencies ruft GetDependenciesFunc auf und gibt das Ergebnis zurück.
+// Ist GetDependenciesFunc nicht gesetzt, hat der Task keine Abhängigkeiten.
+func (m *MockTaskRepository) GetDependencies(taskID int) ([]*models.Task, error) {
+	if m.GetDependenciesFunc == nil {
+		return nil, nil
+	}
+	return m.GetDependenciesFunc(taskID)
+}
+
+// GetDependencyGraph ruft GetDependencyGraphFunc auf und gibt das Ergebnis zurück.
+// Ist GetDependencyGraphFunc nicht gesetzt, existieren keine Abhängigkeiten.
+func (m *MockTaskRepository) GetDependencyGraph() (map[int][]int, error) {
+	if m.GetDependencyGraphFunc == nil {
+		return map[int][]int{}, nil
+	}
+	return m.GetDependencyGraphFunc()
+}
+
+// ClaimDueReminders ruft ClaimDueRemindersFunc auf und gibt das Ergebnis zurück.
+// Die Events werden nur bei Erfolg einmal je Task in Events gesammelt.
+func (m *MockTaskRepository) ClaimDueReminders(until time.Time, events ...models.TaskEvent) ([]int, error) {
+	ids, err := m.ClaimDueRemindersFunc(until)
+	if err == nil {
+		for range ids {
+			m.Events = append(m.Events, events...)
+		}
+	}
+	return ids, err
+}
+
+// GetSeries ruft GetSeriesFunc auf und gibt das Ergebnis zurück.
+func (m *MockTaskRepository) GetSeries(seriesID int) ([]*models.Task, error) {
+	return m.GetSeriesFunc(seriesID)
+}
+
+// HasOccurrenceFrom ruft HasOccurrenceFromFunc auf und gibt das Ergebnis zurück.
+func (m *MockTaskRepository) HasOccurrenceFrom(seriesID int, from time.Time) (bool, error) {
+	return m.HasOccurrenceFromFunc(seriesID, from)
+}
+
+// GetLatestOccurrences ruft GetLatestOccurrencesFunc auf und gibt das Ergebnis zurück.
+func (m *MockTaskRepository) GetLatestOccurrences() ([]*models.Task, error) {
+	return m.GetLatestOccurrencesFunc()
+}
+
+// SliceCursor ist ein TaskCursor über eine Liste von Tasks für Tests.
+type SliceCursor struct {
+	Tasks []*models.Task
+
+	// Closed ist true, nachdem Close aufgerufen wurde.
+	Closed bool
+}
+
+// NewSliceCursor erstellt einen Cursor, der die übergebenen Tasks der Reihe nach liefert.
+func NewSliceCursor(tasks []*models.Task) *SliceCursor {
+	return &SliceCursor{Tasks: tasks}
+}
+
+// Next gibt den nächsten Task der Liste zurück, nil, nil am Ende.
+func (c *SliceCursor) Next() (*models.Task, error) {
+	if len(c.Tasks) == 0 {
+		return nil, nil
+	}
+	t := c.Tasks[0]
+	c.Tasks = c.Tasks[1:]
+	return t, nil
+}
+
+// Close vermerkt, dass der Cursor geschlossen wurde.
+func (c *SliceCursor) Close() error {
+	c.Closed = true
+	return nil
 }
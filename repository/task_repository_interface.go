@@ -1,22 +1,90 @@
 package repository
 
-import "task-api/models"
+import (
+	"task-api/models"
+	"time"
+)
 
 // TaskRepositoryInterface definiert die CRUD-Methoden, die jedes Repository implementieren muss.
 type TaskRepositoryInterface interface {
-	// Create speichert einen neuen Task und gibt den vollständigen Task zurück.
-	Create(task *models.Task) (*models.Task, error)
+	// Create speichert einen neuen Task und gibt den vollständigen Task zurück. Ein wiederkehrender Task
+	// ohne SeriesID beginnt eine neue Serie mit seiner ID. Events werden in derselben Transaktion in die
+	// Outbox geschrieben.
+	Create(task *models.Task, events ...models.TaskEvent) (*models.Task, error)
 
-	// GetAll gibt alle gespeicherten Tasks zurück.
-	GetAll() ([]*models.Task, error)
+	// CreateOccurrence speichert ein neues Vorkommen einer Serie wie Create. Existiert in der Serie bereits
+	// ein Vorkommen mit derselben Fälligkeit (auch ein gelöschtes), wird nil, nil zurückgegeben.
+	CreateOccurrence(task *models.Task, events ...models.TaskEvent) (*models.Task, error)
+
+	// GetAll gibt alle gespeicherten Tasks zurück, die den Filtern entsprechen.
+	GetAll(filter models.TaskFilter) ([]*models.Task, error)
+
+	// GetAllProjected gibt den Ausschnitt page der Tasks zurück, die den Filtern entsprechen, lädt aber nur
+	// die Felder der Projektion und bettet die angeforderten Beziehungen ein (Task.Relations).
+	// Zurückgegeben wird auch die Anzahl aller passenden Tasks.
+	GetAllProjected(filter models.TaskFilter, projection models.TaskProjection, page models.TaskPage) ([]*models.Task, int, error)
+
+	// OpenCursor öffnet einen Cursor über alle Tasks, die den Filtern entsprechen, und lädt nur die Felder
+	// der Projektion (ohne Beziehungen). Für Exporte, die nicht vollständig in den Speicher passen.
+	OpenCursor(filter models.TaskFilter, projection models.TaskProjection) (TaskCursor, error)
 
 	// GetByID gibt einen Task anhand seiner ID zurück.
 	// Gibt nil, nil zurück, wenn kein Task gefunden wird.
 	GetByID(id int) (*models.Task, error)
 
+	// GetSubtasks gibt alle direkten Subtasks eines Tasks zurück.
+	GetSubtasks(parentID int) ([]*models.Task, error)
+
+	// GetByIDs gibt alle Tasks mit den angegebenen IDs zurück (für das Batching der GraphQL-API).
+	GetByIDs(ids []int) ([]*models.Task, error)
+
+	// GetSubtasksOf gibt alle direkten Subtasks der angegebenen Tasks in einer Abfrage zurück.
+	GetSubtasksOf(parentIDs []int) ([]*models.Task, error)
+
+	// GetTree gibt einen Task inklusive aller Subtasks als verschachtelte Struktur zurück.
+	// Gibt nil, nil zurück, wenn kein Task gefunden wird.
+	GetTree(id int) (*models.TaskNode, error)
+
 	// Update aktualisiert einen bestehenden Task und gibt den aktualisierten Task zurück.
-	Update(task *models.Task) (*models.Task, error)
+	// Events werden in derselben Transaktion in die Outbox geschrieben.
+	Update(task *models.Task, events ...models.TaskEvent) (*models.Task, error)
+
+	// Delete markiert einen Task anhand seiner ID als gelöscht; er ist danach für alle Abfragen unsichtbar.
+	// Events werden mit dem Stand vor dem Löschen in derselben Transaktion in die Outbox geschrieben.
+	Delete(id int, events ...models.TaskEvent) error
+
+	// DeleteWithSubtasks markiert einen Task samt aller Subtasks als gelöscht.
+	// Events werden für jeden gelöschten Task in die Outbox geschrieben.
+	DeleteWithSubtasks(id int, events ...models.TaskEvent) error
+
+	// PurgeDeleted löscht alle vor before gelöschten Tasks endgültig und gibt deren Anzahl sowie die
+	// Storage-Keys ihrer Anhänge zurück. Mit dryRun wird nur gezählt.
+	PurgeDeleted(before time.Time, dryRun bool) (int, []string, error)
+
+	// AddDependency speichert, dass taskID von dependsOnID abhängt.
+	AddDependency(taskID, dependsOnID int) error
+
+	// RemoveDependency entfernt eine Abhängigkeit.
+	// Gibt false zurück, wenn die Abhängigkeit nicht existiert hat.
+	RemoveDependency(taskID, dependsOnID int) (bool, error)
+
+	// GetDependencies gibt alle Tasks zurück, von denen taskID direkt abhängt.
+	GetDependencies(taskID int) ([]*models.Task, error)
+
+	// ClaimDueReminders vermerkt für alle offenen Tasks, die bis until fällig werden und noch keine
+	// Erinnerung erhalten haben, den Versand und schreibt die Events in die Outbox. Jeder Task wird
+	// auch bei mehreren Instanzen nur einmal zurückgegeben.
+	ClaimDueReminders(until time.Time, events ...models.TaskEvent) ([]int, error)
+
+	// GetSeries gibt alle Vorkommen einer wiederkehrenden Serie sortiert nach Fälligkeit zurück.
+	GetSeries(seriesID int) ([]*models.Task, error)
+
+	// HasOccurrenceFrom meldet, ob die Serie ein Vorkommen (auch ein gelöschtes) mit Fälligkeit ab from hat.
+	HasOccurrenceFrom(seriesID int, from time.Time) (bool, error)
+
+	// GetLatestOccurrences gibt pro wiederkehrender Serie das zuletzt fällige Vorkommen zurück.
+	GetLatestOccurrences() ([]*models.Task, error)
 
-	// Delete entfernt einen Task anhand seiner ID.
-	Delete(id int) error
+	// GetDependencyGraph gibt alle Abhängigkeiten als Adjazenzliste (Task-ID -> abhängig von) zurück.
+	GetDependencyGraph() (map[int][]int, error)
 }
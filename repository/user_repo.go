@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"task-api/models"
+)
+
+// PostgresUserRepository implementiert die Persistenzschicht für Benutzer
+// und deren Zuordnung zu Tasks (Tabellen task_assignees und task_watchers).
+type PostgresUserRepository struct {
+	DB *sql.DB
+}
+
+// userSelect ist die gemeinsame SELECT-Klausel für alle Benutzer-Abfragen.
+const userSelect = `SELECT id, name, email, tenant, created_at FROM users`
+
+// Create speichert einen neuen Benutzer in der Datenbank.
+func (r *PostgresUserRepository) Create(user *models.User, apiKeyHash string) (*models.User, error) {
+	err := r.DB.QueryRow(`INSERT INTO users (name, email, tenant, api_key_hash) VALUES ($1, $2, $3, $4)
+	                      RETURNING id, created_at`, user.Name, user.Email, user.Tenant, apiKeyHash).
+		Scan(&user.ID, &user.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetAll gibt alle Benutzer eines Mandanten sortiert nach ID zurück.
+func (r *PostgresUserRepository) GetAll(tenant string) ([]*models.User, error) {
+	return r.queryUsers(userSelect+` WHERE tenant = $1 ORDER BY id`, tenant)
+}
+
+// GetByEmails gibt alle Benutzer zurück, deren E-Mail-Adresse in emails enthalten ist.
+func (r *PostgresUserRepository) GetByEmails(emails []string) ([]*models.User, error) {
+	return r.queryUsers(userSelect+` WHERE email = ANY($1) ORDER BY id`, pq.Array(emails))
+}
+
+// GetByIDs gibt alle Benutzer mit den angegebenen IDs zurück.
+func (r *PostgresUserRepository) GetByIDs(ids []int) ([]*models.User, error) {
+	return r.queryUsers(userSelect+` WHERE id = ANY($1) ORDER BY id`, pq.Array(ids))
+}
+
+// queryUsers führt eine Abfrage aus und liest alle Zeilen als Benutzer ein.
+func (r *PostgresUserRepository) queryUsers(query string, args ...any) ([]*models.User, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user := &models.User{}
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Tenant, &user.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// GetByID gibt einen Benutzer anhand der ID zurück.
+// Gibt nil zurück, wenn kein Benutzer mit der ID existiert.
+func (r *PostgresUserRepository) GetByID(id int) (*models.User, error) {
+	return r.getOne(userSelect+` WHERE id = $1`, id)
+}
+
+// GetByEmail gibt einen Benutzer anhand der E-Mail-Adresse zurück.
+// Gibt nil zurück, wenn kein Benutzer mit der Adresse existiert.
+func (r *PostgresUserRepository) GetByEmail(email string) (*models.User, error) {
+	return r.getOne(userSelect+` WHERE email = $1`, email)
+}
+
+// GetByAPIKeyHash gibt den Benutzer zu einem API-Key-Hash zurück.
+// Gibt nil zurück, wenn kein Benutzer diesen Key besitzt.
+func (r *PostgresUserRepository) GetByAPIKeyHash(hash string) (*models.User, error) {
+	return r.getOne(userSelect+` WHERE api_key_hash = $1`, hash)
+}
+
+// SetAPIKeyHash ersetzt den Hash des API-Keys eines Benutzers.
+func (r *PostgresUserRepository) SetAPIKeyHash(userID int, hash string) error {
+	_, err := r.DB.Exec(`UPDATE users SET api_key_hash = $2 WHERE id = $1`, userID, hash)
+	return err
+}
+
+// SetCalendarTokenHash speichert den Hash des Kalender-Tokens eines Benutzers; "" entfernt das Token.
+func (r *PostgresUserRepository) SetCalendarTokenHash(userID int, hash string) error {
+	_, err := r.DB.Exec(`UPDATE users SET calendar_token_hash = NULLIF($2, '') WHERE id = $1`, userID, hash)
+	return err
+}
+
+// GetByCalendarTokenHash gibt den Benutzer zu einem Kalender-Token-Hash zurück.
+// Gibt nil zurück, wenn kein Benutzer dieses Token besitzt.
+func (r *PostgresUserRepository) GetByCalendarTokenHash(hash string) (*models.User, error) {
+	return r.getOne(userSelect+` WHERE calendar_token_hash = $1`, hash)
+}
+
+// getOne führt eine Abfrage aus, die höchstens einen Benutzer liefert.
+func (r *PostgresUserRepository) getOne(query string, arg any) (*models.User, error) {
+	user := &models.User{}
+	err := r.DB.QueryRow(query, arg).Scan(&user.ID, &user.Name, &user.Email, &user.Tenant, &user.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// AddAssignee weist einen Task einem Benutzer zu. Existiert die Zuordnung bereits, passiert nichts.
+// Übergebene Events werden bei einer neuen Zuweisung in derselben Transaktion in die Outbox geschrieben.
+func (r *PostgresUserRepository) AddAssignee(taskID, userID int, events ...models.TaskEvent) error {
+	_, err := execTaskChange(r.DB, taskID, events,
+		`INSERT INTO task_assignees (task_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, taskID, userID)
+	return err
+}
+
+// RemoveAssignee entfernt die Zuweisung eines Benutzers zu einem Task.
+// Übergebene Events werden in derselben Transaktion in die Outbox geschrieben.
+// Gibt false zurück, wenn die Zuweisung nicht existiert hat.
+func (r *PostgresUserRepository) RemoveAssignee(taskID, userID int, events ...models.TaskEvent) (bool, error) {
+	return execTaskChange(r.DB, taskID, events, `DELETE FROM task_assignees WHERE task_id = $1 AND user_id = $2`, taskID, userID)
+}
+
+// AddWatcher trägt einen Benutzer als Beobachter eines Tasks ein. Existiert der Eintrag bereits, passiert nichts.
+func (r *PostgresUserRepository) AddWatcher(taskID, userID int) error {
+	_, err := r.DB.Exec(`INSERT INTO task_watchers (task_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, taskID, userID)
+	return err
+}
+
+// RemoveWatcher entfernt einen Beobachter eines Tasks.
+// Gibt false zurück, wenn der Benutzer den Task nicht beobachtet hat.
+func (r *PostgresUserRepository) RemoveWatcher(taskID, userID int) (bool, error) {
+	return r.remove(`DELETE FROM task_watchers WHERE task_id = $1 AND user_id = $2`, taskID, userID)
+}
+
+// remove führt ein DELETE aus und meldet, ob dabei eine Zeile entfernt wurde.
+func (r *PostgresUserRepository) remove(query string, taskID, userID int) (bool, error) {
+	res, err := r.DB.Exec(query, taskID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
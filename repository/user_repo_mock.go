@@ -0,0 +1,132 @@
+package repository
+
+import "task-api/models"
+
+// MockUserRepository ist ein Mock des UserRepositoryInterface für Tests.
+// Jede Methode wird durch eine Funktion ersetzt, die individuell gesetzt werden kann.
+type MockUserRepository struct {
+	// CreateFunc simuliert das Erstellen eines Benutzers.
+	CreateFunc func(user *models.User, apiKeyHash string) (*models.User, error)
+
+	// GetAllFunc simuliert das Abrufen aller Benutzer eines Mandanten.
+	GetAllFunc func(tenant string) ([]*models.User, error)
+
+	// GetByIdFunc simuliert das Abrufen eines Benutzers anhand der ID.
+	GetByIdFunc func(id int) (*models.User, error)
+
+	// GetByEmailFunc simuliert das Abrufen eines Benutzers anhand der E-Mail-Adresse.
+	GetByEmailFunc func(email string) (*models.User, error)
+
+	// GetByEmailsFunc simuliert das Abrufen mehrerer Benutzer anhand ihrer E-Mail-Adressen.
+	GetByEmailsFunc func(emails []string) ([]*models.User, error)
+
+	// GetByIDsFunc simuliert das Abrufen mehrerer Benutzer anhand ihrer IDs.
+	GetByIDsFunc func(ids []int) ([]*models.User, error)
+
+	// GetByAPIKeyHashFunc simuliert das Abrufen eines Benutzers anhand des API-Key-Hashes.
+	GetByAPIKeyHashFunc func(hash string) (*models.User, error)
+
+	// SetAPIKeyHashFunc simuliert das Ersetzen des API-Keys.
+	SetAPIKeyHashFunc func(userID int, hash string) error
+
+	// SetCalendarTokenHashFunc simuliert das Speichern bzw. Entfernen eines Kalender-Tokens.
+	SetCalendarTokenHashFunc func(userID int, hash string) error
+
+	// GetByCalendarTokenHashFunc simuliert das Abrufen eines Benutzers anhand des Kalender-Token-Hashes.
+	GetByCalendarTokenHashFunc func(hash string) (*models.User, error)
+
+	// AddAssigneeFunc simuliert das Zuweisen eines Tasks.
+	AddAssigneeFunc func(taskID, userID int) error
+
+	// RemoveAssigneeFunc simuliert das Entfernen einer Zuweisung.
+	RemoveAssigneeFunc func(taskID, userID int) (bool, error)
+
+	// AddWatcherFunc simuliert das Eintragen eines Beobachters.
+	AddWatcherFunc func(taskID, userID int) error
+
+	// RemoveWatcherFunc simuliert das Entfernen eines Beobachters.
+	RemoveWatcherFunc func(taskID, userID int) (bool, error)
+
+	// Events sammelt alle Events, die an AddAssignee und RemoveAssignee übergeben wurden.
+	Events []models.TaskEvent
+}
+
+// Create ruft CreateFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) Create(user *models.User, apiKeyHash string) (*models.User, error) {
+	return m.CreateFunc(user, apiKeyHash)
+}
+
+// GetAll ruft GetAllFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) GetAll(tenant string) ([]*models.User, error) {
+	return m.GetAllFunc(tenant)
+}
+
+// GetByID ruft GetByIdFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) GetByID(id int) (*models.User, error) {
+	return m.GetByIdFunc(id)
+}
+
+// GetByEmail ruft GetByEmailFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
+	return m.GetByEmailFunc(email)
+}
+
+// GetByIDs ruft GetByIDsFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) GetByIDs(ids []int) ([]*models.User, error) {
+	return m.GetByIDsFunc(ids)
+}
+
+// GetByEmails ruft GetByEmailsFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) GetByEmails(emails []string) ([]*models.User, error) {
+	return m.GetByEmailsFunc(emails)
+}
+
+// GetByAPIKeyHash ruft GetByAPIKeyHashFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) GetByAPIKeyHash(hash string) (*models.User, error) {
+	return m.GetByAPIKeyHashFunc(hash)
+}
+
+// SetAPIKeyHash ruft SetAPIKeyHashFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) SetAPIKeyHash(userID int, hash string) error {
+	return m.SetAPIKeyHashFunc(userID, hash)
+}
+
+// SetCalendarTokenHash ruft SetCalendarTokenHashFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) SetCalendarTokenHash(userID int, hash string) error {
+	return m.SetCalendarTokenHashFunc(userID, hash)
+}
+
+// GetByCalendarTokenHash ruft GetByCalendarTokenHashFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) GetByCalendarTokenHash(hash string) (*models.User, error) {
+	return m.GetByCalendarTokenHashFunc(hash)
+}
+
+// AddAssignee ruft AddAssigneeFunc auf und gibt das Ergebnis zurück.
+// Die Events werden nur bei Erfolg in Events gesammelt.
+func (m *MockUserRepository) AddAssignee(taskID, userID int, events ...models.TaskEvent) error {
+	err := m.AddAssigneeFunc(taskID, userID)
+	if err == nil {
+		m.Events = append(m.Events, events...)
+	}
+	return err
+}
+
+// RemoveAssignee ruft RemoveAssigneeFunc auf und gibt das Ergebnis zurück.
+// Die Events werden nur gesammelt, wenn eine Zuweisung entfernt wurde.
+func (m *MockUserRepository) RemoveAssignee(taskID, userID int, events ...models.TaskEvent) (bool, error) {
+	removed, err := m.RemoveAssigneeFunc(taskID, userID)
+	if err == nil && removed {
+		m.Events = append(m.Events, events...)
+	}
+	return removed, err
+}
+
+// AddWatcher ruft AddWatcherFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) AddWatcher(taskID, userID int) error {
+	return m.AddWatcherFunc(taskID, userID)
+}
+
+// RemoveWatcher ruft RemoveWatcherFunc auf und gibt das Ergebnis zurück.
+func (m *MockUserRepository) RemoveWatcher(taskID, userID int) (bool, error) {
+	return m.RemoveWatcherFunc(taskID, userID)
+}
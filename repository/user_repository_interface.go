@@ -0,0 +1,57 @@
+package repository
+
+import "task-api/models"
+
+// UserRepositoryInterface definiert die Methoden zur Verwaltung von Benutzern
+// sowie ihrer Zuordnung zu Tasks als Verantwortliche oder Beobachter.
+type UserRepositoryInterface interface {
+	// Create speichert einen neuen Benutzer mit dem Hash seines API-Keys.
+	Create(user *models.User, apiKeyHash string) (*models.User, error)
+
+	// GetAll gibt alle Benutzer eines Mandanten sortiert nach ID zurück.
+	GetAll(tenant string) ([]*models.User, error)
+
+	// GetByID gibt einen Benutzer anhand seiner ID zurück.
+	// Gibt nil, nil zurück, wenn kein Benutzer gefunden wird.
+	GetByID(id int) (*models.User, error)
+
+	// GetByEmail gibt einen Benutzer anhand seiner E-Mail-Adresse zurück.
+	// Gibt nil, nil zurück, wenn kein Benutzer gefunden wird.
+	GetByEmail(email string) (*models.User, error)
+
+	// GetByEmails gibt alle Benutzer zurück, deren E-Mail-Adresse in emails enthalten ist.
+	GetByEmails(emails []string) ([]*models.User, error)
+
+	// GetByIDs gibt alle Benutzer mit den angegebenen IDs zurück.
+	GetByIDs(ids []int) ([]*models.User, error)
+
+	// GetByAPIKeyHash gibt den Benutzer zu einem API-Key-Hash zurück.
+	// Gibt nil, nil zurück, wenn der Key unbekannt ist.
+	GetByAPIKeyHash(hash string) (*models.User, error)
+
+	// SetAPIKeyHash ersetzt den Hash des API-Keys eines Benutzers; der bisherige Key wird damit ungültig.
+	SetAPIKeyHash(userID int, hash string) error
+
+	// SetCalendarTokenHash speichert den Hash des Kalender-Tokens eines Benutzers; "" entfernt das Token.
+	SetCalendarTokenHash(userID int, hash string) error
+
+	// GetByCalendarTokenHash gibt den Benutzer zu einem Kalender-Token-Hash zurück.
+	// Gibt nil, nil zurück, wenn das Token unbekannt ist.
+	GetByCalendarTokenHash(hash string) (*models.User, error)
+
+	// AddAssignee weist einen Task einem Benutzer zu. Existiert die Zuordnung bereits, passiert nichts.
+	// Die Events werden bei einer neuen Zuweisung transaktional in die Outbox geschrieben.
+	AddAssignee(taskID, userID int, events ...models.TaskEvent) error
+
+	// RemoveAssignee entfernt die Zuweisung eines Benutzers.
+	// Die Events werden transaktional in die Outbox geschrieben.
+	// Gibt false zurück, wenn die Zuweisung nicht existiert hat.
+	RemoveAssignee(taskID, userID int, events ...models.TaskEvent) (bool, error)
+
+	// AddWatcher trägt einen Benutzer als Beobachter eines Tasks ein. Existiert der Eintrag bereits, passiert nichts.
+	AddWatcher(taskID, userID int) error
+
+	// RemoveWatcher entfernt einen Beobachter.
+	// Gibt false zurück, wenn der Benutzer den Task nicht beobachtet hat.
+	RemoveWatcher(taskID, userID int) (bool, error)
+}
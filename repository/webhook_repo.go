@@ -0,0 +1,351 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"task-api/models"
+	"time"
+)
+
+// PostgresWebhookRepository implementiert die Persistenzschicht für Webhooks, die Outbox
+// und die Zustellungen inklusive Retry-Zustand und Protokoll.
+type PostgresWebhookRepository struct {
+	DB *sql.DB
+}
+
+// webhookSelect ist die gemeinsame SELECT-Klausel für alle Webhook-Abfragen.
+const webhookSelect = `SELECT id, url, secret, events, active, created_at, updated_at FROM webhooks`
+
+// scanWebhook liest eine Zeile im Format von webhookSelect in einen Webhook ein.
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	w := &models.Webhook{}
+	err := row.Scan(&w.ID, &w.URL, &w.Secret, pq.Array(&w.Events), &w.Active, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if w.Events == nil {
+		w.Events = []string{}
+	}
+	return w, nil
+}
+
+// deliverySelect ist die gemeinsame SELECT-Klausel für alle Zustellungs-Abfragen.
+// Der Payload stammt aus dem zugehörigen Outbox-Eintrag.
+const deliverySelect = `SELECT d.id, d.webhook_id, d.event_id, d.event_type, o.payload, d.status, d.attempts,
+	       d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.updated_at
+	  FROM webhook_deliveries d
+	  JOIN outbox o ON o.id = d.event_id`
+
+// scanDelivery liest eine Zeile im Format von deliverySelect in eine Zustellung ein.
+func scanDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	d := &models.WebhookDelivery{}
+	var nextAttemptAt sql.NullTime
+	var lastStatusCode sql.NullInt64
+	err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.Event, &d.Payload, &d.Status, &d.Attempts,
+		&nextAttemptAt, &lastStatusCode, &d.LastError, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	d.NextAttemptAt = nullTimePtr(nextAttemptAt)
+	d.LastStatusCode = nullIntPtr(lastStatusCode)
+	return d, nil
+}
+
+// Create speichert einen neuen Webhook und gibt ihn inklusive ID zurück.
+func (r *PostgresWebhookRepository) Create(webhook *models.Webhook) (*models.Webhook, error) {
+	err := r.DB.QueryRow(`INSERT INTO webhooks (url, secret, events, active) VALUES ($1, $2, $3, $4)
+	                      RETURNING id, created_at, updated_at`,
+		webhook.URL, webhook.Secret, pq.Array(webhook.Events), webhook.Active).
+		Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// GetAll gibt alle Webhooks sortiert nach ID zurück.
+func (r *PostgresWebhookRepository) GetAll() ([]*models.Webhook, error) {
+	rows, err := r.DB.Query(webhookSelect + ` ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*models.Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+// GetByID gibt einen Webhook anhand der ID zurück.
+// Gibt nil zurück, wenn kein Webhook mit der ID existiert.
+func (r *PostgresWebhookRepository) GetByID(id int) (*models.Webhook, error) {
+	w, err := scanWebhook(r.DB.QueryRow(webhookSelect+` WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Update speichert URL, Secret, Events und Active eines bestehenden Webhooks.
+func (r *PostgresWebhookRepository) Update(webhook *models.Webhook) (*models.Webhook, error) {
+	err := r.DB.QueryRow(`UPDATE webhooks SET url = $1, secret = $2, events = $3, active = $4, updated_at = NOW()
+	                      WHERE id = $5 RETURNING created_at, updated_at`,
+		webhook.URL, webhook.Secret, pq.Array(webhook.Events), webhook.Active, webhook.ID).
+		Scan(&webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Delete entfernt einen Webhook; Zustellungen und Protokoll werden per CASCADE gelöscht.
+// Gibt false zurück, wenn kein Webhook mit der ID existiert hat.
+func (r *PostgresWebhookRepository) Delete(id int) (bool, error) {
+	res, err := r.DB.Exec(`DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// queryDeliveries führt eine Abfrage aus, liest alle Zeilen als Zustellungen ein
+// und lädt deren Protokoll mit einer einzigen weiteren Abfrage nach.
+func (r *PostgresWebhookRepository) queryDeliveries(query string, args ...any) ([]*models.WebhookDelivery, error) {
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	byID := map[int]*models.WebhookDelivery{}
+	var ids []int
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+		byID[d.ID] = d
+		ids = append(ids, d.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return deliveries, nil
+	}
+
+	attempts, err := r.DB.Query(`SELECT delivery_id, attempt, status_code, error, duration_ms, created_at
+	                               FROM webhook_delivery_attempts
+	                              WHERE delivery_id = ANY($1)
+	                              ORDER BY delivery_id, attempt`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer attempts.Close()
+
+	for attempts.Next() {
+		var deliveryID int
+		var statusCode sql.NullInt64
+		var a models.DeliveryAttempt
+		if err := attempts.Scan(&deliveryID, &a.Attempt, &statusCode, &a.Error, &a.DurationMs, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		a.StatusCode = nullIntPtr(statusCode)
+		byID[deliveryID].Log = append(byID[deliveryID].Log, a)
+	}
+	return deliveries, attempts.Err()
+}
+
+// GetDeliveries gibt die letzten limit Zustellungen eines Webhooks (neueste zuerst) zurück.
+func (r *PostgresWebhookRepository) GetDeliveries(webhookID, limit int) ([]*models.WebhookDelivery, error) {
+	return r.queryDeliveries(deliverySelect+` WHERE d.webhook_id = $1 ORDER BY d.id DESC LIMIT $2`, webhookID, limit)
+}
+
+// GetDeadLetters gibt die letzten limit endgültig gescheiterten Zustellungen zurück.
+func (r *PostgresWebhookRepository) GetDeadLetters(limit int) ([]*models.WebhookDelivery, error) {
+	return r.queryDeliveries(deliverySelect+` WHERE d.status = $1 ORDER BY d.updated_at DESC, d.id DESC LIMIT $2`,
+		models.DeliveryDead, limit)
+}
+
+// GetDelivery gibt eine Zustellung anhand der ID zurück.
+// Gibt nil zurück, wenn keine Zustellung mit der ID existiert.
+func (r *PostgresWebhookRepository) GetDelivery(id int) (*models.WebhookDelivery, error) {
+	deliveries, err := r.queryDeliveries(deliverySelect+` WHERE d.id = $1`, id)
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+	return deliveries[0], nil
+}
+
+// RetryDelivery setzt eine Zustellung auf pending zurück und plant sie sofort ein.
+// Der Versuchszähler beginnt von vorn, das Protokoll bleibt erhalten.
+func (r *PostgresWebhookRepository) RetryDelivery(id int) error {
+	_, err := r.DB.Exec(`UPDATE webhook_deliveries
+	                        SET status = $1, attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+	                      WHERE id = $2`, models.DeliveryPending, id)
+	return err
+}
+
+// DispatchEvents verteilt noch nicht verteilte Outbox-Ereignisse auf die passenden Webhooks.
+// Die Ereignisse werden mit SKIP LOCKED reserviert, damit mehrere Instanzen parallel arbeiten können.
+func (r *PostgresWebhookRepository) DispatchEvents(limit int) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id FROM outbox WHERE dispatched_at IS NULL
+	                        ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return 0, err
+	}
+	var ids pq.Int64Array
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(`INSERT INTO webhook_deliveries (webhook_id, event_id, event_type)
+	                  SELECT w.id, o.id, o.event_type
+	                    FROM outbox o
+	                    JOIN webhooks w ON w.active AND (cardinality(w.events) = 0 OR o.event_type = ANY(w.events))
+	                   WHERE o.id = ANY($1)
+	                  ON CONFLICT (webhook_id, event_id) DO NOTHING`, ids)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`UPDATE outbox SET dispatched_at = NOW() WHERE id = ANY($1)`, ids); err != nil {
+		return 0, err
+	}
+	return len(ids), tx.Commit()
+}
+
+// ClaimDueDeliveries reserviert fällige Zustellungen aktiver Webhooks, indem next_attempt_at um
+// lease in die Zukunft verschoben wird. Stürzt eine Instanz ab, wird die Zustellung danach erneut versucht.
+func (r *PostgresWebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]*models.DeliveryJob, error) {
+	query := `WITH claimed AS (
+	              UPDATE webhook_deliveries d
+	                 SET next_attempt_at = NOW() + make_interval(secs => $1)
+	               WHERE d.id IN (
+	                     SELECT d2.id FROM webhook_deliveries d2
+	                       JOIN webhooks w ON w.id = d2.webhook_id AND w.active
+	                      WHERE d2.status = $2 AND d2.next_attempt_at <= NOW()
+	                      ORDER BY d2.next_attempt_at
+	                      LIMIT $3
+	                      FOR UPDATE OF d2 SKIP LOCKED)
+	              RETURNING d.*
+	          )
+	          SELECT c.id, c.webhook_id, c.event_id, c.event_type, o.payload, c.status, c.attempts,
+	                 c.next_attempt_at, c.last_status_code, c.last_error, c.created_at, c.updated_at,
+	                 w.url, w.secret
+	            FROM claimed c
+	            JOIN outbox o ON o.id = c.event_id
+	            JOIN webhooks w ON w.id = c.webhook_id
+	           ORDER BY c.id`
+
+	rows, err := r.DB.Query(query, lease.Seconds(), models.DeliveryPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*models.DeliveryJob
+	for rows.Next() {
+		job := &models.DeliveryJob{}
+		var nextAttemptAt sql.NullTime
+		var lastStatusCode sql.NullInt64
+		err := rows.Scan(&job.ID, &job.WebhookID, &job.EventID, &job.Event, &job.Payload, &job.Status, &job.Attempts,
+			&nextAttemptAt, &lastStatusCode, &job.LastError, &job.CreatedAt, &job.UpdatedAt, &job.URL, &job.Secret)
+		if err != nil {
+			return nil, err
+		}
+		job.NextAttemptAt = nullTimePtr(nextAttemptAt)
+		job.LastStatusCode = nullIntPtr(lastStatusCode)
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// RecordAttempt protokolliert einen Zustellversuch und aktualisiert die Zustellung in einer Transaktion.
+// nextAttemptAt ist nil, wenn die Zustellung abgeschlossen ist (succeeded oder dead).
+// Die Zustellung wird nur geändert, solange sie noch pending ist und attempt.Attempt-1 Versuche hat, also
+// seit der Reservierung niemand (z.B. eine andere Instanz nach Ablauf der Reservierung) einen Versuch
+// protokolliert hat. Andernfalls wird nichts geschrieben und ErrClaimLost zurückgegeben.
+func (r *PostgresWebhookRepository) RecordAttempt(deliveryID int, attempt models.DeliveryAttempt, status string, nextAttemptAt *time.Time) error {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE webhook_deliveries
+	                        SET status = $1, attempts = $2, next_attempt_at = $3, last_status_code = $4,
+	                            last_error = $5, updated_at = NOW()
+	                      WHERE id = $6 AND status = $7 AND attempts = $8`,
+		status, attempt.Attempt, nextAttemptAt, attempt.StatusCode, attempt.Error, deliveryID,
+		models.DeliveryPending, attempt.Attempt-1)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrClaimLost
+	}
+	_, err = tx.Exec(`INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, duration_ms, created_at)
+	                  VALUES ($1, $2, $3, $4, $5, $6)`,
+		deliveryID, attempt.Attempt, attempt.StatusCode, attempt.Error, attempt.DurationMs, attempt.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// PurgeEvents löscht alte, verteilte Ereignisse ohne offene Zustellungen. Zustellungen und Versuche
+// entfallen per ON DELETE CASCADE. Mit dryRun wird die Transaktion zurückgerollt.
+func (r *PostgresWebhookRepository) PurgeEvents(before time.Time, dryRun bool) (int, error) {
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM outbox o
+	                      WHERE o.dispatched_at IS NOT NULL AND o.created_at < $1
+	                        AND NOT EXISTS (SELECT 1 FROM webhook_deliveries d WHERE d.event_id = o.id AND d.status = 'pending')`, before)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if dryRun {
+		return int(n), nil
+	}
+	return int(n), tx.Commit()
+}
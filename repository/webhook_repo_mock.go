@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"task-api/models"
+	"time"
+)
+
+// MockWebhookRepository ist ein Mock des WebhookRepositoryInterface für Tests.
+// Jede Methode wird durch eine Funktion ersetzt, die individuell gesetzt werden kann.
+type MockWebhookRepository struct {
+	// CreateFunc simuliert das Erstellen eines Webhooks.
+	CreateFunc func(webhook *models.Webhook) (*models.Webhook, error)
+
+	// GetAllFunc simuliert das Abrufen aller Webhooks.
+	GetAllFunc func() ([]*models.Webhook, error)
+
+	// GetByIdFunc simuliert das Abrufen eines Webhooks anhand der ID.
+	GetByIdFunc func(id int) (*models.Webhook, error)
+
+	// UpdateFunc simuliert das Aktualisieren eines Webhooks.
+	UpdateFunc func(webhook *models.Webhook) (*models.Webhook, error)
+
+	// DeleteFunc simuliert das Löschen eines Webhooks.
+	DeleteFunc func(id int) (bool, error)
+
+	// GetDeliveriesFunc simuliert das Abrufen der Zustellungen eines Webhooks.
+	GetDeliveriesFunc func(webhookID, limit int) ([]*models.WebhookDelivery, error)
+
+	// GetDeadLettersFunc simuliert das Abrufen gescheiterter Zustellungen.
+	GetDeadLettersFunc func(limit int) ([]*models.WebhookDelivery, error)
+
+	// GetDeliveryFunc simuliert das Abrufen einer Zustellung anhand der ID.
+	GetDeliveryFunc func(id int) (*models.WebhookDelivery, error)
+
+	// RetryDeliveryFunc simuliert das Zurücksetzen einer Zustellung.
+	RetryDeliveryFunc func(id int) error
+
+	// DispatchEventsFunc simuliert das Verteilen der Outbox-Ereignisse.
+	DispatchEventsFunc func(limit int) (int, error)
+
+	// ClaimDueDeliveriesFunc simuliert das Reservieren fälliger Zustellungen.
+	ClaimDueDeliveriesFunc func(limit int, lease time.Duration) ([]*models.DeliveryJob, error)
+
+	// RecordAttemptFunc simuliert das Protokollieren eines Zustellversuchs.
+	RecordAttemptFunc func(deliveryID int, attempt models.DeliveryAttempt, status string, nextAttemptAt *time.Time) error
+
+	// PurgeEventsFunc simuliert das Löschen alter Outbox-Ereignisse.
+	PurgeEventsFunc func(before time.Time, dryRun bool) (int, error)
+}
+
+// Create ruft CreateFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) Create(webhook *models.Webhook) (*models.Webhook, error) {
+	return m.CreateFunc(webhook)
+}
+
+// GetAll ruft GetAllFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) GetAll() ([]*models.Webhook, error) {
+	return m.GetAllFunc()
+}
+
+// GetByID ruft GetByIdFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) GetByID(id int) (*models.Webhook, error) {
+	return m.GetByIdFunc(id)
+}
+
+// Update ruft UpdateFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) Update(webhook *models.Webhook) (*models.Webhook, error) {
+	return m.UpdateFunc(webhook)
+}
+
+// Delete ruft DeleteFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) Delete(id int) (bool, error) {
+	return m.DeleteFunc(id)
+}
+
+// GetDeliveries ruft GetDeliveriesFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) GetDeliveries(webhookID, limit int) ([]*models.WebhookDelivery, error) {
+	return m.GetDeliveriesFunc(webhookID, limit)
+}
+
+// GetDeadLetters ruft GetDeadLettersFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) GetDeadLetters(limit int) ([]*models.WebhookDelivery, error) {
+	return m.GetDeadLettersFunc(limit)
+}
+
+// GetDelivery ruft GetDeliveryFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) GetDelivery(id int) (*models.WebhookDelivery, error) {
+	return m.GetDeliveryFunc(id)
+}
+
+// RetryDelivery ruft RetryDeliveryFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) RetryDelivery(id int) error {
+	return m.RetryDeliveryFunc(id)
+}
+
+// DispatchEvents ruft DispatchEventsFunc auf und gibt das Ergebnis zurück.
+// Ist DispatchEventsFunc nicht gesetzt, gibt es keine neuen Ereignisse.
+func (m *MockWebhookRepository) DispatchEvents(limit int) (int, error) {
+	if m.DispatchEventsFunc == nil {
+		return 0, nil
+	}
+	return m.DispatchEventsFunc(limit)
+}
+
+// ClaimDueDeliveries ruft ClaimDueDeliveriesFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]*models.DeliveryJob, error) {
+	return m.ClaimDueDeliveriesFunc(limit, lease)
+}
+
+// RecordAttempt ruft RecordAttemptFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) RecordAttempt(deliveryID int, attempt models.DeliveryAttempt, status string, nextAttemptAt *time.Time) error {
+	return m.RecordAttemptFunc(deliveryID, attempt, status, nextAttemptAt)
+}
+
+// PurgeEvents ruft PurgeEventsFunc auf und gibt das Ergebnis zurück.
+func (m *MockWebhookRepository) PurgeEvents(before time.Time, dryRun bool) (int, error) {
+	return m.PurgeEventsFunc(before, dryRun)
+}
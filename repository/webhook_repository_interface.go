@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"task-api/models"
+	"time"
+)
+
+// ErrClaimLost gibt RecordAttempt zurück, wenn die Zustellung nicht mehr von diesem Versuch reserviert ist.
+var ErrClaimLost = errors.New("delivery claim lost")
+
+// WebhookRepositoryInterface definiert die Methoden zur Verwaltung von Webhooks,
+// zur Verteilung der Outbox-Ereignisse und zur Protokollierung der Zustellungen.
+type WebhookRepositoryInterface interface {
+	// Create speichert einen neuen Webhook und gibt ihn inklusive ID zurück.
+	Create(webhook *models.Webhook) (*models.Webhook, error)
+
+	// GetAll gibt alle Webhooks sortiert nach ID zurück.
+	GetAll() ([]*models.Webhook, error)
+
+	// GetByID gibt einen Webhook anhand seiner ID zurück.
+	// Gibt nil, nil zurück, wenn kein Webhook gefunden wird.
+	GetByID(id int) (*models.Webhook, error)
+
+	// Update speichert URL, Secret, Events und Active eines bestehenden Webhooks.
+	Update(webhook *models.Webhook) (*models.Webhook, error)
+
+	// Delete entfernt einen Webhook samt Zustellungen.
+	// Gibt false zurück, wenn der Webhook nicht existiert hat.
+	Delete(id int) (bool, error)
+
+	// GetDeliveries gibt die letzten limit Zustellungen eines Webhooks (neueste zuerst) inklusive
+	// Protokoll der Zustellversuche zurück.
+	GetDeliveries(webhookID, limit int) ([]*models.WebhookDelivery, error)
+
+	// GetDeadLetters gibt die letzten limit endgültig gescheiterten Zustellungen aller Webhooks zurück.
+	GetDeadLetters(limit int) ([]*models.WebhookDelivery, error)
+
+	// GetDelivery gibt eine Zustellung anhand ihrer ID zurück.
+	// Gibt nil, nil zurück, wenn keine Zustellung gefunden wird.
+	GetDelivery(id int) (*models.WebhookDelivery, error)
+
+	// RetryDelivery setzt eine Zustellung auf pending zurück, damit sie sofort erneut versucht wird.
+	RetryDelivery(id int) error
+
+	// DispatchEvents verteilt bis zu limit noch nicht verteilte Outbox-Ereignisse auf alle aktiven
+	// Webhooks, die das Ereignis abonniert haben. Gibt die Anzahl verteilter Ereignisse zurück.
+	DispatchEvents(limit int) (int, error)
+
+	// ClaimDueDeliveries reserviert bis zu limit fällige Zustellungen für die Dauer lease,
+	// damit sie von keiner anderen Instanz gleichzeitig versendet werden.
+	ClaimDueDeliveries(limit int, lease time.Duration) ([]*models.DeliveryJob, error)
+
+	// RecordAttempt protokolliert einen Zustellversuch und setzt Status und nächsten Versuch der Zustellung.
+	// Hat seit der Reservierung bereits jemand anderes einen Versuch protokolliert, wird nichts geschrieben
+	// und ErrClaimLost zurückgegeben.
+	RecordAttempt(deliveryID int, attempt models.DeliveryAttempt, status string, nextAttemptAt *time.Time) error
+
+	// PurgeEvents löscht verteilte Outbox-Ereignisse, die vor before entstanden sind und keine offenen
+	// Zustellungen mehr haben, samt Zustellungen und Protokoll. Mit dryRun wird nur gezählt.
+	// Gibt die Anzahl der (zu) löschenden Ereignisse zurück.
+	PurgeEvents(before time.Time, dryRun bool) (int, error)
+}
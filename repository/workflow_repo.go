@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"database/sql"
+	"task-api/models"
+)
+
+// PostgresWorkflowRepository lädt die Workflow-Definition aus den Tabellen
+// workflow_statuses und workflow_transitions.
+type PostgresWorkflowRepository struct {
+	DB *sql.DB
+}
+
+// GetWorkflow lädt alle Status (sortiert nach position) und alle erlaubten Übergänge.
+func (r *PostgresWorkflowRepository) GetWorkflow() (*models.Workflow, error) {
+	rows, err := r.DB.Query(`SELECT name, label, is_initial, is_final FROM workflow_statuses ORDER BY position, name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	wf := &models.Workflow{}
+	for rows.Next() {
+		var s models.WorkflowStatus
+		if err := rows.Scan(&s.Name, &s.Label, &s.Initial, &s.Final); err != nil {
+			return nil, err
+		}
+		wf.Statuses = append(wf.Statuses, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tRows, err := r.DB.Query(`SELECT from_status, to_status, requires_reopen FROM workflow_transitions ORDER BY from_status, to_status`)
+	if err != nil {
+		return nil, err
+	}
+	defer tRows.Close()
+
+	for tRows.Next() {
+		var t models.WorkflowTransition
+		if err := tRows.Scan(&t.From, &t.To, &t.RequiresReopen); err != nil {
+			return nil, err
+		}
+		wf.Transitions = append(wf.Transitions, t)
+	}
+
+	return wf, tRows.Err()
+}
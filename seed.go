@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"slices"
+	"task-api/models"
+	"task-api/services"
+	"time"
+)
+
+// seedTask ist eine Vorlage für die Beispiel-Tasks von "server seed".
+type seedTask struct {
+	Title    string
+	Priority string
+	Tags     []string
+}
+
+// seedTasks werden reihum verwendet; ab der zweiten Runde mit laufender Nummer im Titel.
+var seedTasks = []seedTask{
+	{"Release vorbereiten", "high", []string{"release"}},
+	{"Changelog schreiben", "medium", []string{"release", "docs"}},
+	{"Login-Fehler beheben", "high", []string{"bug", "backend"}},
+	{"Dashboard-Layout überarbeiten", "low", []string{"frontend"}},
+	{"API-Dokumentation ergänzen", "medium", []string{"docs"}},
+	{"Datenbank-Backup testen", "medium", []string{"ops"}},
+	{"Onboarding-Mail entwerfen", "low", []string{"marketing"}},
+	{"Ladezeiten messen", "medium", []string{"frontend", "backend"}},
+}
+
+// seedOptions steuert "server seed".
+type seedOptions struct {
+	Name   string
+	Email  string
+	Tenant string
+	Tasks  int  // Anzahl der Beispiel-Tasks
+	Force  bool // Auch anlegen, wenn bereits Tasks existieren
+}
+
+// seedResult fasst zusammen, was seedDemo angelegt hat.
+type seedResult struct {
+	User   *models.User
+	APIKey string // Leer, wenn der Benutzer bereits existierte
+	Tasks  []*models.Task
+}
+
+// seedDemo legt einen Demo-Benutzer (bzw. verwendet den bestehenden mit derselben E-Mail) und opts.Tasks
+// Beispiel-Tasks mit Prioritäten, Tags, Fälligkeiten um now und Zuweisung an den Benutzer an. Status
+// werden reihum aus dem Workflow gewählt. Existieren bereits Tasks, bricht seedDemo ohne opts.Force ab.
+func seedDemo(tasks services.TaskServiceInterface, users services.UserServiceInterface, tags services.TagServiceInterface, opts seedOptions, now time.Time) (*seedResult, error) {
+	if opts.Tasks < 0 {
+		return nil, fmt.Errorf("tasks must not be negative")
+	}
+	if !opts.Force {
+		existing, err := tasks.GetAllTasks(models.TaskFilter{})
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 {
+			return nil, fmt.Errorf("database already contains %d tasks; use --force to seed anyway", len(existing))
+		}
+	}
+
+	result := &seedResult{}
+	all, err := users.GetAllUsers(opts.Tenant)
+	if err != nil {
+		return nil, err
+	}
+	if i := slices.IndexFunc(all, func(u *models.User) bool { return u.Email == opts.Email }); i >= 0 {
+		result.User = all[i]
+	} else {
+		created, err := users.CreateUser(models.CreateUserRequest{Name: opts.Name, Email: opts.Email}, adminActor(opts.Tenant))
+		if err != nil {
+			return nil, fmt.Errorf("user: %w", err)
+		}
+		result.User, result.APIKey = created.User, created.APIKey
+	}
+
+	statuses := tasks.GetWorkflow().StatusNames()
+	day := now.Truncate(time.Hour)
+	for i := range opts.Tasks {
+		tmpl := seedTasks[i%len(seedTasks)]
+		title := tmpl.Title
+		if round := i / len(seedTasks); round > 0 {
+			title = fmt.Sprintf("%s #%d", title, round+1)
+		}
+		// Die ersten beiden Tasks sind überfällig, die übrigen im Abstand von einem Tag fällig
+		due := day.Add(time.Duration(i-2) * 24 * time.Hour)
+		task, err := tasks.CreateTask(models.CreateTaskRequest{
+			Title:    title,
+			Status:   statuses[i%len(statuses)],
+			Priority: tmpl.Priority,
+			DueAt:    &due,
+		})
+		if err != nil {
+			return result, fmt.Errorf("task %q: %w", title, err)
+		}
+		for _, tag := range tmpl.Tags {
+			if _, err := tags.AttachTag(task.ID, models.AttachTagRequest{Name: tag}); err != nil {
+				return result, fmt.Errorf("task %q: tag %s: %w", title, tag, err)
+			}
+		}
+		if err := tasks.AssignUser(task.ID, result.User.ID, nil); err != nil {
+			return result, fmt.Errorf("task %q: assign: %w", title, err)
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+	return result, nil
+}
+
+// printSeedResult gibt die angelegten Daten und den API-Key des Demo-Benutzers aus.
+func printSeedResult(w io.Writer, r *seedResult) {
+	if r.APIKey != "" {
+		fmt.Fprintf(w, "created user %d (%s, tenant %s)\napi key: %s\n", r.User.ID, r.User.Email, r.User.Tenant, r.APIKey)
+	} else {
+		fmt.Fprintf(w, "using existing user %d (%s); rotate its key with \"server keys rotate %d\"\n", r.User.ID, r.User.Email, r.User.ID)
+	}
+	fmt.Fprintf(w, "created %d tasks\n", len(r.Tasks))
+}
+
+// newSeedCmd erstellt "server seed".
+func newSeedCmd(getenv func(string) string) *cobra.Command {
+	opts := seedOptions{}
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Create a demo user and sample tasks for local development",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withRepositories(getenv, func(cfg *config, repos *repositories) error {
+				tasks, err := repos.taskService(cfg)
+				if err != nil {
+					return err
+				}
+				users := &services.UserService{Repo: repos.Users}
+				tags := &services.TagService{Repo: repos.Tags, Tasks: repos.Tasks}
+				result, err := seedDemo(tasks, users, tags, opts, time.Now())
+				if result != nil {
+					printSeedResult(cmd.OutOrStdout(), result)
+				}
+				return err
+			})
+		},
+	}
+	cmd.Flags().StringVar(&opts.Name, "name", "Demo", "name of the demo user")
+	cmd.Flags().StringVar(&opts.Email, "email", "demo@example.com", "email of the demo user")
+	cmd.Flags().StringVar(&opts.Tenant, "tenant", services.DefaultTenant, "tenant of the demo user")
+	cmd.Flags().IntVar(&opts.Tasks, "tasks", 20, "number of sample tasks")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "seed even if the database already contains tasks")
+	return cmd
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"task-api/models"
+	"task-api/services"
+	"testing"
+	"time"
+)
+
+// storingTaskService speichert angelegte Tasks im Mock, damit Zuweisungen sie finden.
+type storingTaskService struct {
+	*services.MockTaskService
+}
+
+func (s storingTaskService) CreateTask(req models.CreateTaskRequest) (*models.Task, error) {
+	task, err := s.MockTaskService.CreateTask(req)
+	if err != nil {
+		return nil, err
+	}
+	task.ID = len(s.Tasks) + 1
+	s.Tasks = append(s.Tasks, task)
+	return task, nil
+}
+
+// Test_SeedDemo prüft Demo-Benutzer, Tasks, Tags, Fälligkeiten und Zuweisungen.
+func Test_SeedDemo(t *testing.T) {
+	users := &services.MockUserService{}
+	// Der Task-Mock kennt den Demo-Benutzer, den MockUserService mit ID 1 anlegt
+	tasks := storingTaskService{&services.MockTaskService{Users: []*models.User{{ID: 1, Tenant: "default"}}}}
+	tags := &services.MockTagService{}
+	now := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	opts := seedOptions{Name: "Demo", Email: "demo@example.com", Tenant: "default", Tasks: 10}
+
+	result, err := seedDemo(tasks, users, tags, opts, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "key-1", result.APIKey)
+	assert.Len(t, result.Tasks, 10)
+	assert.Equal(t, "Release vorbereiten", tasks.Tasks[0].Title)
+	assert.Equal(t, "Changelog schreiben #2", tasks.Tasks[9].Title)
+	assert.Equal(t, []string{"todo", "in progress", "done"}, []string{tasks.Tasks[0].Status, tasks.Tasks[1].Status, tasks.Tasks[2].Status})
+	assert.Equal(t, "high", tasks.Tasks[2].Priority)
+	assert.Equal(t, time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC), *tasks.Tasks[0].DueAt)
+	assert.Equal(t, time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC), *tasks.Tasks[4].DueAt)
+	assert.Len(t, tags.TaskTags[2], 2)
+	for _, task := range tasks.Tasks {
+		assert.Equal(t, []int{1}, task.Assignees)
+	}
+
+	var out bytes.Buffer
+	printSeedResult(&out, result)
+	assert.Equal(t, "created user 1 (demo@example.com, tenant default)\napi key: key-1\ncreated 10 tasks\n", out.String())
+
+	// Erneuter Aufruf nur mit --force; der bestehende Benutzer wird wiederverwendet
+	_, err = seedDemo(tasks, users, tags, opts, now)
+	assert.EqualError(t, err, "database already contains 10 tasks; use --force to seed anyway")
+
+	opts.Force, opts.Tasks = true, 1
+	result, err = seedDemo(tasks, users, tags, opts, now)
+	assert.NoError(t, err)
+	assert.Empty(t, result.APIKey)
+	assert.Equal(t, 1, result.User.ID)
+	assert.Len(t, users.Users, 1)
+}
+
+// Test_SeedDemo_Tenant prüft, dass der Demo-Benutzer im angegebenen Mandanten angelegt wird.
+func Test_SeedDemo_Tenant(t *testing.T) {
+	users := &services.MockUserService{}
+	result, err := seedDemo(storingTaskService{&services.MockTaskService{}}, users, &services.MockTagService{},
+		seedOptions{Name: "Demo", Email: "demo@example.com", Tenant: "acme"}, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", result.User.Tenant)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"log"
+	"net"
+	"task-api/handlers"
+	"task-api/proto/taskpb"
+	"task-api/services"
+)
+
+// serve startet den Server:
+// - Stellt die PostgreSQL-Datenbankverbindung her
+// - Initialisiert Repository-, Service- und Handler-Layer
+// - Registriert alle HTTP-Routen
+// - Startet den Fiber Webserver unter Port 8080 und die gRPC-API unter GRPC_PORT
+func serve(cfg *config) error {
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	repos := newRepositories(db, cfg)
+
+	// Dependency-Injection:
+	// Repository -> Service -> Handler
+	service, err := repos.taskService(cfg)
+	if err != nil {
+		return err
+	}
+	handler := &handlers.TaskHandler{Service: service}
+
+	userService := &services.UserService{Repo: repos.Users}
+	userHandler := &handlers.UserHandler{Service: userService}
+
+	commentService := &services.CommentService{
+		Repo:  repos.Comments,
+		Tasks: repos.Tasks,
+		Users: repos.Users,
+	}
+	commentHandler := &handlers.CommentHandler{Service: commentService}
+
+	attachmentService := &services.AttachmentService{
+		Repo:         repos.Attachments,
+		Tasks:        repos.Tasks,
+		Store:        cfg.blobStore(),
+		MaxSize:      cfg.AttachmentMaxSize,
+		AllowedTypes: cfg.AttachmentAllowedTypes,
+	}
+	attachmentHandler := &handlers.AttachmentHandler{Service: attachmentService}
+
+	tagService := &services.TagService{Repo: repos.Tags, Tasks: repos.Tasks}
+	tagHandler := &handlers.TagHandler{Service: tagService}
+
+	// IMPORT_MAX_SYNC_ROWS begrenzt synchrone Importe; größere Dateien werden mit async=true als Job importiert
+	importHandler := &handlers.ImportHandler{Service: &services.ImportService{
+		Tasks:       service,
+		Repo:        repos.ImportJobs,
+		MaxSyncRows: cfg.ImportMaxSyncRows,
+	}}
+
+	webhookHandler := &handlers.WebhookHandler{Service: &services.WebhookService{Repo: repos.Webhooks}}
+
+	// Startet den Reminder-Scheduler im Hintergrund.
+	// REMINDER_LEAD_TIME legt fest, wie lange vor der Fälligkeit erinnert wird (Default: 1h).
+	reminders := &services.ReminderScheduler{
+		Repo:         repos.Tasks,
+		LeadTime:     cfg.ReminderLeadTime,
+		PollInterval: cfg.ReminderPollInterval,
+	}
+	go reminders.Run(context.Background())
+
+	// Startet den Worker, der wiederkehrende Tasks fortsetzt.
+	// RECURRENCE_LEAD_TIME legt fest, wie lange vor seiner Fälligkeit ein Vorkommen angelegt wird (Default: 24h).
+	recurrence := &services.RecurrenceWorker{
+		Service:      service,
+		LeadTime:     cfg.RecurrenceLeadTime,
+		PollInterval: cfg.RecurrencePollInterval,
+	}
+	go recurrence.Run(context.Background())
+
+	// Startet den Worker, der Task-Ereignisse aus der Outbox an die Webhooks zustellt.
+	// Fehlgeschlagene Zustellungen werden mit exponentiellem Backoff bis WEBHOOK_MAX_ATTEMPTS wiederholt.
+	webhooks := &services.WebhookDispatcher{
+		Repo:         repos.Webhooks,
+		PollInterval: cfg.WebhookPollInterval,
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+		BaseBackoff:  cfg.WebhookBaseBackoff,
+		MaxBackoff:   cfg.WebhookMaxBackoff,
+	}
+	go webhooks.Run(context.Background())
+
+	// Startet den Event-Broker für GET /tasks/events. Er empfängt neue Ereignisse per LISTEN/NOTIFY
+	// (auch von anderen API-Instanzen) und hält die letzten EVENT_LOG_SIZE Ereignisse für Last-Event-ID vor.
+	broker := &services.EventBroker{
+		Repo:    repos.Events,
+		LogSize: cfg.EventLogSize,
+	}
+	if err := broker.Start(context.Background()); err != nil {
+		return err
+	}
+	eventHandler := &handlers.EventHandler{Broker: broker}
+	socketHandler := &handlers.SocketHandler{Tasks: handler, Broker: broker, Users: userService}
+	graphQLHandler := &handlers.GraphQLHandler{
+		Tasks:         handler,
+		Comments:      commentService,
+		Users:         userService,
+		MaxDepth:      cfg.GraphQLMaxDepth,
+		MaxComplexity: cfg.GraphQLMaxComplexity,
+	}
+
+	// ---------------------- ROUTES ----------------------
+	app := handlers.NewApp(handlers.APIHandlers{
+		Tasks:       handler,
+		Users:       userHandler,
+		Comments:    commentHandler,
+		Attachments: attachmentHandler,
+		Tags:        tagHandler,
+		Webhooks:    webhookHandler,
+		Events:      eventHandler,
+		Socket:      socketHandler,
+		Imports:     importHandler,
+		Calendar:    &handlers.CalendarHandler{Tasks: service, Users: userService},
+		GraphQL:     graphQLHandler,
+		OpenAPI:     &handlers.OpenAPIHandler{},
+	}, handlers.AppOptions{
+		// Anhänge werden als multipart/form-data hochgeladen; das Body-Limit muss daher
+		// über der maximalen Anhangsgröße liegen (1 MiB Reserve für Form-Felder und Header).
+		BodyLimit: int(cfg.AttachmentMaxSize) + 1<<20,
+		Users:     userService,
+		Version: handlers.VersionOptions{
+			Deprecation: cfg.APIv1Deprecation,
+			Sunset:      cfg.APIv1Sunset,
+		},
+	})
+
+	// Startet die gRPC-API (proto/task.proto) auf GRPC_PORT neben der REST-API
+	go serveGRPC(cfg.GRPCPort, &handlers.TaskGRPCServer{Tasks: handler, Broker: broker}, userService)
+
+	// Startet Server unter Port 8080 (Blockierend)
+	return app.Listen(":8080")
+}
+
+// serveGRPC startet den gRPC-Server für die Task-API auf port.
+func serveGRPC(port string, server *handlers.TaskGRPCServer, users services.UserServiceInterface) {
+	ln, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	unary, stream := handlers.GRPCAuthenticate(users)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream))
+	taskpb.RegisterTaskServiceServer(srv, server)
+	log.Fatal(srv.Serve(ln))
+}
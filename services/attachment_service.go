@@ -0,0 +1,232 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"task-api/models"
+	"task-api/repository"
+	"task-api/storage"
+	"unicode/utf8"
+)
+
+// DefaultMaxAttachmentSize ist die maximale Größe eines Anhangs, wenn MaxSize nicht gesetzt ist (10 MiB).
+const DefaultMaxAttachmentSize = 10 << 20
+
+// DefaultAttachmentTypes sind die erlaubten MIME-Typen, wenn AllowedTypes nicht gesetzt ist.
+var DefaultAttachmentTypes = []string{
+	"image/png", "image/jpeg", "image/gif", "image/webp",
+	"text/plain", "application/pdf", "application/zip", "application/x-gzip",
+}
+
+// AttachmentService kapselt die Businesslogik für Dateianhänge: Validierung von Größe und Typ,
+// Prüfsumme und Ablage des Inhalts im Blob-Storage sowie der Metadaten im Repository.
+type AttachmentService struct {
+	Repo  repository.AttachmentRepositoryInterface
+	Tasks repository.TaskRepositoryInterface
+	Store storage.BlobStore
+
+	// MaxSize ist die maximale Größe eines Anhangs in Bytes (0 = DefaultMaxAttachmentSize).
+	MaxSize int64
+
+	// AllowedTypes sind die erlaubten MIME-Typen (nil = DefaultAttachmentTypes).
+	// Der Typ wird anhand des Inhalts erkannt, nicht anhand der Angabe des Clients.
+	AllowedTypes []string
+}
+
+// maxSize gibt die effektive Maximalgröße zurück.
+func (s *AttachmentService) maxSize() int64 {
+	if s.MaxSize <= 0 {
+		return DefaultMaxAttachmentSize
+	}
+	return s.MaxSize
+}
+
+// allowedType prüft, ob ein MIME-Typ hochgeladen werden darf.
+func (s *AttachmentService) allowedType(contentType string) bool {
+	types := s.AllowedTypes
+	if types == nil {
+		types = DefaultAttachmentTypes
+	}
+	for _, t := range types {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeFilename entfernt Pfadanteile und prüft die Länge des Dateinamens.
+func normalizeFilename(name string) (string, error) {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), `\`, "/"))
+	if name == "" || name == "." || name == "/" || utf8.RuneCountInString(name) > 255 {
+		return "", fmt.Errorf("invalid filename")
+	}
+	return name, nil
+}
+
+// countingReader zählt die gelesenen Bytes.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Upload speichert einen neuen Anhang für den Task taskID. size ist die vom Client gemeldete Größe.
+// Gibt "authentication required", "invalid filename", "empty file", "file too large",
+// "unsupported file type", "incomplete upload" oder "not found" (Task existiert nicht) zurück.
+func (s *AttachmentService) Upload(taskID int, filename string, r io.Reader, size int64, uploader *models.User) (*models.Attachment, error) {
+	if uploader == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+	name, err := normalizeFilename(filename)
+	if err != nil {
+		return nil, err
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("empty file")
+	}
+	if size > s.maxSize() {
+		return nil, fmt.Errorf("file too large")
+	}
+
+	task, err := s.Tasks.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("not found")
+	}
+
+	// Typ anhand der ersten 512 Bytes erkennen
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !s.allowedType(contentType) {
+		return nil, fmt.Errorf("unsupported file type")
+	}
+
+	key, err := newStorageKey(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inhalt gestreamt speichern und dabei Prüfsumme und tatsächliche Größe bestimmen
+	hash := sha256.New()
+	counter := &countingReader{r: io.LimitReader(io.MultiReader(bytes.NewReader(head), r), size)}
+	ctx := context.Background()
+	if err := s.Store.Put(ctx, key, io.TeeReader(counter, hash), size, contentType); err != nil {
+		return nil, err
+	}
+	if counter.n != size {
+		s.deleteBlob(key)
+		return nil, fmt.Errorf("incomplete upload")
+	}
+
+	attachment, err := s.Repo.Create(&models.Attachment{
+		TaskID:      taskID,
+		Filename:    name,
+		ContentType: contentType,
+		Size:        counter.n,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		StorageKey:  key,
+		UploaderID:  &uploader.ID,
+	})
+	if err != nil {
+		s.deleteBlob(key)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// newStorageKey erzeugt einen zufälligen, eindeutigen Key für den Blob-Storage.
+func newStorageKey(taskID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(b)), nil
+}
+
+// deleteBlob entfernt einen Blob und protokolliert Fehler, ohne sie weiterzugeben.
+func (s *AttachmentService) deleteBlob(key string) {
+	if err := s.Store.Delete(context.Background(), key); err != nil {
+		log.Printf("attachments: could not delete blob %s: %v", key, err)
+	}
+}
+
+// GetAttachments gibt alle Anhänge eines Tasks zurück.
+// Gibt "not found" zurück, wenn der Task nicht existiert.
+func (s *AttachmentService) GetAttachments(taskID int) ([]*models.Attachment, error) {
+	task, err := s.Tasks.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	return s.Repo.GetByTask(taskID)
+}
+
+// GetAttachment gibt die Metadaten eines Anhangs zurück.
+// Gibt "not found" zurück, wenn der Anhang nicht existiert.
+func (s *AttachmentService) GetAttachment(id int) (*models.Attachment, error) {
+	attachment, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	return attachment, nil
+}
+
+// OpenAttachment öffnet den Inhalt eines Anhangs ab offset mit length Bytes (length < 0: bis zum Ende).
+// Gibt "not found" zurück, wenn der Inhalt im Blob-Storage fehlt.
+func (s *AttachmentService) OpenAttachment(attachment *models.Attachment, offset, length int64) (io.ReadCloser, error) {
+	r, err := s.Store.Get(context.Background(), attachment.StorageKey, offset, length)
+	if err == storage.ErrNotFound {
+		return nil, fmt.Errorf("not found")
+	}
+	return r, err
+}
+
+// DeleteAttachment entfernt einen Anhang. Nur der hochladende Benutzer darf löschen.
+// Gibt "authentication required", "not found" oder "forbidden" zurück.
+func (s *AttachmentService) DeleteAttachment(id int, actor *models.User) error {
+	if actor == nil {
+		return fmt.Errorf("authentication required")
+	}
+	attachment, err := s.GetAttachment(id)
+	if err != nil {
+		return err
+	}
+	if attachment.UploaderID == nil || *attachment.UploaderID != actor.ID {
+		return fmt.Errorf("forbidden")
+	}
+
+	// Erst die Metadaten löschen: ein verwaister Blob ist harmlos, ein Eintrag ohne Inhalt nicht
+	if err := s.Repo.Delete(id); err != nil {
+		return err
+	}
+	s.deleteBlob(attachment.StorageKey)
+	return nil
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"io"
+	"task-api/models"
+)
+
+// AttachmentServiceInterface definiert die Methoden, die jeder AttachmentService implementieren muss.
+// Dient dazu, die echte Service-Logik in Handler-Tests durch einen Mock zu ersetzen.
+type AttachmentServiceInterface interface {
+	// Upload speichert einen neuen Anhang; uploader ist der angemeldete Benutzer.
+	// Gibt "authentication required", "invalid filename", "empty file", "file too large",
+	// "unsupported file type" oder "not found" zurück.
+	Upload(taskID int, filename string, r io.Reader, size int64, uploader *models.User) (*models.Attachment, error)
+
+	// GetAttachments gibt alle Anhänge eines Tasks zurück.
+	// Gibt "not found" zurück, wenn der Task nicht existiert.
+	GetAttachments(taskID int) ([]*models.Attachment, error)
+
+	// GetAttachment gibt die Metadaten eines Anhangs zurück.
+	// Gibt "not found" zurück, wenn der Anhang nicht existiert.
+	GetAttachment(id int) (*models.Attachment, error)
+
+	// OpenAttachment öffnet den Inhalt eines Anhangs ab offset mit length Bytes (length < 0: bis zum Ende).
+	OpenAttachment(attachment *models.Attachment, offset, length int64) (io.ReadCloser, error)
+
+	// DeleteAttachment entfernt einen eigenen Anhang.
+	// Gibt "authentication required", "not found" oder "forbidden" zurück.
+	DeleteAttachment(id int, actor *models.User) error
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"mime"
+	"net/http"
+	"task-api/models"
+)
+
+// MockAttachmentService implementiert AttachmentServiceInterface für Tests.
+// Felder:
+// - TaskIDs: IDs existierender Tasks.
+// - Attachments: Vorhandene Anhänge.
+// - Contents: Inhalte je Anhang-ID.
+// - MaxSize: Maximale Größe (0 = DefaultMaxAttachmentSize).
+// - ShouldFail: Wenn true, schlagen alle Methoden absichtlich fehl.
+type MockAttachmentService struct {
+	TaskIDs     []int
+	Attachments []*models.Attachment
+	Contents    map[int][]byte
+	MaxSize     int64
+	ShouldFail  bool
+}
+
+// hasTask prüft, ob der Task im Mock existiert.
+func (m *MockAttachmentService) hasTask(id int) bool {
+	for _, t := range m.TaskIDs {
+		if t == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Upload simuliert das Hochladen; Typ und Prüfsumme werden wie im echten Service bestimmt.
+func (m *MockAttachmentService) Upload(taskID int, filename string, r io.Reader, size int64, uploader *models.User) (*models.Attachment, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if uploader == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+	name, err := normalizeFilename(filename)
+	if err != nil {
+		return nil, err
+	}
+	maxSize := m.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxAttachmentSize
+	}
+	if size > maxSize {
+		return nil, fmt.Errorf("file too large")
+	}
+	if !m.hasTask(taskID) {
+		return nil, fmt.Errorf("not found")
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty file")
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if !(&AttachmentService{}).allowedType(contentType) {
+		return nil, fmt.Errorf("unsupported file type")
+	}
+
+	sum := sha256.Sum256(data)
+	attachment := &models.Attachment{
+		ID: len(m.Attachments) + 1, TaskID: taskID, Filename: name, ContentType: contentType,
+		Size: int64(len(data)), Checksum: hex.EncodeToString(sum[:]), UploaderID: &uploader.ID,
+	}
+	m.Attachments = append(m.Attachments, attachment)
+	if m.Contents == nil {
+		m.Contents = map[int][]byte{}
+	}
+	m.Contents[attachment.ID] = data
+	return attachment, nil
+}
+
+// GetAttachments gibt die Anhänge eines Tasks im Mock zurück.
+// Liefert "not found", wenn der Task nicht existiert.
+func (m *MockAttachmentService) GetAttachments(taskID int) ([]*models.Attachment, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if !m.hasTask(taskID) {
+		return nil, fmt.Errorf("not found")
+	}
+	var attachments []*models.Attachment
+	for _, a := range m.Attachments {
+		if a.TaskID == taskID {
+			attachments = append(attachments, a)
+		}
+	}
+	return attachments, nil
+}
+
+// GetAttachment sucht einen Anhang im Mock.
+// Liefert "not found", wenn kein Anhang existiert.
+func (m *MockAttachmentService) GetAttachment(id int) (*models.Attachment, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	for _, a := range m.Attachments {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+// OpenAttachment liefert den angeforderten Bereich aus Contents.
+func (m *MockAttachmentService) OpenAttachment(attachment *models.Attachment, offset, length int64) (io.ReadCloser, error) {
+	data := m.Contents[attachment.ID][offset:]
+	if length >= 0 {
+		data = data[:length]
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// DeleteAttachment simuliert das Löschen eines eigenen Anhangs.
+func (m *MockAttachmentService) DeleteAttachment(id int, actor *models.User) error {
+	if actor == nil {
+		return fmt.Errorf("authentication required")
+	}
+	attachment, err := m.GetAttachment(id)
+	if err != nil {
+		return err
+	}
+	if *attachment.UploaderID != actor.ID {
+		return fmt.Errorf("forbidden")
+	}
+	for i, a := range m.Attachments {
+		if a.ID == id {
+			m.Attachments = append(m.Attachments[:i], m.Attachments[i+1:]...)
+			break
+		}
+	}
+	delete(m.Contents, id)
+	return nil
+}
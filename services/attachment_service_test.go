@@ -0,0 +1,112 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"task-api/models"
+	"task-api/repository"
+	"task-api/storage"
+	"testing"
+)
+
+// Diese Datei enthält Unit-Tests für den AttachmentService.
+
+// attachmentService erstellt einen AttachmentService mit lokalem Blob-Storage im Temp-Verzeichnis.
+// Task 1 existiert; gespeicherte Metadaten landen in saved.
+func attachmentService(t *testing.T, saved **models.Attachment) *AttachmentService {
+	return &AttachmentService{
+		Store: &storage.LocalStore{Dir: t.TempDir()},
+		Tasks: &repository.MockTaskRepository{
+			GetByIdFunc: func(id int) (*models.Task, error) {
+				if id != 1 {
+					return nil, nil
+				}
+				return &models.Task{ID: id}, nil
+			},
+		},
+		Repo: &repository.MockAttachmentRepository{
+			CreateFunc: func(a *models.Attachment) (*models.Attachment, error) {
+				a.ID = 1
+				*saved = a
+				return a, nil
+			},
+		},
+		MaxSize: 1024,
+	}
+}
+
+// Test_AttachmentService_Upload_StoresBlobAndChecksum prüft, dass Inhalt, Typ, Größe und Prüfsumme
+// korrekt gespeichert werden und der Inhalt wieder gelesen werden kann.
+func Test_AttachmentService_Upload_StoresBlobAndChecksum(t *testing.T) {
+	var saved *models.Attachment
+	service := attachmentService(t, &saved)
+	content := "line 1\nline 2\n"
+
+	attachment, err := service.Upload(1, `C:\logs\..\app.log`, strings.NewReader(content), int64(len(content)), &models.User{ID: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, "app.log", attachment.Filename)
+	assert.Equal(t, "text/plain", attachment.ContentType)
+	assert.Equal(t, int64(len(content)), attachment.Size)
+	sum := sha256.Sum256([]byte(content))
+	assert.Equal(t, hex.EncodeToString(sum[:]), attachment.Checksum)
+	assert.Equal(t, 7, *saved.UploaderID)
+
+	r, err := service.OpenAttachment(attachment, 7, 6)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "line 2", string(data))
+}
+
+// Test_AttachmentService_Upload_Limits prüft Anmeldung, Größenlimit, Dateityp und fehlende Tasks.
+func Test_AttachmentService_Upload_Limits(t *testing.T) {
+	var saved *models.Attachment
+	service := attachmentService(t, &saved)
+	user := &models.User{ID: 1}
+
+	_, err := service.Upload(1, "a.txt", strings.NewReader("x"), 1, nil)
+	assert.Equal(t, "authentication required", err.Error())
+
+	_, err = service.Upload(1, "a.txt", strings.NewReader("x"), 2048, user)
+	assert.Equal(t, "file too large", err.Error())
+
+	_, err = service.Upload(1, "a.txt", strings.NewReader(""), 0, user)
+	assert.Equal(t, "empty file", err.Error())
+
+	exe := append([]byte("MZ\x90\x00"), bytes.Repeat([]byte{0}, 100)...)
+	_, err = service.Upload(1, "a.exe", bytes.NewReader(exe), int64(len(exe)), user)
+	assert.Equal(t, "unsupported file type", err.Error())
+
+	_, err = service.Upload(2, "a.txt", strings.NewReader("x"), 1, user)
+	assert.Equal(t, "not found", err.Error())
+
+	assert.Nil(t, saved)
+}
+
+// Test_AttachmentService_DeleteAttachment_OnlyUploader prüft, dass nur der hochladende Benutzer löschen darf.
+func Test_AttachmentService_DeleteAttachment_OnlyUploader(t *testing.T) {
+	uploader := 1
+	deleted := false
+	service := &AttachmentService{
+		Store: &storage.LocalStore{Dir: t.TempDir()},
+		Repo: &repository.MockAttachmentRepository{
+			GetByIdFunc: func(id int) (*models.Attachment, error) {
+				return &models.Attachment{ID: id, UploaderID: &uploader, StorageKey: "tasks/1/x"}, nil
+			},
+			DeleteFunc: func(id int) error {
+				deleted = true
+				return nil
+			},
+		},
+	}
+
+	assert.Equal(t, "forbidden", service.DeleteAttachment(1, &models.User{ID: 2}).Error())
+	assert.False(t, deleted)
+
+	assert.NoError(t, service.DeleteAttachment(1, &models.User{ID: 1}))
+	assert.True(t, deleted)
+}
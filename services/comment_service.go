@@ -0,0 +1,196 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"task-api/models"
+	"task-api/repository"
+	"unicode/utf8"
+)
+
+// CommentService kapselt die Businesslogik für Kommentare zu Tasks:
+// Validierung, Berechtigungen, @-Erwähnungen und das Rendern des Markdown-Bodys.
+type CommentService struct {
+	Repo  repository.CommentRepositoryInterface
+	Tasks repository.TaskRepositoryInterface
+	Users repository.UserRepositoryInterface
+}
+
+// validateCommentBody prüft, dass der Body nicht leer und höchstens 10000 Zeichen lang ist.
+func validateCommentBody(body string) error {
+	if strings.TrimSpace(body) == "" || utf8.RuneCountInString(body) > 10000 {
+		return fmt.Errorf("invalid comment body")
+	}
+	return nil
+}
+
+// render füllt BodyHTML der übergebenen Kommentare.
+func render(comments ...*models.Comment) {
+	for _, c := range comments {
+		c.BodyHTML = renderMarkdown(c.Body, c.Mentions)
+	}
+}
+
+// checkTask gibt "not found" zurück, wenn der Task nicht existiert.
+func (s *CommentService) checkTask(taskID int) error {
+	task, err := s.Tasks.GetByID(taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("not found")
+	}
+	return nil
+}
+
+// resolveMentions sucht die im Body erwähnten Benutzer. Berücksichtigt werden nur
+// Benutzer aus dem Mandanten des Autors; unbekannte Adressen bleiben reiner Text.
+func (s *CommentService) resolveMentions(body string, author *models.User) ([]int, error) {
+	emails := parseMentions(body)
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	users, err := s.Users.GetByEmails(emails)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, u := range users {
+		if u.Tenant == author.Tenant {
+			ids = append(ids, u.ID)
+		}
+	}
+	return ids, nil
+}
+
+// GetComments gibt alle Kommentare eines Tasks zurück.
+// Gibt "not found" zurück, wenn der Task nicht existiert.
+func (s *CommentService) GetComments(taskID int) ([]*models.Comment, error) {
+	if err := s.checkTask(taskID); err != nil {
+		return nil, err
+	}
+	comments, err := s.Repo.GetByTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	render(comments...)
+	return comments, nil
+}
+
+// GetCommentsForTasks gibt alle Kommentare der angegebenen Tasks zurück.
+// Nicht existierende Tasks haben keine Kommentare.
+func (s *CommentService) GetCommentsForTasks(taskIDs []int) ([]*models.Comment, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	comments, err := s.Repo.GetByTasks(taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	render(comments...)
+	return comments, nil
+}
+
+// AddComment legt einen Kommentar von author zum Task taskID an.
+// Gibt "authentication required", "invalid comment body" oder "not found" zurück.
+func (s *CommentService) AddComment(taskID int, req models.CommentRequest, author *models.User) (*models.Comment, error) {
+	if author == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+	if err := validateCommentBody(req.Body); err != nil {
+		return nil, err
+	}
+	if err := s.checkTask(taskID); err != nil {
+		return nil, err
+	}
+
+	mentions, err := s.resolveMentions(req.Body, author)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.Repo.Create(&models.Comment{TaskID: taskID, AuthorID: &author.ID, Body: req.Body}, mentions)
+	if err != nil {
+		return nil, err
+	}
+	render(comment)
+	return comment, nil
+}
+
+// loadOwnComment lädt einen Kommentar und prüft, dass actor sein Autor ist.
+// Gibt "authentication required", "not found" oder "forbidden" zurück.
+func (s *CommentService) loadOwnComment(id int, actor *models.User) (*models.Comment, error) {
+	if actor == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+	comment, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	if comment.AuthorID == nil || *comment.AuthorID != actor.ID {
+		return nil, fmt.Errorf("forbidden")
+	}
+	return comment, nil
+}
+
+// UpdateComment ersetzt den Body eines Kommentars. Nur der Autor darf seinen Kommentar bearbeiten;
+// die vorherige Fassung bleibt als Revision erhalten.
+// Gibt "authentication required", "invalid comment body", "not found" oder "forbidden" zurück.
+func (s *CommentService) UpdateComment(id int, req models.CommentRequest, actor *models.User) (*models.Comment, error) {
+	if err := validateCommentBody(req.Body); err != nil {
+		return nil, err
+	}
+	comment, err := s.loadOwnComment(id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Body == req.Body {
+		render(comment)
+		return comment, nil
+	}
+
+	mentions, err := s.resolveMentions(req.Body, actor)
+	if err != nil {
+		return nil, err
+	}
+	comment, err = s.Repo.Update(id, req.Body, mentions, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	render(comment)
+	return comment, nil
+}
+
+// DeleteComment entfernt einen Kommentar. Nur der Autor darf seinen Kommentar löschen.
+// Gibt "authentication required", "not found" oder "forbidden" zurück.
+func (s *CommentService) DeleteComment(id int, actor *models.User) error {
+	if _, err := s.loadOwnComment(id, actor); err != nil {
+		return err
+	}
+	return s.Repo.Delete(id, actor.ID)
+}
+
+// GetRevisions gibt die früheren Fassungen eines Kommentars zurück.
+// Gibt "not found" zurück, wenn der Kommentar nicht existiert.
+func (s *CommentService) GetRevisions(id int) ([]*models.CommentRevision, error) {
+	comment, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	return s.Repo.GetRevisions(id)
+}
+
+// GetTaskHistory gibt die Audit-Historie eines Tasks zurück.
+// Gibt "not found" zurück, wenn der Task nicht existiert.
+func (s *CommentService) GetTaskHistory(taskID int) ([]*models.HistoryEntry, error) {
+	if err := s.checkTask(taskID); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetHistory(taskID)
+}
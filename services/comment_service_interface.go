@@ -0,0 +1,34 @@
+package services
+
+import "task-api/models"
+
+// CommentServiceInterface definiert die Methoden, die jeder CommentService implementieren muss.
+// Dient dazu, die echte Service-Logik in Handler-Tests durch einen Mock zu ersetzen.
+type CommentServiceInterface interface {
+	// GetComments gibt alle Kommentare eines Tasks zurück.
+	// Gibt "not found" zurück, wenn der Task nicht existiert.
+	GetComments(taskID int) ([]*models.Comment, error)
+
+	// GetCommentsForTasks gibt alle Kommentare der angegebenen Tasks in einer Abfrage zurück.
+	GetCommentsForTasks(taskIDs []int) ([]*models.Comment, error)
+
+	// AddComment legt einen Kommentar an; author ist der angemeldete Benutzer.
+	// Gibt "authentication required", "invalid comment body" oder "not found" zurück.
+	AddComment(taskID int, req models.CommentRequest, author *models.User) (*models.Comment, error)
+
+	// UpdateComment bearbeitet einen eigenen Kommentar.
+	// Gibt "authentication required", "invalid comment body", "not found" oder "forbidden" zurück.
+	UpdateComment(id int, req models.CommentRequest, actor *models.User) (*models.Comment, error)
+
+	// DeleteComment löscht einen eigenen Kommentar.
+	// Gibt "authentication required", "not found" oder "forbidden" zurück.
+	DeleteComment(id int, actor *models.User) error
+
+	// GetRevisions gibt die früheren Fassungen eines Kommentars zurück.
+	// Gibt "not found" zurück, wenn der Kommentar nicht existiert.
+	GetRevisions(id int) ([]*models.CommentRevision, error)
+
+	// GetTaskHistory gibt die Audit-Historie eines Tasks zurück.
+	// Gibt "not found" zurück, wenn der Task nicht existiert.
+	GetTaskHistory(taskID int) ([]*models.HistoryEntry, error)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"task-api/models"
+	"time"
+)
+
+// MockCommentService implementiert CommentServiceInterface für Tests.
+// Felder:
+// - TaskIDs: IDs existierender Tasks.
+// - Comments: Vorhandene Kommentare.
+// - Revisions: Frühere Fassungen je Kommentar-ID.
+// - History: Audit-Historie aller Tasks.
+// - ShouldFail: Wenn true, schlagen alle Methoden absichtlich fehl.
+type MockCommentService struct {
+	TaskIDs    []int
+	Comments   []*models.Comment
+	Revisions  map[int][]*models.CommentRevision
+	History    []*models.HistoryEntry
+	ShouldFail bool
+}
+
+// hasTask prüft, ob der Task im Mock existiert.
+func (m *MockCommentService) hasTask(id int) bool {
+	for _, t := range m.TaskIDs {
+		if t == id {
+			return true
+		}
+	}
+	return false
+}
+
+// byID sucht einen Kommentar im Mock.
+func (m *MockCommentService) byID(id int) *models.Comment {
+	for _, c := range m.Comments {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// record schreibt einen Eintrag in die Audit-Historie des Mocks.
+func (m *MockCommentService) record(taskID, userID int, action string, commentID int) {
+	m.History = append(m.History, &models.HistoryEntry{
+		ID: len(m.History) + 1, TaskID: taskID, UserID: &userID, Action: action, CommentID: &commentID,
+	})
+}
+
+// GetComments gibt die Kommentare eines Tasks im Mock zurück.
+// Liefert "not found", wenn der Task nicht existiert.
+func (m *MockCommentService) GetComments(taskID int) ([]*models.Comment, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if !m.hasTask(taskID) {
+		return nil, fmt.Errorf("not found")
+	}
+	var comments []*models.Comment
+	for _, c := range m.Comments {
+		if c.TaskID == taskID {
+			c.BodyHTML = renderMarkdown(c.Body, c.Mentions)
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+// GetCommentsForTasks gibt alle Kommentare im Mock zu den angegebenen Tasks zurück.
+func (m *MockCommentService) GetCommentsForTasks(taskIDs []int) ([]*models.Comment, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	var comments []*models.Comment
+	for _, c := range m.Comments {
+		if containsID(taskIDs, c.TaskID) {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+// AddComment simuliert das Anlegen eines Kommentars (ohne Auflösung von Erwähnungen).
+func (m *MockCommentService) AddComment(taskID int, req models.CommentRequest, author *models.User) (*models.Comment, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if author == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+	if err := validateCommentBody(req.Body); err != nil {
+		return nil, err
+	}
+	if !m.hasTask(taskID) {
+		return nil, fmt.Errorf("not found")
+	}
+	now := time.Now()
+	comment := &models.Comment{
+		ID: len(m.Comments) + 1, TaskID: taskID, AuthorID: &author.ID, Body: req.Body,
+		Mentions: []models.Mention{}, CreatedAt: now, UpdatedAt: now,
+	}
+	comment.BodyHTML = renderMarkdown(comment.Body, comment.Mentions)
+	m.Comments = append(m.Comments, comment)
+	m.record(taskID, author.ID, models.HistoryCommentAdded, comment.ID)
+	return comment, nil
+}
+
+// ownComment sucht einen Kommentar und prüft, dass actor sein Autor ist.
+func (m *MockCommentService) ownComment(id int, actor *models.User) (*models.Comment, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if actor == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+	comment := m.byID(id)
+	if comment == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	if comment.AuthorID == nil || *comment.AuthorID != actor.ID {
+		return nil, fmt.Errorf("forbidden")
+	}
+	return comment, nil
+}
+
+// UpdateComment simuliert das Bearbeiten eines Kommentars und legt eine Revision an.
+func (m *MockCommentService) UpdateComment(id int, req models.CommentRequest, actor *models.User) (*models.Comment, error) {
+	if err := validateCommentBody(req.Body); err != nil {
+		return nil, err
+	}
+	comment, err := m.ownComment(id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if m.Revisions == nil {
+		m.Revisions = map[int][]*models.CommentRevision{}
+	}
+	now := time.Now()
+	m.Revisions[id] = append(m.Revisions[id], &models.CommentRevision{
+		ID: len(m.Revisions[id]) + 1, CommentID: id, Body: comment.Body, EditedBy: &actor.ID, EditedAt: now,
+	})
+	comment.Body = req.Body
+	comment.BodyHTML = renderMarkdown(comment.Body, comment.Mentions)
+	comment.EditedAt = &now
+	m.record(comment.TaskID, actor.ID, models.HistoryCommentEdited, id)
+	return comment, nil
+}
+
+// DeleteComment simuliert das Löschen eines Kommentars.
+func (m *MockCommentService) DeleteComment(id int, actor *models.User) error {
+	comment, err := m.ownComment(id, actor)
+	if err != nil {
+		return err
+	}
+	for i, c := range m.Comments {
+		if c.ID == id {
+			m.Comments = append(m.Comments[:i], m.Comments[i+1:]...)
+			break
+		}
+	}
+	m.record(comment.TaskID, actor.ID, models.HistoryCommentDeleted, id)
+	return nil
+}
+
+// GetRevisions gibt die Revisionen eines Kommentars im Mock zurück.
+// Liefert "not found", wenn der Kommentar nicht existiert.
+func (m *MockCommentService) GetRevisions(id int) ([]*models.CommentRevision, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if m.byID(id) == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	return m.Revisions[id], nil
+}
+
+// GetTaskHistory gibt die Audit-Historie eines Tasks im Mock zurück.
+// Liefert "not found", wenn der Task nicht existiert.
+func (m *MockCommentService) GetTaskHistory(taskID int) ([]*models.HistoryEntry, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if !m.hasTask(taskID) {
+		return nil, fmt.Errorf("not found")
+	}
+	var entries []*models.HistoryEntry
+	for _, e := range m.History {
+		if e.TaskID == taskID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+)
+
+// Diese Datei enthält Unit-Tests für den CommentService und das Rendern von Kommentaren.
+
+// Test_RenderMarkdown_Sanitizes prüft, dass Markdown gerendert und gefährliches HTML entfernt wird.
+func Test_RenderMarkdown_Sanitizes(t *testing.T) {
+	html := renderMarkdown("**fett** <script>alert(1)</script> [x](javascript:alert(1))", nil)
+
+	assert.Contains(t, html, "<strong>fett</strong>")
+	assert.NotContains(t, html, "<script")
+	assert.NotContains(t, html, "javascript:")
+}
+
+// Test_RenderMarkdown_LinksMentions prüft, dass nur bekannte Erwähnungen verlinkt werden.
+func Test_RenderMarkdown_LinksMentions(t *testing.T) {
+	html := renderMarkdown("Hallo @anna_b@example.com und @unknown@example.com.",
+		[]models.Mention{{UserID: 4, Email: "anna_b@example.com"}})
+
+	assert.Contains(t, html, `<a href="/users/4"`)
+	assert.Contains(t, html, "@anna_b@example.com</a>")
+	assert.NotContains(t, html, `@unknown@example.com</a>`)
+}
+
+// Test_ParseMentions prüft das Erkennen von Erwähnungen ohne Duplikate und ohne normale E-Mail-Adressen.
+func Test_ParseMentions(t *testing.T) {
+	emails := parseMentions("@a@example.com, siehe mail an b@example.com und nochmal @a@example.com. (@c@example.org)")
+
+	assert.Equal(t, []string{"a@example.com", "c@example.org"}, emails)
+}
+
+// Test_CommentService_AddComment_ResolvesMentions prüft, dass nur Benutzer aus dem Mandanten des Autors
+// als Erwähnung gespeichert werden.
+func Test_CommentService_AddComment_ResolvesMentions(t *testing.T) {
+	var storedMentions []int
+	service := CommentService{
+		Tasks: &repository.MockTaskRepository{
+			GetByIdFunc: func(id int) (*models.Task, error) {
+				return &models.Task{ID: id}, nil
+			},
+		},
+		Users: &repository.MockUserRepository{
+			GetByEmailsFunc: func(emails []string) ([]*models.User, error) {
+				return []*models.User{
+					{ID: 2, Email: "b@example.com", Tenant: "acme"},
+					{ID: 3, Email: "c@example.com", Tenant: "other"},
+				}, nil
+			},
+		},
+		Repo: &repository.MockCommentRepository{
+			CreateFunc: func(comment *models.Comment, mentionIDs []int) (*models.Comment, error) {
+				storedMentions = mentionIDs
+				comment.ID = 1
+				return comment, nil
+			},
+		},
+	}
+	author := &models.User{ID: 1, Tenant: "acme"}
+
+	comment, err := service.AddComment(5, models.CommentRequest{Body: "@b@example.com @c@example.com"}, author)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, storedMentions)
+	assert.Equal(t, 1, *comment.AuthorID)
+	assert.NotEmpty(t, comment.BodyHTML)
+}
+
+// Test_CommentService_AddComment_Validation prüft Anmeldung und Länge des Bodys (Umlaute zählen als ein Zeichen).
+func Test_CommentService_AddComment_Validation(t *testing.T) {
+	service := CommentService{}
+
+	_, err := service.AddComment(1, models.CommentRequest{Body: "x"}, nil)
+	assert.Equal(t, "authentication required", err.Error())
+
+	_, err = service.AddComment(1, models.CommentRequest{Body: "  "}, &models.User{ID: 1})
+	assert.Equal(t, "invalid comment body", err.Error())
+
+	assert.NoError(t, validateCommentBody(strings.Repeat("ä", 10000)))
+	assert.Error(t, validateCommentBody(strings.Repeat("ä", 10001)))
+}
+
+// Test_CommentService_UpdateComment_OnlyAuthor prüft, dass nur der Autor seinen Kommentar bearbeiten darf.
+func Test_CommentService_UpdateComment_OnlyAuthor(t *testing.T) {
+	authorID := 1
+	updated := false
+	service := CommentService{
+		Repo: &repository.MockCommentRepository{
+			GetByIdFunc: func(id int) (*models.Comment, error) {
+				return &models.Comment{ID: id, AuthorID: &authorID, Body: "alt"}, nil
+			},
+			UpdateFunc: func(id int, body string, mentionIDs []int, editorID int) (*models.Comment, error) {
+				updated = true
+				return &models.Comment{ID: id, AuthorID: &authorID, Body: body}, nil
+			},
+		},
+		Users: &repository.MockUserRepository{},
+	}
+
+	_, err := service.UpdateComment(1, models.CommentRequest{Body: "neu"}, &models.User{ID: 2})
+	assert.Equal(t, "forbidden", err.Error())
+	assert.False(t, updated)
+
+	comment, err := service.UpdateComment(1, models.CommentRequest{Body: "neu"}, &models.User{ID: 1})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, "neu", comment.Body)
+}
+
+// Test_CommentService_GetCommentsForTasks prüft, dass alle Kommentare mit einer Abfrage geladen und gerendert werden.
+func Test_CommentService_GetCommentsForTasks(t *testing.T) {
+	var calls int
+	service := CommentService{
+		Repo: &repository.MockCommentRepository{
+			GetByTasksFunc: func(taskIDs []int) ([]*models.Comment, error) {
+				calls++
+				return []*models.Comment{{ID: 1, TaskID: 1, Body: "**a**"}, {ID: 2, TaskID: 2, Body: "b"}}, nil
+			},
+		},
+	}
+
+	comments, err := service.GetCommentsForTasks([]int{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	if assert.Len(t, comments, 2) {
+		assert.Contains(t, comments[0].BodyHTML, "<strong>a</strong>")
+	}
+
+	comments, err = service.GetCommentsForTasks(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, comments)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"task-api/models"
+	"task-api/repository"
+)
+
+// DefaultEventLogSize ist die Anzahl der Ereignisse, die der EventBroker für die
+// Wiederaufnahme per Last-Event-ID vorhält.
+const DefaultEventLogSize = 1000
+
+// subscriberBuffer ist die Anzahl der Ereignisse, die ein Abonnent im Rückstand sein darf,
+// bevor er getrennt wird. Der Client setzt danach per Last-Event-ID wieder auf.
+const subscriberBuffer = 64
+
+// EventSubscription ist ein Abonnement des Event-Streams.
+// Events wird geschlossen, wenn der Abonnent zu langsam ist oder der Broker beendet wird.
+type EventSubscription struct {
+	Events <-chan *models.EventPayload
+
+	events chan *models.EventPayload
+	filter models.TaskEventFilter
+}
+
+// EventBroker verteilt Task-Ereignisse (created, updated, deleted, reminder) an alle Abonnenten dieser Instanz.
+// Die Ereignisse stammen aus der Outbox und werden per LISTEN/NOTIFY von allen API-Instanzen empfangen,
+// sodass jede Instanz dieselbe Reihenfolge und dasselbe begrenzte Ereignis-Log besitzt.
+type EventBroker struct {
+	Repo repository.EventRepositoryInterface
+
+	// LogSize begrenzt das Ereignis-Log für die Wiederaufnahme. Default: DefaultEventLogSize.
+	LogSize int
+
+	mu          sync.Mutex
+	log         []*models.EventPayload
+	logged      map[int64]bool
+	lastID      int64
+	subscribers map[*EventSubscription]bool
+}
+
+// streamable gibt an, ob ein Ereignistyp im Stream ausgeliefert wird.
+// task.status_changed entfällt, da jeder Statuswechsel auch ein task.updated erzeugt.
+func streamable(event *models.EventPayload) bool {
+	switch event.Event {
+	case models.EventTaskCreated, models.EventTaskUpdated, models.EventTaskDeleted, models.EventTaskReminder:
+		return true
+	}
+	return false
+}
+
+// Start füllt das Ereignis-Log mit den letzten Ereignissen aus der Outbox und lauscht im Hintergrund
+// auf neue Ereignisse, bis ctx beendet wird.
+func (b *EventBroker) Start(ctx context.Context) error {
+	latest, err := b.Repo.GetLatestEvents(b.logSize())
+	if err != nil {
+		return err
+	}
+	for _, event := range latest {
+		b.Publish(event)
+	}
+
+	go func() {
+		if err := b.Repo.Listen(ctx, b.handleNotification); err != nil {
+			log.Printf("event broker: %v", err)
+		}
+		b.Close()
+	}()
+	return nil
+}
+
+// handleNotification lädt das per NOTIFY gemeldete Ereignis und verteilt es.
+// Die ID 0 meldet eine neu aufgebaute Verbindung; dann werden verpasste Ereignisse nachgeladen.
+func (b *EventBroker) handleNotification(id int64) {
+	var events []*models.EventPayload
+	if id == 0 {
+		b.mu.Lock()
+		lastID := b.lastID
+		b.mu.Unlock()
+
+		var err error
+		if events, err = b.Repo.GetEventsAfter(lastID, b.logSize()); err != nil {
+			log.Printf("event broker: %v", err)
+			return
+		}
+	} else {
+		event, err := b.Repo.GetEvent(id)
+		if err != nil {
+			log.Printf("event broker: %v", err)
+			return
+		}
+		if event != nil {
+			events = append(events, event)
+		}
+	}
+
+	for _, event := range events {
+		b.Publish(event)
+	}
+}
+
+// Publish nimmt ein Ereignis in das Log auf und stellt es allen passenden Abonnenten zu.
+// Bereits bekannte Ereignisse werden ignoriert. Abonnenten, deren Puffer voll ist, werden getrennt.
+func (b *EventBroker) Publish(event *models.EventPayload) {
+	if !streamable(event) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.logged == nil {
+		b.logged = map[int64]bool{}
+	}
+	if b.logged[event.ID] {
+		return
+	}
+	b.logged[event.ID] = true
+	b.log = append(b.log, event)
+	if over := len(b.log) - b.logSize(); over > 0 {
+		for _, e := range b.log[:over] {
+			delete(b.logged, e.ID)
+		}
+		b.log = append([]*models.EventPayload(nil), b.log[over:]...)
+	}
+	b.lastID = max(b.lastID, event.ID)
+
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Subscribe registriert einen Abonnenten für alle Ereignisse, die filter erfüllen.
+// Mit lastEventID werden zusätzlich alle danach geloggten Ereignisse als Rückstand zurückgegeben.
+// resumed ist false, wenn lastEventID nicht mehr im Log enthalten ist; der Client muss dann neu laden.
+func (b *EventBroker) Subscribe(filter models.TaskEventFilter, lastEventID string) (sub *EventSubscription, backlog []*models.EventPayload, resumed bool) {
+	events := make(chan *models.EventPayload, subscriberBuffer)
+	sub = &EventSubscription{Events: events, events: events, filter: filter}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers == nil {
+		b.subscribers = map[*EventSubscription]bool{}
+	}
+	b.subscribers[sub] = true
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+	id, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil || !b.logged[id] {
+		return sub, nil, false
+	}
+
+	// Die Reihenfolge im Log entspricht der Commit-Reihenfolge, nicht zwingend der ID-Reihenfolge
+	for i := len(b.log) - 1; i >= 0 && b.log[i].ID != id; i-- {
+		if filter.Matches(b.log[i]) {
+			backlog = append(backlog, b.log[i])
+		}
+	}
+	slices.Reverse(backlog)
+	return sub, backlog, true
+}
+
+// Unsubscribe beendet ein Abonnement und schließt dessen Channel.
+func (b *EventBroker) Unsubscribe(sub *EventSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers[sub] {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Close beendet alle Abonnements, z.B. beim Herunterfahren.
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// logSize gibt LogSize oder DefaultEventLogSize zurück.
+func (b *EventBroker) logSize() int {
+	if b.LogSize <= 0 {
+		return DefaultEventLogSize
+	}
+	return b.LogSize
+}
@@ -0,0 +1,13 @@
+package services
+
+import "task-api/models"
+
+// EventBrokerInterface definiert die Methoden, die der Event-Stream-Handler benötigt.
+type EventBrokerInterface interface {
+	// Subscribe registriert einen Abonnenten für alle Ereignisse, die filter erfüllen, und liefert
+	// die nach lastEventID geloggten Ereignisse. resumed ist false, wenn lastEventID unbekannt ist.
+	Subscribe(filter models.TaskEventFilter, lastEventID string) (sub *EventSubscription, backlog []*models.EventPayload, resumed bool)
+
+	// Unsubscribe beendet ein Abonnement.
+	Unsubscribe(sub *EventSubscription)
+}
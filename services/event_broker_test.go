@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+)
+
+// Diese Datei enthält Unit-Tests für den EventBroker des Event-Streams.
+
+// taskEvent erstellt ein Ereignis für einen Task mit Status und Tags.
+func taskEvent(id int64, event, status string, tags ...string) *models.EventPayload {
+	return &models.EventPayload{ID: id, Event: event, Task: &models.Task{ID: int(id), Status: status, Tags: tags}}
+}
+
+// eventIDs gibt die IDs der Ereignisse zurück.
+func eventIDs(events []*models.EventPayload) []int64 {
+	ids := []int64{}
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// Test_EventBroker_Resume prüft die Wiederaufnahme per Last-Event-ID inklusive Filter,
+// das begrenzte Log, das Überspringen von task.status_changed und das Ausliefern von task.reminder.
+func Test_EventBroker_Resume(t *testing.T) {
+	broker := &EventBroker{
+		LogSize: 4,
+		Repo: &repository.MockEventRepository{
+			GetLatestEventsFunc: func(limit int) ([]*models.EventPayload, error) {
+				assert.Equal(t, 4, limit)
+				return []*models.EventPayload{
+					taskEvent(1, models.EventTaskCreated, "todo"),
+					taskEvent(2, models.EventTaskCreated, "todo", "backend"),
+				}, nil
+			},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, broker.Start(ctx))
+
+	broker.Publish(taskEvent(3, models.EventTaskUpdated, "done", "backend"))
+	broker.Publish(taskEvent(4, models.EventTaskStatusChanged, "done", "backend"))
+	broker.Publish(taskEvent(3, models.EventTaskUpdated, "done", "backend"))
+	broker.Publish(taskEvent(5, models.EventTaskDeleted, "todo"))
+
+	_, backlog, resumed := broker.Subscribe(models.TaskEventFilter{}, "1")
+	assert.True(t, resumed)
+	assert.Equal(t, []int64{2, 3, 5}, eventIDs(backlog))
+
+	_, backlog, resumed = broker.Subscribe(models.TaskEventFilter{Tags: []string{"backend"}}, "1")
+	assert.True(t, resumed)
+	assert.Equal(t, []int64{2, 3}, eventIDs(backlog))
+
+	_, backlog, resumed = broker.Subscribe(models.TaskEventFilter{Statuses: []string{"todo"}}, "2")
+	assert.True(t, resumed)
+	assert.Equal(t, []int64{5}, eventIDs(backlog))
+
+	// Ereignis 1 fällt durch die Erinnerung aus dem Log (LogSize 4), danach ist keine Wiederaufnahme mehr möglich
+	broker.Publish(taskEvent(6, models.EventTaskReminder, "todo"))
+	_, backlog, resumed = broker.Subscribe(models.TaskEventFilter{}, "1")
+	assert.False(t, resumed)
+	assert.Empty(t, backlog)
+
+	_, _, resumed = broker.Subscribe(models.TaskEventFilter{}, "kaputt")
+	assert.False(t, resumed)
+}
+
+// Test_EventBroker_Live prüft die Zustellung per NOTIFY, den Filter und das Trennen langsamer Abonnenten.
+func Test_EventBroker_Live(t *testing.T) {
+	events := map[int64]*models.EventPayload{
+		7: taskEvent(7, models.EventTaskCreated, "todo"),
+		8: taskEvent(8, models.EventTaskCreated, "done"),
+		9: taskEvent(9, models.EventTaskUpdated, "todo"),
+	}
+	broker := &EventBroker{Repo: &repository.MockEventRepository{
+		GetEventFunc: func(id int64) (*models.EventPayload, error) {
+			return events[id], nil
+		},
+		GetEventsAfterFunc: func(afterID int64, limit int) ([]*models.EventPayload, error) {
+			assert.Equal(t, int64(8), afterID)
+			return []*models.EventPayload{events[9]}, nil
+		},
+	}}
+
+	todo, _, _ := broker.Subscribe(models.TaskEventFilter{Statuses: []string{"todo"}}, "")
+	slow, _, _ := broker.Subscribe(models.TaskEventFilter{}, "")
+
+	broker.handleNotification(7)
+	broker.handleNotification(8)
+	broker.handleNotification(0) // Verbindung neu aufgebaut: verpasste Ereignisse nachladen
+
+	assert.Equal(t, int64(7), (<-todo.Events).ID)
+	assert.Equal(t, int64(9), (<-todo.Events).ID)
+	assert.Len(t, slow.Events, 3)
+
+	for i := 0; i < subscriberBuffer; i++ {
+		broker.Publish(taskEvent(int64(100+i), models.EventTaskCreated, "done"))
+	}
+	for range slow.Events {
+	}
+	_, ok := <-slow.Events
+	assert.False(t, ok, "slow subscriber must be disconnected")
+
+	broker.Unsubscribe(todo)
+	_, ok = <-todo.Events
+	assert.False(t, ok)
+}
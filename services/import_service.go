@@ -0,0 +1,424 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"task-api/export"
+	"task-api/importer"
+	"task-api/models"
+	"task-api/repository"
+	"task-api/validation"
+	"time"
+)
+
+// DefaultMaxSyncImportRows ist die maximale Anzahl Datenzeilen eines synchronen Imports, wenn MaxSyncRows
+// nicht gesetzt ist. Größere Dateien werden als Job importiert.
+const DefaultMaxSyncImportRows = 1000
+
+// DefaultImportJobTTL ist die Dauer, die ein beendeter Job abrufbar bleibt, wenn JobTTL nicht gesetzt ist.
+const DefaultImportJobTTL = 24 * time.Hour
+
+// Ein laufender Job speichert seinen Fortschritt nach importSaveRows Zeilen, spätestens aber nach
+// importSaveInterval. Wurde er länger als importStaleAfter nicht gespeichert (z.B. weil die Instanz beendet
+// wurde), gilt er als abgebrochen.
+const (
+	importSaveRows     = 100
+	importSaveInterval = time.Second
+	importStaleAfter   = 5 * time.Minute
+)
+
+// ImportService importiert Tasks aus CSV- oder NDJSON-Dateien. Jede Zeile wird über TaskService.CreateTask
+// angelegt und dabei genauso geprüft wie ein einzelner Request. Asynchrone Jobs verarbeitet die Instanz, die
+// die Datei empfangen hat; Stand und Fehlerbericht liegen in Repo und sind über jede Instanz abrufbar.
+type ImportService struct {
+	Tasks TaskServiceInterface
+	Repo  repository.ImportJobRepositoryInterface
+
+	// MaxSyncRows ist die maximale Anzahl Datenzeilen eines synchronen Imports (0 = DefaultMaxSyncImportRows).
+	MaxSyncRows int
+
+	// JobTTL ist die Dauer, die ein beendeter Job abrufbar bleibt (0 = DefaultImportJobTTL).
+	JobTTL time.Duration
+}
+
+// Import liest alle Zeilen von data und legt für jede gültige Zeile einen Task an.
+func (s *ImportService) Import(format export.Format, data []byte, opts models.ImportOptions) (*models.ImportResult, error) {
+	rows, err := s.prepare(format, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	if rows > s.maxSyncRows() {
+		return nil, fmt.Errorf("too many rows")
+	}
+
+	result := &models.ImportResult{ImportSummary: models.ImportSummary{DryRun: opts.DryRun}, Errors: []models.ImportRowError{}}
+	err = s.process(format, data, opts, func(rowErrs []models.ImportRowError, created bool) {
+		countRow(&result.ImportSummary, rowErrs, created)
+		result.Errors = append(result.Errors, rowErrs...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StartImport prüft Datei und Mapping, speichert den Job und verarbeitet die Zeilen in einer eigenen Goroutine.
+func (s *ImportService) StartImport(format export.Format, data []byte, opts models.ImportOptions) (*models.ImportJob, error) {
+	rows, err := s.prepare(format, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newImportJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Repo.DeleteExpired(time.Now().Add(-s.jobTTL())); err != nil {
+		return nil, err
+	}
+	job := models.ImportJob{
+		ID:            id,
+		Status:        models.ImportJobRunning,
+		TotalRows:     rows,
+		ImportSummary: models.ImportSummary{DryRun: opts.DryRun},
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := s.Repo.Create(&job); err != nil {
+		return nil, err
+	}
+	snapshot := importSnapshot(job)
+
+	go s.run(job, format, data, opts)
+	return snapshot, nil
+}
+
+// run verarbeitet die Zeilen eines Jobs und speichert Fortschritt und Fehler blockweise.
+// Scheitert das Speichern, wird der Fehler geloggt und die Verarbeitung fortgesetzt.
+func (s *ImportService) run(job models.ImportJob, format export.Format, data []byte, opts models.ImportOptions) {
+	var pending []models.ImportRowError
+	saved := time.Now()
+	save := func() {
+		if err := s.Repo.Update(&job, pending); err != nil {
+			log.Printf("import job %s: saving progress failed: %v", job.ID, err)
+			return
+		}
+		pending, saved = nil, time.Now()
+	}
+
+	err := s.process(format, data, opts, func(rowErrs []models.ImportRowError, created bool) {
+		countRow(&job.ImportSummary, rowErrs, created)
+		pending = append(pending, rowErrs...)
+		if job.Rows%importSaveRows == 0 || time.Since(saved) >= importSaveInterval {
+			save()
+		}
+	})
+
+	finished := time.Now().UTC()
+	job.FinishedAt = &finished
+	job.Status = models.ImportJobCompleted
+	if err != nil {
+		log.Printf("import job %s failed: %v", job.ID, err)
+		job.Status = models.ImportJobFailed
+		job.Error = err.Error()
+	}
+	save()
+}
+
+// GetImportJob gibt den aktuellen Stand eines Jobs zurück.
+func (s *ImportService) GetImportJob(id string) (*models.ImportJob, error) {
+	job, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	if job.Status == models.ImportJobRunning && time.Since(job.UpdatedAt) > importStaleAfter {
+		job.Status = models.ImportJobFailed
+		job.Error = "import interrupted"
+	}
+	return importSnapshot(*job), nil
+}
+
+// GetImportErrors gibt die bisher gefundenen Fehler eines Jobs zurück.
+func (s *ImportService) GetImportErrors(id string) ([]models.ImportRowError, error) {
+	job, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	return s.Repo.GetErrors(id)
+}
+
+// importSnapshot kopiert den Job und berechnet den Fortschritt.
+func importSnapshot(job models.ImportJob) *models.ImportJob {
+	switch {
+	case job.FinishedAt != nil:
+		job.Progress = 100
+	case job.TotalRows > 0:
+		job.Progress = job.Rows * 100 / job.TotalRows
+	}
+	return &job
+}
+
+// jobTTL gibt JobTTL oder DefaultImportJobTTL zurück.
+func (s *ImportService) jobTTL() time.Duration {
+	if s.JobTTL <= 0 {
+		return DefaultImportJobTTL
+	}
+	return s.JobTTL
+}
+
+func (s *ImportService) maxSyncRows() int {
+	if s.MaxSyncRows <= 0 {
+		return DefaultMaxSyncImportRows
+	}
+	return s.MaxSyncRows
+}
+
+// prepare prüft Format und Mapping und zählt die Datenzeilen; ein fehlerhafter CSV-Kopf fällt dabei auf.
+func (s *ImportService) prepare(format export.Format, data []byte, opts models.ImportOptions) (int, error) {
+	if !importer.Supported(format) {
+		return 0, fmt.Errorf("unsupported format %q", format)
+	}
+	if err := validateImportMapping(opts.Mapping); err != nil {
+		return 0, err
+	}
+	reader, err := importer.NewReader(format, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	rows := 0
+	for {
+		rec, err := reader.Next()
+		if err != nil {
+			return 0, err
+		}
+		if rec == nil {
+			return rows, nil
+		}
+		rows++
+	}
+}
+
+// validateImportMapping prüft, dass jede Spalte auf ein Feld aus models.ImportFields und jedes Feld auf
+// höchstens eine Spalte abgebildet wird.
+func validateImportMapping(mapping map[string]string) error {
+	seen := map[string]bool{}
+	for _, field := range mapping {
+		if !slices.Contains(models.ImportFields, field) {
+			return fmt.Errorf("mapping targets must be one of: %s", strings.Join(models.ImportFields, ", "))
+		}
+		if seen[field] {
+			return fmt.Errorf("mapping maps several columns to %s", field)
+		}
+		seen[field] = true
+	}
+	return nil
+}
+
+// process liest alle Zeilen und meldet für jede Zeile deren Fehler und ob ein Task angelegt wurde.
+func (s *ImportService) process(format export.Format, data []byte, opts models.ImportOptions, record func([]models.ImportRowError, bool)) error {
+	reader, err := importer.NewReader(format, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	workflow := s.Tasks.GetWorkflow()
+	for {
+		rec, err := reader.Next()
+		if err != nil {
+			return err
+		}
+		if rec == nil {
+			return nil
+		}
+		if rec.Err != nil {
+			record([]models.ImportRowError{{Row: rec.Line, Message: rec.Err.Error()}}, false)
+			continue
+		}
+
+		req, convErrs := importRequest(rec.Values, opts)
+		if convErrs != nil {
+			record(rowErrors(rec.Line, convErrs), false)
+			continue
+		}
+		if opts.DryRun {
+			record(rowErrors(rec.Line, s.checkRow(workflow, req)), false)
+			continue
+		}
+		_, err = s.Tasks.CreateTask(req)
+		record(rowErrors(rec.Line, err), err == nil)
+	}
+}
+
+// checkRow prüft eine Zeile im Dry-Run wie CreateTask, ohne sie anzulegen.
+func (s *ImportService) checkRow(workflow *models.Workflow, req models.CreateTaskRequest) error {
+	if err := ValidateTaskRequest(workflow, req, true); err != nil {
+		return err
+	}
+	if req.ParentID != nil && *req.ParentID != 0 {
+		if _, err := s.Tasks.GetTaskByID(*req.ParentID); err != nil {
+			if err.Error() == "not found" {
+				return fmt.Errorf("parent not found")
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// countRow zählt eine verarbeitete Zeile.
+func countRow(summary *models.ImportSummary, rowErrs []models.ImportRowError, created bool) {
+	summary.Rows++
+	if len(rowErrs) > 0 {
+		summary.Failed++
+		return
+	}
+	summary.Valid++
+	if created {
+		summary.Created++
+	}
+}
+
+// rowErrors übersetzt den Fehler einer Zeile in Zeilenfehler: validation.Errors je Feld, Fehler des Parents
+// zu parent_id und alle anderen Fehler für die ganze Zeile.
+func rowErrors(line int, err error) []models.ImportRowError {
+	if err == nil {
+		return nil
+	}
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		rowErrs := make([]models.ImportRowError, len(errs))
+		for i, fe := range errs {
+			rowErrs[i] = models.ImportRowError{Row: line, Field: fe.Field, Message: fe.Message}
+		}
+		return rowErrs
+	}
+	if serviceParentErrors[err.Error()] {
+		return []models.ImportRowError{{Row: line, Field: "parent_id", Message: err.Error()}}
+	}
+	return []models.ImportRowError{{Row: line, Message: err.Error()}}
+}
+
+// serviceParentErrors sind Fehler von CreateTask, die sich auf parent_id beziehen.
+var serviceParentErrors = map[string]bool{
+	"parent not found":              true,
+	"task cannot be its own parent": true,
+	"cycle detected":                true,
+}
+
+// importRequest wandelt die Werte einer Zeile über das Mapping in einen CreateTaskRequest um.
+// Leere Werte lassen das Feld ungesetzt. Werte, die nicht zum Feld passen, werden als validation.Errors gemeldet.
+func importRequest(values map[string]any, opts models.ImportOptions) (models.CreateTaskRequest, error) {
+	fields := map[string]any{}
+	targets := map[string]bool{}
+	for column, field := range opts.Mapping {
+		targets[field] = true
+		if v, ok := values[column]; ok {
+			fields[field] = v
+		}
+	}
+	for _, field := range models.ImportFields {
+		if v, ok := values[field]; ok && !targets[field] {
+			fields[field] = v
+		}
+	}
+
+	var req models.CreateTaskRequest
+	var v validation.Validator
+	for _, field := range models.ImportFields {
+		value, ok := fields[field]
+		if !ok || value == nil || value == "" {
+			continue
+		}
+		switch field {
+		case "title", "description", "status", "priority", "recurrence_rule":
+			text, ok := value.(string)
+			if !ok {
+				v.Check(false, field, "must be a string")
+				continue
+			}
+			switch field {
+			case "title":
+				req.Title = text
+			case "description":
+				req.Description = text
+			case "status":
+				req.Status = text
+			case "priority":
+				req.Priority = text
+			case "recurrence_rule":
+				req.RecurrenceRule = &text
+			}
+		case "parent_id":
+			id, ok := importInt(value)
+			if !ok {
+				v.Check(false, field, "must be an integer")
+				continue
+			}
+			req.ParentID = &id
+		case "start_at", "due_at":
+			t, ok := importTime(value, opts.Location)
+			if !ok {
+				v.Check(false, field, "must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+				continue
+			}
+			if field == "start_at" {
+				req.StartAt = &t
+			} else {
+				req.DueAt = &t
+			}
+		}
+	}
+	return req, v.Err()
+}
+
+// importInt liest eine ganze Zahl aus einem Text (CSV) oder einer JSON-Zahl (NDJSON).
+func importInt(value any) (int, bool) {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case json.Number:
+		text = v.String()
+	default:
+		return 0, false
+	}
+	n, err := strconv.Atoi(text)
+	return n, err == nil
+}
+
+// importTime liest einen Zeitpunkt als RFC 3339 oder ein Datum (Tagesbeginn in loc, Default UTC).
+func importTime(value any, loc *time.Location) (time.Time, bool) {
+	text, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t, true
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation(time.DateOnly, text, loc)
+	return t, err == nil
+}
+
+// newImportJobID erzeugt eine zufällige, nicht erratbare Job-ID.
+func newImportJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"task-api/export"
+	"task-api/models"
+)
+
+// ImportServiceInterface definiert die Methoden, die jeder ImportService implementieren muss.
+type ImportServiceInterface interface {
+	// Import liest alle Zeilen von data und legt für jede gültige Zeile einen Task an (bei DryRun nur Prüfung).
+	// Gibt "too many rows" zurück, wenn die Datei mehr als MaxSyncRows Datenzeilen hat, und einen Fehler,
+	// wenn die Datei oder das Mapping ungültig ist. Fehler einzelner Zeilen stehen im Ergebnis.
+	Import(format export.Format, data []byte, opts models.ImportOptions) (*models.ImportResult, error)
+
+	// StartImport prüft Datei und Mapping und verarbeitet die Zeilen im Hintergrund.
+	// Gibt den gestarteten Job zurück.
+	StartImport(format export.Format, data []byte, opts models.ImportOptions) (*models.ImportJob, error)
+
+	// GetImportJob gibt den aktuellen Stand eines Jobs zurück. Ein laufender Job, dessen Fortschritt lange
+	// nicht gespeichert wurde, wird als failed ("import interrupted") gemeldet.
+	// Gibt "not found" zurück, wenn der Job nicht (mehr) existiert.
+	GetImportJob(id string) (*models.ImportJob, error)
+
+	// GetImportErrors gibt die bisher gefundenen Fehler eines Jobs sortiert nach Zeile zurück.
+	// Gibt "not found" zurück, wenn der Job nicht (mehr) existiert.
+	GetImportErrors(id string) ([]models.ImportRowError, error)
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"task-api/export"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+	"time"
+)
+
+// recordingTaskService zählt die über CreateTask angelegten Tasks.
+type recordingTaskService struct {
+	*MockTaskService
+	created []models.CreateTaskRequest
+}
+
+func (r *recordingTaskService) CreateTask(req models.CreateTaskRequest) (*models.Task, error) {
+	task, err := r.MockTaskService.CreateTask(req)
+	if err == nil {
+		r.created = append(r.created, req)
+	}
+	return task, err
+}
+
+// importJobRepo liefert ein MockImportJobRepository, das Jobs und Fehlerberichte wie die Datenbank speichert.
+// Der Mutex schützt die Maps, da der Job in einer eigenen Goroutine gespeichert wird.
+func importJobRepo() *repository.MockImportJobRepository {
+	var mu sync.Mutex
+	jobs := map[string]models.ImportJob{}
+	rowErrors := map[string][]models.ImportRowError{}
+	return &repository.MockImportJobRepository{
+		CreateFunc: func(job *models.ImportJob) error {
+			mu.Lock()
+			defer mu.Unlock()
+			job.UpdatedAt = time.Now()
+			jobs[job.ID] = *job
+			return nil
+		},
+		UpdateFunc: func(job *models.ImportJob, errs []models.ImportRowError) error {
+			mu.Lock()
+			defer mu.Unlock()
+			job.UpdatedAt = time.Now()
+			jobs[job.ID] = *job
+			rowErrors[job.ID] = append(rowErrors[job.ID], errs...)
+			return nil
+		},
+		GetByIdFunc: func(id string) (*models.ImportJob, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			job, ok := jobs[id]
+			if !ok {
+				return nil, nil
+			}
+			return &job, nil
+		},
+		GetErrorsFunc: func(id string) ([]models.ImportRowError, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]models.ImportRowError{}, rowErrors[id]...), nil
+		},
+		DeleteExpiredFunc: func(before time.Time) (int, error) {
+			return 0, nil
+		},
+	}
+}
+
+// importCSV ist eine Datei mit einer gültigen und zwei fehlerhaften Zeilen in den Spalten eines Alt-Systems.
+const importCSV = "Summary,Prio,Due,Parent\n" +
+	"Release,high,2025-03-01,\n" +
+	",urgent,,\n" +
+	"Docs,low,next week,abc\n"
+
+var importMapping = map[string]string{"Summary": "title", "Prio": "priority", "Due": "due_at", "Parent": "parent_id"}
+
+// Test_ImportService_Import_CreatesValidRows prüft Mapping, Umwandlung der Werte und Fehler je Zeile und Feld.
+func Test_ImportService_Import_CreatesValidRows(t *testing.T) {
+	tasks := &recordingTaskService{MockTaskService: &MockTaskService{}}
+	service := &ImportService{Tasks: tasks}
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	result, err := service.Import(export.CSV, []byte(importCSV), models.ImportOptions{Mapping: importMapping, Location: berlin})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ImportSummary{Rows: 3, Valid: 1, Created: 1, Failed: 2}, result.ImportSummary)
+	assert.Equal(t, []models.ImportRowError{
+		{Row: 3, Field: "title", Message: "is required"},
+		{Row: 3, Field: "priority", Message: "must be one of: low, medium, high"},
+		{Row: 4, Field: "parent_id", Message: "must be an integer"},
+		{Row: 4, Field: "due_at", Message: "must be a date (YYYY-MM-DD) or RFC 3339 timestamp"},
+	}, result.Errors)
+	if assert.Len(t, tasks.created, 1) {
+		assert.Equal(t, "Release", tasks.created[0].Title)
+		assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, berlin), *tasks.created[0].DueAt)
+	}
+}
+
+// Test_ImportService_Import_DryRun prüft, dass ein Dry-Run nichts anlegt und fehlende Parents meldet.
+func Test_ImportService_Import_DryRun(t *testing.T) {
+	tasks := &recordingTaskService{MockTaskService: &MockTaskService{Tasks: []*models.Task{{ID: 1, Title: "Epic"}}}}
+	service := &ImportService{Tasks: tasks}
+	data := "{\"title\":\"Child\",\"parent_id\":1}\n{\"title\":\"Orphan\",\"parent_id\":9}\n{\"title\":7}\n"
+
+	result, err := service.Import(export.NDJSON, []byte(data), models.ImportOptions{DryRun: true})
+
+	assert.NoError(t, err)
+	assert.Empty(t, tasks.created)
+	assert.Equal(t, models.ImportSummary{DryRun: true, Rows: 3, Valid: 1, Failed: 2}, result.ImportSummary)
+	assert.Equal(t, []models.ImportRowError{
+		{Row: 2, Field: "parent_id", Message: "parent not found"},
+		{Row: 3, Field: "title", Message: "must be a string"},
+	}, result.Errors)
+}
+
+// Test_ImportService_Import_Limits prüft ungültige Mappings und die Grenze synchroner Importe.
+func Test_ImportService_Import_Limits(t *testing.T) {
+	service := &ImportService{Tasks: &MockTaskService{}, MaxSyncRows: 2}
+
+	_, err := service.Import(export.CSV, []byte(importCSV), models.ImportOptions{Mapping: importMapping})
+	assert.EqualError(t, err, "too many rows")
+
+	_, err = service.Import(export.CSV, []byte(importCSV), models.ImportOptions{Mapping: map[string]string{"Summary": "owner"}})
+	assert.ErrorContains(t, err, "mapping targets must be one of")
+
+	_, err = service.Import(export.CSV, []byte(importCSV), models.ImportOptions{Mapping: map[string]string{"Summary": "title", "Prio": "title"}})
+	assert.EqualError(t, err, "mapping maps several columns to title")
+}
+
+// Test_ImportService_StartImport_ReportsProgress prüft, dass ein Job im Hintergrund läuft und danach Zähler
+// und Fehlerbericht liefert, auch über eine andere Instanz mit derselben Datenbank.
+func Test_ImportService_StartImport_ReportsProgress(t *testing.T) {
+	tasks := &recordingTaskService{MockTaskService: &MockTaskService{}}
+	repo := importJobRepo()
+	var expiredBefore time.Time
+	repo.DeleteExpiredFunc = func(before time.Time) (int, error) {
+		expiredBefore = before
+		return 0, nil
+	}
+	service := &ImportService{Tasks: tasks, Repo: repo, MaxSyncRows: 1}
+	other := &ImportService{Tasks: tasks, Repo: repo}
+
+	job, err := service.StartImport(export.CSV, []byte(importCSV), models.ImportOptions{Mapping: importMapping})
+	assert.NoError(t, err)
+	assert.Equal(t, models.ImportJobRunning, job.Status)
+	assert.Equal(t, 3, job.TotalRows)
+	assert.WithinDuration(t, time.Now().Add(-DefaultImportJobTTL), expiredBefore, time.Minute)
+
+	assert.Eventually(t, func() bool {
+		job, _ = other.GetImportJob(job.ID)
+		return job.Status != models.ImportJobRunning
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, models.ImportJobCompleted, job.Status)
+	assert.Equal(t, 100, job.Progress)
+	assert.Equal(t, models.ImportSummary{Rows: 3, Valid: 1, Created: 1, Failed: 2}, job.ImportSummary)
+	assert.NotNil(t, job.FinishedAt)
+
+	rowErrs, err := other.GetImportErrors(job.ID)
+	assert.NoError(t, err)
+	assert.Len(t, rowErrs, 4)
+
+	_, err = service.GetImportJob("unknown")
+	assert.EqualError(t, err, "not found")
+	_, err = service.GetImportErrors("unknown")
+	assert.EqualError(t, err, "not found")
+}
+
+// Test_ImportService_GetImportJob_Interrupted prüft, dass ein laufender Job ohne gespeicherten Fortschritt
+// (z.B. nach dem Beenden seiner Instanz) als abgebrochen gemeldet wird.
+func Test_ImportService_GetImportJob_Interrupted(t *testing.T) {
+	repo := importJobRepo()
+	repo.GetByIdFunc = func(id string) (*models.ImportJob, error) {
+		return &models.ImportJob{ID: id, Status: models.ImportJobRunning, TotalRows: 10,
+			ImportSummary: models.ImportSummary{Rows: 5}, UpdatedAt: time.Now().Add(-time.Hour)}, nil
+	}
+	service := &ImportService{Tasks: &MockTaskService{}, Repo: repo}
+
+	job, err := service.GetImportJob("abc")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ImportJobFailed, job.Status)
+	assert.Equal(t, "import interrupted", job.Error)
+	assert.Equal(t, 50, job.Progress)
+}
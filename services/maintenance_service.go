@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"task-api/migrations"
+	"task-api/repository"
+	"task-api/storage"
+	"time"
+)
+
+// MaintenanceService kapselt die Wartungsaufgaben der Admin-Befehle: Migrationen anwenden, die
+// Schema-Version prüfen, gelöschte Tasks endgültig entfernen und alte Outbox-Ereignisse löschen.
+type MaintenanceService struct {
+	Schema     repository.SchemaRepositoryInterface
+	Tasks      repository.TaskRepositoryInterface
+	Webhooks   repository.WebhookRepositoryInterface
+	Store      storage.BlobStore      // Ablage der Anhänge gelöschter Tasks
+	Migrations []migrations.Migration // Aufsteigend nach Version, siehe migrations.All
+}
+
+// SchemaStatus beschreibt den Stand der Datenbank im Vergleich zu den Migrationen des Binarys.
+type SchemaStatus struct {
+	Tracked bool                   // schema_migrations existiert
+	Current int                    // Höchste angewendete Version, 0 ohne Einträge
+	Latest  int                    // Höchste Version der Migrationen
+	Pending []migrations.Migration // Noch nicht angewendete Migrationen
+	Unknown []int                  // Angewendete Versionen ohne Migration (Datenbank neuer als das Binary)
+}
+
+// SchemaStatus vergleicht die angewendeten Versionen mit den Migrationen.
+func (s *MaintenanceService) SchemaStatus() (*SchemaStatus, error) {
+	applied, err := s.Schema.AppliedVersions()
+	if err != nil {
+		return nil, err
+	}
+	status := &SchemaStatus{Tracked: applied != nil, Latest: migrations.Latest(s.Migrations)}
+	if len(applied) > 0 {
+		status.Current = applied[len(applied)-1]
+	}
+	for _, m := range s.Migrations {
+		if !slices.Contains(applied, m.Version) {
+			status.Pending = append(status.Pending, m)
+		}
+	}
+	for _, v := range applied {
+		if !slices.ContainsFunc(s.Migrations, func(m migrations.Migration) bool { return m.Version == v }) {
+			status.Unknown = append(status.Unknown, v)
+		}
+	}
+	return status, nil
+}
+
+// Migrate wendet alle ausstehenden Migrationen in aufsteigender Reihenfolge an und gibt sie zurück.
+// Eine fehlgeschlagene Migration bricht ab; bereits angewendete bleiben eingetragen.
+//
+// Datenbanken, die ohne schema_migrations angelegt wurden (z.B. über docker-entrypoint-initdb.d vor
+// Einführung der Versionstabelle), müssen einmalig mit baseline übernommen werden: Die Versionen 1 bis
+// baseline gelten dann als angewendet. Ohne baseline gibt Migrate für solche Datenbanken
+// "database is not tracked" zurück, statt die Migrationen erneut auszuführen.
+func (s *MaintenanceService) Migrate(baseline int) ([]migrations.Migration, error) {
+	status, err := s.SchemaStatus()
+	if err != nil {
+		return nil, err
+	}
+	untracked := !status.Tracked || status.Current == 0
+	if baseline < 0 || baseline > status.Latest {
+		return nil, fmt.Errorf("baseline must be between 1 and %d", status.Latest)
+	}
+	if baseline > 0 && !untracked {
+		return nil, fmt.Errorf("baseline is only allowed for untracked databases (current version %d)", status.Current)
+	}
+	if untracked && baseline == 0 {
+		exists, err := s.Schema.HasTable("tasks")
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("database is not tracked: tables exist but schema_migrations is empty; " +
+				"run migrate with --baseline <last applied version>")
+		}
+	}
+
+	if err := s.Schema.EnsureVersionTable(); err != nil {
+		return nil, err
+	}
+	if baseline > 0 {
+		var versions []int
+		for _, m := range s.Migrations {
+			if m.Version <= baseline {
+				versions = append(versions, m.Version)
+			}
+		}
+		if err := s.Schema.MarkApplied(versions); err != nil {
+			return nil, err
+		}
+	}
+
+	var applied []migrations.Migration
+	for _, m := range status.Pending {
+		if m.Version <= baseline {
+			continue
+		}
+		if err := s.Schema.Apply(m.Version, m.SQL); err != nil {
+			return applied, fmt.Errorf("%s: %w", m.Name, err)
+		}
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
+
+// PurgeTasks löscht Tasks endgültig, die vor mehr als olderThan über die API gelöscht wurden, samt Tags,
+// Zuweisungen, Kommentaren und Anhängen. Die Blobs der Anhänge werden danach aus Store entfernt; Fehler
+// dabei werden nur protokolliert. Mit dryRun wird nur gezählt.
+// Gibt "older than must be positive" für olderThan <= 0 zurück.
+func (s *MaintenanceService) PurgeTasks(olderThan time.Duration, dryRun bool) (int, error) {
+	if olderThan <= 0 {
+		return 0, fmt.Errorf("older than must be positive")
+	}
+	n, keys, err := s.Tasks.PurgeDeleted(time.Now().Add(-olderThan), dryRun)
+	if err != nil || dryRun {
+		return n, err
+	}
+	for _, key := range keys {
+		if err := s.Store.Delete(context.Background(), key); err != nil {
+			log.Printf("purge: could not delete blob %s: %v", key, err)
+		}
+	}
+	return n, nil
+}
+
+// PurgeEvents löscht verteilte Outbox-Ereignisse, die älter als olderThan sind und keine offenen
+// Zustellungen mehr haben, samt Zustellprotokoll (auch Dead Letters). Mit dryRun wird nur gezählt. Gibt "older than must be positive" für olderThan <= 0 zurück.
+func (s *MaintenanceService) PurgeEvents(olderThan time.Duration, dryRun bool) (int, error) {
+	if olderThan <= 0 {
+		return 0, fmt.Errorf("older than must be positive")
+	}
+	return s.Webhooks.PurgeEvents(time.Now().Add(-olderThan), dryRun)
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"task-api/migrations"
+	"task-api/repository"
+	"task-api/storage"
+	"testing"
+	"time"
+)
+
+// testMigrations sind drei Migrationen für die Tests des MaintenanceService.
+var testMigrations = []migrations.Migration{
+	{Version: 1, Name: "001_a.sql", SQL: "A"},
+	{Version: 2, Name: "002_b.sql", SQL: "B"},
+	{Version: 3, Name: "003_c.sql", SQL: "C"},
+}
+
+// schemaRepo erstellt einen MockSchemaRepository, der angewendete Versionen in applied (nil = keine
+// Versionstabelle) führt. tasksExist legt fest, ob die Tabelle tasks bereits existiert.
+func schemaRepo(applied *[]int, tasksExist bool) *repository.MockSchemaRepository {
+	return &repository.MockSchemaRepository{
+		AppliedVersionsFunc: func() ([]int, error) { return *applied, nil },
+		HasTableFunc:        func(name string) (bool, error) { return name == "tasks" && tasksExist, nil },
+		EnsureVersionTableFunc: func() error {
+			if *applied == nil {
+				*applied = []int{}
+			}
+			return nil
+		},
+		MarkAppliedFunc: func(versions []int) error {
+			*applied = append(*applied, versions...)
+			return nil
+		},
+		ApplyFunc: func(version int, sql string) error {
+			if sql == "FAIL" {
+				return errors.New("syntax error")
+			}
+			*applied = append(*applied, version)
+			return nil
+		},
+	}
+}
+
+// Test_MaintenanceService_Migrate prüft das Anwenden ausstehender Migrationen auf einer leeren und
+// einer teilweise migrierten Datenbank.
+func Test_MaintenanceService_Migrate(t *testing.T) {
+	var applied []int
+	service := MaintenanceService{Schema: schemaRepo(&applied, false), Migrations: testMigrations}
+
+	done, err := service.Migrate(0)
+	assert.NoError(t, err)
+	assert.Len(t, done, 3)
+	assert.Equal(t, []int{1, 2, 3}, applied)
+
+	status, err := service.SchemaStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, &SchemaStatus{Tracked: true, Current: 3, Latest: 3}, status)
+
+	done, err = service.Migrate(0)
+	assert.NoError(t, err)
+	assert.Empty(t, done)
+
+	applied = []int{1, 2, 3, 4}
+	status, _ = service.SchemaStatus()
+	assert.Equal(t, []int{4}, status.Unknown)
+}
+
+// Test_MaintenanceService_MigrateBaseline prüft, dass eine Datenbank ohne Versionstabelle nur mit
+// baseline übernommen wird und dabei keine bereits vorhandenen Migrationen erneut laufen.
+func Test_MaintenanceService_MigrateBaseline(t *testing.T) {
+	var applied []int
+	service := MaintenanceService{Schema: schemaRepo(&applied, true), Migrations: testMigrations}
+
+	status, err := service.SchemaStatus()
+	assert.NoError(t, err)
+	assert.False(t, status.Tracked)
+	assert.Len(t, status.Pending, 3)
+
+	_, err = service.Migrate(0)
+	assert.ErrorContains(t, err, "database is not tracked")
+	assert.Nil(t, applied)
+
+	_, err = service.Migrate(4)
+	assert.EqualError(t, err, "baseline must be between 1 and 3")
+
+	done, err := service.Migrate(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []migrations.Migration{testMigrations[2]}, done)
+	assert.Equal(t, []int{1, 2, 3}, applied)
+
+	_, err = service.Migrate(1)
+	assert.EqualError(t, err, "baseline is only allowed for untracked databases (current version 3)")
+}
+
+// Test_MaintenanceService_MigrateFailure prüft, dass eine fehlerhafte Migration abbricht und die
+// vorherigen eingetragen bleiben.
+func Test_MaintenanceService_MigrateFailure(t *testing.T) {
+	var applied []int
+	list := []migrations.Migration{testMigrations[0], {Version: 2, Name: "002_kaputt.sql", SQL: "FAIL"}, testMigrations[2]}
+	service := MaintenanceService{Schema: schemaRepo(&applied, false), Migrations: list}
+
+	done, err := service.Migrate(0)
+	assert.EqualError(t, err, "002_kaputt.sql: syntax error")
+	assert.Equal(t, []migrations.Migration{testMigrations[0]}, done)
+	assert.Equal(t, []int{1}, applied)
+}
+
+// Test_MaintenanceService_PurgeTasks prüft den Stichtag, dryRun und das Entfernen der Anhang-Blobs.
+func Test_MaintenanceService_PurgeTasks(t *testing.T) {
+	store := &storage.LocalStore{Dir: t.TempDir()}
+	ctx := context.Background()
+	assert.NoError(t, store.Put(ctx, "tasks/1/a", strings.NewReader("A"), 1, "text/plain"))
+	assert.NoError(t, store.Put(ctx, "tasks/2/b", strings.NewReader("B"), 1, "text/plain"))
+
+	var gotBefore time.Time
+	service := MaintenanceService{Store: store, Tasks: &repository.MockTaskRepository{
+		PurgeDeletedFunc: func(before time.Time, dryRun bool) (int, []string, error) {
+			gotBefore = before
+			return 2, []string{"tasks/1/a"}, nil
+		},
+	}}
+
+	// Beim Dry-Run bleiben die Blobs erhalten
+	n, err := service.PurgeTasks(72*time.Hour, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.WithinDuration(t, time.Now().Add(-72*time.Hour), gotBefore, time.Minute)
+	_, err = store.Get(ctx, "tasks/1/a", 0, -1)
+	assert.NoError(t, err)
+
+	n, err = service.PurgeTasks(72*time.Hour, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	_, err = store.Get(ctx, "tasks/1/a", 0, -1)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = store.Get(ctx, "tasks/2/b", 0, -1)
+	assert.NoError(t, err)
+
+	_, err = service.PurgeTasks(-time.Hour, false)
+	assert.EqualError(t, err, "older than must be positive")
+}
+
+// Test_MaintenanceService_PurgeEvents prüft den Stichtag und die Weitergabe von dryRun.
+func Test_MaintenanceService_PurgeEvents(t *testing.T) {
+	var gotBefore time.Time
+	var gotDryRun bool
+	service := MaintenanceService{Webhooks: &repository.MockWebhookRepository{
+		PurgeEventsFunc: func(before time.Time, dryRun bool) (int, error) {
+			gotBefore, gotDryRun = before, dryRun
+			return 7, nil
+		},
+	}}
+
+	n, err := service.PurgeEvents(48*time.Hour, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, n)
+	assert.True(t, gotDryRun)
+	assert.WithinDuration(t, time.Now().Add(-48*time.Hour), gotBefore, time.Minute)
+
+	_, err = service.PurgeEvents(0, false)
+	assert.EqualError(t, err, "older than must be positive")
+}
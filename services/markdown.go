@@ -0,0 +1,64 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"regexp"
+	"strings"
+	"task-api/models"
+)
+
+// mentionPattern erkennt Erwähnungen der Form "@erika@example.com".
+// Das @ darf nicht Teil eines Wortes oder einer E-Mail-Adresse sein.
+var mentionPattern = regexp.MustCompile(`(^|[^\w.+@-])@([\w.+-]+@[\w-]+(?:\.[\w-]+)+)`)
+
+// markdownEscaper maskiert Zeichen, die Markdown innerhalb eines Link-Textes interpretieren würde.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, `_`, `\_`, `*`, `\*`, `[`, `\[`, `]`, `\]`)
+
+// htmlPolicy entfernt aus dem gerenderten HTML alles, was nicht für nutzergenerierte Inhalte
+// geeignet ist (Skripte, Event-Handler, javascript:-Links, ...).
+var htmlPolicy = bluemonday.UGCPolicy()
+
+// parseMentions gibt die E-Mail-Adressen aller erwähnten Benutzer in der Reihenfolge ihres
+// ersten Auftretens zurück.
+func parseMentions(body string) []string {
+	var emails []string
+	seen := map[string]bool{}
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		email := strings.TrimRight(m[2], ".")
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// renderMarkdown wandelt den Markdown-Body eines Kommentars in bereinigtes HTML um.
+// Erwähnungen bekannter Benutzer werden dabei zu Links auf /users/:id.
+func renderMarkdown(body string, mentions []models.Mention) string {
+	ids := make(map[string]int, len(mentions))
+	for _, m := range mentions {
+		ids[m.Email] = m.UserID
+	}
+
+	linked := mentionPattern.ReplaceAllStringFunc(body, func(match string) string {
+		sub := mentionPattern.FindStringSubmatch(match)
+		email := strings.TrimRight(sub[2], ".")
+		id, ok := ids[email]
+		if !ok {
+			return match
+		}
+		rest := strings.TrimPrefix(sub[2], email)
+		return fmt.Sprintf("%s[@%s](/users/%d)%s", sub[1], markdownEscaper.Replace(email), id, rest)
+	})
+
+	var buf bytes.Buffer
+	if err := goldmark.Convert([]byte(linked), &buf); err != nil {
+		// Fällt auf reinen, maskierten Text zurück
+		return htmlPolicy.Sanitize(body)
+	}
+	return htmlPolicy.Sanitize(buf.String())
+}
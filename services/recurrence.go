@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/teambition/rrule-go"
+	"log"
+	"task-api/models"
+	"time"
+)
+
+// Gültige Werte für CreateTaskRequest.Scope beim Aktualisieren eines Vorkommens einer Serie.
+const (
+	// ScopeThis ändert nur das angegebene Vorkommen (Default).
+	ScopeThis = "this"
+	// ScopeFuture überträgt Titel, Beschreibung, Priorität und Regel auf alle folgenden Vorkommen.
+	ScopeFuture = "future"
+)
+
+// normalizeRecurrenceRule prüft eine Wiederholungsregel nach RFC 5545 und ergänzt DTSTART,
+// falls die Regel keinen Startzeitpunkt enthält. Als Startzeitpunkt dient die Fälligkeit (anchor).
+// Gibt die Regel in kanonischer Form ("DTSTART:...\nRRULE:...") zurück.
+func normalizeRecurrenceRule(rule string, anchor *time.Time) (string, error) {
+	if anchor == nil {
+		return "", fmt.Errorf("requires due_at")
+	}
+
+	opt, err := rrule.StrToROption(rule)
+	if err != nil {
+		return "", fmt.Errorf("must be a valid RRULE (RFC 5545)")
+	}
+	if opt.Dtstart.IsZero() {
+		opt.Dtstart = *anchor
+	}
+
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return "", fmt.Errorf("must be a valid RRULE (RFC 5545)")
+	}
+	return r.String(), nil
+}
+
+// nextOccurrence berechnet das erste Vorkommen der Regel nach dem Zeitpunkt after.
+// Gibt nil zurück, wenn die Serie (z.B. durch COUNT oder UNTIL) beendet ist.
+func nextOccurrence(rule string, after time.Time) (*time.Time, error) {
+	r, err := rrule.StrToRRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	next := r.After(after, false)
+	if next.IsZero() {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+// spawnNextOccurrence legt das nächste Vorkommen einer Serie an, sofern die Regel ein weiteres
+// Vorkommen vorsieht und dieses noch nicht existiert. Titel, Beschreibung, Priorität und Parent
+// werden übernommen, der Status wird auf den Startstatus des Workflows gesetzt und ein geplanter
+// Beginn um denselben Abstand zur Fälligkeit verschoben.
+// Gibt das neue Vorkommen zurück oder nil, wenn keines angelegt wurde.
+func (s *TaskService) spawnNextOccurrence(task *models.Task) (*models.Task, error) {
+	if task.RecurrenceRule == "" || task.DueAt == nil {
+		return nil, nil
+	}
+
+	next, err := nextOccurrence(task.RecurrenceRule, *task.DueAt)
+	if err != nil || next == nil {
+		return nil, err
+	}
+
+	seriesID := task.ID
+	if task.SeriesID != nil {
+		seriesID = *task.SeriesID
+	}
+
+	// Existiert bereits ein Vorkommen zu oder nach diesem Termin, wurde die Serie schon fortgesetzt.
+	// Gelöschte Vorkommen zählen mit, damit ein gelöschtes Vorkommen nicht erneut angelegt wird.
+	exists, err := s.Repo.HasOccurrenceFrom(seriesID, *next)
+	if err != nil || exists {
+		return nil, err
+	}
+
+	occurrence := &models.Task{
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         s.GetWorkflow().InitialStatus(),
+		Priority:       task.Priority,
+		ParentID:       task.ParentID,
+		DueAt:          next,
+		RecurrenceRule: task.RecurrenceRule,
+		SeriesID:       &seriesID,
+	}
+	if task.StartAt != nil {
+		start := next.Add(task.StartAt.Sub(*task.DueAt))
+		occurrence.StartAt = &start
+	}
+
+	// Legt eine parallele Instanz das Vorkommen zeitgleich an, verhindert der eindeutige Index ein Duplikat
+	return s.Repo.CreateOccurrence(occurrence, models.TaskEvent{Type: models.EventTaskCreated})
+}
+
+// updateFutureOccurrences überträgt Titel, Beschreibung, Priorität und Wiederholungsregel
+// des Vorkommens task auf alle später fälligen Vorkommen derselben Serie.
+func (s *TaskService) updateFutureOccurrences(task *models.Task) error {
+	if task.SeriesID == nil || task.DueAt == nil {
+		return nil
+	}
+
+	occurrences, err := s.Repo.GetSeries(*task.SeriesID)
+	if err != nil {
+		return err
+	}
+	for _, o := range occurrences {
+		if o.ID == task.ID || o.DueAt == nil || !o.DueAt.After(*task.DueAt) {
+			continue
+		}
+		o.Title = task.Title
+		o.Description = task.Description
+		o.Priority = task.Priority
+		o.RecurrenceRule = task.RecurrenceRule
+		if _, err := s.Repo.Update(o, models.TaskEvent{Type: models.EventTaskUpdated}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SpawnDueOccurrences setzt alle wiederkehrenden Serien fort, deren nächstes Vorkommen
+// bis zum Zeitpunkt until fällig wird – unabhängig davon, ob das letzte Vorkommen erledigt ist.
+// Gibt die Anzahl der angelegten Vorkommen zurück.
+func (s *TaskService) SpawnDueOccurrences(until time.Time) (int, error) {
+	latest, err := s.Repo.GetLatestOccurrences()
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, task := range latest {
+		// Nach längerem Ausfall werden alle verpassten Vorkommen nacheinander nachgeholt
+		for task != nil && task.DueAt != nil {
+			next, err := nextOccurrence(task.RecurrenceRule, *task.DueAt)
+			if err != nil {
+				return created, err
+			}
+			if next == nil || next.After(until) {
+				break
+			}
+
+			task, err = s.spawnNextOccurrence(task)
+			if err != nil {
+				return created, err
+			}
+			if task != nil {
+				created++
+			}
+		}
+	}
+	return created, nil
+}
+
+// RecurrenceWorker legt im Hintergrund regelmäßig die nächsten Vorkommen wiederkehrender
+// Tasks an, sobald diese innerhalb von LeadTime fällig werden.
+type RecurrenceWorker struct {
+	Service *TaskService
+
+	// LeadTime legt fest, wie lange vor seiner Fälligkeit ein Vorkommen angelegt wird (z.B. 24h).
+	LeadTime time.Duration
+
+	// PollInterval legt fest, wie oft geprüft wird. Default: 1 Minute.
+	PollInterval time.Duration
+}
+
+// Run startet die Prüfschleife und blockiert, bis ctx beendet wird.
+func (w *RecurrenceWorker) Run(ctx context.Context) {
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := w.Service.SpawnDueOccurrences(time.Now().Add(w.LeadTime)); err != nil {
+			log.Printf("recurrence worker: %v", err)
+		} else if n > 0 {
+			log.Printf("recurrence worker: created %d occurrence(s)", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
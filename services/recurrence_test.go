@@ -0,0 +1,221 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+	"time"
+)
+
+// Diese Datei enthält Unit-Tests für wiederkehrende Tasks im TaskService.
+
+// seriesRepo liefert ein MockTaskRepository, das Tasks in einer Map speichert, damit Serien
+// über mehrere Service-Aufrufe hinweg nachvollzogen werden können. Wie der eindeutige Index der
+// Datenbank lehnt CreateOccurrence ein zweites Vorkommen mit derselben Fälligkeit ab.
+func seriesRepo(tasks map[int]*models.Task) *repository.MockTaskRepository {
+	nextID := len(tasks) + 1
+	create := func(task *models.Task) (*models.Task, error) {
+		task.ID = nextID
+		nextID++
+		tasks[task.ID] = task
+		return task, nil
+	}
+	inSeries := func(seriesID int, match func(due time.Time) bool) bool {
+		for _, t := range tasks {
+			if t.SeriesID != nil && *t.SeriesID == seriesID && t.DueAt != nil && match(*t.DueAt) {
+				return true
+			}
+		}
+		return false
+	}
+	return &repository.MockTaskRepository{
+		CreateFunc: create,
+		CreateOccurrenceFunc: func(task *models.Task) (*models.Task, error) {
+			if inSeries(*task.SeriesID, task.DueAt.Equal) {
+				return nil, nil
+			}
+			return create(task)
+		},
+		HasOccurrenceFromFunc: func(seriesID int, from time.Time) (bool, error) {
+			return inSeries(seriesID, func(due time.Time) bool { return !due.Before(from) }), nil
+		},
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			return tasks[id], nil
+		},
+		UpdateFunc: func(task *models.Task) (*models.Task, error) {
+			tasks[task.ID] = task
+			return task, nil
+		},
+		GetSeriesFunc: func(seriesID int) ([]*models.Task, error) {
+			var series []*models.Task
+			for id := 1; id < nextID; id++ {
+				if t := tasks[id]; t != nil && t.SeriesID != nil && *t.SeriesID == seriesID {
+					series = append(series, t)
+				}
+			}
+			return series, nil
+		},
+	}
+}
+
+// Test_Service_CreateTask_Recurring prüft, dass die Regel normalisiert (DTSTART ergänzt) und das erste
+// Vorkommen beim Anlegen als Serie markiert wird, ohne nachträgliches Update.
+func Test_Service_CreateTask_Recurring(t *testing.T) {
+	tasks := map[int]*models.Task{}
+	mockRepo := seriesRepo(tasks)
+	mockRepo.UpdateFunc = nil
+	service := TaskService{Repo: mockRepo}
+
+	due := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	rule := "FREQ=WEEKLY;BYDAY=MO"
+	task, err := service.CreateTask(models.CreateTaskRequest{Title: "Wochenbericht", DueAt: &due, RecurrenceRule: &rule})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "DTSTART:20250303T090000Z\nRRULE:FREQ=WEEKLY;BYDAY=MO", task.RecurrenceRule)
+	assert.Equal(t, task.ID, *task.SeriesID)
+	assert.Equal(t, []models.TaskEvent{{Type: models.EventTaskCreated}}, mockRepo.Events)
+}
+
+// Test_Service_CreateTask_RecurringInvalid prüft, dass ungültige Regeln und Regeln ohne Fälligkeit abgelehnt werden.
+func Test_Service_CreateTask_RecurringInvalid(t *testing.T) {
+	service := TaskService{Repo: seriesRepo(map[int]*models.Task{})}
+
+	rule := "FREQ=SOMETIMES"
+	due := time.Now()
+	_, err := service.CreateTask(models.CreateTaskRequest{Title: "Audit", DueAt: &due, RecurrenceRule: &rule})
+	assert.Equal(t, "recurrence_rule: must be a valid RRULE (RFC 5545)", err.Error())
+
+	rule = "FREQ=MONTHLY"
+	_, err = service.CreateTask(models.CreateTaskRequest{Title: "Audit", RecurrenceRule: &rule})
+	assert.Equal(t, "recurrence_rule: requires due_at", err.Error())
+}
+
+// Test_Service_UpdateTask_DoneSpawnsNextOccurrence prüft, dass beim Erledigen eines Vorkommens genau ein
+// Folgevorkommen angelegt wird, auch wenn der Task wieder geöffnet und erneut erledigt wird.
+func Test_Service_UpdateTask_DoneSpawnsNextOccurrence(t *testing.T) {
+	tasks := map[int]*models.Task{}
+	service := TaskService{Repo: seriesRepo(tasks)}
+
+	due := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	start := due.Add(-2 * time.Hour)
+	rule := "FREQ=WEEKLY;BYDAY=MO"
+	first, err := service.CreateTask(models.CreateTaskRequest{Title: "Wochenbericht", StartAt: &start, DueAt: &due, RecurrenceRule: &rule})
+	assert.NoError(t, err)
+
+	_, err = service.UpdateTask(first.ID, models.CreateTaskRequest{Status: "done"})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	next := tasks[2]
+	assert.Equal(t, "todo", next.Status)
+	assert.Equal(t, due.AddDate(0, 0, 7), next.DueAt.UTC())
+	assert.Equal(t, start.AddDate(0, 0, 7), next.StartAt.UTC())
+	assert.Equal(t, first.ID, *next.SeriesID)
+
+	_, err = service.UpdateTask(first.ID, models.CreateTaskRequest{Status: "todo", Reopen: true})
+	assert.NoError(t, err)
+	_, err = service.UpdateTask(first.ID, models.CreateTaskRequest{Status: "done"})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+}
+
+// Test_Service_UpdateTask_ScopeFuture prüft, dass Änderungen mit Scope "future" auf folgende, aber nicht auf
+// vorherige Vorkommen übertragen werden.
+func Test_Service_UpdateTask_ScopeFuture(t *testing.T) {
+	seriesID := 1
+	rule := "DTSTART:20250303T090000Z\nRRULE:FREQ=WEEKLY"
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 3, d, 9, 0, 0, 0, time.UTC)
+		return &t
+	}
+	tasks := map[int]*models.Task{
+		1: {ID: 1, Title: "Bericht", Status: "done", DueAt: day(3), RecurrenceRule: rule, SeriesID: &seriesID},
+		2: {ID: 2, Title: "Bericht", Status: "todo", DueAt: day(10), RecurrenceRule: rule, SeriesID: &seriesID},
+		3: {ID: 3, Title: "Bericht", Status: "todo", DueAt: day(17), RecurrenceRule: rule, SeriesID: &seriesID},
+	}
+	service := TaskService{Repo: seriesRepo(tasks)}
+
+	_, err := service.UpdateTask(2, models.CreateTaskRequest{Title: "Monatsbericht", Scope: ScopeFuture})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bericht", tasks[1].Title)
+	assert.Equal(t, "Monatsbericht", tasks[2].Title)
+	assert.Equal(t, "Monatsbericht", tasks[3].Title)
+
+	_, err = service.UpdateTask(2, models.CreateTaskRequest{Title: "Nur dieser", Scope: ScopeThis})
+	assert.NoError(t, err)
+	assert.Equal(t, "Monatsbericht", tasks[3].Title)
+
+	_, err = service.UpdateTask(2, models.CreateTaskRequest{Scope: "all"})
+	assert.Equal(t, "scope: must be one of: this, future", err.Error())
+}
+
+// Test_Service_SpawnDueOccurrences prüft, dass der Worker verpasste Vorkommen bis zum Zeitpunkt until nachholt
+// und eine Serie mit COUNT nicht über ihr Ende hinaus fortsetzt.
+func Test_Service_SpawnDueOccurrences(t *testing.T) {
+	seriesID := 1
+	due := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	tasks := map[int]*models.Task{
+		1: {ID: 1, Title: "Backup prüfen", Status: "todo", DueAt: &due, SeriesID: &seriesID,
+			RecurrenceRule: "DTSTART:20250303T090000Z\nRRULE:FREQ=DAILY;COUNT=3"},
+	}
+	mockRepo := seriesRepo(tasks)
+	mockRepo.GetLatestOccurrencesFunc = func() ([]*models.Task, error) {
+		return []*models.Task{tasks[1]}, nil
+	}
+	service := TaskService{Repo: mockRepo}
+
+	created, err := service.SpawnDueOccurrences(due.AddDate(0, 0, 10))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+	assert.Len(t, tasks, 3)
+}
+
+// Test_Service_UpdateTask_DeletedOccurrenceNotRespawned prüft, dass ein gelöschtes Folgevorkommen beim
+// Erledigen des vorherigen Vorkommens nicht erneut angelegt wird.
+func Test_Service_UpdateTask_DeletedOccurrenceNotRespawned(t *testing.T) {
+	seriesID := 1
+	rule := "DTSTART:20250303T090000Z\nRRULE:FREQ=WEEKLY"
+	first := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 7)
+	tasks := map[int]*models.Task{
+		1: {ID: 1, Title: "Bericht", Status: "todo", DueAt: &first, RecurrenceRule: rule, SeriesID: &seriesID},
+		2: {ID: 2, Title: "Bericht", Status: "todo", DueAt: &second, RecurrenceRule: rule, SeriesID: &seriesID},
+	}
+	mockRepo := seriesRepo(tasks)
+	service := TaskService{Repo: mockRepo}
+
+	// Das gelöschte Vorkommen 2 bleibt als Tombstone in der Tabelle und zählt für HasOccurrenceFrom
+	_, err := service.UpdateTask(1, models.CreateTaskRequest{Status: "done"})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.NotContains(t, mockRepo.Events, models.TaskEvent{Type: models.EventTaskCreated})
+}
+
+// Test_Service_SpawnDueOccurrences_Concurrent prüft, dass ein von einer anderen Instanz zeitgleich angelegtes
+// Vorkommen weder doppelt angelegt noch mitgezählt wird.
+func Test_Service_SpawnDueOccurrences_Concurrent(t *testing.T) {
+	seriesID := 1
+	due := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	next := due.AddDate(0, 0, 1)
+	rule := "DTSTART:20250303T090000Z\nRRULE:FREQ=DAILY"
+	tasks := map[int]*models.Task{
+		1: {ID: 1, Title: "Backup prüfen", Status: "todo", DueAt: &due, SeriesID: &seriesID, RecurrenceRule: rule},
+		2: {ID: 2, Title: "Backup prüfen", Status: "todo", DueAt: &next, SeriesID: &seriesID, RecurrenceRule: rule},
+	}
+	mockRepo := seriesRepo(tasks)
+	mockRepo.GetLatestOccurrencesFunc = func() ([]*models.Task, error) {
+		return []*models.Task{tasks[1]}, nil
+	}
+	// Die andere Instanz legt Vorkommen 2 erst nach der Prüfung an
+	mockRepo.HasOccurrenceFromFunc = func(int, time.Time) (bool, error) {
+		return false, nil
+	}
+	service := TaskService{Repo: mockRepo}
+
+	created, err := service.SpawnDueOccurrences(next)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+	assert.Len(t, tasks, 2)
+	assert.Empty(t, mockRepo.Events)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"log"
+	"task-api/models"
+	"task-api/repository"
+	"time"
+)
+
+// ReminderScheduler prüft im Hintergrund regelmäßig, welche Tasks bald fällig werden, und schreibt für
+// jeden dieser Tasks genau ein task.reminder-Ereignis in die Outbox, das wie alle Task-Ereignisse an
+// Webhooks und Event-Streams ausgeliefert wird. Auch bei mehreren Instanzen wird jede Erinnerung nur
+// einmal ausgelöst. Wird die Fälligkeit eines Tasks geändert, wird die Erinnerung erneut versendet.
+type ReminderScheduler struct {
+	Repo repository.TaskRepositoryInterface
+
+	// LeadTime legt fest, wie lange vor der Fälligkeit erinnert wird (z.B. 1h).
+	LeadTime time.Duration
+
+	// PollInterval legt fest, wie oft nach fälligen Tasks gesucht wird. Default: 1 Minute.
+	PollInterval time.Duration
+
+	// now liefert die aktuelle Zeit; in Tests überschreibbar.
+	now func() time.Time
+}
+
+// Run startet die Prüfschleife und blockiert, bis ctx beendet wird.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	interval := s.PollInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.CheckDue(); err != nil {
+			log.Printf("reminder scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckDue reserviert einmalig alle Tasks, die innerhalb von LeadTime fällig werden, und löst für jeden
+// ein task.reminder-Ereignis aus.
+func (s *ReminderScheduler) CheckDue() error {
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+
+	ids, err := s.Repo.ClaimDueReminders(now.Add(s.LeadTime), models.TaskEvent{Type: models.EventTaskReminder})
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		log.Printf("reminder scheduler: sent reminders for %d task(s)", len(ids))
+	}
+	return nil
+}
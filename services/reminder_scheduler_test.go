@@ -0,0 +1,44 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+	"time"
+)
+
+// Test_ReminderScheduler_CheckDue prüft, dass für bald fällige Tasks je ein task.reminder-Ereignis in die
+// Outbox geschrieben wird.
+func Test_ReminderScheduler_CheckDue(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	var until time.Time
+	mockRepo := &repository.MockTaskRepository{
+		ClaimDueRemindersFunc: func(u time.Time) ([]int, error) {
+			until = u
+			return []int{7, 8}, nil
+		},
+	}
+	scheduler := ReminderScheduler{
+		Repo:     mockRepo,
+		LeadTime: time.Hour,
+		now:      func() time.Time { return now },
+	}
+
+	assert.NoError(t, scheduler.CheckDue())
+	assert.Equal(t, now.Add(time.Hour), until)
+	assert.Equal(t, []models.TaskEvent{{Type: models.EventTaskReminder}, {Type: models.EventTaskReminder}}, mockRepo.Events)
+}
+
+// Test_Service_CreateTask_StartAfterDue prüft, dass start_at nicht nach due_at liegen darf.
+func Test_Service_CreateTask_StartAfterDue(t *testing.T) {
+	service := TaskService{Repo: &repository.MockTaskRepository{}}
+
+	due := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	start := due.Add(time.Hour)
+	task, err := service.CreateTask(models.CreateTaskRequest{Title: "Plan", StartAt: &start, DueAt: &due})
+
+	assert.Nil(t, task)
+	assert.Equal(t, "due_at: must not be before start_at", err.Error())
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"task-api/models"
+	"task-api/repository"
+	"unicode/utf8"
+)
+
+// TagService kapselt die Businesslogik für Tags und deren Zuordnung zu Tasks.
+type TagService struct {
+	Repo  repository.TagRepositoryInterface
+	Tasks repository.TaskRepositoryInterface
+}
+
+// normalizeTagName entfernt führende und abschließende Leerzeichen und prüft die Länge.
+// Gibt "invalid tag name" zurück, wenn der Name leer oder länger als 50 Zeichen ist.
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > 50 {
+		return "", fmt.Errorf("invalid tag name")
+	}
+	return name, nil
+}
+
+// CreateTag legt einen neuen Tag an.
+// Gibt "tag already exists" zurück, wenn bereits ein Tag mit dem Namen existiert.
+func (s *TagService) CreateTag(req models.TagRequest) (*models.Tag, error) {
+	name, err := normalizeTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Repo.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("tag already exists")
+	}
+
+	return s.Repo.Create(name)
+}
+
+// GetAllTags gibt alle Tags zurück.
+func (s *TagService) GetAllTags() ([]*models.Tag, error) {
+	return s.Repo.GetAll()
+}
+
+// RenameTag ändert den Namen eines Tags.
+// Gibt "not found" zurück, wenn der Tag nicht existiert, und "tag already exists",
+// wenn der neue Name bereits von einem anderen Tag verwendet wird.
+func (s *TagService) RenameTag(id int, req models.TagRequest) (*models.Tag, error) {
+	name, err := normalizeTagName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, fmt.Errorf("not found")
+	}
+
+	existing, err := s.Repo.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ID != id {
+		return nil, fmt.Errorf("tag already exists")
+	}
+
+	return s.Repo.Rename(id, name)
+}
+
+// DeleteTag entfernt einen Tag inklusive aller Zuordnungen zu Tasks.
+// Gibt "not found" zurück, wenn der Tag nicht existiert.
+func (s *TagService) DeleteTag(id int) error {
+	tag, err := s.Repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if tag == nil {
+		return fmt.Errorf("not found")
+	}
+	return s.Repo.Delete(id)
+}
+
+// MergeTags führt den Tag sourceID in den Tag intoID über: Alle Tasks mit sourceID
+// erhalten intoID, danach wird sourceID gelöscht. Gibt den Ziel-Tag zurück.
+// Gibt "not found" zurück, wenn sourceID nicht existiert, und "target tag not found",
+// wenn intoID nicht existiert.
+func (s *TagService) MergeTags(sourceID, intoID int) (*models.Tag, error) {
+	if sourceID == intoID {
+		return nil, fmt.Errorf("cannot merge tag into itself")
+	}
+
+	source, err := s.Repo.GetByID(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, fmt.Errorf("not found")
+	}
+
+	target, err := s.Repo.GetByID(intoID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, fmt.Errorf("target tag not found")
+	}
+
+	if err := s.Repo.Merge(sourceID, intoID); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// AttachTag ordnet einem Task einen Tag zu, entweder über die ID eines bestehenden Tags
+// oder über einen Namen. Ein Tag mit unbekanntem Namen wird dabei angelegt.
+// Eine neue Zuordnung schreibt task.updated in die Outbox.
+// Gibt "task not found" zurück, wenn der Task nicht existiert, und "not found",
+// wenn die angegebene Tag-ID nicht existiert.
+func (s *TagService) AttachTag(taskID int, req models.AttachTagRequest) (*models.Tag, error) {
+	task, err := s.Tasks.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	var tag *models.Tag
+	if req.TagID != 0 {
+		tag, err = s.Repo.GetByID(req.TagID)
+		if err != nil {
+			return nil, err
+		}
+		if tag == nil {
+			return nil, fmt.Errorf("not found")
+		}
+	} else {
+		name, err := normalizeTagName(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		tag, err = s.Repo.GetByName(name)
+		if err != nil {
+			return nil, err
+		}
+		if tag == nil {
+			if tag, err = s.Repo.Create(name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.Repo.Attach(taskID, tag.ID, models.TaskEvent{Type: models.EventTaskUpdated}); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// DetachTag entfernt die Zuordnung eines Tags zu einem Task und schreibt task.updated in die Outbox.
+// Gibt "not found" zurück, wenn der Task diesen Tag nicht hat.
+func (s *TagService) DetachTag(taskID, tagID int) error {
+	removed, err := s.Repo.Detach(taskID, tagID, models.TaskEvent{Type: models.EventTaskUpdated})
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("not found")
+	}
+	return nil
+}
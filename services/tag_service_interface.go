@@ -0,0 +1,34 @@
+package services
+
+import "task-api/models"
+
+// TagServiceInterface definiert die Methoden, die jeder TagService implementieren muss.
+// Dient dazu, die echte Service-Logik in Handler-Tests durch einen Mock zu ersetzen.
+type TagServiceInterface interface {
+	// CreateTag legt einen neuen Tag an.
+	// Gibt "tag already exists" zurück, wenn der Name bereits vergeben ist.
+	CreateTag(req models.TagRequest) (*models.Tag, error)
+
+	// GetAllTags gibt alle Tags zurück.
+	GetAllTags() ([]*models.Tag, error)
+
+	// RenameTag ändert den Namen eines Tags.
+	// Gibt "not found" oder "tag already exists" zurück.
+	RenameTag(id int, req models.TagRequest) (*models.Tag, error)
+
+	// DeleteTag entfernt einen Tag inklusive aller Zuordnungen.
+	// Gibt "not found" zurück, wenn der Tag nicht existiert.
+	DeleteTag(id int) error
+
+	// MergeTags überträgt alle Zuordnungen von sourceID auf intoID und löscht sourceID.
+	// Gibt den Ziel-Tag zurück.
+	MergeTags(sourceID, intoID int) (*models.Tag, error)
+
+	// AttachTag ordnet einem Task einen bestehenden oder neu angelegten Tag zu.
+	// Gibt "task not found" zurück, wenn der Task nicht existiert.
+	AttachTag(taskID int, req models.AttachTagRequest) (*models.Tag, error)
+
+	// DetachTag entfernt die Zuordnung eines Tags zu einem Task.
+	// Gibt "not found" zurück, wenn die Zuordnung nicht existiert.
+	DetachTag(taskID, tagID int) error
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"task-api/models"
+)
+
+// MockTagService implementiert TagServiceInterface für Tests.
+// Felder:
+// - Tags: Vorhandene Tags.
+// - TaskTags: Zuordnungen Task-ID -> Tag-IDs.
+// - ShouldFail: Wenn true, schlagen alle Methoden absichtlich fehl.
+type MockTagService struct {
+	Tags       []*models.Tag
+	TaskTags   map[int][]int
+	ShouldFail bool
+}
+
+// CreateTag simuliert das Anlegen eines Tags.
+// Liefert "tag already exists", wenn der Name bereits vergeben ist.
+func (m *MockTagService) CreateTag(req models.TagRequest) (*models.Tag, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if m.byName(req.Name) != nil {
+		return nil, fmt.Errorf("tag already exists")
+	}
+	tag := &models.Tag{ID: len(m.Tags) + 1, Name: req.Name}
+	m.Tags = append(m.Tags, tag)
+	return tag, nil
+}
+
+// GetAllTags gibt alle Tags im Mock zurück.
+func (m *MockTagService) GetAllTags() ([]*models.Tag, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	return m.Tags, nil
+}
+
+// RenameTag simuliert das Umbenennen eines Tags.
+// Liefert "not found", wenn der Tag nicht existiert.
+func (m *MockTagService) RenameTag(id int, req models.TagRequest) (*models.Tag, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	tag := m.byID(id)
+	if tag == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	if other := m.byName(req.Name); other != nil && other.ID != id {
+		return nil, fmt.Errorf("tag already exists")
+	}
+	tag.Name = req.Name
+	return tag, nil
+}
+
+// DeleteTag simuliert das Löschen eines Tags.
+// Liefert "not found", wenn der Tag nicht existiert.
+func (m *MockTagService) DeleteTag(id int) error {
+	if m.ShouldFail {
+		return fiber.ErrInternalServerError
+	}
+	for i, t := range m.Tags {
+		if t.ID == id {
+			m.Tags = append(m.Tags[:i], m.Tags[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("not found")
+}
+
+// MergeTags simuliert das Zusammenführen zweier Tags.
+// Liefert "not found" bzw. "target tag not found", wenn einer der Tags nicht existiert.
+func (m *MockTagService) MergeTags(sourceID, intoID int) (*models.Tag, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if sourceID == intoID {
+		return nil, fmt.Errorf("cannot merge tag into itself")
+	}
+	if m.byID(sourceID) == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	target := m.byID(intoID)
+	if target == nil {
+		return nil, fmt.Errorf("target tag not found")
+	}
+	_ = m.DeleteTag(sourceID)
+	return target, nil
+}
+
+// AttachTag simuliert das Zuordnen eines Tags zu einem Task.
+// Ein unbekannter Name legt einen neuen Tag an.
+func (m *MockTagService) AttachTag(taskID int, req models.AttachTagRequest) (*models.Tag, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	tag := m.byID(req.TagID)
+	if tag == nil && req.TagID != 0 {
+		return nil, fmt.Errorf("not found")
+	}
+	if tag == nil {
+		if tag = m.byName(req.Name); tag == nil {
+			tag, _ = m.CreateTag(models.TagRequest{Name: req.Name})
+		}
+	}
+	if m.TaskTags == nil {
+		m.TaskTags = map[int][]int{}
+	}
+	m.TaskTags[taskID] = append(m.TaskTags[taskID], tag.ID)
+	return tag, nil
+}
+
+// DetachTag simuliert das Entfernen einer Zuordnung.
+// Liefert "not found", wenn die Zuordnung nicht existiert.
+func (m *MockTagService) DetachTag(taskID, tagID int) error {
+	if m.ShouldFail {
+		return fiber.ErrInternalServerError
+	}
+	ids := m.TaskTags[taskID]
+	for i, id := range ids {
+		if id == tagID {
+			m.TaskTags[taskID] = append(ids[:i], ids[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("not found")
+}
+
+// byID sucht einen Tag anhand der ID.
+func (m *MockTagService) byID(id int) *models.Tag {
+	for _, t := range m.Tags {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// byName sucht einen Tag anhand des Namens.
+func (m *MockTagService) byName(name string) *models.Tag {
+	for _, t := range m.Tags {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
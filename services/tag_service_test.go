@@ -0,0 +1,128 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+)
+
+// Diese Datei enthält Unit-Tests für den TagService.
+
+// Test_TagService_CreateTag_Validation prüft, dass leere, zu lange und doppelte Namen abgelehnt werden
+// und Umlaute als einzelne Zeichen zählen.
+func Test_TagService_CreateTag_Validation(t *testing.T) {
+	mockRepo := &repository.MockTagRepository{
+		GetByNameFunc: func(name string) (*models.Tag, error) {
+			if name == "backend" {
+				return &models.Tag{ID: 1, Name: name}, nil
+			}
+			return nil, nil
+		},
+		CreateFunc: func(name string) (*models.Tag, error) {
+			return &models.Tag{ID: 2, Name: name}, nil
+		},
+	}
+	service := TagService{Repo: mockRepo}
+
+	_, err := service.CreateTag(models.TagRequest{Name: "   "})
+	assert.Equal(t, "invalid tag name", err.Error())
+
+	_, err = service.CreateTag(models.TagRequest{Name: strings.Repeat("x", 51)})
+	assert.Equal(t, "invalid tag name", err.Error())
+
+	_, err = service.CreateTag(models.TagRequest{Name: "backend"})
+	assert.Equal(t, "tag already exists", err.Error())
+
+	tag, err := service.CreateTag(models.TagRequest{Name: " " + strings.Repeat("ü", 50) + " "})
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("ü", 50), tag.Name)
+}
+
+// Test_TagService_MergeTags prüft, dass beide Tags existieren müssen und ein Tag nicht in sich selbst
+// zusammengeführt werden kann.
+func Test_TagService_MergeTags(t *testing.T) {
+	var merged [2]int
+	mockRepo := &repository.MockTagRepository{
+		GetByIdFunc: func(id int) (*models.Tag, error) {
+			if id > 2 {
+				return nil, nil
+			}
+			return &models.Tag{ID: id}, nil
+		},
+		MergeFunc: func(sourceID, targetID int) error {
+			merged = [2]int{sourceID, targetID}
+			return nil
+		},
+	}
+	service := TagService{Repo: mockRepo}
+
+	_, err := service.MergeTags(1, 1)
+	assert.Equal(t, "cannot merge tag into itself", err.Error())
+
+	_, err = service.MergeTags(1, 3)
+	assert.Equal(t, "target tag not found", err.Error())
+
+	target, err := service.MergeTags(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, target.ID)
+	assert.Equal(t, [2]int{1, 2}, merged)
+}
+
+// Test_TagService_AttachTag_CreatesUnknownName prüft, dass beim Zuordnen per Name ein unbekannter Tag angelegt wird.
+func Test_TagService_AttachTag_CreatesUnknownName(t *testing.T) {
+	var attached [2]int
+	tagRepo := &repository.MockTagRepository{
+		GetByNameFunc: func(name string) (*models.Tag, error) {
+			return nil, nil
+		},
+		CreateFunc: func(name string) (*models.Tag, error) {
+			return &models.Tag{ID: 9, Name: name}, nil
+		},
+		AttachFunc: func(taskID, tagID int) error {
+			attached = [2]int{taskID, tagID}
+			return nil
+		},
+	}
+	taskRepo := &repository.MockTaskRepository{
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			return &models.Task{ID: id}, nil
+		},
+	}
+	service := TagService{Repo: tagRepo, Tasks: taskRepo}
+
+	tag, err := service.AttachTag(4, models.AttachTagRequest{Name: "urgent"})
+	assert.NoError(t, err)
+	assert.Equal(t, "urgent", tag.Name)
+	assert.Equal(t, [2]int{4, 9}, attached)
+	assert.Equal(t, []models.TaskEvent{{Type: models.EventTaskUpdated}}, tagRepo.Events)
+}
+
+// Test_TagService_DetachTag_Event prüft, dass das Entfernen eines Tags task.updated erzeugt, eine nicht
+// vorhandene Zuordnung aber nicht.
+func Test_TagService_DetachTag_Event(t *testing.T) {
+	tagRepo := &repository.MockTagRepository{
+		DetachFunc: func(taskID, tagID int) (bool, error) {
+			return tagID == 9, nil
+		},
+	}
+	service := TagService{Repo: tagRepo}
+
+	assert.NoError(t, service.DetachTag(4, 9))
+	assert.Equal(t, "not found", service.DetachTag(4, 10).Error())
+	assert.Equal(t, []models.TaskEvent{{Type: models.EventTaskUpdated}}, tagRepo.Events)
+}
+
+// Test_TagService_AttachTag_TaskNotFound prüft, dass Tags nur existierenden Tasks zugeordnet werden.
+func Test_TagService_AttachTag_TaskNotFound(t *testing.T) {
+	taskRepo := &repository.MockTaskRepository{
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			return nil, nil
+		},
+	}
+	service := TagService{Repo: &repository.MockTagRepository{}, Tasks: taskRepo}
+
+	_, err := service.AttachTag(4, models.AttachTagRequest{TagID: 1})
+	assert.Equal(t, "task not found", err.Error())
+}
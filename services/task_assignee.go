@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+	"task-api/models"
+)
+
+// AssignUser weist den Task taskID dem Benutzer userID zu.
+// Der Benutzer muss existieren und demselben Mandanten angehören wie der anfragende
+// Benutzer actor (falls angemeldet) und wie die bisherigen Verantwortlichen des Tasks.
+// Eine neue Zuweisung schreibt task.updated in die Outbox.
+// Gibt "not found", "user not found" oder "user belongs to another tenant" zurück.
+func (s *TaskService) AssignUser(taskID, userID int, actor *models.User) error {
+	task, user, err := s.loadTaskAndUser(taskID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkTenant(task, user, actor); err != nil {
+		return err
+	}
+	return s.Users.AddAssignee(taskID, userID, models.TaskEvent{Type: models.EventTaskUpdated})
+}
+
+// UnassignUser entfernt die Zuweisung des Benutzers userID vom Task taskID und schreibt task.updated in die Outbox.
+// Gibt "assignment not found" zurück, wenn der Benutzer nicht zugewiesen war.
+func (s *TaskService) UnassignUser(taskID, userID int) error {
+	removed, err := s.Users.RemoveAssignee(taskID, userID, models.TaskEvent{Type: models.EventTaskUpdated})
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("assignment not found")
+	}
+	return nil
+}
+
+// WatchTask trägt den Benutzer userID als Beobachter des Tasks taskID ein.
+// Es gelten dieselben Prüfungen wie bei AssignUser.
+func (s *TaskService) WatchTask(taskID, userID int, actor *models.User) error {
+	task, user, err := s.loadTaskAndUser(taskID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkTenant(task, user, actor); err != nil {
+		return err
+	}
+	return s.Users.AddWatcher(taskID, userID)
+}
+
+// UnwatchTask entfernt den Benutzer userID als Beobachter des Tasks taskID.
+// Gibt "watcher not found" zurück, wenn der Benutzer den Task nicht beobachtet hat.
+func (s *TaskService) UnwatchTask(taskID, userID int) error {
+	removed, err := s.Users.RemoveWatcher(taskID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("watcher not found")
+	}
+	return nil
+}
+
+// loadTaskAndUser lädt Task und Benutzer und gibt "not found" bzw. "user not found" zurück,
+// wenn einer von beiden nicht existiert.
+func (s *TaskService) loadTaskAndUser(taskID, userID int) (*models.Task, *models.User, error) {
+	task, err := s.Repo.GetByID(taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if task == nil {
+		return nil, nil, fmt.Errorf("not found")
+	}
+
+	user, err := s.Users.GetByID(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, fmt.Errorf("user not found")
+	}
+	return task, user, nil
+}
+
+// checkTenant prüft, dass user demselben Mandanten angehört wie actor und wie die
+// bereits zugewiesenen Benutzer des Tasks. Ein Task gehört damit nie mehreren Mandanten.
+func (s *TaskService) checkTenant(task *models.Task, user *models.User, actor *models.User) error {
+	if actor != nil && actor.Tenant != user.Tenant {
+		return fmt.Errorf("user belongs to another tenant")
+	}
+	for _, id := range task.Assignees {
+		if id == user.ID {
+			continue
+		}
+		// Alle bisherigen Verantwortlichen teilen einen Mandanten, daher genügt der erste
+		other, err := s.Users.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if other != nil && other.Tenant != user.Tenant {
+			return fmt.Errorf("user belongs to another tenant")
+		}
+		break
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"task-api/models"
+)
+
+// AddDependency legt fest, dass der Task taskID erst nach dependsOnID bearbeitet werden kann.
+// Gibt "not found" zurück, wenn taskID nicht existiert, "dependency not found", wenn dependsOnID
+// nicht existiert, und "dependency cycle detected", wenn die Abhängigkeit einen Zyklus erzeugen würde.
+func (s *TaskService) AddDependency(taskID, dependsOnID int) error {
+	if taskID == dependsOnID {
+		return fmt.Errorf("task cannot depend on itself")
+	}
+
+	task, err := s.Repo.GetByID(taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("not found")
+	}
+
+	dependsOn, err := s.Repo.GetByID(dependsOnID)
+	if err != nil {
+		return err
+	}
+	if dependsOn == nil {
+		return fmt.Errorf("dependency not found")
+	}
+
+	graph, err := s.Repo.GetDependencyGraph()
+	if err != nil {
+		return err
+	}
+	for _, id := range graph[taskID] {
+		if id == dependsOnID {
+			return fmt.Errorf("dependency already exists")
+		}
+	}
+
+	// Die neue Kante taskID -> dependsOnID erzeugt genau dann einen Zyklus,
+	// wenn taskID von dependsOnID aus bereits erreichbar ist.
+	if reachable(graph, dependsOnID, taskID) {
+		return fmt.Errorf("dependency cycle detected")
+	}
+
+	return s.Repo.AddDependency(taskID, dependsOnID)
+}
+
+// RemoveDependency entfernt die Abhängigkeit von taskID zu dependsOnID.
+// Gibt "dependency not found" zurück, wenn diese nicht existiert.
+func (s *TaskService) RemoveDependency(taskID, dependsOnID int) error {
+	removed, err := s.Repo.RemoveDependency(taskID, dependsOnID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("dependency not found")
+	}
+	return nil
+}
+
+// GetDependencies gibt alle Tasks zurück, von denen der Task direkt abhängt.
+// Gibt "not found" zurück, wenn der Task nicht existiert.
+func (s *TaskService) GetDependencies(taskID int) ([]*models.Task, error) {
+	task, err := s.Repo.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("not found")
+	}
+
+	return s.Repo.GetDependencies(taskID)
+}
+
+// GetTopologicalOrder gibt alle Tasks in einer Reihenfolge zurück, in der jeder Task
+// erst nach allen Tasks erscheint, von denen er abhängt (Kahn-Algorithmus).
+// Bei mehreren möglichen Kandidaten wird der Task mit der kleinsten ID zuerst gewählt.
+func (s *TaskService) GetTopologicalOrder() ([]*models.Task, error) {
+	tasks, err := s.Repo.GetAll(models.TaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+	graph, err := s.Repo.GetDependencyGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*models.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	// inDegree zählt offene Abhängigkeiten, dependents die Rückrichtung der Kanten
+	inDegree := make(map[int]int, len(tasks))
+	dependents := map[int][]int{}
+	for taskID, deps := range graph {
+		for _, dep := range deps {
+			if byID[taskID] == nil || byID[dep] == nil {
+				continue
+			}
+			inDegree[taskID]++
+			dependents[dep] = append(dependents[dep], taskID)
+		}
+	}
+
+	var ready []int
+	for _, t := range tasks {
+		if inDegree[t.ID] == 0 {
+			ready = append(ready, t.ID)
+		}
+	}
+
+	ordered := make([]*models.Task, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		id := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, byID[id])
+
+		for _, next := range dependents[id] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+
+	if len(ordered) != len(tasks) {
+		return nil, fmt.Errorf("dependency cycle detected")
+	}
+	return ordered, nil
+}
+
+// checkBlockers prüft, ob alle Tasks, von denen taskID abhängt, in einem finalen Status sind.
+// Gibt "task is blocked" zurück, wenn mindestens ein Blocker noch offen ist.
+func (s *TaskService) checkBlockers(taskID int) error {
+	blockers, err := s.Repo.GetDependencies(taskID)
+	if err != nil {
+		return err
+	}
+	workflow := s.GetWorkflow()
+	for _, b := range blockers {
+		if !workflow.IsFinal(b.Status) {
+			return fmt.Errorf("task is blocked")
+		}
+	}
+	return nil
+}
+
+// reachable prüft per Tiefensuche, ob target von start aus über die Kanten des Graphen erreichbar ist.
+func reachable(graph map[int][]int, start, target int) bool {
+	visited := map[int]bool{}
+	stack := []int{start}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == target {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, graph[id]...)
+	}
+	return false
+}
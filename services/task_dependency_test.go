@@ -0,0 +1,104 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+)
+
+// Diese Datei enthält Unit-Tests für die Abhängigkeiten zwischen Tasks im TaskService.
+
+// existingTasksRepo liefert ein MockTaskRepository, in dem jede ID existiert und die übergebenen
+// Abhängigkeiten gespeichert sind.
+func existingTasksRepo(graph map[int][]int) *repository.MockTaskRepository {
+	return &repository.MockTaskRepository{
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			return &models.Task{ID: id, Title: "Task", Status: "todo"}, nil
+		},
+		GetDependencyGraphFunc: func() (map[int][]int, error) {
+			return graph, nil
+		},
+		AddDependencyFunc: func(taskID, dependsOnID int) error {
+			graph[taskID] = append(graph[taskID], dependsOnID)
+			return nil
+		},
+	}
+}
+
+// Test_Service_AddDependency_Success prüft, dass eine gültige Abhängigkeit gespeichert wird.
+func Test_Service_AddDependency_Success(t *testing.T) {
+	graph := map[int][]int{}
+	service := TaskService{Repo: existingTasksRepo(graph)}
+
+	err := service.AddDependency(2, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, graph[2])
+}
+
+// Test_Service_AddDependency_Cycle prüft, dass direkte und transitive Zyklen abgelehnt werden.
+func Test_Service_AddDependency_Cycle(t *testing.T) {
+	// 3 hängt von 2 ab, 2 hängt von 1 ab
+	graph := map[int][]int{3: {2}, 2: {1}}
+	service := TaskService{Repo: existingTasksRepo(graph)}
+
+	err := service.AddDependency(1, 3)
+	assert.Equal(t, "dependency cycle detected", err.Error())
+
+	err = service.AddDependency(1, 1)
+	assert.Equal(t, "task cannot depend on itself", err.Error())
+
+	err = service.AddDependency(3, 2)
+	assert.Equal(t, "dependency already exists", err.Error())
+}
+
+// Test_Service_UpdateTask_Blocked prüft, dass ein Task mit offenen Blockern nicht auf "in progress" gesetzt
+// werden kann, außer OverrideBlockers ist gesetzt.
+func Test_Service_UpdateTask_Blocked(t *testing.T) {
+	mockRepo := &repository.MockTaskRepository{
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			return &models.Task{ID: id, Title: "Task", Status: "todo"}, nil
+		},
+		GetDependenciesFunc: func(taskID int) ([]*models.Task, error) {
+			return []*models.Task{{ID: 1, Status: "in progress"}}, nil
+		},
+		UpdateFunc: func(task *models.Task) (*models.Task, error) {
+			return task, nil
+		},
+	}
+
+	service := TaskService{Repo: mockRepo}
+
+	updated, err := service.UpdateTask(2, models.CreateTaskRequest{Status: "in progress"})
+	assert.Nil(t, updated)
+	assert.Equal(t, "task is blocked", err.Error())
+
+	updated, err = service.UpdateTask(2, models.CreateTaskRequest{Status: "in progress", OverrideBlockers: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "in progress", updated.Status)
+}
+
+// Test_Service_GetTopologicalOrder prüft, dass Tasks nach ihren Abhängigkeiten sortiert werden.
+func Test_Service_GetTopologicalOrder(t *testing.T) {
+	mockRepo := &repository.MockTaskRepository{
+		GetAllFunc: func(filter models.TaskFilter) ([]*models.Task, error) {
+			return []*models.Task{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}, nil
+		},
+		GetDependencyGraphFunc: func() (map[int][]int, error) {
+			// 1 hängt von 3 ab, 3 hängt von 4 ab
+			return map[int][]int{1: {3}, 3: {4}}, nil
+		},
+	}
+
+	service := TaskService{Repo: mockRepo}
+
+	ordered, err := service.GetTopologicalOrder()
+	assert.NoError(t, err)
+
+	var ids []int
+	for _, task := range ordered {
+		ids = append(ids, task.ID)
+	}
+	assert.Equal(t, []int{2, 4, 3, 1}, ids)
+}
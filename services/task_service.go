@@ -4,24 +4,60 @@ import (
 	"fmt"
 	"task-api/models"
 	"task-api/repository"
+	"task-api/validation"
 	"time"
 )
 
+// Mögliche Verhaltensweisen beim Löschen eines Tasks, der Subtasks besitzt.
+const (
+	// ParentDeleteReject verweigert das Löschen, solange Subtasks existieren (Default).
+	ParentDeleteReject = "reject"
+	// ParentDeleteOrphan löscht nur den Parent, Subtasks werden zu Top-Level-Tasks.
+	ParentDeleteOrphan = "orphan"
+	// ParentDeleteCascade löscht den Parent inklusive aller Subtasks.
+	ParentDeleteCascade = "cascade"
+)
+
 // TaskService kapselt die Businesslogik für Tasks.
 // Nutzt ein Repository (Postgres), um Daten zu speichern und abzurufen.
 // Verantwortlich für Default-Werte und Fehlerbehandlung.
 type TaskService struct {
 	Repo repository.TaskRepositoryInterface
+
+	// ParentDeleteMode legt fest, wie DeleteTask mit Subtasks umgeht
+	// (ParentDeleteReject, ParentDeleteOrphan oder ParentDeleteCascade). Leer bedeutet reject.
+	ParentDeleteMode string
+
+	// Workflow definiert erlaubte Status und Übergänge. Ist nil, gilt models.DefaultWorkflow().
+	Workflow *models.Workflow
+
+	// Users wird für Zuweisungen (Assignees) und Beobachter (Watchers) benötigt.
+	Users repository.UserRepositoryInterface
 }
 
-// CreateTask erstellt einen neuen Task anhand der übergebenen CreateTaskRequest.
-// Setzt Default-Werte: Status="todo", Priority="medium", falls nicht angegeben.
-// Gibt den gespeicherten Task zurück oder einen Fehler.
+// GetWorkflow gibt die aktive Workflow-Definition zurück.
+func (s *TaskService) GetWorkflow() *models.Workflow {
+	if s.Workflow == nil {
+		return models.DefaultWorkflow()
+	}
+	return s.Workflow
+}
+
+// CreateTask erstellt einen neuen Task anhand der übergebenen CreateTaskRequest
+// und schreibt das Event task.created in die Outbox.
+// Setzt Default-Werte: Status=Startstatus des Workflows ("todo"), Priority="medium", falls nicht angegeben.
+// Mit RecurrenceRule wird der Task zum ersten Vorkommen einer wiederkehrenden Serie.
+// Gibt den gespeicherten Task zurück oder einen Fehler: validation.Errors bei ungültigen Eingaben
+// (siehe ValidateTaskRequest) oder "parent not found".
 func (s *TaskService) CreateTask(req models.CreateTaskRequest) (*models.Task, error) {
+	workflow := s.GetWorkflow()
+	if err := ValidateTaskRequest(workflow, req, true); err != nil {
+		return nil, err
+	}
 
 	// Default Status/Priority
 	if req.Status == "" {
-		req.Status = "todo"
+		req.Status = workflow.InitialStatus()
 	}
 	if req.Priority == "" {
 		req.Priority = "medium"
@@ -32,15 +68,51 @@ func (s *TaskService) CreateTask(req models.CreateTaskRequest) (*models.Task, er
 		Description: req.Description,
 		Status:      req.Status,
 		Priority:    req.Priority,
+		StartAt:     req.StartAt,
+		DueAt:       req.DueAt,
+	}
+	if req.RecurrenceRule != nil && *req.RecurrenceRule != "" {
+		rule, err := normalizeRecurrenceRule(*req.RecurrenceRule, task.DueAt)
+		if err != nil {
+			return nil, err
+		}
+		task.RecurrenceRule = rule
+	}
+
+	// Optionaler Parent-Task muss existieren
+	if req.ParentID != nil && *req.ParentID != 0 {
+		parent, err := s.Repo.GetByID(*req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, fmt.Errorf("parent not found")
+		}
+		task.ParentID = req.ParentID
 	}
 
-	return s.Repo.Create(task)
+	// Bei einem wiederkehrenden Task setzt das Repository series_id in derselben Transaktion auf die
+	// neue ID; das Event enthält damit bereits die Serie
+	return s.Repo.Create(task, models.TaskEvent{Type: models.EventTaskCreated})
 }
 
-// GetAllTasks gibt alle gespeicherten Tasks zurück.
+// GetAllTasks gibt alle gespeicherten Tasks zurück, die den Filtern entsprechen.
 // Gibt ein Slice von Tasks oder einen Fehler zurück.
-func (s *TaskService) GetAllTasks() ([]*models.Task, error) {
-	return s.Repo.GetAll()
+func (s *TaskService) GetAllTasks(filter models.TaskFilter) ([]*models.Task, error) {
+	return s.Repo.GetAll(filter)
+}
+
+// ListTasks gibt den Ausschnitt page der Tasks zurück, die den Filtern entsprechen, und die Anzahl aller
+// passenden Tasks. Das Repository lädt nur die Tasks der Seite mit den Feldern der Projektion und bettet die
+// angeforderten Beziehungen per Join ein.
+func (s *TaskService) ListTasks(filter models.TaskFilter, projection models.TaskProjection, page models.TaskPage) ([]*models.Task, int, error) {
+	return s.Repo.GetAllProjected(filter, projection, page)
+}
+
+// ExportTasks öffnet einen Cursor über die gefilterten Tasks. Die Zeilen werden blockweise aus der
+// Datenbank gelesen, sodass auch sehr große Exporte nicht im Speicher gehalten werden.
+func (s *TaskService) ExportTasks(filter models.TaskFilter, projection models.TaskProjection) (repository.TaskCursor, error) {
+	return s.Repo.OpenCursor(filter, projection)
 }
 
 // GetTaskByID gibt einen Task anhand der ID zurück.
@@ -58,9 +130,18 @@ func (s *TaskService) GetTaskByID(id int) (*models.Task, error) {
 
 // UpdateTask aktualisiert einen bestehenden Task anhand der ID und der neuen Werte.
 // Felder, die im Request leer bleiben, werden nicht verändert.
-// Setzt UpdatedAt auf die aktuelle Zeit.
-// Gibt den aktualisierten Task zurück oder einen Fehler.
+// Statuswechsel müssen im Workflow erlaubt sein (siehe checkTransition).
+// Bei wiederkehrenden Tasks legt Scope fest, ob Änderungen nur für dieses ("this") oder auch für
+// alle folgenden Vorkommen ("future") gelten; beim Erledigen wird das nächste Vorkommen angelegt.
+// Setzt UpdatedAt auf die aktuelle Zeit und schreibt task.updated (bei Statuswechsel zusätzlich
+// task.status_changed) in die Outbox.
+// Gibt den aktualisierten Task zurück oder einen Fehler; ungültige Eingaben werden als
+// validation.Errors gemeldet (siehe ValidateTaskRequest).
 func (s *TaskService) UpdateTask(id int, req models.CreateTaskRequest) (*models.Task, error) {
+	if err := ValidateTaskRequest(s.GetWorkflow(), req, false); err != nil {
+		return nil, err
+	}
+
 	task, err := s.Repo.GetByID(id)
 	if err != nil {
 		return nil, err
@@ -68,6 +149,8 @@ func (s *TaskService) UpdateTask(id int, req models.CreateTaskRequest) (*models.
 	if task == nil {
 		return nil, fmt.Errorf("not found")
 	}
+	wasFinal := s.GetWorkflow().IsFinal(task.Status)
+	events := []models.TaskEvent{{Type: models.EventTaskUpdated}}
 
 	if req.Title != "" {
 		task.Title = req.Title
@@ -75,24 +158,187 @@ func (s *TaskService) UpdateTask(id int, req models.CreateTaskRequest) (*models.
 	if req.Description != "" {
 		task.Description = req.Description
 	}
-	if req.Status != "" {
+	if req.Status != "" && req.Status != task.Status {
+		if err := s.checkTransition(id, task.Status, req); err != nil {
+			return nil, err
+		}
+		events = append(events, models.TaskEvent{Type: models.EventTaskStatusChanged, PreviousStatus: task.Status})
 		task.Status = req.Status
 	}
 	if req.Priority != "" {
 		task.Priority = req.Priority
 	}
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			task.ParentID = nil
+		} else {
+			if err := s.checkParent(id, *req.ParentID); err != nil {
+				return nil, err
+			}
+			task.ParentID = req.ParentID
+		}
+	}
+
+	if req.StartAt != nil {
+		task.StartAt = req.StartAt
+	}
+	if req.DueAt != nil {
+		task.DueAt = req.DueAt
+	}
+	// Beginn und Fälligkeit werden zusammen mit den bestehenden Werten erneut geprüft
+	var v validation.Validator
+	checkSchedule(&v, task.StartAt, task.DueAt, req.RecurrenceRule)
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+	if req.RecurrenceRule != nil {
+		task.RecurrenceRule = ""
+		if *req.RecurrenceRule != "" {
+			rule, err := normalizeRecurrenceRule(*req.RecurrenceRule, task.DueAt)
+			if err != nil {
+				return nil, err
+			}
+			task.RecurrenceRule = rule
+			if task.SeriesID == nil {
+				task.SeriesID = &task.ID
+			}
+		}
+	}
 
 	task.UpdatedAt = time.Now()
 
-	updatedTask, err := s.Repo.Update(task)
+	updatedTask, err := s.Repo.Update(task, events...)
 	if err != nil {
 		return nil, err
 	}
 
+	if req.Scope == ScopeFuture {
+		if err := s.updateFutureOccurrences(updatedTask); err != nil {
+			return nil, err
+		}
+	}
+
+	// Wird ein Vorkommen einer Serie erledigt, wird das nächste Vorkommen angelegt
+	if !wasFinal && s.GetWorkflow().IsFinal(updatedTask.Status) {
+		if _, err := s.spawnNextOccurrence(updatedTask); err != nil {
+			return nil, err
+		}
+	}
+
 	return updatedTask, nil
 }
 
+// checkTransition prüft, ob der Task id vom Status from in den Status req.Status wechseln darf.
+// Mögliche Fehler:
+//   - "invalid status": Zielstatus existiert im Workflow nicht
+//   - "invalid status transition": Übergang ist im Workflow nicht vorgesehen
+//   - "reopen required": Übergang muss explizit mit Reopen angefordert werden (z.B. done -> todo)
+//   - "task is blocked": Zielstatus ist kein Startstatus und Abhängigkeiten sind unerledigt,
+//     außer OverrideBlockers ist gesetzt
+func (s *TaskService) checkTransition(id int, from string, req models.CreateTaskRequest) error {
+	workflow := s.GetWorkflow()
+
+	target := workflow.Status(req.Status)
+	if target == nil {
+		return fmt.Errorf("invalid status")
+	}
+
+	transition := workflow.Transition(from, req.Status)
+	if transition == nil {
+		return fmt.Errorf("invalid status transition")
+	}
+	if transition.RequiresReopen && !req.Reopen {
+		return fmt.Errorf("reopen required")
+	}
+
+	if !target.Initial && !req.OverrideBlockers {
+		return s.checkBlockers(id)
+	}
+	return nil
+}
+
+// checkParent prüft, ob parentID als neuer Parent für den Task id zulässig ist.
+// Der Parent muss existieren und darf weder der Task selbst noch einer seiner
+// Subtasks sein, da sonst ein Zyklus in der Hierarchie entstehen würde.
+func (s *TaskService) checkParent(id, parentID int) error {
+	if parentID == id {
+		return fmt.Errorf("task cannot be its own parent")
+	}
+
+	// Ausgehend vom neuen Parent die Hierarchie nach oben ablaufen
+	visited := map[int]bool{}
+	current := parentID
+	for {
+		if visited[current] {
+			return fmt.Errorf("cycle detected")
+		}
+		visited[current] = true
+
+		ancestor, err := s.Repo.GetByID(current)
+		if err != nil {
+			return err
+		}
+		if ancestor == nil {
+			return fmt.Errorf("parent not found")
+		}
+		if ancestor.ParentID == nil {
+			return nil
+		}
+		if *ancestor.ParentID == id {
+			return fmt.Errorf("cycle detected")
+		}
+		current = *ancestor.ParentID
+	}
+}
+
+// GetSubtasks gibt alle direkten Subtasks eines Tasks zurück.
+// Gibt einen Fehler "not found", wenn der Parent-Task nicht existiert.
+func (s *TaskService) GetSubtasks(id int) ([]*models.Task, error) {
+	task, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("not found")
+	}
+
+	return s.Repo.GetSubtasks(id)
+}
+
+// GetTasksByIDs gibt alle Tasks mit den angegebenen IDs zurück.
+// Nicht existierende IDs werden ignoriert.
+func (s *TaskService) GetTasksByIDs(ids []int) ([]*models.Task, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return s.Repo.GetByIDs(ids)
+}
+
+// GetSubtasksOf gibt alle direkten Subtasks der angegebenen Tasks zurück.
+func (s *TaskService) GetSubtasksOf(parentIDs []int) ([]*models.Task, error) {
+	if len(parentIDs) == 0 {
+		return nil, nil
+	}
+	return s.Repo.GetSubtasksOf(parentIDs)
+}
+
+// GetTaskTree gibt einen Task mit allen Subtasks als verschachtelte Struktur zurück.
+// Gibt einen Fehler "not found", wenn der Task nicht existiert.
+func (s *TaskService) GetTaskTree(id int) (*models.TaskNode, error) {
+	tree, err := s.Repo.GetTree(id)
+	if err != nil {
+		return nil, err
+	}
+	if tree == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	return tree, nil
+}
+
 // DeleteTask entfernt einen Task anhand der ID.
+// Besitzt der Task Subtasks, entscheidet ParentDeleteMode über das Verhalten:
+// Bei "reject" wird der Fehler "task has subtasks" zurückgegeben.
+// Für jeden gelöschten Task wird task.deleted in die Outbox geschrieben.
 // Gibt einen Fehler "not found", falls der Task nicht existiert.
 func (s *TaskService) DeleteTask(id int) error {
 	task, err := s.Repo.GetByID(id)
@@ -103,5 +349,21 @@ func (s *TaskService) DeleteTask(id int) error {
 		return fmt.Errorf("not found")
 	}
 
-	return s.Repo.Delete(id)
+	subtasks, err := s.Repo.GetSubtasks(id)
+	if err != nil {
+		return err
+	}
+	deleted := models.TaskEvent{Type: models.EventTaskDeleted}
+	if len(subtasks) == 0 {
+		return s.Repo.Delete(id, deleted)
+	}
+
+	switch s.ParentDeleteMode {
+	case ParentDeleteCascade:
+		return s.Repo.DeleteWithSubtasks(id, deleted)
+	case ParentDeleteOrphan:
+		return s.Repo.Delete(id, deleted)
+	default:
+		return fmt.Errorf("task has subtasks")
+	}
 }
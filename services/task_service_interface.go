@@ -1,6 +1,9 @@
 package services
 
-import "task-api/models"
+import (
+	"task-api/models"
+	"task-api/repository"
+)
 
 // TaskServiceInterface definiert die Methoden, die jeder TaskService implementieren muss.
 // Dient dazu, unterschiedliche Implementierungen (z.B. echte Service-Logik oder Mocks) austauschbar zu machen.
@@ -9,20 +12,78 @@ type TaskServiceInterface interface {
 	// Gibt den gespeicherten Task zurück oder einen Fehler.
 	CreateTask(req models.CreateTaskRequest) (*models.Task, error)
 
-	// GetAllTasks gibt alle gespeicherten Tasks zurück.
+	// GetAllTasks gibt alle gespeicherten Tasks zurück, die den Filtern entsprechen.
 	// Liefert ein Slice von Tasks oder einen Fehler.
-	GetAllTasks() ([]*models.Task, error)
+	GetAllTasks(filter models.TaskFilter) ([]*models.Task, error)
+
+	// ListTasks gibt den Ausschnitt page der Tasks zurück, die den Filtern entsprechen, mit den Feldern und
+	// eingebetteten Beziehungen der Projektion (fields=, include=, limit= und offset= von GET /tasks),
+	// sowie die Anzahl aller passenden Tasks.
+	ListTasks(filter models.TaskFilter, projection models.TaskProjection, page models.TaskPage) ([]*models.Task, int, error)
+
+	// ExportTasks öffnet einen Cursor über alle Tasks, die den Filtern entsprechen, mit den Feldern der
+	// Projektion (GET /tasks/export). Der Aufrufer muss den Cursor schließen.
+	ExportTasks(filter models.TaskFilter, projection models.TaskProjection) (repository.TaskCursor, error)
 
 	// GetTaskByID gibt einen Task anhand der ID zurück.
 	// Gibt einen Fehler "not found", wenn keine Task mit dieser ID existiert.
 	GetTaskByID(id int) (*models.Task, error)
 
 	// UpdateTask aktualisiert einen bestehenden Task anhand der ID und der übergebenen Werte.
-	// Nicht gesetzte Felder bleiben unverändert.
+	// Nicht gesetzte Felder bleiben unverändert. Statuswechsel müssen im Workflow erlaubt sein.
 	// Liefert den aktualisierten Task oder einen Fehler.
 	UpdateTask(id int, req models.CreateTaskRequest) (*models.Task, error)
 
+	// GetSubtasks gibt alle direkten Subtasks eines Tasks zurück.
+	// Gibt einen Fehler "not found", wenn der Task nicht existiert.
+	GetSubtasks(id int) ([]*models.Task, error)
+
+	// GetTasksByIDs gibt alle Tasks mit den angegebenen IDs in einer Abfrage zurück.
+	// Nicht existierende IDs werden ignoriert.
+	GetTasksByIDs(ids []int) ([]*models.Task, error)
+
+	// GetSubtasksOf gibt alle direkten Subtasks der angegebenen Tasks in einer Abfrage zurück.
+	GetSubtasksOf(parentIDs []int) ([]*models.Task, error)
+
+	// GetTaskTree gibt einen Task inklusive aller Subtasks als Baum zurück.
+	// Gibt einen Fehler "not found", wenn der Task nicht existiert.
+	GetTaskTree(id int) (*models.TaskNode, error)
+
+	// AddDependency legt fest, dass taskID erst nach dependsOnID bearbeitet werden kann.
+	// Lehnt Abhängigkeiten ab, die einen Zyklus erzeugen würden.
+	AddDependency(taskID, dependsOnID int) error
+
+	// RemoveDependency entfernt eine Abhängigkeit.
+	// Gibt "dependency not found" zurück, wenn diese nicht existiert.
+	RemoveDependency(taskID, dependsOnID int) error
+
+	// GetDependencies gibt alle Tasks zurück, von denen ein Task direkt abhängt.
+	GetDependencies(taskID int) ([]*models.Task, error)
+
+	// GetTopologicalOrder gibt alle Tasks in einer Reihenfolge zurück,
+	// die alle Abhängigkeiten respektiert.
+	GetTopologicalOrder() ([]*models.Task, error)
+
+	// AssignUser weist einen Task einem Benutzer zu. actor ist der anfragende Benutzer (nil ohne Authentifizierung).
+	// Gibt "not found", "user not found" oder "user belongs to another tenant" zurück.
+	AssignUser(taskID, userID int, actor *models.User) error
+
+	// UnassignUser entfernt eine Zuweisung.
+	// Gibt "assignment not found" zurück, wenn der Benutzer nicht zugewiesen war.
+	UnassignUser(taskID, userID int) error
+
+	// WatchTask trägt einen Benutzer als Beobachter eines Tasks ein (Prüfungen wie AssignUser).
+	WatchTask(taskID, userID int, actor *models.User) error
+
+	// UnwatchTask entfernt einen Beobachter.
+	// Gibt "watcher not found" zurück, wenn der Benutzer den Task nicht beobachtet hat.
+	UnwatchTask(taskID, userID int) error
+
+	// GetWorkflow gibt die aktive Workflow-Definition (Status und erlaubte Übergänge) zurück.
+	GetWorkflow() *models.Workflow
+
 	// DeleteTask entfernt einen Task anhand der ID.
-	// Gibt einen Fehler "not found", falls der Task nicht existiert.
+	// Gibt einen Fehler "not found", falls der Task nicht existiert,
+	// bzw. "task has subtasks", wenn das Löschen wegen Subtasks abgelehnt wird.
 	DeleteTask(id int) error
 }
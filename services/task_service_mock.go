@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"task-api/models"
+	"task-api/repository"
 	"time"
 )
 
@@ -13,34 +14,139 @@ import (
 // - Tasks: Vordefinierte Tasks für Tests.
 // - Err: Optionaler Fehler, der bei GetTaskByID zurückgegeben wird.
 // - ShouldFail: Wenn true, schlagen alle Methoden absichtlich fehl.
+// - Dependencies: Abhängigkeiten als Adjazenzliste (Task-ID -> abhängig von).
+// - Users: Bekannte Benutzer für Zuweisungen und Beobachter.
+// - CommentCounts: Anzahl der Kommentare je Task-ID für include=comments_count.
 type MockTaskService struct {
-	Tasks      []*models.Task
-	Err        error
-	ShouldFail bool
+	Tasks         []*models.Task
+	Err           error
+	ShouldFail    bool
+	Dependencies  map[int][]int
+	Users         []*models.User
+	CommentCounts map[int]int
 }
 
 // CreateTask simuliert das Erstellen eines Tasks.
+// Prüft den Request wie TaskService (ValidateTaskRequest).
 // Gibt einen Task zurück oder einen internen Serverfehler, wenn ShouldFail=true ist.
 func (m *MockTaskService) CreateTask(req models.CreateTaskRequest) (*models.Task, error) {
 	if m.ShouldFail {
 		return nil, fiber.ErrInternalServerError
 	}
-	return &models.Task{
+	if err := ValidateTaskRequest(m.GetWorkflow(), req, true); err != nil {
+		return nil, err
+	}
+	task := &models.Task{
 		ID:          1,
 		Title:       req.Title,
 		Description: req.Description,
 		Status:      req.Status,
 		Priority:    req.Priority,
-	}, nil
+		StartAt:     req.StartAt,
+		DueAt:       req.DueAt,
+		Tags:        []string{},
+		Assignees:   []int{},
+		Watchers:    []int{},
+	}
+	if req.RecurrenceRule != nil {
+		task.RecurrenceRule = *req.RecurrenceRule
+	}
+	return task, nil
 }
 
-// GetAllTasks gibt alle Tasks im Mock zurück.
+// GetAllTasks gibt alle Tasks im Mock zurück, die den Filtern entsprechen.
 // Liefert einen Fehler, wenn ShouldFail=true ist.
-func (m *MockTaskService) GetAllTasks() ([]*models.Task, error) {
+func (m *MockTaskService) GetAllTasks(filter models.TaskFilter) ([]*models.Task, error) {
 	if m.ShouldFail {
 		return nil, fiber.ErrInternalServerError
 	}
-	return m.Tasks, nil
+
+	var tasks []*models.Task
+	for _, t := range m.Tasks {
+		if filter.Overdue != nil && t.IsOverdue != *filter.Overdue {
+			continue
+		}
+		if filter.DueBefore != nil && (t.DueAt == nil || !t.DueAt.Before(*filter.DueBefore)) {
+			continue
+		}
+		if filter.DueAfter != nil && (t.DueAt == nil || !t.DueAt.After(*filter.DueAfter)) {
+			continue
+		}
+		if filter.HasDueDate && t.DueAt == nil {
+			continue
+		}
+		if !matchesTags(t.Tags, filter) {
+			continue
+		}
+		if filter.Assignee != nil && !containsID(t.Assignees, *filter.Assignee) {
+			continue
+		}
+		if filter.Watcher != nil && !containsID(t.Watchers, *filter.Watcher) {
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// ListTasks gibt den Ausschnitt page der gefilterten Tasks wie GetAllTasks und deren Gesamtanzahl zurück und
+// bettet die angeforderten Beziehungen aus den Daten des Mocks ein (Tags nur mit Namen, Verantwortliche aus
+// Users, Kommentare aus CommentCounts). Die Feldauswahl übernimmt der Handler, der Mock liefert immer alle Felder.
+func (m *MockTaskService) ListTasks(filter models.TaskFilter, projection models.TaskProjection, page models.TaskPage) ([]*models.Task, int, error) {
+	all, err := m.GetAllTasks(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	tasks := page.Apply(all)
+	if len(projection.Include) == 0 {
+		return tasks, len(all), nil
+	}
+
+	result := make([]*models.Task, len(tasks))
+	for i, t := range tasks {
+		projected := *t
+		projected.Relations = m.relations(t, projection)
+		result[i] = &projected
+	}
+	return result, len(all), nil
+}
+
+// ExportTasks liefert die gefilterten Tasks wie GetAllTasks über einen repository.SliceCursor.
+// Die Feldauswahl übernimmt der Handler.
+func (m *MockTaskService) ExportTasks(filter models.TaskFilter, projection models.TaskProjection) (repository.TaskCursor, error) {
+	tasks, err := m.GetAllTasks(filter)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewSliceCursor(tasks), nil
+}
+
+// relations stellt die Beziehungen eines Tasks aus den Daten des Mocks zusammen.
+func (m *MockTaskService) relations(t *models.Task, projection models.TaskProjection) *models.TaskRelations {
+	rel := &models.TaskRelations{CommentsCount: m.CommentCounts[t.ID]}
+	if projection.Includes("subtasks") {
+		rel.Subtasks = []*models.Task{}
+		for _, s := range m.Tasks {
+			if s.ParentID != nil && *s.ParentID == t.ID {
+				rel.Subtasks = append(rel.Subtasks, s)
+			}
+		}
+	}
+	if projection.Includes("tags") {
+		rel.Tags = []*models.Tag{}
+		for _, name := range t.Tags {
+			rel.Tags = append(rel.Tags, &models.Tag{Name: name})
+		}
+	}
+	if projection.Includes("assignees") {
+		rel.Assignees = []*models.User{}
+		for _, u := range m.Users {
+			if containsID(t.Assignees, u.ID) {
+				rel.Assignees = append(rel.Assignees, u)
+			}
+		}
+	}
+	return rel
 }
 
 // GetTaskByID gibt einen Task anhand der ID zurück.
@@ -60,11 +166,15 @@ func (m *MockTaskService) GetTaskByID(id int) (*models.Task, error) {
 
 // UpdateTask simuliert das Aktualisieren eines Tasks.
 // Felder, die im Request leer sind, bleiben unverändert.
+// Prüft den Request wie TaskService (ValidateTaskRequest).
 // Liefert "not found" oder internen Serverfehler je nach Konfiguration.
 func (m *MockTaskService) UpdateTask(id int, req models.CreateTaskRequest) (*models.Task, error) {
 	if m.ShouldFail {
 		return nil, fiber.ErrInternalServerError
 	}
+	if err := ValidateTaskRequest(m.GetWorkflow(), req, false); err != nil {
+		return nil, err
+	}
 
 	var task *models.Task
 	for _, t := range m.Tasks {
@@ -83,17 +193,245 @@ func (m *MockTaskService) UpdateTask(id int, req models.CreateTaskRequest) (*mod
 	if req.Description != "" {
 		task.Description = req.Description
 	}
-	if req.Status != "" {
+	if req.Status != "" && req.Status != task.Status {
+		target := m.GetWorkflow().Status(req.Status)
+		if target != nil && !target.Initial && !req.OverrideBlockers {
+			blockers, _ := m.GetDependencies(id)
+			for _, b := range blockers {
+				if !m.GetWorkflow().IsFinal(b.Status) {
+					return nil, fmt.Errorf("task is blocked")
+				}
+			}
+		}
 		task.Status = req.Status
 	}
 	if req.Priority != "" {
 		task.Priority = req.Priority
 	}
+	if req.StartAt != nil {
+		task.StartAt = req.StartAt
+	}
+	if req.DueAt != nil {
+		task.DueAt = req.DueAt
+	}
+	if req.RecurrenceRule != nil {
+		task.RecurrenceRule = *req.RecurrenceRule
+	}
 	task.UpdatedAt = time.Now()
 
 	return task, nil
 }
 
+// GetSubtasks gibt alle Tasks im Mock zurück, deren ParentID der übergebenen ID entspricht.
+// Liefert "not found", wenn der Parent-Task nicht existiert.
+func (m *MockTaskService) GetSubtasks(id int) ([]*models.Task, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if _, err := m.GetTaskByID(id); err != nil {
+		return nil, err
+	}
+
+	subtasks := []*models.Task{}
+	for _, t := range m.Tasks {
+		if t.ParentID != nil && *t.ParentID == id {
+			subtasks = append(subtasks, t)
+		}
+	}
+	return subtasks, nil
+}
+
+// GetTasksByIDs gibt alle Tasks im Mock mit den angegebenen IDs zurück.
+func (m *MockTaskService) GetTasksByIDs(ids []int) ([]*models.Task, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	var tasks []*models.Task
+	for _, t := range m.Tasks {
+		if containsID(ids, t.ID) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+// GetSubtasksOf gibt alle Tasks im Mock zurück, deren ParentID in parentIDs enthalten ist.
+func (m *MockTaskService) GetSubtasksOf(parentIDs []int) ([]*models.Task, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	var subtasks []*models.Task
+	for _, t := range m.Tasks {
+		if t.ParentID != nil && containsID(parentIDs, *t.ParentID) {
+			subtasks = append(subtasks, t)
+		}
+	}
+	return subtasks, nil
+}
+
+// GetTaskTree baut aus den Tasks im Mock rekursiv den Baum unterhalb der übergebenen ID auf.
+// Liefert "not found", wenn der Task nicht existiert.
+func (m *MockTaskService) GetTaskTree(id int) (*models.TaskNode, error) {
+	task, err := m.GetTaskByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	node := &models.TaskNode{Task: task, Subtasks: []*models.TaskNode{}}
+	subtasks, _ := m.GetSubtasks(id)
+	for _, sub := range subtasks {
+		child, err := m.GetTaskTree(sub.ID)
+		if err != nil {
+			return nil, err
+		}
+		node.Subtasks = append(node.Subtasks, child)
+	}
+	return node, nil
+}
+
+// AddDependency speichert eine Abhängigkeit in Dependencies.
+// Liefert "not found", wenn einer der beiden Tasks nicht existiert.
+func (m *MockTaskService) AddDependency(taskID, dependsOnID int) error {
+	if m.ShouldFail {
+		return fiber.ErrInternalServerError
+	}
+	if _, err := m.GetTaskByID(taskID); err != nil {
+		return err
+	}
+	if _, err := m.GetTaskByID(dependsOnID); err != nil {
+		return fmt.Errorf("dependency not found")
+	}
+	if m.Dependencies == nil {
+		m.Dependencies = map[int][]int{}
+	}
+	m.Dependencies[taskID] = append(m.Dependencies[taskID], dependsOnID)
+	return nil
+}
+
+// RemoveDependency entfernt eine Abhängigkeit aus Dependencies.
+// Liefert "dependency not found", wenn die Abhängigkeit nicht existiert.
+func (m *MockTaskService) RemoveDependency(taskID, dependsOnID int) error {
+	if m.ShouldFail {
+		return fiber.ErrInternalServerError
+	}
+	deps := m.Dependencies[taskID]
+	for i, id := range deps {
+		if id == dependsOnID {
+			m.Dependencies[taskID] = append(deps[:i], deps[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("dependency not found")
+}
+
+// GetDependencies gibt die Tasks zurück, von denen der Task laut Dependencies abhängt.
+// Liefert "not found", wenn der Task nicht existiert.
+func (m *MockTaskService) GetDependencies(taskID int) ([]*models.Task, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if _, err := m.GetTaskByID(taskID); err != nil {
+		return nil, err
+	}
+
+	blockers := []*models.Task{}
+	for _, id := range m.Dependencies[taskID] {
+		if t, err := m.GetTaskByID(id); err == nil {
+			blockers = append(blockers, t)
+		}
+	}
+	return blockers, nil
+}
+
+// GetTopologicalOrder gibt die Tasks im Mock in ihrer gespeicherten Reihenfolge zurück.
+// Liefert einen Fehler, wenn ShouldFail=true ist.
+func (m *MockTaskService) GetTopologicalOrder() ([]*models.Task, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	return m.Tasks, nil
+}
+
+// AssignUser simuliert das Zuweisen eines Tasks.
+// Liefert "not found", "user not found" oder "user belongs to another tenant".
+func (m *MockTaskService) AssignUser(taskID, userID int, actor *models.User) error {
+	task, err := m.checkUser(taskID, userID, actor)
+	if err != nil {
+		return err
+	}
+	if !containsID(task.Assignees, userID) {
+		task.Assignees = append(task.Assignees, userID)
+	}
+	return nil
+}
+
+// UnassignUser simuliert das Entfernen einer Zuweisung.
+// Liefert "assignment not found", wenn der Benutzer nicht zugewiesen ist.
+func (m *MockTaskService) UnassignUser(taskID, userID int) error {
+	if m.ShouldFail {
+		return fiber.ErrInternalServerError
+	}
+	for _, t := range m.Tasks {
+		if t.ID == taskID && containsID(t.Assignees, userID) {
+			t.Assignees = removeID(t.Assignees, userID)
+			return nil
+		}
+	}
+	return fmt.Errorf("assignment not found")
+}
+
+// WatchTask simuliert das Beobachten eines Tasks (Prüfungen wie AssignUser).
+func (m *MockTaskService) WatchTask(taskID, userID int, actor *models.User) error {
+	task, err := m.checkUser(taskID, userID, actor)
+	if err != nil {
+		return err
+	}
+	if !containsID(task.Watchers, userID) {
+		task.Watchers = append(task.Watchers, userID)
+	}
+	return nil
+}
+
+// UnwatchTask simuliert das Entfernen eines Beobachters.
+// Liefert "watcher not found", wenn der Benutzer den Task nicht beobachtet.
+func (m *MockTaskService) UnwatchTask(taskID, userID int) error {
+	if m.ShouldFail {
+		return fiber.ErrInternalServerError
+	}
+	for _, t := range m.Tasks {
+		if t.ID == taskID && containsID(t.Watchers, userID) {
+			t.Watchers = removeID(t.Watchers, userID)
+			return nil
+		}
+	}
+	return fmt.Errorf("watcher not found")
+}
+
+// checkUser sucht Task und Benutzer und prüft den Mandanten gegen actor.
+func (m *MockTaskService) checkUser(taskID, userID int, actor *models.User) (*models.Task, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	task, err := m.GetTaskByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range m.Users {
+		if u.ID == userID {
+			if actor != nil && actor.Tenant != u.Tenant {
+				return nil, fmt.Errorf("user belongs to another tenant")
+			}
+			return task, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+// GetWorkflow gibt den Standard-Workflow zurück.
+func (m *MockTaskService) GetWorkflow() *models.Workflow {
+	return models.DefaultWorkflow()
+}
+
 // DeleteTask simuliert das Löschen eines Tasks anhand der ID.
 // Liefert "not found", wenn kein Task existiert, oder einen Fehler, wenn ShouldFail=true ist.
 func (m *MockTaskService) DeleteTask(id int) error {
@@ -110,3 +448,53 @@ func (m *MockTaskService) DeleteTask(id int) error {
 
 	return fmt.Errorf("not found")
 }
+
+// matchesTags prüft die Tag-Filter (any/all/none) gegen die Tags eines Tasks.
+func matchesTags(tags []string, filter models.TaskFilter) bool {
+	has := map[string]bool{}
+	for _, t := range tags {
+		has[t] = true
+	}
+
+	if len(filter.TagsAny) > 0 {
+		found := false
+		for _, t := range filter.TagsAny {
+			found = found || has[t]
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, t := range filter.TagsAll {
+		if !has[t] {
+			return false
+		}
+	}
+	for _, t := range filter.TagsNone {
+		if has[t] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsID prüft, ob id in ids enthalten ist.
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// removeID gibt ids ohne id zurück.
+func removeID(ids []int, id int) []int {
+	var out []int
+	for _, v := range ids {
+		if v != id {
+			out = append(out, v)
+		}
+	}
+	return out
+}
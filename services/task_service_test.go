@@ -2,9 +2,12 @@ package services
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"task-api/models"
 	"task-api/repository"
+	"task-api/validation"
 	"testing"
+	"time"
 )
 
 // Diese Datei enthält Unit-Tests für den TaskService.
@@ -87,6 +90,58 @@ func Test_Service_GetTaskByID_Success(t *testing.T) {
 	assert.Equal(t, 1, task.ID)
 }
 
+// Test_Service_ListTasks_PassesProjection prüft, dass Filter und Projektion unverändert an das Repository gehen,
+// damit Feldauswahl und Beziehungen in der Abfrage statt nachträglich umgesetzt werden.
+func Test_Service_ListTasks_PassesProjection(t *testing.T) {
+	overdue := true
+	var gotFilter models.TaskFilter
+	var gotProjection models.TaskProjection
+	var gotPage models.TaskPage
+	mockRepo := &repository.MockTaskRepository{
+		GetAllProjectedFunc: func(filter models.TaskFilter, projection models.TaskProjection, page models.TaskPage) ([]*models.Task, int, error) {
+			gotFilter, gotProjection, gotPage = filter, projection, page
+			return []*models.Task{{ID: 1, Relations: &models.TaskRelations{CommentsCount: 2}}}, 7, nil
+		},
+	}
+
+	service := TaskService{Repo: mockRepo}
+	projection := models.TaskProjection{Fields: []string{"id", "title"}, Include: []string{"comments_count"}}
+
+	tasks, total, err := service.ListTasks(models.TaskFilter{Overdue: &overdue}, projection, models.TaskPage{Limit: 1, Offset: 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, total)
+	assert.Equal(t, models.TaskPage{Limit: 1, Offset: 3}, gotPage)
+	assert.Equal(t, projection, gotProjection)
+	assert.Equal(t, &overdue, gotFilter.Overdue)
+	assert.Equal(t, 2, tasks[0].Relations.CommentsCount)
+}
+
+// Test_Service_ExportTasks_OpensCursor prüft, dass der Export Filter und Projektion an den Cursor übergibt.
+func Test_Service_ExportTasks_OpensCursor(t *testing.T) {
+	var gotProjection models.TaskProjection
+	mockRepo := &repository.MockTaskRepository{
+		OpenCursorFunc: func(filter models.TaskFilter, projection models.TaskProjection) (repository.TaskCursor, error) {
+			gotProjection = projection
+			return repository.NewSliceCursor([]*models.Task{{ID: 1}, {ID: 2}}), nil
+		},
+	}
+
+	service := TaskService{Repo: mockRepo}
+	projection := models.TaskProjection{Fields: []string{"id", "title"}}
+
+	cursor, err := service.ExportTasks(models.TaskFilter{}, projection)
+	assert.NoError(t, err)
+	defer cursor.Close()
+	assert.Equal(t, projection, gotProjection)
+
+	var ids []int
+	for task, err := cursor.Next(); task != nil && err == nil; task, err = cursor.Next() {
+		ids = append(ids, task.ID)
+	}
+	assert.Equal(t, []int{1, 2}, ids)
+}
+
 // Test_Service_GetTaskByID_NotFound prüft, dass ein Fehler zurückgegeben wird, wenn die Task-ID nicht existiert.
 func Test_Service_GetTaskByID_NotFound(t *testing.T) {
 	mockRepo := &repository.MockTaskRepository{
@@ -199,3 +254,172 @@ func Test_Service_DeleteTask_NotFound(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "not found", err.Error())
 }
+
+// Test_Service_UpdateTask_ParentCycle prüft, dass ein Task nicht einem seiner eigenen Subtasks
+// untergeordnet werden kann.
+func Test_Service_UpdateTask_ParentCycle(t *testing.T) {
+	parentOf := map[int]int{2: 1, 3: 2}
+	mockRepo := &repository.MockTaskRepository{
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			task := &models.Task{ID: id, Title: "Task"}
+			if p, ok := parentOf[id]; ok {
+				task.ParentID = &p
+			}
+			return task, nil
+		},
+		UpdateFunc: func(task *models.Task) (*models.Task, error) {
+			return task, nil
+		},
+	}
+
+	service := TaskService{Repo: mockRepo}
+
+	parentID := 3
+	updated, err := service.UpdateTask(1, models.CreateTaskRequest{ParentID: &parentID})
+
+	assert.Nil(t, updated)
+	assert.Error(t, err)
+	assert.Equal(t, "cycle detected", err.Error())
+
+	selfID := 1
+	_, err = service.UpdateTask(1, models.CreateTaskRequest{ParentID: &selfID})
+	assert.Equal(t, "task cannot be its own parent", err.Error())
+}
+
+// Test_Service_UpdateTask_SetParent prüft, dass ein gültiger Parent gesetzt und mit 0 wieder entfernt werden kann.
+func Test_Service_UpdateTask_SetParent(t *testing.T) {
+	mockRepo := &repository.MockTaskRepository{
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			return &models.Task{ID: id, Title: "Task"}, nil
+		},
+		UpdateFunc: func(task *models.Task) (*models.Task, error) {
+			return task, nil
+		},
+	}
+
+	service := TaskService{Repo: mockRepo}
+
+	parentID := 5
+	updated, err := service.UpdateTask(1, models.CreateTaskRequest{ParentID: &parentID})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, *updated.ParentID)
+
+	noParent := 0
+	updated, err = service.UpdateTask(1, models.CreateTaskRequest{ParentID: &noParent})
+	assert.NoError(t, err)
+	assert.Nil(t, updated.ParentID)
+}
+
+// Test_Service_CreateTask_ParentNotFound prüft, dass ein Task nicht einem nicht existierenden Parent
+// zugeordnet werden kann.
+func Test_Service_CreateTask_ParentNotFound(t *testing.T) {
+	mockRepo := &repository.MockTaskRepository{
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			return nil, nil
+		},
+	}
+
+	service := TaskService{Repo: mockRepo}
+
+	parentID := 42
+	task, err := service.CreateTask(models.CreateTaskRequest{Title: "Sub", ParentID: &parentID})
+
+	assert.Nil(t, task)
+	assert.Equal(t, "parent not found", err.Error())
+}
+
+// Test_Service_DeleteTask_WithSubtasks prüft das Verhalten beim Löschen eines Parent-Tasks
+// für alle drei ParentDeleteModes.
+func Test_Service_DeleteTask_WithSubtasks(t *testing.T) {
+	var deleted, cascaded bool
+	mockRepo := &repository.MockTaskRepository{
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			return &models.Task{ID: id, Title: "Parent"}, nil
+		},
+		GetSubtasksFunc: func(parentID int) ([]*models.Task, error) {
+			return []*models.Task{{ID: 2, ParentID: &parentID}}, nil
+		},
+		DeleteFunc: func(id int) error {
+			deleted = true
+			return nil
+		},
+		DeleteWithSubtasksFunc: func(id int) error {
+			cascaded = true
+			return nil
+		},
+	}
+
+	service := TaskService{Repo: mockRepo}
+	err := service.DeleteTask(1)
+	assert.Equal(t, "task has subtasks", err.Error())
+	assert.False(t, deleted)
+	assert.False(t, cascaded)
+
+	service.ParentDeleteMode = ParentDeleteOrphan
+	assert.NoError(t, service.DeleteTask(1))
+	assert.True(t, deleted)
+	assert.False(t, cascaded)
+
+	deleted = false
+	service.ParentDeleteMode = ParentDeleteCascade
+	assert.NoError(t, service.DeleteTask(1))
+	assert.False(t, deleted)
+	assert.True(t, cascaded)
+}
+
+// Test_Service_GetTaskTree_NotFound prüft, dass ein Fehler zurückgegeben wird, wenn der Wurzel-Task nicht existiert.
+func Test_Service_GetTaskTree_NotFound(t *testing.T) {
+	mockRepo := &repository.MockTaskRepository{
+		GetTreeFunc: func(id int) (*models.TaskNode, error) {
+			return nil, nil
+		},
+	}
+
+	service := TaskService{Repo: mockRepo}
+
+	tree, err := service.GetTaskTree(99)
+
+	assert.Nil(t, tree)
+	assert.Equal(t, "not found", err.Error())
+}
+
+// Test_Service_ValidateTaskRequest prüft, dass alle Verstöße eines Requests gemeinsam gemeldet werden
+// und Längen in Zeichen gezählt werden.
+func Test_Service_ValidateTaskRequest(t *testing.T) {
+	service := TaskService{Repo: &repository.MockTaskRepository{}}
+
+	start := time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC)
+	due := start.Add(-time.Hour)
+	rule := "FREQ=SOMETIMES"
+	_, err := service.CreateTask(models.CreateTaskRequest{
+		Title:          strings.Repeat("ä", 201),
+		Priority:       "urgent",
+		StartAt:        &start,
+		DueAt:          &due,
+		RecurrenceRule: &rule,
+	})
+	assert.Equal(t, validation.Errors{
+		{Field: "title", Message: "must be at most 200 characters"},
+		{Field: "priority", Message: "must be one of: low, medium, high"},
+		{Field: "due_at", Message: "must not be before start_at"},
+		{Field: "recurrence_rule", Message: "must be a valid RRULE (RFC 5545)"},
+	}, err)
+
+	assert.NoError(t, ValidateTaskRequest(models.DefaultWorkflow(), models.CreateTaskRequest{Title: strings.Repeat("ä", 200)}, true))
+	assert.NoError(t, ValidateTaskRequest(models.DefaultWorkflow(), models.CreateTaskRequest{}, false))
+}
+
+// Test_Service_UpdateTask_ScheduleWithExisting prüft die Fälligkeit gegen den bereits gespeicherten Beginn.
+func Test_Service_UpdateTask_ScheduleWithExisting(t *testing.T) {
+	start := time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC)
+	mockRepo := &repository.MockTaskRepository{
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			return &models.Task{ID: id, Title: "Plan", Status: "todo", StartAt: &start}, nil
+		},
+	}
+	service := TaskService{Repo: mockRepo}
+
+	due := start.Add(-time.Hour)
+	_, err := service.UpdateTask(1, models.CreateTaskRequest{DueAt: &due})
+	assert.Equal(t, validation.Errors{{Field: "due_at", Message: "must not be before start_at"}}, err)
+}
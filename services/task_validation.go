@@ -0,0 +1,37 @@
+package services
+
+import (
+	"strings"
+	"task-api/models"
+	"task-api/validation"
+	"time"
+)
+
+// ValidateTaskRequest prüft einen Create- (create=true) oder Update-Request gegen die Regeln für Tasks
+// und liefert alle Verstöße als validation.Errors bzw. nil.
+// Geprüft werden die Tags in models.CreateTaskRequest (Längen in Zeichen, erlaubte Werte), der Titel als
+// Pflichtfeld beim Erstellen, der Status gegen den Workflow sowie Beginn, Fälligkeit und Wiederholungsregel
+// im Zusammenhang. TaskService ruft die Prüfung selbst auf, sodass REST, gRPC, GraphQL und WebSocket
+// dieselben Regeln und Meldungen verwenden.
+func ValidateTaskRequest(workflow *models.Workflow, req models.CreateTaskRequest, create bool) error {
+	var v validation.Validator
+	if create {
+		v.Check(req.Title != "", "title", "is required")
+	}
+	v.Struct(req)
+	v.Check(req.Status == "" || workflow.Status(req.Status) != nil,
+		"status", "must be one of: "+strings.Join(workflow.StatusNames(), ", "))
+	checkSchedule(&v, req.StartAt, req.DueAt, req.RecurrenceRule)
+	return v.Err()
+}
+
+// checkSchedule prüft, dass der geplante Beginn nicht nach der Fälligkeit liegt und eine gesetzte
+// Wiederholungsregel gültig ist und eine Fälligkeit als Anker hat.
+func checkSchedule(v *validation.Validator, startAt, dueAt *time.Time, rule *string) {
+	v.Check(startAt == nil || dueAt == nil || !startAt.After(*dueAt), "due_at", "must not be before start_at")
+	if rule != nil && *rule != "" {
+		if _, err := normalizeRecurrenceRule(*rule, dueAt); err != nil {
+			v.Check(false, "recurrence_rule", err.Error())
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"strings"
+	"task-api/models"
+	"task-api/repository"
+	"unicode/utf8"
+)
+
+// DefaultTenant ist der Mandant für Benutzer, die ohne Angabe eines Mandanten angelegt werden.
+const DefaultTenant = "default"
+
+// UserService kapselt die Businesslogik für Benutzer und deren Authentifizierung per API-Key.
+type UserService struct {
+	Repo repository.UserRepositoryInterface
+}
+
+// HashAPIKey berechnet den SHA-256-Hash eines API-Keys, wie er in der Datenbank gespeichert wird.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateAPIKey erzeugt einen zufälligen API-Key (32 Byte, hex-kodiert).
+func GenerateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CreateUser legt einen neuen Benutzer an und gibt ihn zusammen mit seinem API-Key zurück.
+// Benutzer werden immer im Mandanten von actor angelegt (bzw. in DefaultTenant ohne actor); ein
+// abweichender Tenant ist nicht erlaubt. Gibt "invalid name", "invalid email", "email already exists" oder
+// "user belongs to another tenant" (Benutzer in fremdem Mandanten, auch ohne actor) zurück.
+func (s *UserService) CreateUser(req models.CreateUserRequest, actor *models.User) (*models.CreatedUser, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > 100 {
+		return nil, fmt.Errorf("invalid name")
+	}
+	addr, err := mail.ParseAddress(strings.TrimSpace(req.Email))
+	if err != nil || addr.Name != "" {
+		return nil, fmt.Errorf("invalid email")
+	}
+
+	// Anonyme Aufrufer (POST /users ist öffentlich) dürfen nur im Default-Mandanten anlegen; sonst
+	// könnte sich jeder einen API-Key in einem fremden Mandanten verschaffen.
+	actorTenant := DefaultTenant
+	if actor != nil {
+		actorTenant = actor.Tenant
+	}
+	tenant := strings.TrimSpace(req.Tenant)
+	if tenant == "" {
+		tenant = actorTenant
+	}
+	if tenant != actorTenant {
+		return nil, fmt.Errorf("user belongs to another tenant")
+	}
+
+	existing, err := s.Repo.GetByEmail(addr.Address)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("email already exists")
+	}
+
+	key, err := GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.Repo.Create(&models.User{Name: name, Email: addr.Address, Tenant: tenant}, HashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+	return &models.CreatedUser{User: user, APIKey: key}, nil
+}
+
+// GetAllUsers gibt alle Benutzer eines Mandanten zurück.
+func (s *UserService) GetAllUsers(tenant string) ([]*models.User, error) {
+	return s.Repo.GetAll(tenant)
+}
+
+// GetUserByID gibt einen Benutzer anhand der ID zurück.
+// Gibt "not found" zurück, wenn der Benutzer nicht existiert.
+func (s *UserService) GetUserByID(id int) (*models.User, error) {
+	user, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	return user, nil
+}
+
+// GetUsersByIDs gibt alle Benutzer mit den angegebenen IDs zurück.
+func (s *UserService) GetUsersByIDs(ids []int) ([]*models.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return s.Repo.GetByIDs(ids)
+}
+
+// Authenticate gibt den Benutzer zu einem API-Key zurück.
+// Gibt "invalid api key" zurück, wenn der Key leer oder unbekannt ist.
+func (s *UserService) Authenticate(apiKey string) (*models.User, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("invalid api key")
+	}
+	user, err := s.Repo.GetByAPIKeyHash(HashAPIKey(apiKey))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("invalid api key")
+	}
+	return user, nil
+}
+
+// RotateAPIKey erzeugt einen neuen API-Key für einen Benutzer und gibt ihn im Klartext zurück.
+// Der bisherige Key wird ungültig; gespeichert wird nur der Hash.
+// Gibt "not found" zurück, wenn der Benutzer nicht existiert.
+func (s *UserService) RotateAPIKey(userID int) (string, error) {
+	if _, err := s.GetUserByID(userID); err != nil {
+		return "", err
+	}
+	key, err := GenerateAPIKey()
+	if err != nil {
+		return "", err
+	}
+	if err := s.Repo.SetAPIKeyHash(userID, HashAPIKey(key)); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// CreateCalendarToken erzeugt ein neues Kalender-Token für einen Benutzer und gibt es im Klartext zurück.
+// Ein bestehendes Token wird dabei ungültig; gespeichert wird nur der Hash.
+func (s *UserService) CreateCalendarToken(userID int) (string, error) {
+	token, err := GenerateAPIKey()
+	if err != nil {
+		return "", err
+	}
+	if err := s.Repo.SetCalendarTokenHash(userID, HashAPIKey(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// RevokeCalendarToken entfernt das Kalender-Token eines Benutzers.
+func (s *UserService) RevokeCalendarToken(userID int) error {
+	return s.Repo.SetCalendarTokenHash(userID, "")
+}
+
+// AuthenticateCalendar gibt den Benutzer zu einem Kalender-Token zurück.
+// Gibt "invalid calendar token" zurück, wenn das Token leer oder unbekannt ist.
+func (s *UserService) AuthenticateCalendar(token string) (*models.User, error) {
+	if token == "" {
+		return nil, fmt.Errorf("invalid calendar token")
+	}
+	user, err := s.Repo.GetByCalendarTokenHash(HashAPIKey(token))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("invalid calendar token")
+	}
+	return user, nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import "task-api/models"
+
+// UserServiceInterface definiert die Methoden, die jeder UserService implementieren muss.
+// Dient dazu, die echte Service-Logik in Handler-Tests durch einen Mock zu ersetzen.
+type UserServiceInterface interface {
+	// CreateUser legt einen neuen Benutzer an und gibt ihn inklusive API-Key zurück.
+	// actor ist der anfragende Benutzer (nil ohne Authentifizierung).
+	CreateUser(req models.CreateUserRequest, actor *models.User) (*models.CreatedUser, error)
+
+	// GetAllUsers gibt alle Benutzer eines Mandanten zurück.
+	GetAllUsers(tenant string) ([]*models.User, error)
+
+	// GetUserByID gibt einen Benutzer anhand der ID zurück.
+	// Gibt "not found" zurück, wenn der Benutzer nicht existiert.
+	GetUserByID(id int) (*models.User, error)
+
+	// GetUsersByIDs gibt alle Benutzer mit den angegebenen IDs in einer Abfrage zurück.
+	// Nicht existierende IDs werden ignoriert.
+	GetUsersByIDs(ids []int) ([]*models.User, error)
+
+	// Authenticate gibt den Benutzer zu einem API-Key zurück.
+	// Gibt "invalid api key" zurück, wenn der Key unbekannt ist.
+	Authenticate(apiKey string) (*models.User, error)
+
+	// RotateAPIKey erzeugt einen neuen API-Key für einen Benutzer; der bisherige wird ungültig.
+	// Gibt "not found" zurück, wenn der Benutzer nicht existiert.
+	RotateAPIKey(userID int) (string, error)
+
+	// CreateCalendarToken erzeugt ein neues Kalender-Token für GET /calendar.ics; ein bestehendes wird ungültig.
+	CreateCalendarToken(userID int) (string, error)
+
+	// RevokeCalendarToken entfernt das Kalender-Token eines Benutzers.
+	RevokeCalendarToken(userID int) error
+
+	// AuthenticateCalendar gibt den Benutzer zu einem Kalender-Token zurück.
+	// Gibt "invalid calendar token" zurück, wenn das Token unbekannt ist.
+	AuthenticateCalendar(token string) (*models.User, error)
+}
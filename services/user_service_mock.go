@@ -0,0 +1,168 @@
+package services
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"task-api/models"
+)
+
+// MockUserService implementiert UserServiceInterface für Tests.
+// Felder:
+// - Users: Vorhandene Benutzer.
+// - APIKeys: Zuordnung API-Key -> Benutzer-ID.
+// - CalendarTokens: Zuordnung Kalender-Token -> Benutzer-ID.
+// - ShouldFail: Wenn true, schlagen alle Methoden absichtlich fehl.
+type MockUserService struct {
+	Users          []*models.User
+	APIKeys        map[string]int
+	CalendarTokens map[string]int
+	ShouldFail     bool
+
+	rotations int // Anzahl der Aufrufe von RotateAPIKey
+}
+
+// CreateUser simuliert das Anlegen eines Benutzers. Der API-Key lautet "key-<id>".
+// Liefert "email already exists", wenn die Adresse bereits vergeben ist, und
+// "user belongs to another tenant" für einen anderen Mandanten als den von actor (bzw. DefaultTenant).
+func (m *MockUserService) CreateUser(req models.CreateUserRequest, actor *models.User) (*models.CreatedUser, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	for _, u := range m.Users {
+		if u.Email == req.Email {
+			return nil, fmt.Errorf("email already exists")
+		}
+	}
+	actorTenant := DefaultTenant
+	if actor != nil {
+		actorTenant = actor.Tenant
+	}
+	tenant := req.Tenant
+	if tenant == "" {
+		tenant = actorTenant
+	}
+	if tenant != actorTenant {
+		return nil, fmt.Errorf("user belongs to another tenant")
+	}
+	user := &models.User{ID: len(m.Users) + 1, Name: req.Name, Email: req.Email, Tenant: tenant}
+	m.Users = append(m.Users, user)
+
+	key := fmt.Sprintf("key-%d", user.ID)
+	if m.APIKeys == nil {
+		m.APIKeys = map[string]int{}
+	}
+	m.APIKeys[key] = user.ID
+	return &models.CreatedUser{User: user, APIKey: key}, nil
+}
+
+// GetAllUsers gibt alle Benutzer des Mandanten im Mock zurück.
+func (m *MockUserService) GetAllUsers(tenant string) ([]*models.User, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	var users []*models.User
+	for _, u := range m.Users {
+		if u.Tenant == tenant {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// GetUserByID sucht einen Benutzer anhand der ID.
+// Liefert "not found", wenn kein Benutzer existiert.
+func (m *MockUserService) GetUserByID(id int) (*models.User, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	for _, u := range m.Users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+// GetUsersByIDs gibt alle Benutzer im Mock mit den angegebenen IDs zurück.
+func (m *MockUserService) GetUsersByIDs(ids []int) ([]*models.User, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	var users []*models.User
+	for _, u := range m.Users {
+		if containsID(ids, u.ID) {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// Authenticate sucht den Benutzer zu einem API-Key in APIKeys.
+// Liefert "invalid api key", wenn der Key unbekannt ist.
+func (m *MockUserService) Authenticate(apiKey string) (*models.User, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if id, ok := m.APIKeys[apiKey]; ok {
+		return m.GetUserByID(id)
+	}
+	return nil, fmt.Errorf("invalid api key")
+}
+
+// RotateAPIKey ersetzt alle API-Keys des Benutzers in APIKeys durch "key-<id>-r<n>" (n-te Rotation).
+// Liefert "not found", wenn der Benutzer nicht existiert.
+func (m *MockUserService) RotateAPIKey(userID int) (string, error) {
+	if _, err := m.GetUserByID(userID); err != nil {
+		return "", err
+	}
+	for key, id := range m.APIKeys {
+		if id == userID {
+			delete(m.APIKeys, key)
+		}
+	}
+	if m.APIKeys == nil {
+		m.APIKeys = map[string]int{}
+	}
+	m.rotations++
+	key := fmt.Sprintf("key-%d-r%d", userID, m.rotations)
+	m.APIKeys[key] = userID
+	return key, nil
+}
+
+// CreateCalendarToken legt das Kalender-Token "cal-<id>" an und entfernt frühere Tokens des Benutzers.
+func (m *MockUserService) CreateCalendarToken(userID int) (string, error) {
+	if err := m.RevokeCalendarToken(userID); err != nil {
+		return "", err
+	}
+	token := fmt.Sprintf("cal-%d", userID)
+	if m.CalendarTokens == nil {
+		m.CalendarTokens = map[string]int{}
+	}
+	m.CalendarTokens[token] = userID
+	return token, nil
+}
+
+// RevokeCalendarToken entfernt alle Kalender-Tokens des Benutzers aus CalendarTokens.
+func (m *MockUserService) RevokeCalendarToken(userID int) error {
+	if m.ShouldFail {
+		return fiber.ErrInternalServerError
+	}
+	for token, id := range m.CalendarTokens {
+		if id == userID {
+			delete(m.CalendarTokens, token)
+		}
+	}
+	return nil
+}
+
+// AuthenticateCalendar sucht den Benutzer zu einem Kalender-Token in CalendarTokens.
+// Liefert "invalid calendar token", wenn das Token unbekannt ist.
+func (m *MockUserService) AuthenticateCalendar(token string) (*models.User, error) {
+	if m.ShouldFail {
+		return nil, fiber.ErrInternalServerError
+	}
+	if id, ok := m.CalendarTokens[token]; ok {
+		return m.GetUserByID(id)
+	}
+	return nil, fmt.Errorf("invalid calendar token")
+}
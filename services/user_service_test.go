@@ -0,0 +1,227 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+)
+
+// Diese Datei enthält Unit-Tests für den UserService und die Zuweisung von Tasks an Benutzer.
+
+// Test_UserService_CreateUser_StoresKeyHash prüft, dass nur der Hash des API-Keys gespeichert wird
+// und der Mandant des anfragenden Benutzers übernommen wird.
+func Test_UserService_CreateUser_StoresKeyHash(t *testing.T) {
+	var storedHash string
+	mockRepo := &repository.MockUserRepository{
+		GetByEmailFunc: func(email string) (*models.User, error) {
+			return nil, nil
+		},
+		CreateFunc: func(user *models.User, apiKeyHash string) (*models.User, error) {
+			storedHash = apiKeyHash
+			user.ID = 1
+			return user, nil
+		},
+	}
+	service := UserService{Repo: mockRepo}
+
+	created, err := service.CreateUser(models.CreateUserRequest{Name: "Anna", Email: "anna@example.com"},
+		&models.User{ID: 9, Tenant: "acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", created.Tenant)
+	assert.Len(t, created.APIKey, 64)
+	assert.NotEqual(t, created.APIKey, storedHash)
+	assert.Equal(t, HashAPIKey(created.APIKey), storedHash)
+}
+
+// Test_UserService_CreateUser_Validation prüft die Fehlerfälle beim Anlegen eines Benutzers.
+func Test_UserService_CreateUser_Validation(t *testing.T) {
+	mockRepo := &repository.MockUserRepository{
+		GetByEmailFunc: func(email string) (*models.User, error) {
+			if email == "taken@example.com" {
+				return &models.User{ID: 1}, nil
+			}
+			return nil, nil
+		},
+	}
+	service := UserService{Repo: mockRepo}
+
+	_, err := service.CreateUser(models.CreateUserRequest{Name: "", Email: "a@example.com"}, nil)
+	assert.Equal(t, "invalid name", err.Error())
+
+	_, err = service.CreateUser(models.CreateUserRequest{Name: "A", Email: "no-mail"}, nil)
+	assert.Equal(t, "invalid email", err.Error())
+
+	_, err = service.CreateUser(models.CreateUserRequest{Name: "A", Email: "taken@example.com"}, nil)
+	assert.Equal(t, "email already exists", err.Error())
+
+	_, err = service.CreateUser(models.CreateUserRequest{Name: "A", Email: "a@example.com", Tenant: "other"},
+		&models.User{Tenant: "acme"})
+	assert.Equal(t, "user belongs to another tenant", err.Error())
+
+	// Ohne Anmeldung nur im Default-Mandanten
+	_, err = service.CreateUser(models.CreateUserRequest{Name: "A", Email: "a@example.com", Tenant: "other"}, nil)
+	assert.Equal(t, "user belongs to another tenant", err.Error())
+}
+
+// Test_UserService_Authenticate prüft, dass Benutzer über den Hash ihres API-Keys gefunden werden.
+func Test_UserService_Authenticate(t *testing.T) {
+	mockRepo := &repository.MockUserRepository{
+		GetByAPIKeyHashFunc: func(hash string) (*models.User, error) {
+			if hash == HashAPIKey("secret") {
+				return &models.User{ID: 1}, nil
+			}
+			return nil, nil
+		},
+	}
+	service := UserService{Repo: mockRepo}
+
+	user, err := service.Authenticate("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+
+	_, err = service.Authenticate("wrong")
+	assert.Equal(t, "invalid api key", err.Error())
+}
+
+// Test_UserService_CalendarToken prüft, dass nur der Hash eines Kalender-Tokens gespeichert wird, ein neues
+// Token das alte ersetzt und Entfernen den Hash löscht.
+func Test_UserService_CalendarToken(t *testing.T) {
+	hashes := map[int]string{}
+	mockRepo := &repository.MockUserRepository{
+		SetCalendarTokenHashFunc: func(userID int, hash string) error {
+			hashes[userID] = hash
+			return nil
+		},
+		GetByCalendarTokenHashFunc: func(hash string) (*models.User, error) {
+			for id, h := range hashes {
+				if h != "" && h == hash {
+					return &models.User{ID: id}, nil
+				}
+			}
+			return nil, nil
+		},
+	}
+	service := UserService{Repo: mockRepo}
+
+	first, err := service.CreateCalendarToken(1)
+	assert.NoError(t, err)
+	assert.Equal(t, HashAPIKey(first), hashes[1])
+	second, err := service.CreateCalendarToken(1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	user, err := service.AuthenticateCalendar(second)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	_, err = service.AuthenticateCalendar(first)
+	assert.Equal(t, "invalid calendar token", err.Error())
+
+	assert.NoError(t, service.RevokeCalendarToken(1))
+	assert.Empty(t, hashes[1])
+	_, err = service.AuthenticateCalendar(second)
+	assert.Equal(t, "invalid calendar token", err.Error())
+	_, err = service.AuthenticateCalendar("")
+	assert.Equal(t, "invalid calendar token", err.Error())
+}
+
+// Test_UserService_RotateAPIKey prüft, dass nach der Rotation nur noch der neue Key gilt.
+func Test_UserService_RotateAPIKey(t *testing.T) {
+	hashes := map[int]string{1: HashAPIKey("alt")}
+	mockRepo := &repository.MockUserRepository{
+		GetByIdFunc: func(id int) (*models.User, error) {
+			if _, ok := hashes[id]; ok {
+				return &models.User{ID: id}, nil
+			}
+			return nil, nil
+		},
+		SetAPIKeyHashFunc: func(userID int, hash string) error {
+			hashes[userID] = hash
+			return nil
+		},
+		GetByAPIKeyHashFunc: func(hash string) (*models.User, error) {
+			for id, h := range hashes {
+				if h == hash {
+					return &models.User{ID: id}, nil
+				}
+			}
+			return nil, nil
+		},
+	}
+	service := UserService{Repo: mockRepo}
+
+	key, err := service.RotateAPIKey(1)
+	assert.NoError(t, err)
+	assert.Len(t, key, 64)
+	assert.Equal(t, HashAPIKey(key), hashes[1])
+
+	user, err := service.Authenticate(key)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	_, err = service.Authenticate("alt")
+	assert.Equal(t, "invalid api key", err.Error())
+
+	_, err = service.RotateAPIKey(2)
+	assert.Equal(t, "not found", err.Error())
+}
+
+// assignmentService erstellt einen TaskService mit Task 1 (Benutzer 1 zugewiesen) und den
+// Benutzern 1, 2 (Mandant "acme") und 3 (Mandant "other").
+func assignmentService(added *[]int) *TaskService {
+	users := map[int]*models.User{
+		1: {ID: 1, Tenant: "acme"},
+		2: {ID: 2, Tenant: "acme"},
+		3: {ID: 3, Tenant: "other"},
+	}
+	return &TaskService{
+		Repo: &repository.MockTaskRepository{
+			GetByIdFunc: func(id int) (*models.Task, error) {
+				if id != 1 {
+					return nil, nil
+				}
+				return &models.Task{ID: 1, Assignees: []int{1}}, nil
+			},
+		},
+		Users: &repository.MockUserRepository{
+			GetByIdFunc: func(id int) (*models.User, error) {
+				return users[id], nil
+			},
+			AddAssigneeFunc: func(taskID, userID int) error {
+				*added = append(*added, userID)
+				return nil
+			},
+		},
+	}
+}
+
+// Test_TaskService_AssignUser_Validation prüft, dass Verantwortliche existieren und demselben
+// Mandanten angehören müssen wie der anfragende Benutzer und die bisherigen Verantwortlichen.
+func Test_TaskService_AssignUser_Validation(t *testing.T) {
+	var added []int
+	service := assignmentService(&added)
+
+	assert.Equal(t, "not found", service.AssignUser(2, 2, nil).Error())
+	assert.Equal(t, "user not found", service.AssignUser(1, 99, nil).Error())
+
+	// Ohne anfragenden Benutzer entscheidet der Mandant der bisherigen Verantwortlichen
+	assert.Equal(t, "user belongs to another tenant", service.AssignUser(1, 3, nil).Error())
+	assert.Equal(t, "user belongs to another tenant",
+		service.AssignUser(1, 2, &models.User{ID: 3, Tenant: "other"}).Error())
+
+	assert.NoError(t, service.AssignUser(1, 2, &models.User{ID: 1, Tenant: "acme"}))
+	assert.Equal(t, []int{2}, added)
+	assert.Equal(t, []models.TaskEvent{{Type: models.EventTaskUpdated}}, service.Users.(*repository.MockUserRepository).Events)
+}
+
+// Test_TaskService_UnassignUser_NotAssigned prüft den Fehler beim Entfernen einer nicht vorhandenen Zuweisung.
+func Test_TaskService_UnassignUser_NotAssigned(t *testing.T) {
+	service := &TaskService{
+		Users: &repository.MockUserRepository{
+			RemoveAssigneeFunc: func(taskID, userID int) (bool, error) {
+				return false, nil
+			},
+		},
+	}
+
+	assert.Equal(t, "assignment not found", service.UnassignUser(1, 2).Error())
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"task-api/models"
+	"task-api/repository"
+	"time"
+)
+
+// Header, die bei jeder Webhook-Zustellung gesendet werden.
+const (
+	// HeaderWebhookSignature enthält "t=<Unix-Zeit>,v1=<HMAC-SHA256 hex>".
+	HeaderWebhookSignature = "X-Webhook-Signature"
+	// HeaderWebhookEvent enthält den Ereignistyp, z.B. "task.created".
+	HeaderWebhookEvent = "X-Webhook-Event"
+	// HeaderWebhookID enthält die ID des Ereignisses; Empfänger können damit Duplikate erkennen.
+	HeaderWebhookID = "X-Webhook-Id"
+	// HeaderWebhookDelivery enthält die ID der Zustellung.
+	HeaderWebhookDelivery = "X-Webhook-Delivery"
+)
+
+// SignWebhook berechnet den Wert des Signatur-Headers: HMAC-SHA256 mit dem Secret über
+// "<timestamp>.<body>". Der Zeitstempel ist Teil der Signatur, damit alte Requests nicht
+// wiederholt eingespielt werden können.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// VerifyWebhookSignature prüft einen Signatur-Header aus Sicht des Empfängers.
+// Der Zeitstempel darf höchstens tolerance von now abweichen.
+func VerifyWebhookSignature(secret, header string, body []byte, tolerance time.Duration, now time.Time) bool {
+	var timestamp int64 = -1
+	var signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			if t, err := strconv.ParseInt(value, 10, 64); err == nil {
+				timestamp = t
+			}
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp < 0 || signature == "" {
+		return false
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return false
+	}
+	expected := SignWebhook(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(fmt.Sprintf("t=%d,v1=%s", timestamp, signature)))
+}
+
+// WebhookDispatcher verteilt im Hintergrund die Ereignisse aus der Outbox auf die Webhooks
+// und stellt sie per HTTP POST zu. Fehlgeschlagene Zustellungen werden mit exponentiellem
+// Backoff wiederholt und nach MaxAttempts Versuchen als Dead-Letter markiert.
+type WebhookDispatcher struct {
+	Repo repository.WebhookRepositoryInterface
+
+	// Client sendet die Requests. Ist nil, wird ein Client mit 10s Timeout verwendet.
+	Client *http.Client
+
+	// PollInterval legt fest, wie oft die Outbox geprüft wird. Default: 5 Sekunden.
+	PollInterval time.Duration
+
+	// MaxAttempts ist die maximale Anzahl an Zustellversuchen. Default: 8.
+	MaxAttempts int
+
+	// BaseBackoff ist die Wartezeit nach dem ersten Fehlversuch; sie verdoppelt sich mit
+	// jedem weiteren Versuch bis höchstens MaxBackoff. Defaults: 30 Sekunden bzw. 6 Stunden.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+
+	// BatchSize begrenzt die Anzahl der Ereignisse und Zustellungen je Durchlauf. Default: 100.
+	// Zustellungen werden einzeln reserviert, damit die Reservierung nur einen Request abdecken muss.
+	BatchSize int
+
+	// now liefert die aktuelle Zeit; in Tests überschreibbar.
+	now func() time.Time
+}
+
+// Run startet die Zustellschleife und blockiert, bis ctx beendet wird.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	interval := d.PollInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.ProcessOnce(ctx); err != nil {
+			log.Printf("webhook dispatcher: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessOnce verteilt neue Outbox-Ereignisse auf die Webhooks und versendet bis zu BatchSize fällige
+// Zustellungen. Jede Zustellung wird erst unmittelbar vor dem Versand reserviert; die Reservierung muss
+// daher nur länger gelten, als ein einzelner Request maximal dauern kann.
+func (d *WebhookDispatcher) ProcessOnce(ctx context.Context) error {
+	if _, err := d.Repo.DispatchEvents(d.batchSize()); err != nil {
+		return err
+	}
+
+	lease := 2*d.client().Timeout + time.Minute
+	for range d.batchSize() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		jobs, err := d.Repo.ClaimDueDeliveries(1, lease)
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+		if err := d.deliver(ctx, jobs[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backoff gibt die Wartezeit nach dem Fehlversuch attempt (ab 1) zurück:
+// BaseBackoff * 2^(attempt-1), höchstens MaxBackoff.
+func (d *WebhookDispatcher) Backoff(attempt int) time.Duration {
+	backoff, limit := d.BaseBackoff, d.MaxBackoff
+	if backoff <= 0 {
+		backoff = 30 * time.Second
+	}
+	if limit <= 0 {
+		limit = 6 * time.Hour
+	}
+
+	for i := 1; i < attempt && backoff < limit; i++ {
+		backoff *= 2
+	}
+	return min(backoff, limit)
+}
+
+// deliver sendet eine Zustellung und protokolliert das Ergebnis.
+// Nur Fehler beim Protokollieren werden zurückgegeben; Fehler des Empfängers führen zu einem Retry.
+// Hat inzwischen eine andere Instanz die Zustellung übernommen, wird das Ergebnis verworfen.
+func (d *WebhookDispatcher) deliver(ctx context.Context, job *models.DeliveryJob) error {
+	err := d.attempt(ctx, job)
+	if errors.Is(err, repository.ErrClaimLost) {
+		log.Printf("webhook dispatcher: delivery %d was taken over by another worker, result discarded", job.ID)
+		return nil
+	}
+	return err
+}
+
+// attempt führt einen Zustellversuch aus und protokolliert ihn über RecordAttempt.
+func (d *WebhookDispatcher) attempt(ctx context.Context, job *models.DeliveryJob) error {
+	start := d.currentTime()
+	attempt := models.DeliveryAttempt{Attempt: job.Attempts + 1, CreatedAt: start}
+
+	statusCode, err := d.send(ctx, job, start)
+	attempt.DurationMs = d.currentTime().Sub(start).Milliseconds()
+	if statusCode != 0 {
+		attempt.StatusCode = &statusCode
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	switch {
+	case err == nil:
+		return d.Repo.RecordAttempt(job.ID, attempt, models.DeliverySucceeded, nil)
+	case attempt.Attempt >= d.maxAttempts():
+		return d.Repo.RecordAttempt(job.ID, attempt, models.DeliveryDead, nil)
+	default:
+		next := start.Add(d.Backoff(attempt.Attempt))
+		return d.Repo.RecordAttempt(job.ID, attempt, models.DeliveryPending, &next)
+	}
+}
+
+// send führt den signierten HTTP-Request aus. Jeder Status außer 2xx gilt als Fehler.
+func (d *WebhookDispatcher) send(ctx context.Context, job *models.DeliveryJob, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(job.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-api-webhooks/1.0")
+	req.Header.Set(HeaderWebhookEvent, job.Event)
+	req.Header.Set(HeaderWebhookID, strconv.FormatInt(job.EventID, 10))
+	req.Header.Set(HeaderWebhookDelivery, strconv.Itoa(job.ID))
+	req.Header.Set(HeaderWebhookSignature, SignWebhook(job.Secret, now.Unix(), job.Payload))
+
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// client gibt den konfigurierten HTTP-Client oder einen Default-Client zurück.
+func (d *WebhookDispatcher) client() *http.Client {
+	if d.Client == nil {
+		d.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return d.Client
+}
+
+// maxAttempts gibt MaxAttempts oder den Default 8 zurück.
+func (d *WebhookDispatcher) maxAttempts() int {
+	if d.MaxAttempts <= 0 {
+		return 8
+	}
+	return d.MaxAttempts
+}
+
+// batchSize gibt BatchSize oder den Default 100 zurück.
+func (d *WebhookDispatcher) batchSize() int {
+	if d.BatchSize <= 0 {
+		return 100
+	}
+	return d.BatchSize
+}
+
+// currentTime liefert die aktuelle Zeit (in Tests über now überschreibbar).
+func (d *WebhookDispatcher) currentTime() time.Time {
+	if d.now != nil {
+		return d.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+	"time"
+)
+
+// Diese Datei enthält Unit-Tests für die Signatur und die Zustellung von Webhooks.
+
+// recordedAttempt hält einen über RecordAttempt protokollierten Zustellversuch fest.
+type recordedAttempt struct {
+	deliveryID int
+	attempt    models.DeliveryAttempt
+	status     string
+	next       *time.Time
+}
+
+// dispatcherWithJobs erstellt einen WebhookDispatcher, dessen Repository jobs der Reihe nach (jeweils
+// höchstens limit) genau einmal liefert und alle Zustellversuche in recorded sammelt.
+func dispatcherWithJobs(now time.Time, jobs []*models.DeliveryJob, recorded *[]recordedAttempt) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		Repo: &repository.MockWebhookRepository{
+			DispatchEventsFunc: func(limit int) (int, error) { return 0, nil },
+			ClaimDueDeliveriesFunc: func(limit int, lease time.Duration) ([]*models.DeliveryJob, error) {
+				claimed := jobs[:min(limit, len(jobs))]
+				jobs = jobs[len(claimed):]
+				return claimed, nil
+			},
+			RecordAttemptFunc: func(id int, a models.DeliveryAttempt, status string, next *time.Time) error {
+				*recorded = append(*recorded, recordedAttempt{id, a, status, next})
+				return nil
+			},
+		},
+		MaxAttempts: 3,
+		BaseBackoff: time.Minute,
+		MaxBackoff:  time.Hour,
+		now:         func() time.Time { return now },
+	}
+}
+
+// Test_SignWebhook prüft die Signatur gegen einen mit openssl berechneten Wert und die Verifikation beim Empfänger.
+func Test_SignWebhook(t *testing.T) {
+	body := []byte(`{"event":"task.created"}`)
+	signature := SignWebhook("whsec_test", 1700000000, body)
+	assert.Equal(t, "t=1700000000,v1=aabc548901ea3b50be05eb85dc114164830b27c602dcb16a1623b007eff48c20", signature)
+
+	now := time.Unix(1700000060, 0)
+	assert.True(t, VerifyWebhookSignature("whsec_test", signature, body, 5*time.Minute, now))
+	assert.False(t, VerifyWebhookSignature("whsec_other", signature, body, 5*time.Minute, now))
+	assert.False(t, VerifyWebhookSignature("whsec_test", signature, []byte(`{}`), 5*time.Minute, now))
+	assert.False(t, VerifyWebhookSignature("whsec_test", signature, body, 30*time.Second, now))
+	assert.False(t, VerifyWebhookSignature("whsec_test", "v1=abc", body, 5*time.Minute, now))
+}
+
+// Test_WebhookDispatcher_Backoff prüft das exponentielle Wachstum und die Obergrenze.
+func Test_WebhookDispatcher_Backoff(t *testing.T) {
+	d := &WebhookDispatcher{BaseBackoff: time.Minute, MaxBackoff: time.Hour}
+	assert.Equal(t, time.Minute, d.Backoff(1))
+	assert.Equal(t, 2*time.Minute, d.Backoff(2))
+	assert.Equal(t, 32*time.Minute, d.Backoff(6))
+	assert.Equal(t, time.Hour, d.Backoff(7))
+	assert.Equal(t, time.Hour, d.Backoff(40))
+}
+
+// Test_WebhookDispatcher_Deliver prüft eine erfolgreiche, signierte Zustellung,
+// einen Retry mit Backoff und den Übergang in die Dead-Letter-Liste.
+func Test_WebhookDispatcher_Deliver(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	var received []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r)
+		bodies = append(bodies, body)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	payload, _ := json.Marshal(models.EventPayload{ID: 42, Event: models.EventTaskCreated, Task: &models.Task{ID: 7}})
+	job := func(id int, path string, attempts int) *models.DeliveryJob {
+		return &models.DeliveryJob{
+			WebhookDelivery: models.WebhookDelivery{ID: id, EventID: 42, Event: models.EventTaskCreated, Payload: payload, Attempts: attempts},
+			URL:             server.URL + path,
+			Secret:          "whsec_test",
+		}
+	}
+
+	var recorded []recordedAttempt
+	d := dispatcherWithJobs(now, []*models.DeliveryJob{job(1, "/ok", 0), job(2, "/fail", 0), job(3, "/fail", 2)}, &recorded)
+	assert.NoError(t, d.ProcessOnce(context.Background()))
+
+	assert.Len(t, received, 3)
+	r := received[0]
+	assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	assert.Equal(t, models.EventTaskCreated, r.Header.Get(HeaderWebhookEvent))
+	assert.Equal(t, "42", r.Header.Get(HeaderWebhookID))
+	assert.Equal(t, "1", r.Header.Get(HeaderWebhookDelivery))
+	assert.Equal(t, payload, bodies[0])
+	assert.True(t, VerifyWebhookSignature("whsec_test", r.Header.Get(HeaderWebhookSignature), bodies[0], time.Minute, now))
+
+	assert.Len(t, recorded, 3)
+	assert.Equal(t, models.DeliverySucceeded, recorded[0].status)
+	assert.Equal(t, 204, *recorded[0].attempt.StatusCode)
+	assert.Nil(t, recorded[0].next)
+
+	assert.Equal(t, models.DeliveryPending, recorded[1].status)
+	assert.Equal(t, 1, recorded[1].attempt.Attempt)
+	assert.Equal(t, "unexpected status 503", recorded[1].attempt.Error)
+	assert.Equal(t, now.Add(time.Minute), *recorded[1].next)
+
+	assert.Equal(t, models.DeliveryDead, recorded[2].status)
+	assert.Equal(t, 3, recorded[2].attempt.Attempt)
+	assert.Nil(t, recorded[2].next)
+}
+
+// Test_WebhookDispatcher_ConnectionError prüft, dass Verbindungsfehler ohne Statuscode protokolliert
+// und erneut versucht werden.
+func Test_WebhookDispatcher_ConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	var recorded []recordedAttempt
+	now := time.Now()
+	d := dispatcherWithJobs(now, []*models.DeliveryJob{{
+		WebhookDelivery: models.WebhookDelivery{ID: 1, Payload: []byte(`{}`)},
+		URL:             url,
+		Secret:          "whsec_test",
+	}}, &recorded)
+	assert.NoError(t, d.ProcessOnce(context.Background()))
+
+	assert.Len(t, recorded, 1)
+	assert.Nil(t, recorded[0].attempt.StatusCode)
+	assert.NotEmpty(t, recorded[0].attempt.Error)
+	assert.Equal(t, models.DeliveryPending, recorded[0].status)
+}
+
+// Test_WebhookDispatcher_ClaimsOneAtATime prüft, dass jede Zustellung einzeln reserviert wird und ein
+// verlorener Claim (ErrClaimLost) den Durchlauf nicht abbricht.
+func Test_WebhookDispatcher_ClaimsOneAtATime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var limits []int
+	var recorded []int
+	pending := []*models.DeliveryJob{
+		{WebhookDelivery: models.WebhookDelivery{ID: 1, Payload: []byte(`{}`)}, URL: server.URL},
+		{WebhookDelivery: models.WebhookDelivery{ID: 2, Payload: []byte(`{}`)}, URL: server.URL},
+	}
+	d := &WebhookDispatcher{Repo: &repository.MockWebhookRepository{
+		ClaimDueDeliveriesFunc: func(limit int, lease time.Duration) ([]*models.DeliveryJob, error) {
+			limits = append(limits, limit)
+			claimed := pending[:min(limit, len(pending))]
+			pending = pending[len(claimed):]
+			return claimed, nil
+		},
+		RecordAttemptFunc: func(id int, a models.DeliveryAttempt, status string, next *time.Time) error {
+			if id == 1 {
+				return repository.ErrClaimLost
+			}
+			recorded = append(recorded, id)
+			return nil
+		},
+	}}
+
+	assert.NoError(t, d.ProcessOnce(context.Background()))
+	assert.Equal(t, []int{1, 1, 1}, limits)
+	assert.Equal(t, []int{2}, recorded)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"task-api/models"
+	"task-api/repository"
+)
+
+// DeliveryLogLimit ist die Anzahl der Zustellungen, die GetDeliveries und GetDeadLetters höchstens liefern.
+const DeliveryLogLimit = 100
+
+// WebhookService kapselt die Businesslogik für Webhook-Abonnements und deren Zustellungen.
+// Alle Methoden erfordern einen angemeldeten Benutzer.
+type WebhookService struct {
+	Repo repository.WebhookRepositoryInterface
+}
+
+// normalizeWebhookURL prüft, dass die URL absolut ist und http oder https verwendet.
+func normalizeWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid url")
+	}
+	return raw, nil
+}
+
+// normalizeWebhookEvents entfernt Duplikate und prüft, dass alle Ereignistypen bekannt sind.
+// Eine leere Liste abonniert alle Ereignisse.
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	normalized := []string{}
+	for _, e := range events {
+		if !slices.Contains(models.WebhookEvents, e) {
+			return nil, fmt.Errorf("invalid event")
+		}
+		if !slices.Contains(normalized, e) {
+			normalized = append(normalized, e)
+		}
+	}
+	return normalized, nil
+}
+
+// webhookSecret gibt secret zurück oder erzeugt ein zufälliges Secret, wenn secret leer ist.
+// Gibt "invalid secret" zurück, wenn ein angegebenes Secret kürzer als 16 Zeichen ist.
+func webhookSecret(secret string) (string, error) {
+	if secret == "" {
+		key, err := GenerateAPIKey()
+		if err != nil {
+			return "", err
+		}
+		return "whsec_" + key, nil
+	}
+	if len(secret) < 16 {
+		return "", fmt.Errorf("invalid secret")
+	}
+	return secret, nil
+}
+
+// CreateWebhook legt einen neuen Webhook an und gibt ihn zusammen mit seinem Secret zurück.
+// Gibt "authentication required", "invalid url", "invalid event" oder "invalid secret" zurück.
+func (s *WebhookService) CreateWebhook(req models.WebhookRequest, actor *models.User) (*models.CreatedWebhook, error) {
+	if actor == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	webhook := &models.Webhook{Active: req.Active == nil || *req.Active}
+	var err error
+	if webhook.URL, err = normalizeWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	if webhook.Events, err = normalizeWebhookEvents(req.Events); err != nil {
+		return nil, err
+	}
+	if webhook.Secret, err = webhookSecret(req.Secret); err != nil {
+		return nil, err
+	}
+
+	created, err := s.Repo.Create(webhook)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CreatedWebhook{Webhook: created, Secret: created.Secret}, nil
+}
+
+// GetAllWebhooks gibt alle Webhooks zurück.
+// Gibt "authentication required" zurück, wenn kein Benutzer angemeldet ist.
+func (s *WebhookService) GetAllWebhooks(actor *models.User) ([]*models.Webhook, error) {
+	if actor == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+	return s.Repo.GetAll()
+}
+
+// GetWebhook gibt einen Webhook anhand der ID zurück.
+// Gibt "authentication required" oder "not found" zurück.
+func (s *WebhookService) GetWebhook(id int, actor *models.User) (*models.Webhook, error) {
+	if actor == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+	webhook, err := s.Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, fmt.Errorf("not found")
+	}
+	return webhook, nil
+}
+
+// UpdateWebhook ändert einen Webhook. Felder, die im Request leer bleiben, werden nicht verändert;
+// ein neues Secret ersetzt das bisherige sofort.
+// Gibt "authentication required", "not found", "invalid url", "invalid event" oder "invalid secret" zurück.
+func (s *WebhookService) UpdateWebhook(id int, req models.WebhookRequest, actor *models.User) (*models.Webhook, error) {
+	webhook, err := s.GetWebhook(id, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != "" {
+		if webhook.URL, err = normalizeWebhookURL(req.URL); err != nil {
+			return nil, err
+		}
+	}
+	if req.Events != nil {
+		if webhook.Events, err = normalizeWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+	}
+	if req.Secret != "" {
+		if webhook.Secret, err = webhookSecret(req.Secret); err != nil {
+			return nil, err
+		}
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	return s.Repo.Update(webhook)
+}
+
+// DeleteWebhook löscht einen Webhook samt Zustellungen.
+// Gibt "authentication required" oder "not found" zurück.
+func (s *WebhookService) DeleteWebhook(id int, actor *models.User) error {
+	if actor == nil {
+		return fmt.Errorf("authentication required")
+	}
+	deleted, err := s.Repo.Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("not found")
+	}
+	return nil
+}
+
+// GetDeliveries gibt die letzten Zustellungen eines Webhooks inklusive Protokoll der Versuche zurück.
+// Gibt "authentication required" oder "not found" zurück.
+func (s *WebhookService) GetDeliveries(id int, actor *models.User) ([]*models.WebhookDelivery, error) {
+	if _, err := s.GetWebhook(id, actor); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetDeliveries(id, DeliveryLogLimit)
+}
+
+// GetDeadLetters gibt die letzten endgültig gescheiterten Zustellungen aller Webhooks zurück.
+// Gibt "authentication required" zurück, wenn kein Benutzer angemeldet ist.
+func (s *WebhookService) GetDeadLetters(actor *models.User) ([]*models.WebhookDelivery, error) {
+	if actor == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+	return s.Repo.GetDeadLetters(DeliveryLogLimit)
+}
+
+// RetryDelivery plant eine abgeschlossene Zustellung (dead oder succeeded) erneut ein.
+// Gibt "authentication required", "delivery not found" oder "delivery already pending" zurück.
+func (s *WebhookService) RetryDelivery(id int, actor *models.User) (*models.WebhookDelivery, error) {
+	if actor == nil {
+		return nil, fmt.Errorf("authentication required")
+	}
+	delivery, err := s.Repo.GetDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, fmt.Errorf("delivery not found")
+	}
+	if delivery.Status == models.DeliveryPending {
+		return nil, fmt.Errorf("delivery already pending")
+	}
+
+	if err := s.Repo.RetryDelivery(id); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetDelivery(id)
+}
@@ -0,0 +1,38 @@
+package services
+
+import "task-api/models"
+
+// WebhookServiceInterface definiert die Methoden, die jeder WebhookService implementieren muss.
+// Dient dazu, die echte Service-Logik in Handler-Tests durch einen Mock zu ersetzen.
+// Alle Methoden geben "authentication required" zurück, wenn actor nil ist.
+type WebhookServiceInterface interface {
+	// CreateWebhook legt einen Webhook an und gibt ihn einmalig mit seinem Secret zurück.
+	// Gibt "invalid url", "invalid event" oder "invalid secret" zurück.
+	CreateWebhook(req models.WebhookRequest, actor *models.User) (*models.CreatedWebhook, error)
+
+	// GetAllWebhooks gibt alle Webhooks zurück.
+	GetAllWebhooks(actor *models.User) ([]*models.Webhook, error)
+
+	// GetWebhook gibt einen Webhook anhand der ID zurück.
+	// Gibt "not found" zurück, wenn der Webhook nicht existiert.
+	GetWebhook(id int, actor *models.User) (*models.Webhook, error)
+
+	// UpdateWebhook ändert URL, Events, Secret oder Active eines Webhooks.
+	// Gibt "not found", "invalid url", "invalid event" oder "invalid secret" zurück.
+	UpdateWebhook(id int, req models.WebhookRequest, actor *models.User) (*models.Webhook, error)
+
+	// DeleteWebhook löscht einen Webhook samt Zustellungen.
+	// Gibt "not found" zurück, wenn der Webhook nicht existiert.
+	DeleteWebhook(id int, actor *models.User) error
+
+	// GetDeliveries gibt das Zustellprotokoll eines Webhooks zurück.
+	// Gibt "not found" zurück, wenn der Webhook nicht existiert.
+	GetDeliveries(id int, actor *models.User) ([]*models.WebhookDelivery, error)
+
+	// GetDeadLetters gibt alle endgültig gescheiterten Zustellungen zurück.
+	GetDeadLetters(actor *models.User) ([]*models.WebhookDelivery, error)
+
+	// RetryDelivery plant eine abgeschlossene Zustellung erneut ein.
+	// Gibt "delivery not found" oder "delivery already pending" zurück.
+	RetryDelivery(id int, actor *models.User) (*models.WebhookDelivery, error)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"task-api/models"
+	"time"
+)
+
+// MockWebhookService implementiert WebhookServiceInterface für Tests.
+// Felder:
+// - Webhooks: Vorhandene Webhooks.
+// - Deliveries: Vorhandene Zustellungen aller Webhooks.
+// - ShouldFail: Wenn true, schlagen alle Methoden absichtlich fehl.
+type MockWebhookService struct {
+	Webhooks   []*models.Webhook
+	Deliveries []*models.WebhookDelivery
+	ShouldFail bool
+}
+
+// check prüft ShouldFail und den angemeldeten Benutzer.
+func (m *MockWebhookService) check(actor *models.User) error {
+	if m.ShouldFail {
+		return fiber.ErrInternalServerError
+	}
+	if actor == nil {
+		return fmt.Errorf("authentication required")
+	}
+	return nil
+}
+
+// CreateWebhook legt einen Webhook im Mock an. Prüft nur URL und Events grob.
+func (m *MockWebhookService) CreateWebhook(req models.WebhookRequest, actor *models.User) (*models.CreatedWebhook, error) {
+	if err := m.check(actor); err != nil {
+		return nil, err
+	}
+	url, err := normalizeWebhookURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret = fmt.Sprintf("whsec_mock_%d", len(m.Webhooks)+1)
+	}
+
+	webhook := &models.Webhook{
+		ID: len(m.Webhooks) + 1, URL: url, Secret: secret, Events: events,
+		Active: req.Active == nil || *req.Active, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	m.Webhooks = append(m.Webhooks, webhook)
+	return &models.CreatedWebhook{Webhook: webhook, Secret: secret}, nil
+}
+
+// GetAllWebhooks gibt alle Webhooks des Mocks zurück.
+func (m *MockWebhookService) GetAllWebhooks(actor *models.User) ([]*models.Webhook, error) {
+	if err := m.check(actor); err != nil {
+		return nil, err
+	}
+	return m.Webhooks, nil
+}
+
+// GetWebhook sucht einen Webhook im Mock. Liefert "not found", wenn er nicht existiert.
+func (m *MockWebhookService) GetWebhook(id int, actor *models.User) (*models.Webhook, error) {
+	if err := m.check(actor); err != nil {
+		return nil, err
+	}
+	for _, w := range m.Webhooks {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+// UpdateWebhook ändert URL und Active eines Webhooks im Mock.
+func (m *MockWebhookService) UpdateWebhook(id int, req models.WebhookRequest, actor *models.User) (*models.Webhook, error) {
+	webhook, err := m.GetWebhook(id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if req.URL != "" {
+		if webhook.URL, err = normalizeWebhookURL(req.URL); err != nil {
+			return nil, err
+		}
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	webhook.UpdatedAt = time.Now()
+	return webhook, nil
+}
+
+// DeleteWebhook entfernt einen Webhook samt Zustellungen aus dem Mock.
+func (m *MockWebhookService) DeleteWebhook(id int, actor *models.User) error {
+	if _, err := m.GetWebhook(id, actor); err != nil {
+		return err
+	}
+	var webhooks []*models.Webhook
+	for _, w := range m.Webhooks {
+		if w.ID != id {
+			webhooks = append(webhooks, w)
+		}
+	}
+	m.Webhooks = webhooks
+	return nil
+}
+
+// GetDeliveries gibt die Zustellungen eines Webhooks im Mock zurück.
+func (m *MockWebhookService) GetDeliveries(id int, actor *models.User) ([]*models.WebhookDelivery, error) {
+	if _, err := m.GetWebhook(id, actor); err != nil {
+		return nil, err
+	}
+	var deliveries []*models.WebhookDelivery
+	for _, d := range m.Deliveries {
+		if d.WebhookID == id {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+// GetDeadLetters gibt alle Zustellungen mit Status dead im Mock zurück.
+func (m *MockWebhookService) GetDeadLetters(actor *models.User) ([]*models.WebhookDelivery, error) {
+	if err := m.check(actor); err != nil {
+		return nil, err
+	}
+	var deliveries []*models.WebhookDelivery
+	for _, d := range m.Deliveries {
+		if d.Status == models.DeliveryDead {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery setzt eine Zustellung im Mock auf pending zurück.
+func (m *MockWebhookService) RetryDelivery(id int, actor *models.User) (*models.WebhookDelivery, error) {
+	if err := m.check(actor); err != nil {
+		return nil, err
+	}
+	for _, d := range m.Deliveries {
+		if d.ID != id {
+			continue
+		}
+		if d.Status == models.DeliveryPending {
+			return nil, fmt.Errorf("delivery already pending")
+		}
+		now := time.Now()
+		d.Status, d.Attempts, d.NextAttemptAt = models.DeliveryPending, 0, &now
+		return d, nil
+	}
+	return nil, fmt.Errorf("delivery not found")
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+)
+
+// Diese Datei enthält Unit-Tests für den WebhookService und die Task-Ereignisse des TaskService.
+
+// Test_Service_CreateWebhook_Validation prüft Anmeldung, URL, Ereignistypen und Secret.
+func Test_Service_CreateWebhook_Validation(t *testing.T) {
+	service := WebhookService{Repo: &repository.MockWebhookRepository{
+		CreateFunc: func(w *models.Webhook) (*models.Webhook, error) {
+			w.ID = 1
+			return w, nil
+		},
+	}}
+	user := &models.User{ID: 1}
+
+	_, err := service.CreateWebhook(models.WebhookRequest{URL: "https://example.com/hook"}, nil)
+	assert.Equal(t, "authentication required", err.Error())
+
+	for _, url := range []string{"", "example.com/hook", "ftp://example.com", "https://"} {
+		_, err = service.CreateWebhook(models.WebhookRequest{URL: url}, user)
+		assert.Equal(t, "invalid url", err.Error(), url)
+	}
+
+	_, err = service.CreateWebhook(models.WebhookRequest{URL: "https://example.com", Events: []string{"task.exploded"}}, user)
+	assert.Equal(t, "invalid event", err.Error())
+
+	_, err = service.CreateWebhook(models.WebhookRequest{URL: "https://example.com", Secret: "short"}, user)
+	assert.Equal(t, "invalid secret", err.Error())
+
+	created, err := service.CreateWebhook(models.WebhookRequest{
+		URL:    " https://example.com/hook ",
+		Events: []string{models.EventTaskCreated, models.EventTaskCreated, models.EventTaskDeleted},
+	}, user)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", created.URL)
+	assert.Equal(t, []string{models.EventTaskCreated, models.EventTaskDeleted}, created.Events)
+	assert.True(t, created.Active)
+	assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+	assert.Len(t, created.Secret, len("whsec_")+64)
+}
+
+// Test_Service_RetryDelivery prüft, dass nur abgeschlossene Zustellungen erneut eingeplant werden.
+func Test_Service_RetryDelivery(t *testing.T) {
+	deliveries := map[int]*models.WebhookDelivery{
+		1: {ID: 1, Status: models.DeliveryDead, Attempts: 8},
+		2: {ID: 2, Status: models.DeliveryPending, Attempts: 1},
+	}
+	retried := 0
+	service := WebhookService{Repo: &repository.MockWebhookRepository{
+		GetDeliveryFunc: func(id int) (*models.WebhookDelivery, error) {
+			return deliveries[id], nil
+		},
+		RetryDeliveryFunc: func(id int) error {
+			retried = id
+			deliveries[id].Status, deliveries[id].Attempts = models.DeliveryPending, 0
+			return nil
+		},
+	}}
+	user := &models.User{ID: 1}
+
+	_, err := service.RetryDelivery(3, user)
+	assert.Equal(t, "delivery not found", err.Error())
+
+	_, err = service.RetryDelivery(2, user)
+	assert.Equal(t, "delivery already pending", err.Error())
+
+	delivery, err := service.RetryDelivery(1, user)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, retried)
+	assert.Equal(t, models.DeliveryPending, delivery.Status)
+	assert.Equal(t, 0, delivery.Attempts)
+}
+
+// Test_Service_TaskEvents prüft, dass Anlegen, Ändern, Statuswechsel und Löschen die passenden
+// Ereignisse an das Repository übergeben, damit sie transaktional in die Outbox geschrieben werden.
+func Test_Service_TaskEvents(t *testing.T) {
+	stored := &models.Task{ID: 1, Title: "Alt", Status: "todo", Priority: "medium"}
+	repo := &repository.MockTaskRepository{
+		CreateFunc: func(task *models.Task) (*models.Task, error) {
+			task.ID = 1
+			return task, nil
+		},
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			copy := *stored
+			return &copy, nil
+		},
+		UpdateFunc: func(task *models.Task) (*models.Task, error) {
+			return task, nil
+		},
+		DeleteFunc: func(id int) error {
+			return nil
+		},
+	}
+	service := TaskService{Repo: repo}
+
+	_, err := service.CreateTask(models.CreateTaskRequest{Title: "Neu"})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TaskEvent{{Type: models.EventTaskCreated}}, repo.Events)
+
+	repo.Events = nil
+	_, err = service.UpdateTask(1, models.CreateTaskRequest{Title: "Umbenannt"})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TaskEvent{{Type: models.EventTaskUpdated}}, repo.Events)
+
+	repo.Events = nil
+	_, err = service.UpdateTask(1, models.CreateTaskRequest{Status: "in progress"})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.TaskEvent{
+		{Type: models.EventTaskUpdated},
+		{Type: models.EventTaskStatusChanged, PreviousStatus: "todo"},
+	}, repo.Events)
+
+	repo.Events = nil
+	_, err = service.UpdateTask(1, models.CreateTaskRequest{Status: "invalid"})
+	assert.Error(t, err)
+	assert.Empty(t, repo.Events)
+
+	assert.NoError(t, service.DeleteTask(1))
+	assert.Equal(t, []models.TaskEvent{{Type: models.EventTaskDeleted}}, repo.Events)
+}
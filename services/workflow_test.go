@@ -0,0 +1,83 @@
+package services
+
+import (
+	"github.com/stretchr/testify/assert"
+	"task-api/models"
+	"task-api/repository"
+	"testing"
+)
+
+// Diese Datei enthält Unit-Tests für die Workflow-Regeln (erlaubte Statuswechsel) im TaskService.
+
+// taskWithStatusRepo liefert ein MockTaskRepository, in dem jeder Task den übergebenen Status hat.
+func taskWithStatusRepo(status string) *repository.MockTaskRepository {
+	return &repository.MockTaskRepository{
+		GetByIdFunc: func(id int) (*models.Task, error) {
+			return &models.Task{ID: id, Title: "Task", Status: status}, nil
+		},
+		UpdateFunc: func(task *models.Task) (*models.Task, error) {
+			return task, nil
+		},
+	}
+}
+
+// Test_Service_UpdateTask_ReopenRequired prüft, dass done -> todo nur mit Reopen erlaubt ist.
+func Test_Service_UpdateTask_ReopenRequired(t *testing.T) {
+	service := TaskService{Repo: taskWithStatusRepo("done")}
+
+	updated, err := service.UpdateTask(1, models.CreateTaskRequest{Status: "todo"})
+	assert.Nil(t, updated)
+	assert.Equal(t, "reopen required", err.Error())
+
+	updated, err = service.UpdateTask(1, models.CreateTaskRequest{Status: "todo", Reopen: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "todo", updated.Status)
+}
+
+// Test_Service_UpdateTask_InvalidTransition prüft, dass Übergänge außerhalb des Workflows abgelehnt werden.
+func Test_Service_UpdateTask_InvalidTransition(t *testing.T) {
+	workflow := &models.Workflow{
+		Statuses: []models.WorkflowStatus{
+			{Name: "open", Initial: true},
+			{Name: "review"},
+			{Name: "closed", Final: true},
+		},
+		Transitions: []models.WorkflowTransition{
+			{From: "open", To: "review"},
+			{From: "review", To: "closed"},
+		},
+	}
+	service := TaskService{Repo: taskWithStatusRepo("open"), Workflow: workflow}
+
+	_, err := service.UpdateTask(1, models.CreateTaskRequest{Status: "closed"})
+	assert.Equal(t, "invalid status transition", err.Error())
+
+	_, err = service.UpdateTask(1, models.CreateTaskRequest{Status: "done"})
+	assert.Equal(t, "status: must be one of: open, review, closed", err.Error())
+
+	updated, err := service.UpdateTask(1, models.CreateTaskRequest{Status: "review"})
+	assert.NoError(t, err)
+	assert.Equal(t, "review", updated.Status)
+}
+
+// Test_Service_CreateTask_WorkflowInitialStatus prüft, dass neue Tasks den Startstatus des Workflows erhalten
+// und unbekannte Status abgelehnt werden.
+func Test_Service_CreateTask_WorkflowInitialStatus(t *testing.T) {
+	mockRepo := &repository.MockTaskRepository{
+		CreateFunc: func(task *models.Task) (*models.Task, error) {
+			return task, nil
+		},
+	}
+	workflow := &models.Workflow{
+		Statuses: []models.WorkflowStatus{{Name: "backlog", Initial: true}, {Name: "closed", Final: true}},
+	}
+	service := TaskService{Repo: mockRepo, Workflow: workflow}
+
+	task, err := service.CreateTask(models.CreateTaskRequest{Title: "Neu"})
+	assert.NoError(t, err)
+	assert.Equal(t, "backlog", task.Status)
+
+	task, err = service.CreateTask(models.CreateTaskRequest{Title: "Neu", Status: "todo"})
+	assert.Nil(t, task)
+	assert.Equal(t, "status: must be one of: backlog, closed", err.Error())
+}
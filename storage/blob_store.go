@@ -0,0 +1,58 @@
+// Package storage enthält die Ablage für Dateiinhalte (Blobs), z.B. Anhänge von Tasks.
+// Metadaten liegen in Postgres, die Inhalte in einem BlobStore.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound wird zurückgegeben, wenn unter einem Key kein Blob existiert.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore definiert die Methoden, die jede Blob-Ablage implementieren muss.
+// Keys sind relative Pfade aus Buchstaben, Ziffern, "-", "_", "." und "/".
+type BlobStore interface {
+	// Put speichert den Inhalt von r unter key. size ist die Länge in Bytes.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get liefert length Bytes ab offset; length < 0 liest bis zum Ende.
+	// Gibt ErrNotFound zurück, wenn der Blob nicht existiert.
+	Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+
+	// Delete entfernt einen Blob. Ein nicht existierender Blob ist kein Fehler.
+	Delete(ctx context.Context, key string) error
+}
+
+// validKey prüft, dass ein Key keine absoluten Pfade oder ".."-Segmente enthält.
+func validKey(key string) bool {
+	if key == "" || key[0] == '/' {
+		return false
+	}
+	segment := 0
+	for i := 0; i <= len(key); i++ {
+		if i == len(key) || key[i] == '/' {
+			s := key[segment:i]
+			if s == "" || s == "." || s == ".." {
+				return false
+			}
+			segment = i + 1
+			continue
+		}
+		c := key[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// errInvalidKey wird für Keys zurückgegeben, die validKey nicht erfüllen.
+var errInvalidKey = errors.New("invalid blob key")
+
+// limitedReadCloser begrenzt einen ReadCloser auf eine Anzahl Bytes und schließt die Quelle.
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Test_LocalStore_PutGetDelete prüft Speichern, Bereichs-Lesen und Löschen im Dateisystem.
+func Test_LocalStore_PutGetDelete(t *testing.T) {
+	store := &LocalStore{Dir: t.TempDir()}
+	ctx := context.Background()
+
+	assert.NoError(t, store.Put(ctx, "tasks/1/file", strings.NewReader("hello world"), 11, "text/plain"))
+
+	r, err := store.Get(ctx, "tasks/1/file", 6, 3)
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "wor", string(data))
+
+	assert.NoError(t, store.Delete(ctx, "tasks/1/file"))
+	_, err = store.Get(ctx, "tasks/1/file", 0, -1)
+	assert.Equal(t, ErrNotFound, err)
+	assert.NoError(t, store.Delete(ctx, "tasks/1/file"))
+}
+
+// Test_LocalStore_RejectsInvalidKeys prüft, dass Keys nicht aus dem Verzeichnis herausführen können.
+func Test_LocalStore_RejectsInvalidKeys(t *testing.T) {
+	store := &LocalStore{Dir: t.TempDir()}
+
+	for _, key := range []string{"", "/etc/passwd", "../x", "a/../../x", "a//b", "a b"} {
+		err := store.Put(context.Background(), key, strings.NewReader("x"), 1, "")
+		assert.Equal(t, errInvalidKey, err, key)
+	}
+}
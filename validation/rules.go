@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// checkStruct wertet die Tags `validate` aller exportierten Felder von s aus.
+// Eingebettete Structs werden wie eigene Felder behandelt.
+func checkStruct(s any) Errors {
+	return checkValue(reflect.ValueOf(s))
+}
+
+// checkValue wertet die Tags `validate` aller Felder des Structs rv aus.
+func checkValue(rv reflect.Value) Errors {
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs Errors
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		// Felder eingebetteter Structs gehören wie bei encoding/json zum äußeren Objekt
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			errs = append(errs, checkValue(rv.Field(i))...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+		if msg := checkField(rv.Field(i), tag); msg != "" {
+			errs = append(errs, FieldError{Field: jsonName(field), Message: msg})
+		}
+	}
+	return errs
+}
+
+// jsonName liefert den Namen eines Feldes im JSON-Format (laut Tag `json`, sonst den Go-Namen).
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// checkField prüft einen Wert gegen die Regeln des Tags und liefert den ersten Verstoß oder "".
+func checkField(value reflect.Value, tag string) string {
+	rules := strings.Split(tag, ",")
+
+	// Nicht gesetzte Werte werden nur von required geprüft
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			if slices.Contains(rules, "required") {
+				return "is required"
+			}
+			return ""
+		}
+		value = value.Elem()
+	}
+	if value.IsZero() {
+		if slices.Contains(rules, "required") {
+			return "is required"
+		}
+		if value.Kind() == reflect.String {
+			return ""
+		}
+	}
+
+	for _, rule := range rules {
+		name, arg, _ := strings.Cut(rule, "=")
+		var msg string
+		switch name {
+		case "required":
+		case "min", "max":
+			msg = checkBound(value, name, arg)
+		case "oneof":
+			allowed := strings.Fields(arg)
+			if value.Kind() == reflect.String && !slices.Contains(allowed, value.String()) {
+				msg = "must be one of: " + strings.Join(allowed, ", ")
+			}
+		case "format":
+			if !slices.Contains(Formats, arg) {
+				panic(fmt.Sprintf("validation: unknown format %q", arg))
+			}
+			msg = CheckFormat(value.String(), arg)
+		default:
+			panic(fmt.Sprintf("validation: unknown rule %q", rule))
+		}
+		if msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// checkBound prüft min bzw. max: bei Strings die Anzahl Zeichen, bei Slices und Maps die Anzahl
+// Elemente, bei Zahlen den Wert.
+func checkBound(value reflect.Value, rule, arg string) string {
+	limit, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid limit %q", arg))
+	}
+
+	var n float64
+	unit := ""
+	switch value.Kind() {
+	case reflect.String:
+		n, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		n, unit = float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = value.Float()
+	default:
+		return ""
+	}
+
+	if rule == "min" && n < limit {
+		return "must be at least " + arg + unit
+	}
+	if rule == "max" && n > limit {
+		return "must be at most " + arg + unit
+	}
+	return ""
+}
+
+// Formats sind die von CheckFormat geprüften Formate.
+var Formats = []string{"date-time", "email", "uri"}
+
+// CheckFormat prüft einen String auf eines der Formate aus Formats und gibt bei einem Verstoß die
+// Fehlermeldung zurück. Unbekannte Formate sind immer gültig (z.B. in einem OpenAPI-Schema).
+func CheckFormat(value, format string) string {
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return "must be a RFC 3339 date-time"
+		}
+	case "email":
+		if addr, err := mail.ParseAddress(value); err != nil || addr.Address != value {
+			return "must be an email address"
+		}
+	case "uri":
+		if u, err := url.Parse(value); err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be an absolute URI"
+		}
+	}
+	return ""
+}
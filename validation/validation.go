@@ -0,0 +1,84 @@
+// Package validation prüft Eingaben anhand von Struct-Tags und zusätzlichen Regeln und sammelt
+// dabei alle Verstöße, statt beim ersten Fehler abzubrechen.
+//
+// Regeln werden im Tag `validate` angegeben und durch Kommas getrennt:
+//
+//	Title    string `json:"title" validate:"required,max=200"`
+//	Priority string `json:"priority" validate:"oneof=low medium high"`
+//
+// Unterstützte Regeln:
+//
+//	required     - Wert darf nicht leer (bzw. nil) sein
+//	min=N, max=N - Strings: Länge in Zeichen (nicht Bytes); Zahlen: Wert; Slices: Anzahl Elemente
+//	oneof=a b c  - String muss einer der Werte sein
+//	format=F     - String im Format "date-time" (RFC 3339), "email" oder "uri"
+//
+// Alle Regeln außer required gelten nur für gesetzte Werte; leere Strings und nil-Pointer sind erlaubt.
+package validation
+
+import (
+	"strings"
+)
+
+// FieldError ist ein Verstoß gegen eine Regel für ein einzelnes Feld.
+type FieldError struct {
+	Field   string `json:"field"`   // JSON-Name des Feldes, z.B. "title"
+	Message string `json:"message"` // Beschreibung des Verstoßes, z.B. "must be at most 200 characters"
+}
+
+// Errors ist die Liste aller Verstöße einer Eingabe. Als error formatiert ergibt sich z.B.
+// "title: is required; priority: must be one of: low, medium, high".
+type Errors []FieldError
+
+// Error fasst alle Verstöße in einer Meldung zusammen.
+func (e Errors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validator sammelt Verstöße aus Struct-Tags und eigenen Regeln (z.B. Abhängigkeiten zwischen Feldern).
+// Der Nullwert ist einsatzbereit.
+type Validator struct {
+	errs Errors
+}
+
+// Struct prüft die Tags `validate` aller Felder von s (Struct oder Pointer auf Struct).
+func (v *Validator) Struct(s any) {
+	v.errs = append(v.errs, checkStruct(s)...)
+}
+
+// Check meldet für field den Verstoß message, wenn ok false ist.
+// Hat field bereits einen Verstoß, wird kein weiterer hinzugefügt.
+func (v *Validator) Check(ok bool, field, message string) {
+	if !ok && !v.Has(field) {
+		v.errs = append(v.errs, FieldError{Field: field, Message: message})
+	}
+}
+
+// Has meldet, ob für field bereits ein Verstoß gesammelt wurde.
+func (v *Validator) Has(field string) bool {
+	for _, fe := range v.errs {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Err liefert die gesammelten Verstöße als Errors oder nil, wenn die Eingabe gültig ist.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// Struct prüft die Tags `validate` aller Felder von s und liefert die Verstöße oder nil.
+func Struct(s any) error {
+	var v Validator
+	v.Struct(s)
+	return v.Err()
+}
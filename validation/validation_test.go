@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type testMeta struct {
+	Owner string `json:"owner" validate:"format=email"`
+}
+
+type testRequest struct {
+	testMeta
+	Name     string   `json:"name" validate:"required,max=5"`
+	Kind     string   `json:"kind" validate:"oneof=a b"`
+	Count    int      `json:"count" validate:"min=1,max=10"`
+	Parent   *int     `json:"parent_id" validate:"min=0"`
+	Link     string   `json:"link" validate:"format=uri"`
+	Labels   []string `json:"labels" validate:"max=2"`
+	Untagged string   `json:"untagged"`
+}
+
+// Test_Struct_AllErrors prüft, dass alle Verstöße gesammelt und mit JSON-Namen gemeldet werden.
+func Test_Struct_AllErrors(t *testing.T) {
+	parent := -1
+	err := Struct(testRequest{
+		testMeta: testMeta{Owner: "nobody"},
+		Kind:     "c",
+		Count:    11,
+		Parent:   &parent,
+		Link:     "/relative",
+		Labels:   []string{"x", "y", "z"},
+	})
+
+	assert.Equal(t, Errors{
+		{Field: "owner", Message: "must be an email address"},
+		{Field: "name", Message: "is required"},
+		{Field: "kind", Message: "must be one of: a, b"},
+		{Field: "count", Message: "must be at most 10"},
+		{Field: "parent_id", Message: "must be at least 0"},
+		{Field: "link", Message: "must be an absolute URI"},
+		{Field: "labels", Message: "must be at most 2 items"},
+	}, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "owner: must be an email address; name: is required; "))
+}
+
+// Test_Struct_Valid prüft, dass leere optionale Felder und nil-Pointer erlaubt sind und Längen in Zeichen zählen.
+func Test_Struct_Valid(t *testing.T) {
+	assert.NoError(t, Struct(&testRequest{Name: "äöüßé", Count: 1}))
+	assert.Equal(t, Errors{{Field: "name", Message: "must be at most 5 characters"}}, Struct(testRequest{Name: "äöüßéx", Count: 1}))
+	assert.Equal(t, Errors{{Field: "count", Message: "must be at least 1"}}, Struct(testRequest{Name: "ok"}))
+}
+
+// Test_Validator_Check prüft eigene Regeln und dass pro Feld nur der erste Verstoß gemeldet wird.
+func Test_Validator_Check(t *testing.T) {
+	var v Validator
+	assert.NoError(t, v.Err())
+
+	v.Struct(testRequest{Count: 1})
+	v.Check(false, "name", "is too boring")
+	v.Check(true, "due_at", "must not be before start_at")
+	v.Check(false, "due_at", "must not be before start_at")
+
+	assert.True(t, v.Has("due_at"))
+	assert.Equal(t, Errors{
+		{Field: "name", Message: "is required"},
+		{Field: "due_at", Message: "must not be before start_at"},
+	}, v.Err())
+}
+
+// Test_CheckFormat prüft die gemeinsamen Formate und dass unbekannte Formate nur in Tags abgelehnt werden.
+func Test_CheckFormat(t *testing.T) {
+	assert.Empty(t, CheckFormat("2025-03-01T09:00:00+01:00", "date-time"))
+	assert.Equal(t, "must be a RFC 3339 date-time", CheckFormat("2025-03-01", "date-time"))
+	assert.Equal(t, "must be an email address", CheckFormat("Max <max@example.com>", "email"))
+	assert.Empty(t, CheckFormat("irgendwas", "uuid"))
+
+	assert.Panics(t, func() {
+		Struct(struct {
+			ID string `json:"id" validate:"format=uuid"`
+		}{ID: "x"})
+	})
+}